@@ -53,6 +53,12 @@ func main() {
 	rec.RecordInstanceStartEnd("instance", time.Second)
 	rec.RecordReadyWaitBegin("instance")
 	rec.RecordReadyWaitEnd("instance")
+	rec.RecordListenerConnectionOpen("listener")
+	rec.RecordListenerWait("listener", time.Second)
+	rec.RecordListenerBytes("listener", "in", 1)
+	rec.RecordListenerConnectionClose("listener", "forwarded")
+	rec.RecordListenerDrop("listener", "max_connections")
+	rec.RecordInspectCache("hit")
 	// RecordInactiveInstance only records sablier_instance_active_seconds_total
 	// when a non-zero active duration has elapsed; sleep so the sample (and thus
 	// the metric's metadata) is emitted deterministically, even on coarse clocks.
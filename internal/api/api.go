@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/sablierapp/sablier/pkg/config"
 	"github.com/sablierapp/sablier/pkg/metrics"
 	"github.com/sablierapp/sablier/pkg/provider"
@@ -19,6 +20,15 @@ type Sablier interface {
 	RequestReadySession(ctx context.Context, names []string, duration time.Duration, timeout time.Duration) (*sablier.SessionState, error)
 	RequestReadySessionGroup(ctx context.Context, group string, duration time.Duration, timeout time.Duration) (*sablier.SessionState, error)
 	InstanceEvents(ctx context.Context, opts provider.InstanceEventsOptions) sablier.InstanceEventStream
+	ListInstances(ctx context.Context) ([]sablier.InstanceInfoWithError, error)
+	InspectInstance(ctx context.Context, name string) (sablier.InstanceInfo, error)
+	ListSessions(ctx context.Context) ([]sablier.InstanceSession, error)
+	Groups() map[string][]string
+	ExpireSession(ctx context.Context, name string) error
+	ExtendSession(ctx context.Context, name string, duration time.Duration) (sablier.InstanceSession, error)
+	PinInstance(ctx context.Context, name string, ttl time.Duration) (*sablier.SessionState, time.Time, error)
+	PinGroup(ctx context.Context, group string, ttl time.Duration) (*sablier.SessionState, time.Time, error)
+	Usage(ctx context.Context, from, to time.Time) ([]sablier.InstanceUsage, error)
 }
 
 type ServeStrategy struct {
@@ -28,14 +38,34 @@ type ServeStrategy struct {
 	Metrics        metrics.Recorder
 	StrategyConfig config.Strategy
 	SessionsConfig config.Sessions
+	Routing        config.Routing
+
+	// StatusTokens signs the status stream URL of the waiting pages. Nil
+	// when the API does not require authentication.
+	StatusTokens *StatusTokens
 }
 
 // recordSessionRequest emits the session-request counter for the given strategy
-// based on whether the request targets named instances or a group.
-func recordSessionRequest(rec metrics.Recorder, strategy, group string) {
+// based on whether the request targets named instances or a group, and tags the
+// request context with the strategy for the session history.
+func recordSessionRequest(c *gin.Context, rec metrics.Recorder, strategy, group string) {
 	target := "names"
 	if group != "" {
 		target = "group"
 	}
 	rec.RecordSessionRequest(strategy, target)
+	c.Request = c.Request.WithContext(sablier.WithStrategy(c.Request.Context(), strategy))
+}
+
+// bindSessionMode validates the session_mode query parameter and tags the
+// request context with it. On failure it answers the validation problem and
+// returns false.
+func bindSessionMode(c *gin.Context, mode string) bool {
+	sessionMode, err := sablier.ParseSessionMode(mode)
+	if err != nil {
+		AbortWithProblemDetail(c, ProblemValidation(err))
+		return false
+	}
+	c.Request = c.Request.WithContext(sablier.WithSessionMode(c.Request.Context(), sessionMode))
+	return true
 }
@@ -43,6 +43,64 @@ func (m *MockSablier) EXPECT() *MockSablierMockRecorder {
 	return m.recorder
 }
 
+// ExpireSession mocks base method.
+func (m *MockSablier) ExpireSession(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSession", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireSession indicates an expected call of ExpireSession.
+func (mr *MockSablierMockRecorder) ExpireSession(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSession", reflect.TypeOf((*MockSablier)(nil).ExpireSession), ctx, name)
+}
+
+// ExtendSession mocks base method.
+func (m *MockSablier) ExtendSession(ctx context.Context, name string, duration time.Duration) (sablier.InstanceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendSession", ctx, name, duration)
+	ret0, _ := ret[0].(sablier.InstanceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendSession indicates an expected call of ExtendSession.
+func (mr *MockSablierMockRecorder) ExtendSession(ctx, name, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendSession", reflect.TypeOf((*MockSablier)(nil).ExtendSession), ctx, name, duration)
+}
+
+// Groups mocks base method.
+func (m *MockSablier) Groups() map[string][]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Groups")
+	ret0, _ := ret[0].(map[string][]string)
+	return ret0
+}
+
+// Groups indicates an expected call of Groups.
+func (mr *MockSablierMockRecorder) Groups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Groups", reflect.TypeOf((*MockSablier)(nil).Groups))
+}
+
+// InspectInstance mocks base method.
+func (m *MockSablier) InspectInstance(ctx context.Context, name string) (sablier.InstanceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectInstance", ctx, name)
+	ret0, _ := ret[0].(sablier.InstanceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectInstance indicates an expected call of InspectInstance.
+func (mr *MockSablierMockRecorder) InspectInstance(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectInstance", reflect.TypeOf((*MockSablier)(nil).InspectInstance), ctx, name)
+}
+
 // InstanceEvents mocks base method.
 func (m *MockSablier) InstanceEvents(ctx context.Context, opts provider.InstanceEventsOptions) sablier.InstanceEventStream {
 	m.ctrl.T.Helper()
@@ -57,6 +115,68 @@ func (mr *MockSablierMockRecorder) InstanceEvents(ctx, opts any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceEvents", reflect.TypeOf((*MockSablier)(nil).InstanceEvents), ctx, opts)
 }
 
+// ListInstances mocks base method.
+func (m *MockSablier) ListInstances(ctx context.Context) ([]sablier.InstanceInfoWithError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances", ctx)
+	ret0, _ := ret[0].([]sablier.InstanceInfoWithError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockSablierMockRecorder) ListInstances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockSablier)(nil).ListInstances), ctx)
+}
+
+// ListSessions mocks base method.
+func (m *MockSablier) ListSessions(ctx context.Context) ([]sablier.InstanceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx)
+	ret0, _ := ret[0].([]sablier.InstanceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockSablierMockRecorder) ListSessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSablier)(nil).ListSessions), ctx)
+}
+
+// PinGroup mocks base method.
+func (m *MockSablier) PinGroup(ctx context.Context, group string, ttl time.Duration) (*sablier.SessionState, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinGroup", ctx, group, ttl)
+	ret0, _ := ret[0].(*sablier.SessionState)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PinGroup indicates an expected call of PinGroup.
+func (mr *MockSablierMockRecorder) PinGroup(ctx, group, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinGroup", reflect.TypeOf((*MockSablier)(nil).PinGroup), ctx, group, ttl)
+}
+
+// PinInstance mocks base method.
+func (m *MockSablier) PinInstance(ctx context.Context, name string, ttl time.Duration) (*sablier.SessionState, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinInstance", ctx, name, ttl)
+	ret0, _ := ret[0].(*sablier.SessionState)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PinInstance indicates an expected call of PinInstance.
+func (mr *MockSablierMockRecorder) PinInstance(ctx, name, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinInstance", reflect.TypeOf((*MockSablier)(nil).PinInstance), ctx, name, ttl)
+}
+
 // RequestReadySession mocks base method.
 func (m *MockSablier) RequestReadySession(ctx context.Context, names []string, duration, timeout time.Duration) (*sablier.SessionState, error) {
 	m.ctrl.T.Helper()
@@ -116,3 +236,18 @@ func (mr *MockSablierMockRecorder) RequestSessionGroup(ctx, group, duration any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestSessionGroup", reflect.TypeOf((*MockSablier)(nil).RequestSessionGroup), ctx, group, duration)
 }
+
+// Usage mocks base method.
+func (m *MockSablier) Usage(ctx context.Context, from, to time.Time) ([]sablier.InstanceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, from, to)
+	ret0, _ := ret[0].([]sablier.InstanceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockSablierMockRecorder) Usage(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockSablier)(nil).Usage), ctx, from, to)
+}
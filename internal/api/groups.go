@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListGroups registers GET /api/groups.
+//
+// @Summary      List groups
+// @Description  Lists every group known to Sablier with the names of its member instances, as last discovered from the provider.
+// @Tags         groups
+// @Produce      json
+// @Success      200  {object}  GroupsResponse
+// @Router       /api/groups [get]
+func ListGroups(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/groups", func(c *gin.Context) {
+		c.JSON(http.StatusOK, GroupsResponse{Groups: s.Sablier.Groups()})
+	})
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestListGroups(t *testing.T) {
+	app, router, strategy, m := NewApiTest(t)
+	ListGroups(router, strategy)
+	m.EXPECT().Groups().Return(map[string][]string{"default": {"nginx", "whoami"}})
+	r := PerformRequest(app, "GET", "/api/groups")
+	assert.Equal(t, http.StatusOK, r.Code)
+	assert.Equal(t, `{"groups":{"default":["nginx","whoami"]}}`, r.Body.String())
+}
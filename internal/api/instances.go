@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// ListInstances registers GET /api/instances.
+//
+// @Summary      List instances
+// @Description  Lists every instance managed by Sablier (labelled `sablier.enable=true`), running or not, with its current state and parsed configuration. An instance that could not be inspected is listed with the error.
+// @Tags         instances
+// @Produce      json
+// @Success      200  {object}  InstancesResponse
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/instances [get]
+func ListInstances(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/instances", func(c *gin.Context) {
+		instances, err := s.Sablier.ListInstances(c.Request.Context())
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewInstancesResponse(instances))
+	})
+}
+
+// GetInstance registers GET /api/instances/{name}.
+//
+// @Summary      Get an instance
+// @Description  Returns the current state and parsed configuration of one instance managed by Sablier.
+// @Tags         instances
+// @Produce      json
+// @Param        name  path  string  true  "Instance name"
+// @Success      200  {object}  InstanceResponse
+// @Failure      404  {object}  rfc7807.Problem  "Instance not found or not managed by Sablier"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/instances/{name} [get]
+func GetInstance(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/instances/:name", func(c *gin.Context) {
+		info, err := s.Sablier.InspectInstance(c.Request.Context(), c.Param("name"))
+		if notManagedErr, ok := errors.AsType[sablier.ErrInstanceNotManaged](err); ok {
+			AbortWithProblemDetail(c, ProblemInstanceNotManaged(notManagedErr))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, InstanceResponse{Instance: info})
+	})
+}
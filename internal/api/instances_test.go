@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestListInstances(t *testing.T) {
+	t.Run("ListInstances", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ListInstances(router, strategy)
+		m.EXPECT().ListInstances(gomock.Any()).Return([]sablier.InstanceInfoWithError{
+			{Instance: sablier.InstanceInfo{
+				Name:   "whoami",
+				Status: sablier.InstanceStatusReady,
+				Config: &sablier.InstanceConfig{Enabled: true, Groups: []string{"default"}},
+			}},
+			{Instance: sablier.InstanceInfo{Name: "broken"}, Error: errors.New("inspect failed")},
+		}, nil)
+		r := PerformRequest(app, "GET", "/api/instances")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body InstancesResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.DeepEqual(t, body, InstancesResponse{Instances: []InstanceEntryResponse{
+			{Instance: sablier.InstanceInfo{
+				Name:   "whoami",
+				Status: sablier.InstanceStatusReady,
+				Config: &sablier.InstanceConfig{Enabled: true, Groups: []string{"default"}},
+			}},
+			{Instance: sablier.InstanceInfo{Name: "broken"}, Error: "inspect failed"},
+		}})
+	})
+	t.Run("ListInstancesError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ListInstances(router, strategy)
+		m.EXPECT().ListInstances(gomock.Any()).Return(nil, errors.New("provider down"))
+		r := PerformRequest(app, "GET", "/api/instances")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+}
+
+func TestGetInstance(t *testing.T) {
+	t.Run("GetInstance", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		GetInstance(router, strategy)
+		info := sablier.InstanceInfo{
+			Name:   "whoami",
+			Status: sablier.InstanceStatusReady,
+			Config: &sablier.InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		}
+		m.EXPECT().InspectInstance(gomock.Any(), "whoami").Return(info, nil)
+		r := PerformRequest(app, "GET", "/api/instances/whoami")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body InstanceResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.DeepEqual(t, body, InstanceResponse{Instance: info})
+	})
+	t.Run("GetInstanceNotManaged", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		GetInstance(router, strategy)
+		m.EXPECT().InspectInstance(gomock.Any(), "ghost").Return(sablier.InstanceInfo{}, sablier.ErrInstanceNotManaged{Name: "ghost"})
+		r := PerformRequest(app, "GET", "/api/instances/ghost")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("GetInstanceError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		GetInstance(router, strategy)
+		m.EXPECT().InspectInstance(gomock.Any(), "whoami").Return(sablier.InstanceInfo{}, errors.New("provider down"))
+		r := PerformRequest(app, "GET", "/api/instances/whoami")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+type PinRequest struct {
+	Duration time.Duration `form:"duration"`
+}
+
+// PinInstance registers POST /api/instances/{name}/pin.
+//
+// @Summary      Pin an instance
+// @Description  Starts an instance managed by Sablier if needed and keeps it up for the given duration: shorter session requests never bring its expiration closer. Expire its session to release the pin early.
+// @Tags         instances
+// @Produce      json
+// @Param        name      path   string  true  "Instance name"
+// @Param        duration  query  string  true  "How long to keep the instance up as a Go duration (e.g. 2h)."
+// @Success      200  {object}  PinResponse
+// @Failure      400  {object}  rfc7807.Problem  "Validation error"
+// @Failure      404  {object}  rfc7807.Problem  "Instance not managed by Sablier"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/instances/{name}/pin [post]
+func PinInstance(router *gin.RouterGroup, s *ServeStrategy) {
+	router.POST("/instances/:name/pin", func(c *gin.Context) {
+		duration, ok := bindPinDuration(c)
+		if !ok {
+			return
+		}
+
+		session, until, err := s.Sablier.PinInstance(c.Request.Context(), c.Param("name"), duration)
+		if notManagedErr, ok := errors.AsType[sablier.ErrInstanceNotManaged](err); ok {
+			AbortWithProblemDetail(c, ProblemInstanceNotManaged(notManagedErr))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewPinResponse(session, until))
+	})
+}
+
+// PinGroup registers POST /api/groups/{name}/pin.
+//
+// @Summary      Pin a group
+// @Description  Starts every instance of a group if needed and keeps them up for the given duration: shorter session requests never bring their expiration closer. Expire their sessions to release the pin early.
+// @Tags         groups
+// @Produce      json
+// @Param        name      path   string  true  "Group name"
+// @Param        duration  query  string  true  "How long to keep the group up as a Go duration (e.g. 2h)."
+// @Success      200  {object}  PinResponse
+// @Failure      400  {object}  rfc7807.Problem  "Validation error"
+// @Failure      404  {object}  rfc7807.Problem  "Group not found"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/groups/{name}/pin [post]
+func PinGroup(router *gin.RouterGroup, s *ServeStrategy) {
+	router.POST("/groups/:name/pin", func(c *gin.Context) {
+		duration, ok := bindPinDuration(c)
+		if !ok {
+			return
+		}
+
+		session, until, err := s.Sablier.PinGroup(c.Request.Context(), c.Param("name"), duration)
+		if groupNotFoundError, ok := errors.AsType[sablier.ErrGroupNotFound](err); ok {
+			AbortWithProblemDetail(c, ProblemGroupNotFound(groupNotFoundError))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewPinResponse(session, until))
+	})
+}
+
+// bindPinDuration reads the mandatory pin duration, aborting the request with
+// a validation problem when it is missing or invalid.
+func bindPinDuration(c *gin.Context) (time.Duration, bool) {
+	var request PinRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		AbortWithProblemDetail(c, ProblemValidation(err))
+		return 0, false
+	}
+	if request.Duration <= 0 {
+		AbortWithProblemDetail(c, ProblemValidation(errors.New("'duration' query parameter must be a positive duration")))
+		return 0, false
+	}
+	return request.Duration, true
+}
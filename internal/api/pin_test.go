@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestPinInstance(t *testing.T) {
+	t.Run("PinInstance", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		PinInstance(router, strategy)
+		until := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		m.EXPECT().PinInstance(gomock.Any(), "whoami", 2*time.Hour).Return(&sablier.SessionState{
+			Instances: map[string]sablier.InstanceInfoWithError{
+				"whoami": {Instance: sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady}},
+			},
+		}, until, nil)
+		r := PerformRequest(app, "POST", "/api/instances/whoami/pin?duration=2h")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body PinResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.Equal(t, body.PinnedUntil, until)
+		assert.Equal(t, body.Session.Status, "ready")
+	})
+	t.Run("PinInstanceMissingDuration", func(t *testing.T) {
+		app, router, strategy, _ := NewApiTest(t)
+		PinInstance(router, strategy)
+		r := PerformRequest(app, "POST", "/api/instances/whoami/pin")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("PinInstanceNotManaged", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		PinInstance(router, strategy)
+		m.EXPECT().PinInstance(gomock.Any(), "db", time.Hour).Return(nil, time.Time{}, sablier.ErrInstanceNotManaged{Name: "db"})
+		r := PerformRequest(app, "POST", "/api/instances/db/pin?duration=1h")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("PinInstanceError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		PinInstance(router, strategy)
+		m.EXPECT().PinInstance(gomock.Any(), "whoami", time.Hour).Return(nil, time.Time{}, errors.New("provider down"))
+		r := PerformRequest(app, "POST", "/api/instances/whoami/pin?duration=1h")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+	})
+}
+
+func TestPinGroup(t *testing.T) {
+	t.Run("PinGroup", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		PinGroup(router, strategy)
+		m.EXPECT().PinGroup(gomock.Any(), "demo", time.Hour).Return(&sablier.SessionState{}, time.Now().Add(time.Hour), nil)
+		r := PerformRequest(app, "POST", "/api/groups/demo/pin?duration=1h")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("PinGroupNotFound", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		PinGroup(router, strategy)
+		m.EXPECT().PinGroup(gomock.Any(), "ghost", time.Hour).Return(nil, time.Time{}, sablier.ErrGroupNotFound{Group: "ghost"})
+		r := PerformRequest(app, "POST", "/api/groups/ghost/pin?duration=1h")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+}
@@ -104,6 +104,48 @@ func ProblemInstanceNotManaged(e sablier.ErrInstanceNotManaged) rfc7807.Problem
 	return pb
 }
 
+func ProblemSessionNotFound(e sablier.ErrSessionNotFound) rfc7807.Problem {
+	pb := rfc7807.Problem{
+		Type:   "https://sablierapp.dev/#/errors?id=session-not-found",
+		Title:  "Session not found",
+		Status: http.StatusNotFound,
+		Detail: fmt.Sprintf("instance %q has no active session; it may have expired already", e.Name),
+	}
+	_ = pb.Extend("instance", e.Name)
+	_ = pb.Extend("error", e.Error())
+	return pb
+}
+
+func ProblemRouteNotFound(host, path string) rfc7807.Problem {
+	pb := rfc7807.Problem{
+		Type:   "https://sablierapp.dev/#/errors?id=route-not-found",
+		Title:  "Route not found",
+		Status: http.StatusNotFound,
+		Detail: fmt.Sprintf("no route in routing.routes matches host %q and path %q", host, path),
+	}
+	_ = pb.Extend("host", host)
+	_ = pb.Extend("path", path)
+	return pb
+}
+
+func ProblemUnauthorized(detail string) rfc7807.Problem {
+	return rfc7807.Problem{
+		Type:   "https://sablierapp.dev/#/errors?id=unauthorized",
+		Title:  http.StatusText(http.StatusUnauthorized),
+		Status: http.StatusUnauthorized,
+		Detail: detail,
+	}
+}
+
+func ProblemForbidden(detail string) rfc7807.Problem {
+	return rfc7807.Problem{
+		Type:   "https://sablierapp.dev/#/errors?id=forbidden",
+		Title:  http.StatusText(http.StatusForbidden),
+		Status: http.StatusForbidden,
+		Detail: detail,
+	}
+}
+
 func ProblemRequestCancelled() rfc7807.Problem {
 	return rfc7807.Problem{
 		Type:   "https://sablierapp.dev/#/errors?id=request-cancelled",
@@ -112,3 +154,12 @@ func ProblemRequestCancelled() rfc7807.Problem {
 		Detail: "the request was cancelled before the session became ready",
 	}
 }
+
+func ProblemHistoryDisabled(e sablier.ErrHistoryDisabled) rfc7807.Problem {
+	return rfc7807.Problem{
+		Type:   "https://sablierapp.dev/#/errors?id=history-disabled",
+		Title:  "Session history disabled",
+		Status: http.StatusNotFound,
+		Detail: e.Error(),
+	}
+}
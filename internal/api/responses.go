@@ -2,6 +2,7 @@ package api
 
 import (
 	"sort"
+	"time"
 
 	"github.com/sablierapp/sablier/pkg/sablier"
 )
@@ -29,18 +30,21 @@ type SessionStateResponse struct {
 // InstanceEntryResponse is one instance of the session, with the error that
 // prevented it from starting, if any. Optional marks a best-effort instance
 // (requested with the "optional:" name prefix) whose readiness and error do
-// not gate the session status.
+// not gate the session status. SessionDuration is the duration the session of
+// the instance was given, once its sablier.session-duration default and the
+// caps were applied.
 type InstanceEntryResponse struct {
-	Instance sablier.InstanceInfo `json:"instance"`
-	Error    string               `json:"error,omitempty"`
-	Optional bool                 `json:"optional,omitempty"`
+	Instance        sablier.InstanceInfo `json:"instance"`
+	Error           string               `json:"error,omitempty"`
+	Optional        bool                 `json:"optional,omitempty"`
+	SessionDuration time.Duration        `json:"sessionDuration,omitempty"`
 }
 
 // NewSessionResponse maps a domain session to its wire representation.
 func NewSessionResponse(s *sablier.SessionState) SessionResponse {
 	instances := make([]InstanceEntryResponse, 0, len(s.Instances))
 	for _, v := range s.Instances {
-		entry := InstanceEntryResponse{Instance: v.Instance, Optional: v.Optional}
+		entry := InstanceEntryResponse{Instance: v.Instance, Optional: v.Optional, SessionDuration: v.SessionDuration}
 		if v.Error != nil {
 			entry.Error = v.Error.Error()
 		}
@@ -59,3 +63,114 @@ func NewSessionResponse(s *sablier.SessionState) SessionResponse {
 type ThemesResponse struct {
 	Themes []string `json:"themes"`
 }
+
+// InstancesResponse is the JSON body returned by the instance listing
+// endpoint. Instances are sorted by name; an instance that could not be
+// inspected carries the error instead of failing the whole listing.
+type InstancesResponse struct {
+	Instances []InstanceEntryResponse `json:"instances"`
+}
+
+// NewInstancesResponse maps inspected instances to their wire representation.
+func NewInstancesResponse(instances []sablier.InstanceInfoWithError) InstancesResponse {
+	entries := make([]InstanceEntryResponse, 0, len(instances))
+	for _, v := range instances {
+		entry := InstanceEntryResponse{Instance: v.Instance}
+		if v.Error != nil {
+			entry.Error = v.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return InstancesResponse{Instances: entries}
+}
+
+// InstanceResponse is the JSON body returned by the single instance endpoint.
+type InstanceResponse struct {
+	Instance sablier.InstanceInfo `json:"instance"`
+}
+
+// SessionsResponse is the JSON body returned by the session listing endpoint.
+type SessionsResponse struct {
+	Sessions []SessionEntryResponse `json:"sessions"`
+}
+
+// SessionEntryResponse is one active session: the instance info recorded when
+// the session was last requested, the time at which it expires and, while the
+// instance is pinned, the time until which it is kept up.
+type SessionEntryResponse struct {
+	Instance    sablier.InstanceInfo `json:"instance"`
+	ExpiresAt   time.Time            `json:"expiresAt"`
+	PinnedUntil *time.Time           `json:"pinnedUntil,omitempty"`
+}
+
+// NewSessionEntryResponse maps an active session to its wire representation.
+func NewSessionEntryResponse(s sablier.InstanceSession) SessionEntryResponse {
+	return SessionEntryResponse{Instance: s.Instance, ExpiresAt: s.ExpiresAt, PinnedUntil: s.PinnedUntil}
+}
+
+// NewSessionsResponse maps active sessions to their wire representation.
+func NewSessionsResponse(sessions []sablier.InstanceSession) SessionsResponse {
+	entries := make([]SessionEntryResponse, 0, len(sessions))
+	for _, v := range sessions {
+		entries = append(entries, NewSessionEntryResponse(v))
+	}
+	return SessionsResponse{Sessions: entries}
+}
+
+// PinResponse is the JSON body returned by the pin endpoints: the session
+// started (or renewed) for the pinned instances and the time until which they
+// are kept up.
+type PinResponse struct {
+	Session     SessionStateResponse `json:"session"`
+	PinnedUntil time.Time            `json:"pinnedUntil"`
+}
+
+// NewPinResponse maps a pinned session to its wire representation.
+func NewPinResponse(s *sablier.SessionState, until time.Time) PinResponse {
+	return PinResponse{Session: NewSessionResponse(s).Session, PinnedUntil: until}
+}
+
+// GroupsResponse is the JSON body returned by the group listing endpoint: each
+// group name with the names of its member instances.
+type GroupsResponse struct {
+	Groups map[string][]string `json:"groups"`
+}
+
+// UsageResponse is the JSON body returned by the usage endpoint: the usage of
+// every instance with a recorded session over the period.
+type UsageResponse struct {
+	From      time.Time               `json:"from"`
+	To        time.Time               `json:"to"`
+	Instances []InstanceUsageResponse `json:"instances"`
+}
+
+// InstanceUsageResponse is the usage of one instance over the period.
+// Durations are in seconds.
+type InstanceUsageResponse struct {
+	Name string `json:"name"`
+	// Sessions counts the sessions requested by strategy. Sessions not opened
+	// by a strategy request (pins, running hours) are counted under "".
+	Sessions             map[string]int `json:"sessions"`
+	ColdStarts           int            `json:"coldStarts"`
+	ColdStartTimeSeconds float64        `json:"coldStartTimeSeconds"`
+	Stops                map[string]int `json:"stops"`
+	UptimeSeconds        float64        `json:"uptimeSeconds"`
+	IdleSavingsSeconds   float64        `json:"idleSavingsSeconds"`
+}
+
+// NewUsageResponse maps the usage report to its wire representation.
+func NewUsageResponse(from, to time.Time, usages []sablier.InstanceUsage) UsageResponse {
+	instances := make([]InstanceUsageResponse, 0, len(usages))
+	for _, u := range usages {
+		instances = append(instances, InstanceUsageResponse{
+			Name:                 u.Name,
+			Sessions:             u.Sessions,
+			ColdStarts:           u.ColdStarts,
+			ColdStartTimeSeconds: u.ColdStartTime.Seconds(),
+			Stops:                u.Stops,
+			UptimeSeconds:        u.Uptime.Seconds(),
+			IdleSavingsSeconds:   u.IdleSavings.Seconds(),
+		})
+	}
+	return UsageResponse{From: from, To: to, Instances: instances}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/sablierapp/sablier/pkg/sablier"
 	"gotest.tools/v3/assert"
@@ -28,6 +29,21 @@ func TestNewSessionResponse(t *testing.T) {
 		assert.Equal(t, "", resp.Session.Instances[1].Error)
 	})
 
+	t.Run("session duration of each instance", func(t *testing.T) {
+		s := &sablier.SessionState{
+			Instances: map[string]sablier.InstanceInfoWithError{
+				"a": {Instance: sablier.InstanceInfo{Name: "a"}, SessionDuration: 30 * time.Minute},
+			},
+		}
+
+		resp := NewSessionResponse(s)
+
+		assert.Equal(t, 30*time.Minute, resp.Session.Instances[0].SessionDuration)
+		raw, err := json.Marshal(resp)
+		assert.NilError(t, err)
+		assert.Assert(t, containsSub(string(raw), `"sessionDuration":1800000000000`), "%s", raw)
+	})
+
 	t.Run("empty session serializes an empty array, not null", func(t *testing.T) {
 		resp := NewSessionResponse(&sablier.SessionState{})
 		raw, err := json.Marshal(resp)
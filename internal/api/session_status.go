@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// defaultStatusFrequency is how often the session status stream inspects the
+// instances when no waiting page refresh frequency is configured.
+const defaultStatusFrequency = 5 * time.Second
+
+// SessionStatusRequest holds the query parameters of the session status stream.
+type SessionStatusRequest struct {
+	Group string   `form:"group"`
+	Names []string `form:"names"`
+	// Token is the status token of the waiting page, accepted instead of API
+	// credentials. See StatusTokens.
+	Token string `form:"token"`
+}
+
+// SessionStatus registers GET /api/strategies/dynamic/status.
+//
+// The endpoint streams the status of a session as Server-Sent Events so a
+// waiting page can reload the moment the session is ready. Each SSE message
+// carries:
+//
+//	event: status          (the session changed)
+//	data:  <json>          (SessionResponse as JSON)
+//
+// followed by a final `event: ready` once every instance is ready, after which
+// the stream is closed. The stream only inspects the instances: it never
+// starts them nor renews the session, which stays the job of the strategy
+// that rendered the page.
+//
+// When the API requires authentication, the stream also accepts the status
+// token of the `token` query parameter, since EventSource cannot send an
+// Authorization header. StatusURL adds it to the URL given to the waiting
+// page; it grants this stream for the same names or group only.
+//
+// @Summary      Session status (SSE)
+// @Description  Server-Sent Events stream of the session status, pushed whenever an instance changes. Each event is `event: status` followed by `data: <SessionResponse JSON>`; a final `event: ready` is sent once the session is ready, then the stream is closed. The stream never starts instances nor renews the session. Provide either `names` or `group`.
+// @Tags         strategies
+// @Produce      text/event-stream
+// @Param        names  query  []string  false  "Instance name(s). Mutually exclusive with group. Prefix a name with 'optional:' to mark it best-effort."
+// @Param        group  query  string    false  "Group name. Mutually exclusive with names."
+// @Param        token  query  string    false  "Status token of the waiting page, accepted instead of API credentials for the same names or group."
+// @Success      200  {string}  string  "SSE stream (text/event-stream)"
+// @Failure      400  {object}  rfc7807.Problem  "Invalid request"
+// @Failure      404  {object}  rfc7807.Problem  "Group not found"
+// @Router       /api/strategies/dynamic/status [get]
+func SessionStatus(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/strategies/dynamic/status", func(c *gin.Context) {
+		var request SessionStatusRequest
+		if err := c.ShouldBind(&request); err != nil {
+			AbortWithProblemDetail(c, ProblemValidation(err))
+			return
+		}
+
+		if len(request.Names) == 0 && request.Group == "" {
+			AbortWithProblemDetail(c, ProblemValidation(errors.New("'names' or 'group' query parameter must be set")))
+			return
+		}
+
+		if len(request.Names) > 0 && request.Group != "" {
+			AbortWithProblemDetail(c, ProblemValidation(errors.New("'names' and 'group' query parameters are both set, only one must be set")))
+			return
+		}
+
+		names := request.Names
+		if request.Group != "" {
+			groups := s.Sablier.Groups()
+			members, ok := groups[request.Group]
+			if !ok {
+				available := make([]string, 0, len(groups))
+				for group := range groups {
+					available = append(available, group)
+				}
+				slices.Sort(available)
+				AbortWithProblemDetail(c, ProblemGroupNotFound(sablier.ErrGroupNotFound{
+					Group:           request.Group,
+					AvailableGroups: available,
+				}))
+				return
+			}
+			names = members
+		}
+
+		ctx := c.Request.Context()
+		// Subscribe before the first inspection so that no change between the
+		// two is missed.
+		stream := s.Sablier.InstanceEvents(ctx, provider.InstanceEventsOptions{})
+
+		// Waiting pages are served on the application host, not on Sablier's.
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("X-Accel-Buffering", "no")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Status(http.StatusOK)
+
+		flusher, canFlush := c.Writer.(http.Flusher)
+		var last *SessionResponse
+		// push sends the session when it changed and reports whether it is ready.
+		push := func() bool {
+			session := inspectSession(ctx, s, names)
+			response := NewSessionResponse(session)
+			if last == nil || !reflect.DeepEqual(*last, response) {
+				c.SSEvent("status", response)
+				last = &response
+			}
+			ready := session.IsReady()
+			if ready {
+				c.SSEvent("ready", response.Session.Status)
+			}
+			if canFlush {
+				flusher.Flush()
+			}
+			return ready
+		}
+
+		if push() {
+			return
+		}
+
+		// Not every provider reports readiness through its events (a health
+		// check passing is not always one), so the session is also inspected
+		// at the waiting page refresh frequency.
+		frequency := s.StrategyConfig.Dynamic.DefaultRefreshFrequency
+		if frequency <= 0 {
+			frequency = defaultStatusFrequency
+		}
+		ticker := time.NewTicker(frequency)
+		defer ticker.Stop()
+
+		events, errs := stream.Events, stream.Err
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				if !watches(names, event.Info.Name) {
+					continue
+				}
+			case _, ok := <-errs:
+				if ok {
+					// The provider stopped streaming events: keep polling.
+					events = nil
+				}
+				errs = nil
+				continue
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if push() {
+				return
+			}
+		}
+	})
+}
+
+// StatusURL returns the URL of the session status stream for the names or
+// group, or an empty string when no public URL is configured. When the API
+// requires authentication, the URL carries a status token granting only this
+// stream.
+func StatusURL(s *ServeStrategy, names []string, group string) string {
+	if s.StrategyConfig.Dynamic.PublicURL == "" {
+		return ""
+	}
+	query := url.Values{}
+	if group != "" {
+		query.Set("group", group)
+	} else {
+		query["names"] = names
+	}
+	if s.StatusTokens != nil {
+		query.Set("token", s.StatusTokens.Sign(names, group, time.Now()))
+	}
+	return strings.TrimSuffix(s.StrategyConfig.Dynamic.PublicURL, "/") + "/api/strategies/dynamic/status?" + query.Encode()
+}
+
+// inspectSession returns the current state of the named instances.
+func inspectSession(ctx context.Context, s *ServeStrategy, names []string) *sablier.SessionState {
+	session := &sablier.SessionState{Instances: make(map[string]sablier.InstanceInfoWithError, len(names))}
+	for _, n := range names {
+		name, optional := strings.CutPrefix(n, sablier.OptionalPrefix)
+		info, err := s.Sablier.InspectInstance(ctx, name)
+		if info.Name == "" {
+			info.Name = name
+		}
+		session.Instances[name] = sablier.InstanceInfoWithError{Instance: info, Error: err, Optional: optional}
+	}
+	return session
+}
+
+// watches reports whether an event about name concerns the session.
+func watches(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool {
+		return strings.TrimPrefix(n, sablier.OptionalPrefix) == name
+	})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func starting(name string) sablier.InstanceInfo {
+	return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}
+}
+
+func ready(name string) sablier.InstanceInfo {
+	return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}
+}
+
+func TestSessionStatus_PushesChangesUntilReady(t *testing.T) {
+	app, router, strategy, m := NewApiTest(t)
+	strategy.StrategyConfig.Dynamic.DefaultRefreshFrequency = time.Hour
+	SessionStatus(router, strategy)
+
+	events := make(chan sablier.InstanceEvent, 2)
+	events <- sablier.InstanceEvent{Type: provider.InstanceEventUpdated, Info: sablier.InstanceInfo{Name: "other"}}
+	events <- sablier.InstanceEvent{Type: provider.InstanceEventStarted, Info: sablier.InstanceInfo{Name: "nginx"}}
+	m.EXPECT().InstanceEvents(gomock.Any(), provider.InstanceEventsOptions{}).Return(sablier.InstanceEventStream{Events: events})
+	gomock.InOrder(
+		m.EXPECT().InspectInstance(gomock.Any(), "nginx").Return(starting("nginx"), nil),
+		// Only the event about nginx triggers a new inspection.
+		m.EXPECT().InspectInstance(gomock.Any(), "nginx").Return(ready("nginx"), nil),
+	)
+
+	r := PerformRequest(app, "GET", "/api/strategies/dynamic/status?names=nginx")
+
+	assert.Equal(t, http.StatusOK, r.Code)
+	assert.Assert(t, strings.HasPrefix(r.Header().Get("Content-Type"), "text/event-stream"))
+	assert.Equal(t, "*", r.Header().Get("Access-Control-Allow-Origin"))
+	body := r.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "event:status"), body)
+	assert.Assert(t, strings.Contains(body, `"status":"not-ready"`), body)
+	assert.Assert(t, strings.HasSuffix(body, "event:ready\ndata:ready\n\n"), body)
+}
+
+func TestSessionStatus_PollsWithoutEvents(t *testing.T) {
+	app, router, strategy, m := NewApiTest(t)
+	strategy.StrategyConfig.Dynamic.DefaultRefreshFrequency = 10 * time.Millisecond
+	SessionStatus(router, strategy)
+
+	m.EXPECT().Groups().Return(map[string][]string{"blog": {"blog", "optional:search"}})
+	m.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{})
+	m.EXPECT().InspectInstance(gomock.Any(), "search").Return(starting("search"), nil).AnyTimes()
+	gomock.InOrder(
+		m.EXPECT().InspectInstance(gomock.Any(), "blog").Return(starting("blog"), nil).Times(2),
+		m.EXPECT().InspectInstance(gomock.Any(), "blog").Return(ready("blog"), nil),
+	)
+
+	r := PerformRequest(app, "GET", "/api/strategies/dynamic/status?group=blog")
+
+	assert.Equal(t, http.StatusOK, r.Code)
+	body := r.Body.String()
+	// An unchanged session is not sent again, and the optional instance does
+	// not hold the session back.
+	assert.Equal(t, 2, strings.Count(body, "event:status"), body)
+	assert.Assert(t, strings.Contains(body, "event:ready"), body)
+}
+
+func TestSessionStatus_ReadyAtOnce(t *testing.T) {
+	app, router, strategy, m := NewApiTest(t)
+	SessionStatus(router, strategy)
+
+	m.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{})
+	m.EXPECT().InspectInstance(gomock.Any(), "nginx").Return(ready("nginx"), nil)
+
+	r := PerformRequest(app, "GET", "/api/strategies/dynamic/status?names=nginx")
+
+	assert.Equal(t, http.StatusOK, r.Code)
+	assert.Assert(t, strings.Contains(r.Body.String(), "event:ready"))
+}
+
+func TestSessionStatus_GroupNotFound(t *testing.T) {
+	app, router, strategy, m := NewApiTest(t)
+	SessionStatus(router, strategy)
+
+	m.EXPECT().Groups().Return(map[string][]string{"blog": {"blog"}})
+
+	r := PerformRequest(app, "GET", "/api/strategies/dynamic/status?group=unknown")
+	assert.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestSessionStatus_NamesOrGroup(t *testing.T) {
+	app, router, strategy, _ := NewApiTest(t)
+	SessionStatus(router, strategy)
+
+	r := PerformRequest(app, "GET", "/api/strategies/dynamic/status")
+	assert.Equal(t, http.StatusBadRequest, r.Code)
+
+	r = PerformRequest(app, "GET", "/api/strategies/dynamic/status?names=nginx&group=blog")
+	assert.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestStatusURL(t *testing.T) {
+	strategy := &ServeStrategy{}
+	assert.Equal(t, "", StatusURL(strategy, []string{"nginx"}, ""))
+
+	strategy.StrategyConfig.Dynamic.PublicURL = "https://sablier.example.com/base/"
+	assert.Equal(t, "https://sablier.example.com/base/api/strategies/dynamic/status?names=nginx&names=optional%3Asearch",
+		StatusURL(strategy, []string{"nginx", "optional:search"}, ""))
+	assert.Equal(t, "https://sablier.example.com/base/api/strategies/dynamic/status?group=blog",
+		StatusURL(strategy, nil, "blog"))
+
+	// With authentication, the URL carries a status token for the group.
+	tokens, err := NewStatusTokens(config.Auth{Tokens: []config.AuthToken{{Name: "traefik", Token: "secret"}}})
+	assert.NilError(t, err)
+	strategy.StatusTokens = tokens
+	u, err := url.Parse(StatusURL(strategy, nil, "blog"))
+	assert.NilError(t, err)
+	assert.Equal(t, "blog", u.Query().Get("group"))
+	assert.NilError(t, tokens.Verify(u.Query().Get("token"), nil, "blog", time.Now()))
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// ListSessions registers GET /api/sessions.
+//
+// @Summary      List sessions
+// @Description  Lists every active session with the instance it keeps running and its expiration time, sorted by instance name. Listing sessions never extends them.
+// @Tags         sessions
+// @Produce      json
+// @Success      200  {object}  SessionsResponse
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/sessions [get]
+func ListSessions(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/sessions", func(c *gin.Context) {
+		sessions, err := s.Sablier.ListSessions(c.Request.Context())
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewSessionsResponse(sessions))
+	})
+}
+
+// ExpireSession registers DELETE /api/sessions/{name}.
+//
+// @Summary      Expire a session
+// @Description  Ends the session of an instance right away and stops the instance, exactly as if the session had expired. A pin on the instance is removed.
+// @Tags         sessions
+// @Param        name  path  string  true  "Instance name"
+// @Success      204
+// @Failure      404  {object}  rfc7807.Problem  "No active session for the instance"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/sessions/{name} [delete]
+func ExpireSession(router *gin.RouterGroup, s *ServeStrategy) {
+	router.DELETE("/sessions/:name", func(c *gin.Context) {
+		err := s.Sablier.ExpireSession(c.Request.Context(), c.Param("name"))
+		if notFoundErr, ok := errors.AsType[sablier.ErrSessionNotFound](err); ok {
+			AbortWithProblemDetail(c, ProblemSessionNotFound(notFoundErr))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+type ExtendSessionRequest struct {
+	Duration time.Duration `form:"duration"`
+}
+
+// ExtendSession registers POST /api/sessions/{name}/extend.
+//
+// @Summary      Extend a session
+// @Description  Pushes the expiration of an active session back by the given duration, without a strategy request.
+// @Tags         sessions
+// @Produce      json
+// @Param        name      path   string  true  "Instance name"
+// @Param        duration  query  string  true  "Time to add to the session as a Go duration (e.g. 30m)."
+// @Success      200  {object}  SessionEntryResponse
+// @Failure      400  {object}  rfc7807.Problem  "Validation error"
+// @Failure      404  {object}  rfc7807.Problem  "No active session for the instance"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/sessions/{name}/extend [post]
+func ExtendSession(router *gin.RouterGroup, s *ServeStrategy) {
+	router.POST("/sessions/:name/extend", func(c *gin.Context) {
+		var request ExtendSessionRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			AbortWithProblemDetail(c, ProblemValidation(err))
+			return
+		}
+		if request.Duration <= 0 {
+			AbortWithProblemDetail(c, ProblemValidation(errors.New("'duration' query parameter must be a positive duration")))
+			return
+		}
+
+		session, err := s.Sablier.ExtendSession(c.Request.Context(), c.Param("name"), request.Duration)
+		if notFoundErr, ok := errors.AsType[sablier.ErrSessionNotFound](err); ok {
+			AbortWithProblemDetail(c, ProblemSessionNotFound(notFoundErr))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewSessionEntryResponse(session))
+	})
+}
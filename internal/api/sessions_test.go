@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestListSessions(t *testing.T) {
+	t.Run("ListSessions", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ListSessions(router, strategy)
+		expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		m.EXPECT().ListSessions(gomock.Any()).Return([]sablier.InstanceSession{
+			{Instance: sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady}, ExpiresAt: expiresAt},
+		}, nil)
+		r := PerformRequest(app, "GET", "/api/sessions")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body SessionsResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.DeepEqual(t, body, SessionsResponse{Sessions: []SessionEntryResponse{
+			{Instance: sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady}, ExpiresAt: expiresAt},
+		}})
+	})
+	t.Run("ListSessionsEmpty", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ListSessions(router, strategy)
+		m.EXPECT().ListSessions(gomock.Any()).Return(nil, nil)
+		r := PerformRequest(app, "GET", "/api/sessions")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, `{"sessions":[]}`, r.Body.String())
+	})
+	t.Run("ListSessionsError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ListSessions(router, strategy)
+		m.EXPECT().ListSessions(gomock.Any()).Return(nil, errors.New("store down"))
+		r := PerformRequest(app, "GET", "/api/sessions")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+}
+
+func TestExpireSession(t *testing.T) {
+	t.Run("ExpireSession", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ExpireSession(router, strategy)
+		m.EXPECT().ExpireSession(gomock.Any(), "whoami").Return(nil)
+		r := PerformRequest(app, "DELETE", "/api/sessions/whoami")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+	t.Run("ExpireSessionNotFound", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ExpireSession(router, strategy)
+		m.EXPECT().ExpireSession(gomock.Any(), "ghost").Return(sablier.ErrSessionNotFound{Name: "ghost"})
+		r := PerformRequest(app, "DELETE", "/api/sessions/ghost")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("ExpireSessionError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ExpireSession(router, strategy)
+		m.EXPECT().ExpireSession(gomock.Any(), "whoami").Return(errors.New("store down"))
+		r := PerformRequest(app, "DELETE", "/api/sessions/whoami")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+}
+
+func TestExtendSession(t *testing.T) {
+	t.Run("ExtendSession", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ExtendSession(router, strategy)
+		expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		m.EXPECT().ExtendSession(gomock.Any(), "whoami", 30*time.Minute).Return(sablier.InstanceSession{
+			Instance:  sablier.InstanceInfo{Name: "whoami"},
+			ExpiresAt: expiresAt,
+		}, nil)
+		r := PerformRequest(app, "POST", "/api/sessions/whoami/extend?duration=30m")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body SessionEntryResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.Equal(t, body.ExpiresAt, expiresAt)
+	})
+	t.Run("ExtendSessionMissingDuration", func(t *testing.T) {
+		app, router, strategy, _ := NewApiTest(t)
+		ExtendSession(router, strategy)
+		r := PerformRequest(app, "POST", "/api/sessions/whoami/extend")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("ExtendSessionInvalidDuration", func(t *testing.T) {
+		app, router, strategy, _ := NewApiTest(t)
+		ExtendSession(router, strategy)
+		r := PerformRequest(app, "POST", "/api/sessions/whoami/extend?duration=soon")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("ExtendSessionNotFound", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		ExtendSession(router, strategy)
+		m.EXPECT().ExtendSession(gomock.Any(), "ghost", time.Minute).Return(sablier.InstanceSession{}, sablier.ErrSessionNotFound{Name: "ghost"})
+		r := PerformRequest(app, "POST", "/api/sessions/ghost/extend?duration=1m")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
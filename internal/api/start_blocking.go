@@ -14,6 +14,7 @@ type BlockingRequest struct {
 	Names           []string      `form:"names"`
 	Group           string        `form:"group"`
 	SessionDuration time.Duration `form:"session_duration"`
+	SessionMode     string        `form:"session_mode"`
 	Timeout         time.Duration `form:"timeout"`
 }
 
@@ -25,7 +26,8 @@ type BlockingRequest struct {
 // @Produce      json
 // @Param        names             query  []string  false  "Instance name(s); repeat for multiple. Mutually exclusive with group. Prefix a name with 'optional:' to mark it best-effort: it is started with the session but its readiness and errors never gate it."
 // @Param        group             query  string    false  "Group name. Mutually exclusive with names."
-// @Param        session_duration  query  string    false  "Session duration as a Go duration (e.g. 5m). Defaults to the server default."
+// @Param        session_duration  query  string    false  "Session duration as a Go duration (e.g. 5m). Defaults to the sablier.session-duration label of each instance, then to the server default. Capped by the sablier.session-duration-max label and the server maximum."
+// @Param        session_mode      query  string    false  "Session mode: sliding (default) renews the session on every request, fixed sets a deadline on the first request that later requests do not extend."  Enums(sliding, fixed)
 // @Param        timeout           query  string    false  "Maximum time to wait as a Go duration (e.g. 1m)."
 // @Success      200  {object}  SessionResponse
 // @Header       200  {string}  X-Sablier-Session-Status  "ready or not-ready"
@@ -37,8 +39,7 @@ type BlockingRequest struct {
 func StartBlocking(router *gin.RouterGroup, s *ServeStrategy) {
 	router.GET("/strategies/blocking", func(c *gin.Context) {
 		request := BlockingRequest{
-			SessionDuration: s.SessionsConfig.DefaultDuration,
-			Timeout:         s.StrategyConfig.Blocking.DefaultTimeout,
+			Timeout: s.StrategyConfig.Blocking.DefaultTimeout,
 		}
 
 		if err := c.ShouldBind(&request); err != nil {
@@ -56,38 +57,14 @@ func StartBlocking(router *gin.RouterGroup, s *ServeStrategy) {
 			return
 		}
 
-		recordSessionRequest(s.Metrics, "blocking", request.Group)
-
-		var sessionState *sablier.SessionState
-		var err error
-		if len(request.Names) > 0 {
-			sessionState, err = s.Sablier.RequestReadySession(c.Request.Context(), request.Names, request.SessionDuration, request.Timeout)
-		} else {
-			sessionState, err = s.Sablier.RequestReadySessionGroup(c.Request.Context(), request.Group, request.SessionDuration, request.Timeout)
-			if groupNotFoundError, ok := errors.AsType[sablier.ErrGroupNotFound](err); ok {
-				AbortWithProblemDetail(c, ProblemGroupNotFound(groupNotFoundError))
-				return
-			}
-		}
-		if err != nil {
-			if timeoutErr, ok := errors.AsType[sablier.ErrTimeout](err); ok {
-				AbortWithProblemDetail(c, ProblemTimeout(timeoutErr))
-				return
-			}
-			if notManagedErr, ok := errors.AsType[sablier.ErrInstanceNotManaged](err); ok {
-				AbortWithProblemDetail(c, ProblemInstanceNotManaged(notManagedErr))
-				return
-			}
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				AbortWithProblemDetail(c, ProblemRequestCancelled())
-				return
-			}
-			AbortWithProblemDetail(c, ProblemError(err))
+		if !bindSessionMode(c, request.SessionMode) {
 			return
 		}
 
-		if sessionState == nil {
-			AbortWithProblemDetail(c, ProblemError(errors.New("session could not be created, please check logs for more details")))
+		recordSessionRequest(c, s.Metrics, "blocking", request.Group)
+
+		sessionState, ok := requestReadySession(c, s, request.Names, request.Group, request.SessionDuration, request.Timeout)
+		if !ok {
 			return
 		}
 
@@ -96,3 +73,41 @@ func StartBlocking(router *gin.RouterGroup, s *ServeStrategy) {
 		c.JSON(http.StatusOK, NewSessionResponse(sessionState))
 	})
 }
+
+// requestReadySession waits for the names or group session to be ready. On
+// failure it answers the matching problem and returns false.
+func requestReadySession(c *gin.Context, s *ServeStrategy, names []string, group string, duration, timeout time.Duration) (*sablier.SessionState, bool) {
+	var sessionState *sablier.SessionState
+	var err error
+	if len(names) > 0 {
+		sessionState, err = s.Sablier.RequestReadySession(c.Request.Context(), names, duration, timeout)
+	} else {
+		sessionState, err = s.Sablier.RequestReadySessionGroup(c.Request.Context(), group, duration, timeout)
+		if groupNotFoundError, ok := errors.AsType[sablier.ErrGroupNotFound](err); ok {
+			AbortWithProblemDetail(c, ProblemGroupNotFound(groupNotFoundError))
+			return nil, false
+		}
+	}
+	if err != nil {
+		if timeoutErr, ok := errors.AsType[sablier.ErrTimeout](err); ok {
+			AbortWithProblemDetail(c, ProblemTimeout(timeoutErr))
+			return nil, false
+		}
+		if notManagedErr, ok := errors.AsType[sablier.ErrInstanceNotManaged](err); ok {
+			AbortWithProblemDetail(c, ProblemInstanceNotManaged(notManagedErr))
+			return nil, false
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			AbortWithProblemDetail(c, ProblemRequestCancelled())
+			return nil, false
+		}
+		AbortWithProblemDetail(c, ProblemError(err))
+		return nil, false
+	}
+
+	if sessionState == nil {
+		AbortWithProblemDetail(c, ProblemError(errors.New("session could not be created, please check logs for more details")))
+		return nil, false
+	}
+	return sessionState, true
+}
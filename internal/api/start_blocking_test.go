@@ -43,6 +43,15 @@ func TestStartBlocking(t *testing.T) {
 		assert.Equal(t, http.StatusOK, r.Code)
 		assert.Equal(t, SablierStatusReady, r.Header().Get(SablierStatusHeader))
 	})
+	t.Run("StartBlockingWithoutSessionDuration", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		StartBlocking(router, strategy)
+		// Zero lets each instance get its sablier.session-duration label or
+		// the server default.
+		m.EXPECT().RequestReadySession(gomock.Any(), []string{"test"}, time.Duration(0), gomock.Any()).Return(&sablier.SessionState{}, nil)
+		r := PerformRequest(app, "GET", "/api/strategies/blocking?names=test")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
 	t.Run("StartBlockingByGroup", func(t *testing.T) {
 		app, router, strategy, m := NewApiTest(t)
 		StartBlocking(router, strategy)
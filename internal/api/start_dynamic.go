@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"errors"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ type DynamicRequest struct {
 	DisplayName      string        `form:"display_name"`
 	Theme            string        `form:"theme"`
 	SessionDuration  time.Duration `form:"session_duration"`
+	SessionMode      string        `form:"session_mode"`
 	RefreshFrequency time.Duration `form:"refresh_frequency"`
 }
 
@@ -32,7 +34,8 @@ type DynamicRequest struct {
 // @Produce      html
 // @Param        names              query  []string  false  "Instance name(s). Mutually exclusive with group. Prefix a name with 'optional:' to mark it best-effort: it is started with the session but its readiness and errors never gate it."
 // @Param        group              query  string    false  "Group name. Mutually exclusive with names."
-// @Param        session_duration   query  string    false  "Session duration as a Go duration (e.g. 5m)."
+// @Param        session_duration   query  string    false  "Session duration as a Go duration (e.g. 5m). Defaults to the sablier.session-duration label of each instance, then to the server default. Capped by the sablier.session-duration-max label and the server maximum."
+// @Param        session_mode       query  string    false  "Session mode: sliding (default) renews the session on every request, fixed sets a deadline on the first request that later requests do not extend."  Enums(sliding, fixed)
 // @Param        refresh_frequency  query  string    false  "Waiting-page refresh interval as a Go duration (e.g. 5s)."
 // @Param        show_details       query  bool      false  "Show per-instance details on the waiting page."
 // @Param        display_name       query  string    false  "Display name shown on the waiting page."
@@ -48,7 +51,6 @@ func StartDynamic(router *gin.RouterGroup, s *ServeStrategy) {
 			Theme:            s.StrategyConfig.Dynamic.DefaultTheme,
 			ShowDetails:      s.StrategyConfig.Dynamic.ShowDetailsByDefault,
 			RefreshFrequency: s.StrategyConfig.Dynamic.DefaultRefreshFrequency,
-			SessionDuration:  s.SessionsConfig.DefaultDuration,
 		}
 
 		if err := c.ShouldBind(&request); err != nil {
@@ -78,31 +80,14 @@ func StartDynamic(router *gin.RouterGroup, s *ServeStrategy) {
 			return
 		}
 
-		recordSessionRequest(s.Metrics, "dynamic", request.Group)
-
-		var sessionState *sablier.SessionState
-		var err error
-		// c.Request.Context(), never the *gin.Context itself: without gin's
-		// ContextWithFallback, the gin context has a nil Done() channel and its
-		// Value() does not reach the request context, which silently breaks
-		// cancellation and otel trace propagation for everything downstream.
-		if len(request.Names) > 0 {
-			sessionState, err = s.Sablier.RequestSession(c.Request.Context(), request.Names, request.SessionDuration)
-		} else {
-			sessionState, err = s.Sablier.RequestSessionGroup(c.Request.Context(), request.Group, request.SessionDuration)
-			if groupNotFoundError, ok := errors.AsType[sablier.ErrGroupNotFound](err); ok {
-				AbortWithProblemDetail(c, ProblemGroupNotFound(groupNotFoundError))
-				return
-			}
-		}
-
-		if err != nil {
-			AbortWithProblemDetail(c, ProblemError(err))
+		if !bindSessionMode(c, request.SessionMode) {
 			return
 		}
 
-		if sessionState == nil {
-			AbortWithProblemDetail(c, ProblemError(errors.New("session could not be created, please check logs for more details")))
+		recordSessionRequest(c, s.Metrics, "dynamic", request.Group)
+
+		sessionState, ok := requestSession(c, s, request.Names, request.Group, request.SessionDuration)
+		if !ok {
 			return
 		}
 
@@ -111,37 +96,86 @@ func StartDynamic(router *gin.RouterGroup, s *ServeStrategy) {
 		renderOptions := theme.Options{
 			DisplayName:      request.DisplayName,
 			ShowDetails:      request.ShowDetails,
-			SessionDuration:  request.SessionDuration,
+			SessionDuration:  ThemeSessionDuration(s, sessionState),
 			RefreshFrequency: request.RefreshFrequency,
-			InstanceStates:   sessionStateToRenderOptionsInstanceState(sessionState),
+			InstanceStates:   ThemeInstances(sessionState),
+			StatusURL:        StatusURL(s, request.Names, request.Group),
 		}
 
-		// Render into a plain buffer: rendering must fully succeed before any
-		// byte reaches the client, so a template that fails halfway (easy to
-		// author in a custom theme) surfaces as a 500 problem instead of a 200
-		// with a truncated page.
-		buf := new(bytes.Buffer)
-		err = s.Theme.Render(request.Theme, renderOptions, buf)
-		if themeNotFound, ok := errors.AsType[theme.ErrThemeNotFound](err); ok {
-			AbortWithProblemDetail(c, ProblemThemeNotFound(themeNotFound))
-			return
-		}
-		if err != nil {
-			AbortWithProblemDetail(c, ProblemError(err))
-			return
-		}
+		writeThemePage(c, s, request.Theme, renderOptions, http.StatusOK)
+	})
+}
 
-		c.Header("Cache-Control", "no-cache")
-		c.Header("Content-Type", "text/html")
-		c.Header("Content-Length", strconv.Itoa(buf.Len()))
-		if _, err := c.Writer.Write(buf.Bytes()); err != nil {
-			AbortWithProblemDetail(c, ProblemError(err))
-			return
+// requestSession requests the names or group session without waiting for it
+// to be ready. On failure it answers the matching problem and returns false.
+func requestSession(c *gin.Context, s *ServeStrategy, names []string, group string, duration time.Duration) (*sablier.SessionState, bool) {
+	var sessionState *sablier.SessionState
+	var err error
+	// c.Request.Context(), never the *gin.Context itself: without gin's
+	// ContextWithFallback, the gin context has a nil Done() channel and its
+	// Value() does not reach the request context, which silently breaks
+	// cancellation and otel trace propagation for everything downstream.
+	if len(names) > 0 {
+		sessionState, err = s.Sablier.RequestSession(c.Request.Context(), names, duration)
+	} else {
+		sessionState, err = s.Sablier.RequestSessionGroup(c.Request.Context(), group, duration)
+		if groupNotFoundError, ok := errors.AsType[sablier.ErrGroupNotFound](err); ok {
+			AbortWithProblemDetail(c, ProblemGroupNotFound(groupNotFoundError))
+			return nil, false
 		}
-	})
+	}
+
+	if err != nil {
+		AbortWithProblemDetail(c, ProblemError(err))
+		return nil, false
+	}
+
+	if sessionState == nil {
+		AbortWithProblemDetail(c, ProblemError(errors.New("session could not be created, please check logs for more details")))
+		return nil, false
+	}
+	return sessionState, true
+}
+
+// writeThemePage renders the waiting page with the given status code.
+func writeThemePage(c *gin.Context, s *ServeStrategy, name string, options theme.Options, status int) {
+	// Render into a plain buffer: rendering must fully succeed before any
+	// byte reaches the client, so a template that fails halfway (easy to
+	// author in a custom theme) surfaces as a 500 problem instead of a 200
+	// with a truncated page.
+	buf := new(bytes.Buffer)
+	err := s.Theme.Render(name, options, buf)
+	if themeNotFound, ok := errors.AsType[theme.ErrThemeNotFound](err); ok {
+		AbortWithProblemDetail(c, ProblemThemeNotFound(themeNotFound))
+		return
+	}
+	if err != nil {
+		AbortWithProblemDetail(c, ProblemError(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Content-Type", "text/html")
+	c.Header("Content-Length", strconv.Itoa(buf.Len()))
+	c.Status(status)
+	if _, err := c.Writer.Write(buf.Bytes()); err != nil {
+		AbortWithProblemDetail(c, ProblemError(err))
+		return
+	}
+}
+
+// ThemeSessionDuration returns the session duration shown on the waiting
+// page: the shortest duration given to the instances of the session, or
+// sessions.default-duration when none was given one.
+func ThemeSessionDuration(s *ServeStrategy, sessionState *sablier.SessionState) time.Duration {
+	if d := sessionState.SessionDuration(); d > 0 {
+		return d
+	}
+	return s.SessionsConfig.DefaultDuration
 }
 
-func sessionStateToRenderOptionsInstanceState(sessionState *sablier.SessionState) (instances []theme.Instance) {
+// ThemeInstances converts a session into the waiting page instances, sorted by name.
+func ThemeInstances(sessionState *sablier.SessionState) (instances []theme.Instance) {
 	if sessionState == nil {
 		return
 	}
@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
 	"testing/fstest"
 
@@ -63,6 +64,17 @@ func TestStartDynamic(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, r.Code)
 		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
 	})
+	t.Run("StartDynamicLiveUpdates", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		strategy.StrategyConfig.Dynamic.PublicURL = "https://sablier.example.com"
+		StartDynamic(router, strategy)
+		notReady := session()
+		notReady.Instances["test"] = sablier.InstanceInfoWithError{Instance: sablier.InstanceInfo{Name: "test", Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}}
+		m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, gomock.Any()).Return(notReady, nil)
+		r := PerformRequest(app, "GET", "/api/strategies/dynamic?names=test")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Assert(t, strings.Contains(r.Body.String(), `new EventSource("https://sablier.example.com/api/strategies/dynamic/status?names=test")`), r.Body.String())
+	})
 	t.Run("StartDynamicRenderError", func(t *testing.T) {
 		app, router, strategy, m := NewApiTest(t)
 		// A theme that parses fine but fails at execution time (field access on
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/theme"
+)
+
+// StartForwardAuth registers the forward-auth strategy endpoint.
+//
+// @Summary      Forward-auth strategy
+// @Description  Forward-auth (Traefik, Caddy) and auth_request (nginx) compatible strategy. The target is read from `X-Forwarded-Host` and `X-Forwarded-Uri`, or from `X-Original-URL`, and mapped to instance names or a group through `routing.routes`. Answers 200 with no body when the session is ready. Otherwise a `dynamic` route answers 503 with the themed waiting page, and a `blocking` route holds the request until the session is ready. Every HTTP method is accepted.
+// @Tags         strategies
+// @Produce      html
+// @Param        X-Forwarded-Host  header  string  false  "Original request host. Falls back to the host of X-Original-URL, then to Host."
+// @Param        X-Forwarded-Uri   header  string  false  "Original request URI (path and query)."
+// @Param        X-Original-URL    header  string  false  "Original request URL, used when X-Forwarded-Host is not set."
+// @Success      200  "Session is ready"
+// @Header       200  {string}  X-Sablier-Session-Status  "ready"
+// @Failure      400  {object}  rfc7807.Problem  "The original request host is missing"
+// @Failure      404  {object}  rfc7807.Problem  "No route matches, or group, instance or theme not found"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Failure      503  {string}  string           "HTML waiting page (dynamic routes)"
+// @Failure      504  {object}  rfc7807.Problem  "Session was not ready before the timeout (blocking routes)"
+// @Router       /api/strategies/forward-auth [get]
+func StartForwardAuth(router *gin.RouterGroup, s *ServeStrategy) {
+	router.Any("/strategies/forward-auth", func(c *gin.Context) {
+		host, path, err := forwardedTarget(c.Request)
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemValidation(err))
+			return
+		}
+
+		route, ok := s.Routing.Match(host, path)
+		if !ok {
+			AbortWithProblemDetail(c, ProblemRouteNotFound(host, path))
+			return
+		}
+
+		// Without a route duration, each instance gets its
+		// sablier.session-duration label or the server default.
+		duration := route.SessionDuration
+
+		if route.Strategy == config.RouteStrategyBlocking {
+			timeout := s.StrategyConfig.Blocking.DefaultTimeout
+			if route.Timeout > 0 {
+				timeout = route.Timeout
+			}
+
+			recordSessionRequest(c, s.Metrics, "forward-auth", route.Group)
+			sessionState, ok := requestReadySession(c, s, route.Names, route.Group, duration, timeout)
+			if !ok {
+				return
+			}
+			AddSablierHeader(c, sessionState)
+			c.Status(http.StatusOK)
+			return
+		}
+
+		themeName := s.StrategyConfig.Dynamic.DefaultTheme
+		if route.Theme != "" {
+			themeName = route.Theme
+		}
+		// Same as the dynamic strategy: never start instances for a request
+		// that can only ever answer 404.
+		if !s.Theme.Exists(themeName) {
+			AbortWithProblemDetail(c, ProblemThemeNotFound(theme.ErrThemeNotFound{
+				Theme:           themeName,
+				AvailableThemes: s.Theme.List(),
+			}))
+			return
+		}
+
+		recordSessionRequest(c, s.Metrics, "forward-auth", route.Group)
+		sessionState, ok := requestSession(c, s, route.Names, route.Group, duration)
+		if !ok {
+			return
+		}
+		AddSablierHeader(c, sessionState)
+		if sessionState.IsReady() {
+			c.Status(http.StatusOK)
+			return
+		}
+
+		displayName := route.DisplayName
+		if displayName == "" {
+			displayName = host
+		}
+		// Any non-2xx answer is relayed to the client by the proxy: the page
+		// refreshes the original URL until the session is ready.
+		c.Header("Retry-After", strconv.Itoa(int(s.StrategyConfig.Dynamic.DefaultRefreshFrequency.Seconds())))
+		writeThemePage(c, s, themeName, theme.Options{
+			DisplayName:      displayName,
+			ShowDetails:      s.StrategyConfig.Dynamic.ShowDetailsByDefault,
+			SessionDuration:  ThemeSessionDuration(s, sessionState),
+			RefreshFrequency: s.StrategyConfig.Dynamic.DefaultRefreshFrequency,
+			InstanceStates:   ThemeInstances(sessionState),
+			StatusURL:        StatusURL(s, route.Names, route.Group),
+		}, http.StatusServiceUnavailable)
+	})
+}
+
+// forwardedTarget returns the host and path of the request the proxy is
+// authorizing, from X-Forwarded-Host and X-Forwarded-Uri (Traefik, Caddy) or
+// X-Original-URL (nginx).
+func forwardedTarget(r *http.Request) (string, string, error) {
+	host := r.Header.Get("X-Forwarded-Host")
+	uri := r.Header.Get("X-Forwarded-Uri")
+
+	if original := r.Header.Get("X-Original-URL"); original != "" && (host == "" || uri == "") {
+		u, err := url.Parse(original)
+		if err != nil {
+			return "", "", errors.New("X-Original-URL is not a valid URL")
+		}
+		if host == "" {
+			host = u.Host
+		}
+		if uri == "" {
+			uri = u.RequestURI()
+		}
+	}
+	if host == "" {
+		host = r.Host
+	}
+
+	// A chain of proxies appends to the header: the first value is the client's.
+	host, _, _ = strings.Cut(host, ",")
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return "", "", errors.New("the original host is unknown: set the X-Forwarded-Host or X-Original-URL header")
+	}
+
+	path := "/"
+	if uri != "" {
+		u, err := url.ParseRequestURI(uri)
+		if err != nil {
+			return "", "", errors.New("X-Forwarded-Uri is not a valid request URI")
+		}
+		path = u.Path
+	}
+	return host, path, nil
+}
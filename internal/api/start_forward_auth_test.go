@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/internal/api/apitest"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func forwardAuthRouting() config.Routing {
+	return config.Routing{Routes: []config.Route{
+		{Host: "whoami.example.com", Names: []string{"whoami"}, SessionDuration: time.Hour},
+		{Host: "blog.example.com", Group: "blog", Strategy: config.RouteStrategyBlocking, Timeout: 10 * time.Second},
+		{Host: "themed.example.com", Group: "themed", Theme: "does-not-exist"},
+	}}
+}
+
+func performForwardAuth(t *testing.T, setup func(m *apitest.MockSablier), headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	app, router, strategy, m := NewApiTest(t)
+	strategy.Routing = forwardAuthRouting()
+	StartForwardAuth(router, strategy)
+	if setup != nil {
+		setup(m)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/strategies/forward-auth", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func notReadySession() *sablier.SessionState {
+	return &sablier.SessionState{Instances: map[string]sablier.InstanceInfoWithError{
+		"whoami": {Instance: sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}},
+	}}
+}
+
+func TestStartForwardAuth(t *testing.T) {
+	t.Run("ReadyAnswers200", func(t *testing.T) {
+		r := performForwardAuth(t, func(m *apitest.MockSablier) {
+			m.EXPECT().RequestSession(gomock.Any(), []string{"whoami"}, time.Hour).Return(session(), nil)
+		}, map[string]string{"X-Forwarded-Host": "whoami.example.com:443", "X-Forwarded-Uri": "/index.html?q=1"})
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, SablierStatusReady, r.Header().Get(SablierStatusHeader))
+		assert.Equal(t, 0, r.Body.Len())
+	})
+	t.Run("NotReadyAnswersWaitingPage", func(t *testing.T) {
+		r := performForwardAuth(t, func(m *apitest.MockSablier) {
+			m.EXPECT().RequestSession(gomock.Any(), []string{"whoami"}, time.Hour).Return(notReadySession(), nil)
+		}, map[string]string{"X-Original-URL": "https://whoami.example.com/"})
+		assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+		assert.Equal(t, SablierStatusNotReady, r.Header().Get(SablierStatusHeader))
+		assert.Equal(t, "text/html", r.Header().Get("Content-Type"))
+		assert.Equal(t, "5", r.Header().Get("Retry-After"))
+		assert.Assert(t, r.Body.Len() > 0)
+	})
+	t.Run("BlockingRoute", func(t *testing.T) {
+		r := performForwardAuth(t, func(m *apitest.MockSablier) {
+			m.EXPECT().RequestReadySessionGroup(gomock.Any(), "blog", gomock.Any(), 10*time.Second).Return(session(), nil)
+		}, map[string]string{"X-Forwarded-Host": "blog.example.com"})
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("BlockingRouteTimeout", func(t *testing.T) {
+		r := performForwardAuth(t, func(m *apitest.MockSablier) {
+			m.EXPECT().RequestReadySessionGroup(gomock.Any(), "blog", gomock.Any(), gomock.Any()).Return(nil, sablier.ErrTimeout{Duration: 10 * time.Second})
+		}, map[string]string{"X-Forwarded-Host": "blog.example.com"})
+		assert.Equal(t, http.StatusGatewayTimeout, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("NoRoute", func(t *testing.T) {
+		r := performForwardAuth(t, nil, map[string]string{"X-Forwarded-Host": "unknown.example.com"})
+		assert.Equal(t, http.StatusNotFound, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("ThemeNotFoundStartsNothing", func(t *testing.T) {
+		r := performForwardAuth(t, nil, map[string]string{"X-Forwarded-Host": "themed.example.com"})
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("InvalidForwardedUri", func(t *testing.T) {
+		r := performForwardAuth(t, nil, map[string]string{"X-Forwarded-Host": "whoami.example.com", "X-Forwarded-Uri": "not a uri"})
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+}
+
+func TestForwardedTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		host     string
+		wantHost string
+		wantPath string
+	}{
+		{name: "forwarded headers", headers: map[string]string{"X-Forwarded-Host": "app.example.com", "X-Forwarded-Uri": "/a/b?c=d"}, wantHost: "app.example.com", wantPath: "/a/b"},
+		{name: "forwarded host list", headers: map[string]string{"X-Forwarded-Host": "app.example.com:8443, proxy.internal"}, wantHost: "app.example.com", wantPath: "/"},
+		{name: "original url", headers: map[string]string{"X-Original-URL": "https://app.example.com:8443/x?y=z"}, wantHost: "app.example.com", wantPath: "/x"},
+		{name: "relative original url", headers: map[string]string{"X-Original-URL": "/x"}, host: "app.example.com", wantHost: "app.example.com", wantPath: "/x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/strategies/forward-auth", nil)
+			req.Host = tt.host
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			host, path, err := forwardedTarget(req)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantHost, host)
+			assert.Equal(t, tt.wantPath, path)
+		})
+	}
+}
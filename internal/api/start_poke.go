@@ -13,6 +13,7 @@ type PokeRequest struct {
 	Names           []string      `form:"names"`
 	Group           string        `form:"group"`
 	SessionDuration time.Duration `form:"session_duration"`
+	SessionMode     string        `form:"session_mode"`
 }
 
 // StartPoke registers the poke strategy endpoint.
@@ -23,7 +24,8 @@ type PokeRequest struct {
 // @Produce      json
 // @Param        names             query  []string  false  "Instance name(s); repeat for multiple. Mutually exclusive with group."
 // @Param        group             query  string    false  "Group name. Mutually exclusive with names."
-// @Param        session_duration  query  string    false  "Session duration as a Go duration (e.g. 5m). Defaults to the server default."
+// @Param        session_duration  query  string    false  "Session duration as a Go duration (e.g. 5m). Defaults to the sablier.session-duration label of each instance, then to the server default. Capped by the sablier.session-duration-max label and the server maximum."
+// @Param        session_mode      query  string    false  "Session mode: sliding (default) renews the session on every request, fixed sets a deadline on the first request that later requests do not extend."  Enums(sliding, fixed)
 // @Success      200  {object}  SessionResponse
 // @Header       200  {string}  X-Sablier-Session-Status  "ready or not-ready"
 // @Failure      400  {object}  rfc7807.Problem  "Validation error"
@@ -32,9 +34,7 @@ type PokeRequest struct {
 // @Router       /api/strategies/poke [get]
 func StartPoke(router *gin.RouterGroup, s *ServeStrategy) {
 	router.GET("/strategies/poke", func(c *gin.Context) {
-		request := PokeRequest{
-			SessionDuration: s.SessionsConfig.DefaultDuration,
-		}
+		var request PokeRequest
 
 		if err := c.ShouldBind(&request); err != nil {
 			AbortWithProblemDetail(c, ProblemValidation(err))
@@ -51,7 +51,11 @@ func StartPoke(router *gin.RouterGroup, s *ServeStrategy) {
 			return
 		}
 
-		recordSessionRequest(s.Metrics, "poke", request.Group)
+		if !bindSessionMode(c, request.SessionMode) {
+			return
+		}
+
+		recordSessionRequest(c, s.Metrics, "poke", request.Group)
 
 		var sessionState *sablier.SessionState
 		var err error
@@ -19,6 +19,20 @@ func TestStartPoke(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
 	})
+	t.Run("StartPokeInvalidSessionMode", func(t *testing.T) {
+		app, router, strategy, _ := NewApiTest(t)
+		StartPoke(router, strategy)
+		r := PerformRequest(app, "GET", "/api/strategies/poke?names=test&session_mode=forever")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("StartPokeFixedSessionMode", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		StartPoke(router, strategy)
+		m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, gomock.Any()).Return(&sablier.SessionState{}, nil)
+		r := PerformRequest(app, "GET", "/api/strategies/poke?names=test&session_mode=fixed")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
 	t.Run("StartPokeWithoutNamesOrGroup", func(t *testing.T) {
 		app, router, strategy, _ := NewApiTest(t)
 		StartPoke(router, strategy)
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+)
+
+// statusTokenLifetime is how long a status token is accepted. The waiting
+// page refreshes well within it, and every refresh renders a new token.
+const statusTokenLifetime = time.Hour
+
+// StatusTokens signs the tokens that grant a waiting page access to the
+// session status stream of its names or group. Browsers cannot send an
+// Authorization header with EventSource, so the token travels in the URL of
+// the stream instead, and grants nothing else.
+type StatusTokens struct {
+	key []byte
+}
+
+// NewStatusTokens returns the signer of the status tokens when the API
+// requires authentication, nil otherwise. The key is derived from the bearer
+// tokens, so every replica sharing the configuration accepts the tokens of
+// the others. Without bearer tokens, the key is random: a token is only
+// accepted by the replica that rendered the page.
+func NewStatusTokens(conf config.Auth) (*StatusTokens, error) {
+	if !conf.Enabled() {
+		return nil, nil
+	}
+	if len(conf.Tokens) == 0 {
+		key := make([]byte, sha256.Size)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		return &StatusTokens{key: key}, nil
+	}
+	secrets := make([]string, 0, len(conf.Tokens))
+	for _, t := range conf.Tokens {
+		secrets = append(secrets, t.Token)
+	}
+	slices.Sort(secrets)
+	mac := hmac.New(sha256.New, []byte("sablier session status token"))
+	for _, secret := range secrets {
+		mac.Write([]byte(secret))
+		mac.Write([]byte{0})
+	}
+	return &StatusTokens{key: mac.Sum(nil)}, nil
+}
+
+// Sign returns a token granting the status stream of names or group until
+// now plus statusTokenLifetime.
+func (t *StatusTokens) Sign(names []string, group string, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(statusTokenLifetime).Unix(), 10)
+	return expires + "." + base64.RawURLEncoding.EncodeToString(t.sign(names, group, expires))
+}
+
+// Verify checks that token was signed for exactly names or group and has not
+// expired at now.
+func (t *StatusTokens) Verify(token string, names []string, group string, now time.Time) error {
+	expires, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return errors.New("malformed status token")
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errors.New("malformed status token")
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, t.sign(names, group, expires)) {
+		return errors.New("invalid status token")
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return errors.New("expired status token")
+	}
+	return nil
+}
+
+func (t *StatusTokens) sign(names []string, group, expires string) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(expires))
+	mac.Write([]byte{0})
+	if group != "" {
+		mac.Write([]byte("group:" + group))
+	} else {
+		mac.Write([]byte("names:" + strings.Join(names, "\x00")))
+	}
+	return mac.Sum(nil)
+}
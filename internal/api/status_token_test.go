@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestStatusTokens(t *testing.T) {
+	auth := config.Auth{Tokens: []config.AuthToken{{Name: "traefik", Token: "secret"}}}
+	tokens, err := NewStatusTokens(auth)
+	assert.NilError(t, err)
+	now := time.Now()
+	token := tokens.Sign([]string{"nginx", "optional:search"}, "", now)
+
+	assert.NilError(t, tokens.Verify(token, []string{"nginx", "optional:search"}, "", now))
+	assert.ErrorContains(t, tokens.Verify(token, []string{"nginx"}, "", now), "invalid status token")
+	assert.ErrorContains(t, tokens.Verify(token, nil, "nginx", now), "invalid status token")
+	assert.ErrorContains(t, tokens.Verify(token, []string{"nginx", "optional:search"}, "", now.Add(2*statusTokenLifetime)), "expired status token")
+	assert.ErrorContains(t, tokens.Verify("9999999999"+token[10:], []string{"nginx", "optional:search"}, "", now), "invalid status token")
+	assert.ErrorContains(t, tokens.Verify("nope", []string{"nginx"}, "", now), "malformed status token")
+
+	// Replicas sharing the bearer tokens accept the status tokens of each other.
+	replica, err := NewStatusTokens(auth)
+	assert.NilError(t, err)
+	assert.NilError(t, replica.Verify(token, []string{"nginx", "optional:search"}, "", now))
+
+	other, err := NewStatusTokens(config.Auth{Tokens: []config.AuthToken{{Name: "traefik", Token: "other"}}})
+	assert.NilError(t, err)
+	assert.ErrorContains(t, other.Verify(token, []string{"nginx", "optional:search"}, "", now), "invalid status token")
+
+	disabled, err := NewStatusTokens(config.Auth{})
+	assert.NilError(t, err)
+	assert.Assert(t, disabled == nil)
+}
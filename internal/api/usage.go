@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+type UsageRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// Usage registers GET /api/usage.
+//
+// @Summary      Usage report
+// @Description  Reports, for every instance with a recorded session, the sessions requested by strategy, the cold starts, the stops by reason, the uptime and the idle savings over a period. Built on the session history, which requires the database storage (`storage.database.path`).
+// @Tags         sessions
+// @Produce      json
+// @Param        from  query  string  false  "Start of the period, RFC 3339. Defaults to 24 hours before to."
+// @Param        to    query  string  false  "End of the period, RFC 3339. Defaults to now."
+// @Success      200  {object}  UsageResponse
+// @Failure      400  {object}  rfc7807.Problem  "Validation error"
+// @Failure      404  {object}  rfc7807.Problem  "Session history disabled"
+// @Failure      500  {object}  rfc7807.Problem  "Internal error"
+// @Router       /api/usage [get]
+func Usage(router *gin.RouterGroup, s *ServeStrategy) {
+	router.GET("/usage", func(c *gin.Context) {
+		var request UsageRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			AbortWithProblemDetail(c, ProblemValidation(err))
+			return
+		}
+		if request.To.IsZero() {
+			request.To = time.Now()
+		}
+		if request.From.IsZero() {
+			request.From = request.To.Add(-24 * time.Hour)
+		}
+		if !request.From.Before(request.To) {
+			AbortWithProblemDetail(c, ProblemValidation(errors.New("'from' must be before 'to'")))
+			return
+		}
+
+		usages, err := s.Sablier.Usage(c.Request.Context(), request.From, request.To)
+		if disabledErr, ok := errors.AsType[sablier.ErrHistoryDisabled](err); ok {
+			AbortWithProblemDetail(c, ProblemHistoryDisabled(disabledErr))
+			return
+		}
+		if err != nil {
+			AbortWithProblemDetail(c, ProblemError(err))
+			return
+		}
+		c.JSON(http.StatusOK, NewUsageResponse(request.From, request.To, usages))
+	})
+}
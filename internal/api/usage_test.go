@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestUsage(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("Usage", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		Usage(router, strategy)
+		m.EXPECT().Usage(gomock.Any(), from, to).Return([]sablier.InstanceUsage{{
+			Name:          "whoami",
+			Sessions:      map[string]int{"dynamic": 2},
+			ColdStarts:    1,
+			ColdStartTime: 1500 * time.Millisecond,
+			Stops:         map[string]int{sablier.StopReasonExpired: 2},
+			Uptime:        time.Hour,
+			IdleSavings:   23 * time.Hour,
+		}}, nil)
+		r := PerformRequest(app, "GET", "/api/usage?from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z")
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		var body UsageResponse
+		assert.NilError(t, json.Unmarshal(r.Body.Bytes(), &body))
+		assert.DeepEqual(t, body, UsageResponse{From: from, To: to, Instances: []InstanceUsageResponse{{
+			Name:                 "whoami",
+			Sessions:             map[string]int{"dynamic": 2},
+			ColdStarts:           1,
+			ColdStartTimeSeconds: 1.5,
+			Stops:                map[string]int{"expired": 2},
+			UptimeSeconds:        3600,
+			IdleSavingsSeconds:   82800,
+		}}})
+	})
+	t.Run("UsageDefaultsToTheLastDay", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		Usage(router, strategy)
+		m.EXPECT().Usage(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, from, to time.Time) ([]sablier.InstanceUsage, error) {
+			assert.Equal(t, to.Sub(from), 24*time.Hour)
+			assert.Assert(t, time.Since(to) < time.Minute)
+			return nil, nil
+		})
+		r := PerformRequest(app, "GET", "/api/usage")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("UsageInvalidPeriod", func(t *testing.T) {
+		app, router, strategy, _ := NewApiTest(t)
+		Usage(router, strategy)
+		r := PerformRequest(app, "GET", "/api/usage?from=2026-01-02T00:00:00Z&to=2026-01-01T00:00:00Z")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("UsageHistoryDisabled", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		Usage(router, strategy)
+		m.EXPECT().Usage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sablier.ErrHistoryDisabled{})
+		r := PerformRequest(app, "GET", "/api/usage")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+		assert.Equal(t, rfc7807.JSONMediaType, r.Header().Get("Content-Type"))
+	})
+	t.Run("UsageError", func(t *testing.T) {
+		app, router, strategy, m := NewApiTest(t)
+		Usage(router, strategy)
+		m.EXPECT().Usage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("database closed"))
+		r := PerformRequest(app, "GET", "/api/usage")
+		assert.Equal(t, http.StatusInternalServerError, r.Code)
+	})
+}
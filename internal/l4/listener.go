@@ -0,0 +1,326 @@
+// Package l4 implements the TCP and UDP listeners that wake non-HTTP
+// workloads when their own clients connect: the client is held until the
+// instances are ready, then its traffic is forwarded to the backend.
+package l4
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/metrics"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// Sablier is the subset of the Sablier API the listeners need.
+type Sablier interface {
+	RequestSession(ctx context.Context, names []string, duration time.Duration) (*sablier.SessionState, error)
+	RequestSessionGroup(ctx context.Context, group string, duration time.Duration) (*sablier.SessionState, error)
+	RequestReadySession(ctx context.Context, names []string, duration time.Duration, timeout time.Duration) (*sablier.SessionState, error)
+	RequestReadySessionGroup(ctx context.Context, group string, duration time.Duration, timeout time.Duration) (*sablier.SessionState, error)
+}
+
+// Connection results, reported by the sablier_listener_connections_total metric.
+const (
+	resultForwarded    = "forwarded"
+	resultTimeout      = "timeout"
+	resultUnavailable  = "unavailable"
+	resultBackendError = "backend_error"
+	resultCanceled     = "canceled"
+)
+
+// Drop reasons, reported by the sablier_listener_dropped_total metric.
+const (
+	dropMaxConnections = "max_connections"
+	dropQueueFull      = "queue_full"
+)
+
+const (
+	// dialTimeout bounds the connection to the backend once the instances
+	// report ready.
+	dialTimeout = 10 * time.Second
+	// defaultIdleTimeout is the default lifetime of a UDP flow without traffic.
+	defaultIdleTimeout = time.Minute
+	// renewCheckInterval is how often a listener without a session duration
+	// checks for traffic, until Sablier told it the duration of the session.
+	renewCheckInterval = time.Second
+	// defaultMaxConnections is the default number of connections (UDP flows)
+	// a listener holds or forwards at once.
+	defaultMaxConnections = 1024
+)
+
+// Listener is a bound TCP or UDP listener.
+type Listener struct {
+	conf     config.Listener
+	name     string
+	duration time.Duration
+	timeout  time.Duration
+
+	s   Sablier
+	rec metrics.Recorder
+	l   *slog.Logger
+
+	tcp net.Listener
+	udp net.PacketConn
+
+	// active is set when bytes flow through the listener, and cleared when the
+	// session is renewed.
+	active atomic.Bool
+	// renewEvery is half the shortest session duration Sablier gave the
+	// instances, how often the session is renewed while traffic flows.
+	renewEvery atomic.Int64
+
+	// slots holds a token for each open connection (UDP flow), up to
+	// max-connections.
+	slots chan struct{}
+	// saturated is set while the listener drops connections, so the limit is
+	// logged once each time it is reached.
+	saturated atomic.Bool
+}
+
+// Listen binds every configured listener. sessionDuration and timeout are the
+// defaults for the listeners that do not override them. A zero session
+// duration lets Sablier resolve it for each instance, from its session
+// duration label or the server default. On error, the listeners already bound
+// are closed.
+func Listen(logger *slog.Logger, conf config.L4, s Sablier, rec metrics.Recorder, sessionDuration, timeout time.Duration) ([]*Listener, error) {
+	listeners := make([]*Listener, 0, len(conf.Listeners))
+	for _, lc := range conf.Listeners {
+		l, err := listen(logger, lc, s, rec, sessionDuration, timeout)
+		if err != nil {
+			for _, bound := range listeners {
+				_ = bound.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+func listen(logger *slog.Logger, conf config.Listener, s Sablier, rec metrics.Recorder, sessionDuration, timeout time.Duration) (*Listener, error) {
+	l := &Listener{
+		conf:     conf,
+		name:     conf.DisplayName(),
+		duration: sessionDuration,
+		timeout:  timeout,
+		s:        s,
+		rec:      rec,
+	}
+	if conf.SessionDuration > 0 {
+		l.duration = conf.SessionDuration
+	}
+	l.renewEvery.Store(int64(l.duration / 2))
+	if conf.Timeout > 0 {
+		l.timeout = conf.Timeout
+	}
+	maxConnections := conf.MaxConnections
+	if maxConnections <= 0 {
+		maxConnections = defaultMaxConnections
+	}
+	l.slots = make(chan struct{}, maxConnections)
+	l.l = logger.With(slog.String("listener", l.name), slog.String("protocol", conf.Network()))
+
+	var err error
+	switch conf.Network() {
+	case config.ListenerProtocolUDP:
+		l.udp, err = net.ListenPacket("udp", conf.Listen)
+	default:
+		l.tcp, err = net.Listen("tcp", conf.Listen)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listener %s: %w", l.name, err)
+	}
+	return l, nil
+}
+
+// Name returns the name of the listener.
+func (l *Listener) Name() string {
+	return l.name
+}
+
+// Addr returns the address the listener is bound to.
+func (l *Listener) Addr() net.Addr {
+	if l.udp != nil {
+		return l.udp.LocalAddr()
+	}
+	return l.tcp.Addr()
+}
+
+// Close stops accepting connections. Connections already accepted are closed
+// when the context given to Serve is done.
+func (l *Listener) Close() error {
+	if l.udp != nil {
+		return l.udp.Close()
+	}
+	return l.tcp.Close()
+}
+
+// Serve accepts connections until ctx is done, then closes the listener and
+// every connection it accepted. It returns nil after ctx is done, or the
+// error that stopped the listener.
+func (l *Listener) Serve(ctx context.Context) error {
+	l.l.InfoContext(ctx, "listener: waking instances on connect",
+		slog.String("listen", l.Addr().String()),
+		slog.String("backend", l.conf.Backend),
+	)
+	stop := context.AfterFunc(ctx, func() { _ = l.Close() })
+	defer stop()
+	go l.renew(ctx)
+
+	var err error
+	if l.udp != nil {
+		err = l.serveUDP(ctx)
+	} else {
+		err = l.serveTCP(ctx)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (l *Listener) requestSession(ctx context.Context) (*sablier.SessionState, error) {
+	var (
+		session *sablier.SessionState
+		err     error
+	)
+	if l.conf.Group != "" {
+		session, err = l.s.RequestSessionGroup(ctx, l.conf.Group, l.duration)
+	} else {
+		session, err = l.s.RequestSession(ctx, l.conf.Names, l.duration)
+	}
+	l.observe(session)
+	return session, err
+}
+
+func (l *Listener) requestReadySession(ctx context.Context) (*sablier.SessionState, error) {
+	target := "names"
+	if l.conf.Group != "" {
+		target = "group"
+	}
+	l.rec.RecordSessionRequest("l4", target)
+
+	var (
+		session *sablier.SessionState
+		err     error
+	)
+	if l.conf.Group != "" {
+		session, err = l.s.RequestReadySessionGroup(ctx, l.conf.Group, l.duration, l.timeout)
+	} else {
+		session, err = l.s.RequestReadySession(ctx, l.conf.Names, l.duration, l.timeout)
+	}
+	l.observe(session)
+	return session, err
+}
+
+// observe renews the session at half the shortest duration Sablier gave its
+// instances, after their labels and the caps were applied.
+func (l *Listener) observe(session *sablier.SessionState) {
+	if session == nil {
+		return
+	}
+	var shortest time.Duration
+	for _, v := range session.Instances {
+		if v.SessionDuration > 0 && (shortest == 0 || v.SessionDuration < shortest) {
+			shortest = v.SessionDuration
+		}
+	}
+	if shortest > 0 {
+		l.renewEvery.Store(int64(shortest / 2))
+	}
+}
+
+// waitReady holds a new connection until the instances are ready. It returns
+// the connection result when they are not.
+func (l *Listener) waitReady(ctx context.Context, client net.Addr) (string, bool) {
+	start := time.Now()
+	_, err := l.requestReadySession(ctx)
+	if err == nil {
+		l.rec.RecordListenerWait(l.name, time.Since(start))
+		return "", true
+	}
+
+	_, timeout := errors.AsType[sablier.ErrTimeout](err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return resultCanceled, false
+	case timeout || errors.Is(err, context.DeadlineExceeded):
+		l.l.WarnContext(ctx, "listener: instances were not ready in time, closing the connection",
+			slog.String("client", client.String()),
+			slog.Any("error", err),
+		)
+		return resultTimeout, false
+	default:
+		l.l.WarnContext(ctx, "listener: could not start the instances, closing the connection",
+			slog.String("client", client.String()),
+			slog.Any("error", err),
+		)
+		return resultUnavailable, false
+	}
+}
+
+// acquire reserves a slot for a new connection (UDP flow). It returns false,
+// and records the drop, when the listener holds max-connections already.
+func (l *Listener) acquire(client net.Addr) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	l.rec.RecordListenerDrop(l.name, dropMaxConnections)
+	if !l.saturated.Swap(true) {
+		l.l.Warn("listener: too many connections, dropping new ones",
+			slog.Int("max_connections", cap(l.slots)),
+			slog.String("client", client.String()),
+		)
+	}
+	return false
+}
+
+// release frees the slot of a closed connection (UDP flow).
+func (l *Listener) release() {
+	<-l.slots
+	l.saturated.Store(false)
+}
+
+// touch records that n bytes were forwarded in the given direction.
+func (l *Listener) touch(direction string, n int) {
+	l.rec.RecordListenerBytes(l.name, direction, int64(n))
+	l.active.Store(true)
+}
+
+// renew requests the session again every half duration if traffic flowed
+// since the last renewal, so the instances stay up while they are in use.
+// Without a session duration, the interval is learnt from the first session.
+func (l *Listener) renew(ctx context.Context) {
+	timer := time.NewTimer(l.renewInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(l.renewInterval())
+			if !l.active.Swap(false) {
+				continue
+			}
+			if _, err := l.requestSession(ctx); err != nil && ctx.Err() == nil {
+				l.l.WarnContext(ctx, "listener: could not renew the session", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// renewInterval returns how long to wait before the next renewal.
+func (l *Listener) renewInterval() time.Duration {
+	if every := time.Duration(l.renewEvery.Load()); every > 0 {
+		return every
+	}
+	return renewCheckInterval
+}
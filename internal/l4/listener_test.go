@@ -0,0 +1,339 @@
+package l4
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/internal/api/apitest"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/metrics"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func readySession() *sablier.SessionState {
+	return &sablier.SessionState{Instances: map[string]sablier.InstanceInfoWithError{
+		"postgres": {Instance: sablier.InstanceInfo{Name: "postgres", Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}},
+	}}
+}
+
+// tcpEcho serves a TCP backend echoing every line back.
+func tcpEcho(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close() //nolint:errcheck
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// udpEcho serves a UDP backend echoing every datagram back.
+func udpEcho(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = pc.WriteTo(buf[:n], addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+// serve binds and serves a single listener for the "postgres" instance.
+// Expectations are set before serving: the race detector cannot see that a
+// connection is accepted after the client dialed.
+func serve(t *testing.T, conf config.Listener, expect func(m *apitest.MockSablier)) *Listener {
+	t.Helper()
+	return serveWithDuration(t, conf, time.Hour, expect)
+}
+
+// serveWithDuration is serve with the default session duration of the
+// listeners.
+func serveWithDuration(t *testing.T, conf config.Listener, sessionDuration time.Duration, expect func(m *apitest.MockSablier)) *Listener {
+	t.Helper()
+	conf.Listen = "127.0.0.1:0"
+	conf.Names = []string{"postgres"}
+	m := apitest.NewMockSablier(gomock.NewController(t))
+	expect(m)
+
+	listeners, err := Listen(slogt.New(t), config.L4{Listeners: []config.Listener{conf}}, m, metrics.Noop{}, sessionDuration, time.Minute)
+	assert.NilError(t, err)
+	l := listeners[0]
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- l.Serve(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NilError(t, <-done)
+	})
+	return l
+}
+
+func TestListener_TCPForwardsWhenReady(t *testing.T) {
+	l := serve(t, config.Listener{Backend: tcpEcho(t), Timeout: 10 * time.Second}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), []string{"postgres"}, time.Hour, 10*time.Second).Return(readySession(), nil)
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	_, err = io.WriteString(conn, "ping\n")
+	assert.NilError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Equal(t, "ping\n", line)
+}
+
+func TestListener_TCPClosesWhenNotReady(t *testing.T) {
+	l := serve(t, config.Listener{Backend: tcpEcho(t)}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sablier.ErrTimeout{Duration: time.Minute})
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestListener_TCPBackendHalfClose(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer ln.Close() //nolint:errcheck
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close() //nolint:errcheck
+		// Answer only once the client is done sending.
+		body, _ := io.ReadAll(conn)
+		_, _ = conn.Write(append([]byte("got "), body...))
+	}()
+
+	l := serve(t, config.Listener{Backend: ln.Addr().String()}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil)
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+	_, err = io.WriteString(conn, "request")
+	assert.NilError(t, err)
+	assert.NilError(t, conn.(*net.TCPConn).CloseWrite())
+
+	answer, err := io.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Equal(t, "got request", string(answer))
+}
+
+func TestListener_TrafficRenewsSession(t *testing.T) {
+	var renewals atomic.Int32
+	l := serve(t, config.Listener{Backend: tcpEcho(t), SessionDuration: 20 * time.Millisecond}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), 20*time.Millisecond, gomock.Any()).Return(readySession(), nil)
+		m.EXPECT().RequestSession(gomock.Any(), []string{"postgres"}, 20*time.Millisecond).DoAndReturn(
+			func(context.Context, []string, time.Duration) (*sablier.SessionState, error) {
+				renewals.Add(1)
+				return readySession(), nil
+			}).MinTimes(2)
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+	reader := bufio.NewReader(conn)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for renewals.Load() < 2 && time.Now().Before(deadline) {
+		_, err = io.WriteString(conn, "ping\n")
+		assert.NilError(t, err)
+		_, err = reader.ReadString('\n')
+		assert.NilError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Without traffic, the session is left to expire.
+	before := renewals.Load()
+	time.Sleep(100 * time.Millisecond)
+	assert.Assert(t, renewals.Load() <= before+1, "the session must not be renewed without traffic")
+}
+
+func TestListener_TrafficRenewsSessionOfResolvedDuration(t *testing.T) {
+	// Without a session duration, Sablier resolves it from the labels and
+	// reports it in the session.
+	session := readySession()
+	instance := session.Instances["postgres"]
+	instance.SessionDuration = 20 * time.Millisecond
+	session.Instances["postgres"] = instance
+
+	var renewals atomic.Int32
+	l := serveWithDuration(t, config.Listener{Backend: tcpEcho(t)}, 0, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), time.Duration(0), gomock.Any()).Return(session, nil)
+		m.EXPECT().RequestSession(gomock.Any(), []string{"postgres"}, time.Duration(0)).DoAndReturn(
+			func(context.Context, []string, time.Duration) (*sablier.SessionState, error) {
+				renewals.Add(1)
+				return session, nil
+			}).MinTimes(2)
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+	reader := bufio.NewReader(conn)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for renewals.Load() < 2 && time.Now().Before(deadline) {
+		_, err = io.WriteString(conn, "ping\n")
+		assert.NilError(t, err)
+		_, err = reader.ReadString('\n')
+		assert.NilError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Assert(t, renewals.Load() >= 2)
+}
+
+func TestListener_UDPForwardsWhenReady(t *testing.T) {
+	l := serve(t, config.Listener{Protocol: config.ListenerProtocolUDP, Backend: udpEcho(t)}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), []string{"postgres"}, gomock.Any(), gomock.Any()).Return(readySession(), nil)
+	})
+
+	conn, err := net.Dial("udp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	// The first datagrams are queued while the instances start.
+	for _, msg := range []string{"one", "two"} {
+		_, err = conn.Write([]byte(msg))
+		assert.NilError(t, err)
+	}
+	buf := make([]byte, 16)
+	for _, want := range []string{"one", "two"} {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, err := conn.Read(buf)
+		assert.NilError(t, err)
+		assert.Equal(t, want, string(buf[:n]))
+	}
+}
+
+func TestListener_UDPFlowExpiresWhenIdle(t *testing.T) {
+	l := serve(t, config.Listener{Protocol: config.ListenerProtocolUDP, Backend: udpEcho(t), IdleTimeout: 20 * time.Millisecond}, func(m *apitest.MockSablier) {
+		// Each flow requests the session once: after the idle timeout, the next
+		// datagram opens a new flow.
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil).Times(2)
+	})
+
+	conn, err := net.Dial("udp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	buf := make([]byte, 16)
+	for range 2 {
+		_, err = conn.Write([]byte("ping"))
+		assert.NilError(t, err)
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = conn.Read(buf)
+		assert.NilError(t, err)
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// holdReady blocks the session requests until ctx is done, keeping the first
+// connection waiting.
+func holdReady(ctx context.Context, _ []string, _, _ time.Duration) (*sablier.SessionState, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestListener_TCPDropsConnectionsOverTheLimit(t *testing.T) {
+	waiting := make(chan struct{})
+	l := serve(t, config.Listener{Backend: tcpEcho(t), MaxConnections: 1}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, names []string, duration, timeout time.Duration) (*sablier.SessionState, error) {
+				close(waiting)
+				return holdReady(ctx, names, duration, timeout)
+			})
+	})
+
+	held, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer held.Close() //nolint:errcheck
+	<-waiting
+
+	dropped, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer dropped.Close() //nolint:errcheck
+	_ = dropped.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = dropped.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestListener_UDPDropsFlowsOverTheLimit(t *testing.T) {
+	waiting := make(chan struct{})
+	l := serve(t, config.Listener{Protocol: config.ListenerProtocolUDP, Backend: udpEcho(t), MaxConnections: 1}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, names []string, duration, timeout time.Duration) (*sablier.SessionState, error) {
+				close(waiting)
+				return holdReady(ctx, names, duration, timeout)
+			})
+	})
+
+	held, err := net.Dial("udp", l.Addr().String())
+	assert.NilError(t, err)
+	defer held.Close() //nolint:errcheck
+	_, err = held.Write([]byte("one"))
+	assert.NilError(t, err)
+	<-waiting
+
+	// The second client opens no flow: its datagrams are dropped and the mock
+	// fails the test if its session is requested.
+	dropped, err := net.Dial("udp", l.Addr().String())
+	assert.NilError(t, err)
+	defer dropped.Close() //nolint:errcheck
+	_, err = dropped.Write([]byte("two"))
+	assert.NilError(t, err)
+	_ = dropped.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err = dropped.Read(make([]byte, 16))
+	assert.Assert(t, err != nil, "a dropped flow must not be answered")
+}
+
+func TestListen_ClosesBoundListenersOnError(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer taken.Close() //nolint:errcheck
+
+	conf := config.L4{Listeners: []config.Listener{
+		{Name: "first", Listen: "127.0.0.1:0", Backend: "postgres:5432", Group: "db"},
+		{Name: "taken", Listen: taken.Addr().String(), Backend: "postgres:5432", Group: "db"},
+	}}
+	_, err = Listen(slogt.New(t), conf, nil, metrics.Noop{}, time.Hour, time.Minute)
+	assert.ErrorContains(t, err, "listener taken:")
+}
@@ -0,0 +1,105 @@
+package l4
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+)
+
+func (l *Listener) serveTCP(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.tcp.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		if !l.acquire(conn.RemoteAddr()) {
+			_ = conn.Close()
+			continue
+		}
+		wg.Go(func() {
+			defer l.release()
+			l.handleTCP(ctx, conn)
+		})
+	}
+}
+
+func (l *Listener) handleTCP(ctx context.Context, client net.Conn) {
+	l.rec.RecordListenerConnectionOpen(l.name)
+	result := resultForwarded
+	defer func() {
+		_ = client.Close()
+		l.rec.RecordListenerConnectionClose(l.name, result)
+	}()
+	stop := context.AfterFunc(ctx, func() { _ = client.Close() })
+	defer stop()
+
+	if r, ok := l.waitReady(ctx, client.RemoteAddr()); !ok {
+		result = r
+		return
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	backend, err := dialer.DialContext(ctx, "tcp", l.conf.Backend)
+	if err != nil {
+		l.l.WarnContext(ctx, "listener: could not connect to the backend",
+			slog.String("backend", l.conf.Backend),
+			slog.Any("error", err),
+		)
+		result = resultBackendError
+		return
+	}
+	defer backend.Close() //nolint:errcheck
+	stopBackend := context.AfterFunc(ctx, func() { _ = backend.Close() })
+	defer stopBackend()
+
+	l.spliceTCP(client, backend)
+}
+
+// spliceTCP copies bytes both ways until the backend is done. When the client
+// finishes sending, the backend is half-closed so it can still answer.
+func (l *Listener) spliceTCP(client, backend net.Conn) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := io.Copy(meteredWriter{w: backend, l: l, direction: "in"}, client); err != nil {
+			_ = backend.Close()
+			return
+		}
+		closeWrite(backend)
+	}()
+
+	_, _ = io.Copy(meteredWriter{w: client, l: l, direction: "out"}, backend)
+	// The backend is done: the client has nothing left to wait for.
+	_ = client.Close()
+	_ = backend.Close()
+	<-done
+}
+
+func closeWrite(conn net.Conn) {
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+	}
+}
+
+// meteredWriter records the forwarded bytes, which keeps the session alive.
+type meteredWriter struct {
+	w         io.Writer
+	l         *Listener
+	direction string
+}
+
+func (m meteredWriter) Write(p []byte) (int, error) {
+	n, err := m.w.Write(p)
+	if n > 0 {
+		m.l.touch(m.direction, n)
+	}
+	return n, err
+}
@@ -0,0 +1,150 @@
+package l4
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxDatagramSize is the largest UDP payload.
+	maxDatagramSize = 64 * 1024
+	// flowQueueSize is the number of datagrams a flow buffers while its
+	// instances start. Datagrams beyond it are dropped, as the network would.
+	flowQueueSize = 64
+)
+
+// flow is the traffic from one client address, the UDP equivalent of a
+// connection.
+type flow struct {
+	client  net.Addr
+	packets chan []byte
+	// last is the unix nano time of the last datagram in either direction.
+	last atomic.Int64
+}
+
+func (l *Listener) serveUDP(ctx context.Context) error {
+	var (
+		mu    sync.Mutex
+		flows = make(map[string]*flow)
+		wg    sync.WaitGroup
+	)
+	defer wg.Wait()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, client, err := l.udp.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+
+		mu.Lock()
+		f, ok := flows[client.String()]
+		if !ok {
+			if !l.acquire(client) {
+				mu.Unlock()
+				continue
+			}
+			f = &flow{client: client, packets: make(chan []byte, flowQueueSize)}
+			f.last.Store(time.Now().UnixNano())
+			flows[client.String()] = f
+			wg.Go(func() {
+				defer l.release()
+				l.handleUDP(ctx, f)
+				mu.Lock()
+				delete(flows, f.client.String())
+				mu.Unlock()
+			})
+		}
+		select {
+		case f.packets <- packet:
+		default:
+			l.rec.RecordListenerDrop(l.name, dropQueueFull)
+		}
+		mu.Unlock()
+	}
+}
+
+func (l *Listener) handleUDP(ctx context.Context, f *flow) {
+	l.rec.RecordListenerConnectionOpen(l.name)
+	result := resultForwarded
+	defer func() {
+		l.rec.RecordListenerConnectionClose(l.name, result)
+	}()
+
+	if r, ok := l.waitReady(ctx, f.client); !ok {
+		result = r
+		return
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	backend, err := dialer.DialContext(ctx, "udp", l.conf.Backend)
+	if err != nil {
+		l.l.WarnContext(ctx, "listener: could not connect to the backend",
+			slog.String("backend", l.conf.Backend),
+			slog.Any("error", err),
+		)
+		result = resultBackendError
+		return
+	}
+	defer backend.Close() //nolint:errcheck
+
+	backendDone := make(chan struct{})
+	go func() {
+		defer close(backendDone)
+		buf := make([]byte, maxDatagramSize)
+		for {
+			n, err := backend.Read(buf)
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				// Nothing listens on the backend yet: the client retries.
+				continue
+			}
+			if err != nil {
+				return
+			}
+			if _, err := l.udp.WriteTo(buf[:n], f.client); err != nil {
+				return
+			}
+			f.last.Store(time.Now().UnixNano())
+			l.touch("out", n)
+		}
+	}()
+
+	idle := l.conf.IdleTimeout
+	if idle <= 0 {
+		idle = defaultIdleTimeout
+	}
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-backendDone:
+			return
+		case packet := <-f.packets:
+			// A refused datagram is reported on a later read or write, and
+			// is not fatal to the flow.
+			if _, err := backend.Write(packet); err == nil {
+				f.last.Store(time.Now().UnixNano())
+				l.touch("in", len(packet))
+			}
+		case <-timer.C:
+			remaining := idle - time.Since(time.Unix(0, f.last.Load()))
+			if remaining <= 0 {
+				return
+			}
+			timer.Reset(remaining)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// principalKey is the gin context key holding the authenticated principal.
+const principalKey = "sablier.auth.principal"
+
+// target tells the authorization middleware which instances or groups a
+// route acts on, so it can check them against the caller's scopes.
+type target int
+
+const (
+	// targetNone routes act on no instance or group (e.g. themes).
+	targetNone target = iota
+	// targetRequest routes take the `names` or `group` query parameter.
+	targetRequest
+	// targetInstance routes take an instance name as the `name` path parameter.
+	targetInstance
+	// targetGroup routes take a group name as the `name` path parameter.
+	targetGroup
+	// targetAll routes list or stream every instance and group, so they are
+	// only granted to callers allowed to see all of them.
+	targetAll
+)
+
+// principal is an authenticated caller: a bearer token or a TLS client.
+type principal struct {
+	name   string
+	scopes config.AuthScopes
+}
+
+type tokenCredential struct {
+	// digest is the SHA-256 of the token. Comparing fixed-size digests in
+	// constant time does not leak the token length or content through timing.
+	digest [sha256.Size]byte
+	principal
+}
+
+// authenticator authenticates API callers and enforces their scopes.
+type authenticator struct {
+	tokens  []tokenCredential
+	clients map[string]principal
+	l       *slog.Logger
+}
+
+// newAuthenticator returns nil when authentication is disabled. The
+// configuration must be valid (see config.Auth.IsValid).
+func newAuthenticator(logger *slog.Logger, conf config.Auth) *authenticator {
+	if !conf.Enabled() {
+		return nil
+	}
+
+	a := &authenticator{
+		clients: make(map[string]principal, len(conf.Clients)),
+		l:       logger,
+	}
+	for _, t := range conf.Tokens {
+		a.tokens = append(a.tokens, tokenCredential{
+			digest:    sha256.Sum256([]byte(t.Token)),
+			principal: principal{name: "token:" + t.Name, scopes: t.Scopes},
+		})
+	}
+	for _, c := range conf.Clients {
+		a.clients[c.Subject] = principal{name: "client:" + c.Subject, scopes: c.Scopes}
+	}
+	return a
+}
+
+// Authenticate identifies the caller from its bearer token or, when no
+// Authorization header is sent, from its verified TLS client certificate.
+// Unauthenticated calls are answered with 401.
+func (a *authenticator) Authenticate(c *gin.Context) {
+	p, err := a.identify(c)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer realm="sablier"`)
+		api.AbortWithProblemDetail(c, api.ProblemUnauthorized(err.Error()))
+		c.Abort()
+		return
+	}
+	c.Set(principalKey, p)
+	c.Next()
+}
+
+func (a *authenticator) identify(c *gin.Context) (principal, error) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return principal{}, fmt.Errorf("unsupported authorization scheme, expected a bearer token")
+		}
+		digest := sha256.Sum256([]byte(strings.TrimSpace(token)))
+		for _, t := range a.tokens {
+			if subtle.ConstantTimeCompare(digest[:], t.digest[:]) == 1 {
+				return t.principal, nil
+			}
+		}
+		return principal{}, fmt.Errorf("invalid bearer token")
+	}
+
+	if tls := c.Request.TLS; tls != nil && len(tls.VerifiedChains) > 0 && len(tls.VerifiedChains[0]) > 0 {
+		leaf := tls.VerifiedChains[0][0]
+		for _, subject := range certificateSubjects(leaf) {
+			if p, ok := a.clients[subject]; ok {
+				return p, nil
+			}
+		}
+		return principal{}, fmt.Errorf("client certificate %q is not allowed", leaf.Subject.CommonName)
+	}
+
+	return principal{}, fmt.Errorf("authentication required: send a bearer token or a client certificate")
+}
+
+// certificateSubjects returns the identities a client certificate vouches for.
+func certificateSubjects(cert *x509.Certificate) []string {
+	subjects := []string{cert.Subject.CommonName}
+	subjects = append(subjects, cert.DNSNames...)
+	subjects = append(subjects, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		subjects = append(subjects, u.String())
+	}
+	return subjects
+}
+
+// Authorize rejects with 403 callers whose scopes do not grant endpoint, or
+// the instances and groups the route acts on.
+func (a *authenticator) Authorize(endpoint string, t target) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := c.MustGet(principalKey).(principal)
+		if err := authorize(c, p.scopes, endpoint, t); err != nil {
+			a.l.WarnContext(c.Request.Context(), "forbidden API call",
+				slog.String("principal", p.name),
+				slog.String("path", c.FullPath()),
+				slog.Any("reason", err),
+			)
+			api.AbortWithProblemDetail(c, api.ProblemForbidden(err.Error()))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func authorize(c *gin.Context, scopes config.AuthScopes, endpoint string, t target) error {
+	if !scopes.AllowsEndpoint(endpoint) {
+		return fmt.Errorf("endpoint %q is not allowed", endpoint)
+	}
+
+	switch t {
+	case targetRequest:
+		for _, name := range c.QueryArray("names") {
+			name = strings.TrimPrefix(name, sablier.OptionalPrefix)
+			if !scopes.AllowsInstance(name) {
+				return fmt.Errorf("instance %q is not allowed", name)
+			}
+		}
+		if group, ok := c.GetQuery("group"); ok && !scopes.AllowsGroup(group) {
+			return fmt.Errorf("group %q is not allowed", group)
+		}
+	case targetInstance:
+		if name := c.Param("name"); !scopes.AllowsInstance(name) {
+			return fmt.Errorf("instance %q is not allowed", name)
+		}
+	case targetGroup:
+		if name := c.Param("name"); !scopes.AllowsGroup(name) {
+			return fmt.Errorf("group %q is not allowed", name)
+		}
+	case targetAll:
+		if !scopes.AllowsEverything() {
+			return fmt.Errorf("listing requires access to every instance and group")
+		}
+	}
+	return nil
+}
+
+// scoped returns a router group whose routes require the caller to be granted
+// endpoint and target. Without authentication it returns router unchanged.
+func (a *authenticator) scoped(router *gin.RouterGroup, endpoint string, t target) *gin.RouterGroup {
+	if a == nil {
+		return router
+	}
+	return router.Group("", a.Authorize(endpoint, t))
+}
+
+// statusStream returns a router group whose routes require an authenticated
+// caller or, instead, a status token signed for the names or group of the
+// request. Browsers cannot send an Authorization header with EventSource, so
+// the waiting page reaches the session status stream with the token of its
+// URL. Without authentication it returns router unchanged.
+func (a *authenticator) statusStream(router *gin.RouterGroup, tokens *api.StatusTokens) *gin.RouterGroup {
+	if a == nil {
+		return router
+	}
+	return router.Group("", func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" || tokens == nil {
+			a.Authenticate(c)
+			return
+		}
+		names, group := c.QueryArray("names"), c.Query("group")
+		if err := tokens.Verify(token, names, group, time.Now()); err != nil {
+			api.AbortWithProblemDetail(c, api.ProblemUnauthorized(err.Error()))
+			c.Abort()
+			return
+		}
+		// The token grants the stream of what it was signed for, and nothing
+		// else.
+		scopes := config.AuthScopes{Endpoints: []string{config.ScopeStrategies}}
+		if group != "" {
+			scopes.Groups = []string{group}
+		}
+		for _, name := range names {
+			scopes.Instances = append(scopes.Instances, strings.TrimPrefix(name, sablier.OptionalPrefix))
+		}
+		c.Set(principalKey, principal{name: "status-token", scopes: scopes})
+		c.Next()
+	})
+}
+
+// protect returns a router group whose routes require an authenticated caller.
+// Without authentication it returns router unchanged.
+func (a *authenticator) protect(router *gin.RouterGroup) *gin.RouterGroup {
+	if a == nil {
+		return router
+	}
+	return router.Group("", a.Authenticate)
+}
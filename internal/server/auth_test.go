@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/internal/api/apitest"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/tniswong/go.rfcx/rfc7807"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func authServerConfig() config.Server {
+	conf := config.NewServerConfig()
+	conf.Auth = config.Auth{
+		Tokens: []config.AuthToken{
+			{
+				Name:  "blog",
+				Token: "blog-token",
+				Scopes: config.AuthScopes{
+					Endpoints: []string{config.ScopeStrategies},
+					Instances: []string{"blog-*"},
+					Groups:    []string{"blog"},
+				},
+			},
+			{
+				Name:  "reader",
+				Token: "reader-token",
+				Scopes: config.AuthScopes{
+					Endpoints: []string{config.ScopeInstances, config.ScopeGroups},
+					Instances: []string{config.ScopeAll},
+					Groups:    []string{config.ScopeAll},
+				},
+			},
+			{
+				Name:  "admin",
+				Token: "admin-token",
+				Scopes: config.AuthScopes{
+					Endpoints: []string{config.ScopeAll},
+					Instances: []string{config.ScopeAll},
+					Groups:    []string{config.ScopeAll},
+				},
+			},
+		},
+		Clients: []config.AuthClient{
+			{
+				Subject: "proxy.internal",
+				Scopes: config.AuthScopes{
+					Endpoints: []string{config.ScopeThemes},
+				},
+			},
+		},
+	}
+	return conf
+}
+
+func authRouter(t *testing.T) (*gin.Engine, *apitest.MockSablier) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	s := testStrategy(t)
+	m := apitest.NewMockSablier(gomock.NewController(t))
+	s.Sablier = m
+	return setupRouter(context.Background(), slogt.New(t), authServerConfig(), config.Tracing{ServiceName: "sablier"}, s), m
+}
+
+func doRequest(r http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func withToken(req *http.Request, token string) *http.Request {
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestAuth_HealthIsPublic(t *testing.T) {
+	r, _ := authRouter(t)
+	w := doRequest(r, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuth_Unauthenticated(t *testing.T) {
+	r, _ := authRouter(t)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "missing credentials"},
+		{name: "invalid token", header: "Bearer nope"},
+		{name: "unsupported scheme", header: "Basic YWRtaW46YWRtaW4="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/themes", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := doRequest(r, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, rfc7807.JSONMediaType, w.Header().Get("Content-Type"))
+			assert.Equal(t, `Bearer realm="sablier"`, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestAuth_Scopes(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		want  int
+	}{
+		{name: "group in scope", token: "blog-token", path: "/api/strategies/blocking?group=blog", want: http.StatusOK},
+		{name: "group out of scope", token: "blog-token", path: "/api/strategies/blocking?group=admin", want: http.StatusForbidden},
+		{name: "instance in scope", token: "blog-token", path: "/api/strategies/blocking?names=blog-web&names=optional:blog-db", want: http.StatusOK},
+		{name: "one instance out of scope", token: "blog-token", path: "/api/strategies/blocking?names=blog-web&names=db", want: http.StatusForbidden},
+		{name: "endpoint out of scope", token: "blog-token", path: "/api/themes", want: http.StatusForbidden},
+		{name: "listing needs every instance and group", token: "blog-token", path: "/api/groups", want: http.StatusForbidden},
+		{name: "admin lists", token: "admin-token", path: "/api/groups", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := authRouter(t)
+			m.EXPECT().RequestReadySessionGroup(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil).AnyTimes()
+			m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil).AnyTimes()
+			m.EXPECT().Groups().Return(map[string][]string{}).AnyTimes()
+
+			w := doRequest(r, withToken(httptest.NewRequest(http.MethodGet, tt.path, nil), tt.token))
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want == http.StatusForbidden {
+				assert.Equal(t, rfc7807.JSONMediaType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestAuth_StatusStreamAcceptsStatusToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conf := authServerConfig()
+	s := testStrategy(t)
+	m := apitest.NewMockSablier(gomock.NewController(t))
+	s.Sablier = m
+	tokens, err := api.NewStatusTokens(conf.Auth)
+	assert.NilError(t, err)
+	s.StatusTokens = tokens
+	r := setupRouter(context.Background(), slogt.New(t), conf, config.Tracing{ServiceName: "sablier"}, s)
+
+	m.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{})
+	m.EXPECT().InspectInstance(gomock.Any(), "blog-web").Return(sablier.InstanceInfo{Name: "blog-web", Status: sablier.InstanceStatusReady}, nil)
+
+	token := url.QueryEscape(tokens.Sign([]string{"blog-web"}, "", time.Now()))
+	w := doRequest(r, httptest.NewRequest(http.MethodGet, "/api/strategies/dynamic/status?names=blog-web&token="+token, nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The token grants the stream of its names only, and no other endpoint.
+	w = doRequest(r, httptest.NewRequest(http.MethodGet, "/api/strategies/dynamic/status?names=admin&token="+token, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = doRequest(r, httptest.NewRequest(http.MethodGet, "/api/strategies/blocking?names=blog-web&token="+token, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Without a token, the stream requires credentials as before.
+	w = doRequest(r, httptest.NewRequest(http.MethodGet, "/api/strategies/dynamic/status?names=blog-web", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuth_PathTargets(t *testing.T) {
+	r, _ := authRouter(t)
+
+	// The blog token may not act on sessions at all, nor pin other groups.
+	w := doRequest(r, withToken(httptest.NewRequest(http.MethodDelete, "/api/sessions/blog-web", nil), "blog-token"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(r, withToken(httptest.NewRequest(http.MethodPost, "/api/groups/admin/pin?duration=1h", nil), "blog-token"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Listing instances and groups does not grant pinning them.
+	w = doRequest(r, withToken(httptest.NewRequest(http.MethodPost, "/api/instances/blog-web/pin?duration=1h", nil), "reader-token"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(r, withToken(httptest.NewRequest(http.MethodPost, "/api/groups/blog/pin?duration=1h", nil), "reader-token"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuth_ClientCertificate(t *testing.T) {
+	r, _ := authRouter(t)
+
+	withCert := func(cert *x509.Certificate) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/themes", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return req
+	}
+
+	w := doRequest(r, withCert(&x509.Certificate{DNSNames: []string{"proxy.internal"}}))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(r, withCert(&x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A presented but unverified certificate is not a credential.
+	req := httptest.NewRequest(http.MethodGet, "/api/themes", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{DNSNames: []string{"proxy.internal"}}}}
+	w = doRequest(r, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuth_DisabledLeavesRoutesOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter(context.Background(), slogt.New(t), config.NewServerConfig(), config.Tracing{ServiceName: "sablier"}, testStrategy(t))
+	w := doRequest(r, httptest.NewRequest(http.MethodGet, "/api/themes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
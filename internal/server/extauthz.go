@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/pkg/config"
+)
+
+// Context extension keys read from the ext_authz check request. Setting names
+// or group on an Envoy route targets instances directly, bypassing
+// routing.routes.
+const (
+	extAuthzNames           = "names"
+	extAuthzGroup           = "group"
+	extAuthzStrategy        = "strategy"
+	extAuthzSessionDuration = "session-duration"
+	extAuthzTimeout         = "timeout"
+	extAuthzTheme           = "theme"
+	extAuthzDisplayName     = "display-name"
+)
+
+// newExtAuthzServer returns the gRPC server answering Envoy external
+// authorization (envoy.service.auth.v3.Authorization) checks. It serves TLS
+// when conf.TLS is enabled, requiring a client certificate signed by
+// conf.TLS.ClientCAFile when set.
+func newExtAuthzServer(logger *slog.Logger, s *api.ServeStrategy, conf config.ExtAuthz) (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if conf.TLS.Enabled() {
+		tlsConf, err := newTLSConfig(logger, conf.TLS.ServerTLS())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	}
+	srv := grpc.NewServer(opts...)
+	authv3.RegisterAuthorizationServer(srv, &extAuthz{
+		s:      s,
+		l:      logger,
+		tracer: otel.Tracer("github.com/sablierapp/sablier/internal/server"),
+	})
+	return srv, nil
+}
+
+// extAuthz maps a check request to instance names or a group, the same way
+// the forward-auth strategy does: OK when the session is ready, otherwise a
+// denied response carrying the waiting page (dynamic) or an OK once ready
+// (blocking).
+type extAuthz struct {
+	authv3.UnimplementedAuthorizationServer
+
+	s      *api.ServeStrategy
+	l      *slog.Logger
+	tracer trace.Tracer
+}
+
+func (e *extAuthz) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	attrs := req.GetAttributes()
+	httpReq := attrs.GetRequest().GetHttp()
+
+	host := httpReq.GetHost()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	path := "/"
+	if u, err := url.ParseRequestURI(httpReq.GetPath()); err == nil && u.Path != "" {
+		path = u.Path
+	}
+
+	ctx, span := e.tracer.Start(e.extractTraceContext(ctx, httpReq), "envoy.ext_authz.check",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.host", host),
+			attribute.String("http.path", path),
+		))
+	defer span.End()
+
+	route, ok, err := extAuthzRoute(attrs.GetContextExtensions())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return denied(http.StatusBadRequest, err.Error()), nil
+	}
+	if !ok {
+		route, ok = e.s.Routing.Match(host, path)
+	}
+	if !ok {
+		return denied(http.StatusNotFound, fmt.Sprintf("no route matches host %q and path %q", host, path)), nil
+	}
+
+	duration := route.SessionDuration
+	e.s.Metrics.RecordSessionRequest("ext-authz", routeTarget(route))
+
+	if route.Strategy == config.RouteStrategyBlocking {
+		if _, err := requestReadyRouteSession(ctx, e.s, route, duration); err != nil {
+			return e.fail(ctx, span, host, path, err), nil
+		}
+		return allowed(), nil
+	}
+
+	themeName := e.s.StrategyConfig.Dynamic.DefaultTheme
+	if route.Theme != "" {
+		themeName = route.Theme
+	}
+	// Same as the forward-auth strategy: never start instances for a request
+	// that can only ever answer 404.
+	if !e.s.Theme.Exists(themeName) {
+		return denied(http.StatusNotFound, fmt.Sprintf("theme %s not found", themeName)), nil
+	}
+
+	session, err := requestRouteSession(ctx, e.s, route, duration)
+	if err != nil {
+		return e.fail(ctx, span, host, path, err), nil
+	}
+	if session.IsReady() {
+		return allowed(), nil
+	}
+
+	page, err := renderWaitingPage(e.s, route, host, session)
+	if err != nil {
+		e.l.ErrorContext(ctx, "ext_authz: could not render the waiting page", slog.Any("error", err))
+		span.SetStatus(codes.Error, err.Error())
+		return denied(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)), nil
+	}
+	resp := denied(http.StatusServiceUnavailable, string(page))
+	resp.GetDeniedResponse().Headers = headers(
+		"Content-Type", "text/html",
+		"Cache-Control", "no-cache",
+		"Retry-After", retryAfter(e.s),
+		api.SablierStatusHeader, api.SablierStatusNotReady,
+	)
+	return resp, nil
+}
+
+// extractTraceContext continues the trace of Envoy, propagated in the gRPC
+// metadata when Envoy traces the check, or of the original request headers.
+func (e *extAuthz) extractTraceContext(ctx context.Context, httpReq *authv3.AttributeContext_HttpRequest) context.Context {
+	propagator := otel.GetTextMapPropagator()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if extracted := propagator.Extract(ctx, metadataCarrier(md)); trace.SpanContextFromContext(extracted).IsValid() {
+			return extracted
+		}
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(requestHeaders(httpReq)))
+}
+
+func (e *extAuthz) fail(ctx context.Context, span trace.Span, host, path string, err error) *authv3.CheckResponse {
+	if errors.Is(err, context.Canceled) {
+		// Envoy gave up on the check.
+		return denied(http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+	}
+	e.l.WarnContext(ctx, "ext_authz: could not start the route instances",
+		slog.String("host", host),
+		slog.String("path", path),
+		slog.Any("error", err),
+	)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	status := sessionErrorStatus(err)
+	return denied(status, http.StatusText(status))
+}
+
+// extAuthzRoute builds a route from the context extensions of the Envoy
+// route. It reports false when they set neither names nor group.
+func extAuthzRoute(ext map[string]string) (config.Route, bool, error) {
+	route := config.Route{
+		Group:       ext[extAuthzGroup],
+		Strategy:    ext[extAuthzStrategy],
+		Theme:       ext[extAuthzTheme],
+		DisplayName: ext[extAuthzDisplayName],
+	}
+	for name := range strings.SplitSeq(ext[extAuthzNames], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			route.Names = append(route.Names, name)
+		}
+	}
+	if len(route.Names) == 0 && route.Group == "" {
+		return config.Route{}, false, nil
+	}
+
+	var err error
+	if v := ext[extAuthzSessionDuration]; v != "" {
+		if route.SessionDuration, err = time.ParseDuration(v); err != nil {
+			return config.Route{}, false, fmt.Errorf("context extension %s: %w", extAuthzSessionDuration, err)
+		}
+	}
+	if v := ext[extAuthzTimeout]; v != "" {
+		if route.Timeout, err = time.ParseDuration(v); err != nil {
+			return config.Route{}, false, fmt.Errorf("context extension %s: %w", extAuthzTimeout, err)
+		}
+	}
+	if err := route.IsValid(); err != nil {
+		return config.Route{}, false, fmt.Errorf("context extensions: %w", err)
+	}
+	return route, true, nil
+}
+
+// requestHeaders returns the headers of the original request, from the
+// headers map or, when Envoy sends raw headers, from the header map.
+func requestHeaders(httpReq *authv3.AttributeContext_HttpRequest) map[string]string {
+	if h := httpReq.GetHeaders(); len(h) > 0 {
+		return h
+	}
+	h := make(map[string]string)
+	for _, header := range httpReq.GetHeaderMap().GetHeaders() {
+		value := header.GetValue()
+		if value == "" {
+			value = string(header.GetRawValue())
+		}
+		h[strings.ToLower(header.GetKey())] = value
+	}
+	return h
+}
+
+func allowed() *authv3.CheckResponse {
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(grpccodes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{
+			ResponseHeadersToAdd: headers(api.SablierStatusHeader, api.SablierStatusReady),
+		}},
+	}
+}
+
+func denied(status int, body string) *authv3.CheckResponse {
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(grpccodes.PermissionDenied)},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+			Status: &typev3.HttpStatus{Code: typev3.StatusCode(status)},
+			Body:   body,
+		}},
+	}
+}
+
+// headers builds header options from key/value pairs.
+func headers(kv ...string) []*corev3.HeaderValueOption {
+	opts := make([]*corev3.HeaderValueOption, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		opts = append(opts, &corev3.HeaderValueOption{Header: &corev3.HeaderValue{Key: kv[i], Value: kv[i+1]}})
+	}
+	return opts
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/neilotoole/slogt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"gotest.tools/v3/assert"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/internal/api/apitest"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// extAuthzClient serves ext_authz for the routes and returns a client. The
+// expectations are set on the mock before serving.
+func extAuthzClient(t *testing.T, routes []config.Route, expect func(m *apitest.MockSablier)) authv3.AuthorizationClient {
+	t.Helper()
+	addr := serveExtAuthz(t, config.ExtAuthz{}, routes, expect)
+	return dialExtAuthz(t, addr, insecure.NewCredentials())
+}
+
+// serveExtAuthz serves ext_authz with conf for the routes and returns its
+// address.
+func serveExtAuthz(t *testing.T, conf config.ExtAuthz, routes []config.Route, expect func(m *apitest.MockSablier)) string {
+	t.Helper()
+	s := testStrategy(t)
+	m := apitest.NewMockSablier(gomock.NewController(t))
+	expect(m)
+	s.Sablier = m
+	s.Routing = config.Routing{Routes: routes}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	srv, err := newExtAuthzServer(slogt.New(t), s, conf)
+	assert.NilError(t, err)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+	return l.Addr().String()
+}
+
+func dialExtAuthz(t *testing.T, addr string, creds credentials.TransportCredentials) authv3.AuthorizationClient {
+	t.Helper()
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return authv3.NewAuthorizationClient(conn)
+}
+
+func TestExtAuthz_RequiresClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	server := newTestCert(t, "server")
+	server.write(t, certFile, keyFile, time.Now())
+	envoy := newTestCert(t, "envoy")
+	assert.NilError(t, os.WriteFile(caFile, envoy.certPEM, 0o600))
+
+	conf := config.ExtAuthz{TLS: config.ExtAuthzTLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}}
+	addr := serveExtAuthz(t, conf, []config.Route{whoamiRoute}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, time.Hour).Return(readySession(), nil)
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.cert)
+	envoyPair, err := tls.X509KeyPair(envoy.certPEM, envoy.keyPEM)
+	assert.NilError(t, err)
+
+	withCert := dialExtAuthz(t, addr, credentials.NewTLS(&tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{envoyPair},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	resp, err := withCert.Check(t.Context(), checkRequest("whoami.example.com", "/", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, int32(grpccodes.OK), resp.GetStatus().GetCode())
+
+	withoutCert := dialExtAuthz(t, addr, credentials.NewTLS(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}))
+	_, err = withoutCert.Check(t.Context(), checkRequest("whoami.example.com", "/", nil))
+	assert.Assert(t, err != nil, "a client without certificate must fail the handshake")
+
+	plaintext := dialExtAuthz(t, addr, insecure.NewCredentials())
+	_, err = plaintext.Check(t.Context(), checkRequest("whoami.example.com", "/", nil))
+	assert.Assert(t, err != nil, "a plaintext client must be refused")
+}
+
+func checkRequest(host, path string, ext map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{
+		Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+			Method: http.MethodGet,
+			Host:   host,
+			Path:   path,
+		}},
+		ContextExtensions: ext,
+	}}
+}
+
+func deniedStatus(t *testing.T, resp *authv3.CheckResponse) int {
+	t.Helper()
+	assert.Equal(t, int32(grpccodes.PermissionDenied), resp.GetStatus().GetCode())
+	return int(resp.GetDeniedResponse().GetStatus().GetCode())
+}
+
+var whoamiRoute = config.Route{Host: "whoami.example.com", Names: []string{"test"}, SessionDuration: time.Hour}
+
+func TestExtAuthz_ReadyIsAllowed(t *testing.T) {
+	client := extAuthzClient(t, []config.Route{whoamiRoute}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, time.Hour).Return(readySession(), nil)
+	})
+
+	resp, err := client.Check(t.Context(), checkRequest("whoami.example.com:443", "/index.html?q=1", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, int32(grpccodes.OK), resp.GetStatus().GetCode())
+	assert.Assert(t, resp.GetOkResponse() != nil)
+}
+
+func TestExtAuthz_NotReadyIsDeniedWithWaitingPage(t *testing.T) {
+	client := extAuthzClient(t, []config.Route{whoamiRoute}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, time.Hour).Return(&sablier.SessionState{Instances: map[string]sablier.InstanceInfoWithError{
+			"test": {Instance: sablier.InstanceInfo{Name: "test", Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}},
+		}}, nil)
+	})
+
+	resp, err := client.Check(t.Context(), checkRequest("whoami.example.com", "/", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, deniedStatus(t, resp))
+	assert.Assert(t, len(resp.GetDeniedResponse().GetBody()) > 0)
+
+	headers := make(map[string]string)
+	for _, h := range resp.GetDeniedResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "text/html", headers["Content-Type"])
+	assert.Equal(t, "5", headers["Retry-After"])
+	assert.Equal(t, api.SablierStatusNotReady, headers[api.SablierStatusHeader])
+}
+
+func TestExtAuthz_BlockingRouteTimeout(t *testing.T) {
+	route := config.Route{Host: "blog.example.com", Group: "blog", Strategy: config.RouteStrategyBlocking, Timeout: 10 * time.Second}
+	client := extAuthzClient(t, []config.Route{route}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestReadySessionGroup(gomock.Any(), "blog", gomock.Any(), 10*time.Second).Return(nil, sablier.ErrTimeout{Duration: 10 * time.Second})
+	})
+
+	resp, err := client.Check(t.Context(), checkRequest("blog.example.com", "/", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusGatewayTimeout, deniedStatus(t, resp))
+}
+
+func TestExtAuthz_NoRoute(t *testing.T) {
+	client := extAuthzClient(t, []config.Route{whoamiRoute}, func(*apitest.MockSablier) {})
+
+	resp, err := client.Check(t.Context(), checkRequest("unknown.example.com", "/", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusNotFound, deniedStatus(t, resp))
+}
+
+func TestExtAuthz_ContextExtensions(t *testing.T) {
+	client := extAuthzClient(t, nil, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestSessionGroup(gomock.Any(), "blog", 30*time.Minute).Return(readySession(), nil)
+	})
+
+	resp, err := client.Check(t.Context(), checkRequest("blog.example.com", "/", map[string]string{
+		"group":            "blog",
+		"session-duration": "30m",
+	}))
+	assert.NilError(t, err)
+	assert.Equal(t, int32(grpccodes.OK), resp.GetStatus().GetCode())
+
+	resp, err = client.Check(t.Context(), checkRequest("blog.example.com", "/", map[string]string{
+		"group":            "blog",
+		"session-duration": "half an hour",
+	}))
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusBadRequest, deniedStatus(t, resp))
+}
+
+func TestExtAuthz_ContinuesEnvoyTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter), sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var got trace.SpanContext
+	client := extAuthzClient(t, []config.Route{whoamiRoute}, func(m *apitest.MockSablier) {
+		m.EXPECT().RequestSession(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ []string, _ time.Duration) (*sablier.SessionState, error) {
+				got = trace.SpanContextFromContext(ctx)
+				return readySession(), nil
+			})
+	})
+
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	_, err := client.Check(ctx, checkRequest("whoami.example.com", "/", nil))
+	assert.NilError(t, err)
+
+	assert.Equal(t, traceID, got.TraceID().String(), "the session request must continue the trace of Envoy")
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "envoy.ext_authz.check", spans[0].Name)
+}
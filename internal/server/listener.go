@@ -0,0 +1,164 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+)
+
+// listen opens the server listener: the Unix domain socket when one is
+// configured, the TCP port otherwise.
+func listen(serverConf config.Server) (net.Listener, error) {
+	if serverConf.Socket == "" {
+		return net.Listen("tcp", fmt.Sprintf(":%d", serverConf.Port))
+	}
+
+	mode, err := serverConf.ParseSocketMode()
+	if err != nil {
+		return nil, err
+	}
+	// A socket file survives an unclean exit and makes the bind fail. Only
+	// remove sockets: any other file at that path is a configuration mistake.
+	if fi, err := os.Lstat(serverConf.Socket); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", serverConf.Socket)
+		}
+		if err := os.Remove(serverConf.Socket); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	l, err := net.Listen("unix", serverConf.Socket)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(serverConf.Socket, fs.FileMode(mode)); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("setting socket mode: %w", err)
+	}
+	return l, nil
+}
+
+// listenAddr describes where the server listens, for logs.
+func listenAddr(serverConf config.Server) string {
+	if serverConf.Socket != "" {
+		return "unix:" + serverConf.Socket
+	}
+	return fmt.Sprintf(":%d", serverConf.Port)
+}
+
+// newTLSConfig builds the server TLS configuration. The certificate is loaded
+// once up front so a bad certificate fails the start instead of every handshake.
+func newTLSConfig(logger *slog.Logger, conf config.TLS) (*tls.Config, error) {
+	certs := &certReloader{certFile: conf.CertFile, keyFile: conf.KeyFile, l: logger}
+	if err := certs.reload(); err != nil {
+		return nil, err
+	}
+
+	tlsConf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+	}
+	if conf.MinVersion == "1.3" {
+		tlsConf.MinVersion = tls.VersionTLS13
+	}
+
+	if conf.ClientCAFile != "" {
+		pem, err := os.ReadFile(conf.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in client CA file %s", conf.ClientCAFile)
+		}
+		tlsConf.ClientCAs = pool
+		tlsConf.ClientAuth = tls.VerifyClientCertIfGiven
+		if conf.ClientAuth == config.ClientAuthRequire {
+			tlsConf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return tlsConf, nil
+}
+
+// certReloader serves the certificate and key files, reloading them when
+// either file changes so renewed certificates are picked up without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	l        *slog.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// GetCertificate implements tls.Config.GetCertificate. A failed reload keeps
+// serving the previous certificate: a renewal tool writing the certificate
+// and key one after the other briefly leaves a mismatched pair on disk.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if certMod, keyMod, changed := r.changed(); changed {
+		// Remember the attempt so a broken pair is reported once, and retried
+		// as soon as either file changes again.
+		r.certMod, r.keyMod = certMod, keyMod
+		if err := r.reloadLocked(); err != nil {
+			r.l.Warn("server: failed to reload TLS certificate, serving the previous one", slog.Any("error", err))
+		} else {
+			r.l.Info("server: TLS certificate reloaded", slog.String("cert_file", r.certFile))
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloadLocked()
+}
+
+func (r *certReloader) reloadLocked() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate: %w", err)
+	}
+	r.cert, r.certMod, r.keyMod = &cert, certMod, keyMod
+	return nil
+}
+
+// changed reports whether either file was modified since the last load.
+func (r *certReloader) changed() (time.Time, time.Time, bool) {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return certMod, keyMod, !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod)
+}
+
+func (r *certReloader) modTimes() (time.Time, time.Time, error) {
+	// os.Stat follows symlinks, so Kubernetes secret volumes, which swap a
+	// symlinked directory on update, are seen as changed too.
+	cert, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading TLS certificate: %w", err)
+	}
+	key, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading TLS key: %w", err)
+	}
+	return cert.ModTime(), key.ModTime(), nil
+}
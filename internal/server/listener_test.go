@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/config"
+	"gotest.tools/v3/assert"
+)
+
+// testCert is a self-signed certificate, usable both as a server certificate
+// for 127.0.0.1 and as its own CA.
+type testCert struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, commonName string) testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	return testCert{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c testCert) write(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+	assert.NilError(t, os.WriteFile(certFile, c.certPEM, 0o600))
+	assert.NilError(t, os.WriteFile(keyFile, c.keyPEM, 0o600))
+	assert.NilError(t, os.Chtimes(certFile, modTime, modTime))
+	assert.NilError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func startServer(t *testing.T, conf config.Server) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Start(ctx, slogt.New(t), conf, config.NewTracingConfig(), testStrategy(t))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NilError(t, <-done)
+	})
+}
+
+func waitStatus(client *http.Client, url string, timeout time.Duration) (*http.Response, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+			return resp, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s not reachable within %s: %w", url, timeout, err)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+func TestStartServesTLSAndReloadsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := newTestCert(t, "first")
+	first.write(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	conf := config.NewServerConfig()
+	conf.Port = freePort(t)
+	conf.TLS.CertFile, conf.TLS.KeyFile = certFile, keyFile
+	startServer(t, conf)
+
+	second := newTestCert(t, "second")
+	roots := x509.NewCertPool()
+	roots.AddCert(first.cert)
+	roots.AddCert(second.cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		DisableKeepAlives: true,
+	}}
+	url := fmt.Sprintf("https://127.0.0.1:%d/health", conf.Port)
+
+	resp, err := waitStatus(client, url, 5*time.Second)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "first", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	second.write(t, certFile, keyFile, time.Now())
+
+	resp, err = client.Get(url)
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "second", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestStartTLSRequiresClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	server := newTestCert(t, "server")
+	server.write(t, certFile, keyFile, time.Now())
+	client := newTestCert(t, "client")
+	assert.NilError(t, os.WriteFile(caFile, client.certPEM, 0o600))
+
+	conf := config.NewServerConfig()
+	conf.Port = freePort(t)
+	conf.TLS.CertFile, conf.TLS.KeyFile = certFile, keyFile
+	conf.TLS.ClientCAFile = caFile
+	conf.TLS.ClientAuth = config.ClientAuthRequire
+	conf.TLS.MinVersion = "1.3"
+	startServer(t, conf)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.cert)
+	url := fmt.Sprintf("https://127.0.0.1:%d/health", conf.Port)
+
+	clientPair, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	assert.NilError(t, err)
+	withCert := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{clientPair},
+		MinVersion:   tls.VersionTLS13,
+	}}}
+	resp, err := waitStatus(withCert, url, 5*time.Second)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	withoutCert := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS13}}}
+	_, err = withoutCert.Get(url)
+	assert.Assert(t, err != nil, "a client without certificate must fail the handshake")
+}
+
+func TestStartListensOnUnixSocket(t *testing.T) {
+	// Unix socket paths are limited to ~100 bytes, t.TempDir() can be longer.
+	dir, err := os.MkdirTemp("", "sablier")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "sablier.sock")
+
+	// A socket left behind by a previous run must not prevent the bind.
+	stale, err := net.Listen("unix", socket)
+	assert.NilError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NilError(t, stale.Close())
+
+	conf := config.NewServerConfig()
+	conf.Socket = socket
+	conf.SocketMode = "0600"
+	startServer(t, conf)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := waitStatus(client, "http://sablier/health", 5*time.Second)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	fi, err := os.Stat(socket)
+	assert.NilError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestStartRefusesToReplaceRegularFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sablier.sock")
+	assert.NilError(t, os.WriteFile(file, []byte("keep me"), 0o600))
+
+	conf := config.NewServerConfig()
+	conf.Socket = file
+	err := Start(t.Context(), slogt.New(t), conf, config.NewTracingConfig(), testStrategy(t))
+	assert.ErrorContains(t, err, "is not a socket")
+}
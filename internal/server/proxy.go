@@ -0,0 +1,283 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/theme"
+)
+
+// setupProxyRouter returns the handler of the built-in reverse proxy. Every
+// request goes through the proxy; the API is only served on its own listener.
+func setupProxyRouter(logger *slog.Logger, tracingConf config.Tracing, s *api.ServeStrategy) *gin.Engine {
+	r := gin.New()
+	r.Use(otelgin.Middleware(tracingConf.ServiceName))
+	r.Use(StructuredLogger(logger))
+	r.Use(gin.Recovery())
+	r.NoRoute(gin.WrapH(newProxy(logger, s)))
+	return r
+}
+
+// proxy routes requests by host and path to the upstream of the matching
+// route, starting the route's instances on demand and renewing their session
+// on every request.
+type proxy struct {
+	s *api.ServeStrategy
+	l *slog.Logger
+
+	// upstreams holds one reverse proxy per upstream URL, so connections to
+	// an upstream are pooled across the routes sharing it.
+	upstreams map[string]*httputil.ReverseProxy
+}
+
+func newProxy(logger *slog.Logger, s *api.ServeStrategy) *proxy {
+	p := &proxy{
+		s:         s,
+		l:         logger,
+		upstreams: make(map[string]*httputil.ReverseProxy),
+	}
+	for _, route := range s.Routing.Routes {
+		if route.Upstream == "" || p.upstreams[route.Upstream] != nil {
+			continue
+		}
+		// Validated by config.Route.IsValid.
+		target, _ := url.Parse(route.Upstream)
+		p.upstreams[route.Upstream] = &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.SetXForwarded()
+				// Upstreams usually route or build links on the public host.
+				pr.Out.Host = pr.In.Host
+			},
+			// Flush every write so streamed responses (server-sent events,
+			// chunked downloads) reach the client as they are produced.
+			FlushInterval: -1,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				logger.WarnContext(r.Context(), "proxy: upstream request failed",
+					slog.String("upstream", target.String()),
+					slog.Any("error", err),
+				)
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		}
+	}
+	return p
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	route, ok := p.s.Routing.Match(host, r.URL.Path)
+	if !ok || route.Upstream == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Without a route duration, each instance gets its
+	// sablier.session-duration label or the server default.
+	duration := route.SessionDuration
+	p.s.Metrics.RecordSessionRequest("proxy", routeTarget(route))
+
+	var session *sablier.SessionState
+	var err error
+	if route.Strategy == config.RouteStrategyBlocking || !acceptsWaitingPage(r) {
+		// WebSocket upgrades, API calls and form posts cannot be answered
+		// with the waiting page: hold them until the instances are ready.
+		if session, err = requestReadyRouteSession(r.Context(), p.s, route, duration); err != nil {
+			p.fail(w, r, err)
+			return
+		}
+	} else {
+		if session, err = requestRouteSession(r.Context(), p.s, route, duration); err != nil {
+			p.fail(w, r, err)
+			return
+		}
+		if !session.IsReady() {
+			p.writeWaitingPage(w, r, route, host, session)
+			return
+		}
+	}
+
+	// A WebSocket or a streamed response can outlive the session: keep
+	// renewing it for as long as the request is in flight.
+	ctx, stop := context.WithCancel(r.Context())
+	defer stop()
+	go p.renew(ctx, route, duration, renewalPeriod(p.s, route, session))
+
+	p.upstreams[route.Upstream].ServeHTTP(w, r)
+}
+
+// renew requests the session again every half period until ctx is done.
+func (p *proxy) renew(ctx context.Context, route config.Route, duration, period time.Duration) {
+	if period <= 0 {
+		return
+	}
+	ticker := time.NewTicker(period / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := requestRouteSession(ctx, p.s, route, duration); err != nil && ctx.Err() == nil {
+				p.l.WarnContext(ctx, "proxy: could not renew the session of an in-flight request", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (p *proxy) writeWaitingPage(w http.ResponseWriter, r *http.Request, route config.Route, host string, session *sablier.SessionState) {
+	// Render fully before writing, so a broken custom theme answers 500
+	// instead of a truncated page.
+	page, err := renderWaitingPage(p.s, route, host, session)
+	if err != nil {
+		p.l.ErrorContext(r.Context(), "proxy: could not render the waiting page", slog.Any("error", err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+	w.Header().Set("Retry-After", retryAfter(p.s))
+	w.Header().Set(api.SablierStatusHeader, api.SablierStatusNotReady)
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write(page)
+}
+
+func (p *proxy) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) {
+		// The client went away while waiting.
+		return
+	}
+	p.l.WarnContext(r.Context(), "proxy: could not start the route instances",
+		slog.String("host", r.Host),
+		slog.String("path", r.URL.Path),
+		slog.Any("error", err),
+	)
+	status := sessionErrorStatus(err)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// sessionErrorStatus returns the HTTP status answering a failed session
+// request: 404 for unknown instances or groups, 504 when they were not ready
+// in time, and 502 otherwise.
+func sessionErrorStatus(err error) int {
+	_, notManaged := errors.AsType[sablier.ErrInstanceNotManaged](err)
+	_, groupNotFound := errors.AsType[sablier.ErrGroupNotFound](err)
+	_, timeout := errors.AsType[sablier.ErrTimeout](err)
+	switch {
+	case notManaged || groupNotFound:
+		return http.StatusNotFound
+	case timeout || errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// acceptsWaitingPage reports whether the request is a page load that can be
+// answered with the self-refreshing waiting page.
+func acceptsWaitingPage(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if r.Header.Get("Upgrade") != "" {
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// renewalPeriod returns the period over which the session of route must be
+// renewed: the shortest duration given to its instances, else the route
+// duration, else sessions.default-duration.
+func renewalPeriod(s *api.ServeStrategy, route config.Route, session *sablier.SessionState) time.Duration {
+	if d := session.SessionDuration(); d > 0 {
+		return d
+	}
+	if route.SessionDuration > 0 {
+		return route.SessionDuration
+	}
+	return s.SessionsConfig.DefaultDuration
+}
+
+// routeTarget returns the target label of the session request metric.
+func routeTarget(route config.Route) string {
+	if route.Group != "" {
+		return "group"
+	}
+	return "names"
+}
+
+func requestRouteSession(ctx context.Context, s *api.ServeStrategy, route config.Route, duration time.Duration) (*sablier.SessionState, error) {
+	if route.Group != "" {
+		return s.Sablier.RequestSessionGroup(ctx, route.Group, duration)
+	}
+	return s.Sablier.RequestSession(ctx, route.Names, duration)
+}
+
+// requestReadyRouteSession waits for the route's instances to be ready, up to
+// the route timeout or strategy.blocking.default-timeout.
+func requestReadyRouteSession(ctx context.Context, s *api.ServeStrategy, route config.Route, duration time.Duration) (*sablier.SessionState, error) {
+	timeout := s.StrategyConfig.Blocking.DefaultTimeout
+	if route.Timeout > 0 {
+		timeout = route.Timeout
+	}
+	if route.Group != "" {
+		return s.Sablier.RequestReadySessionGroup(ctx, route.Group, duration, timeout)
+	}
+	return s.Sablier.RequestReadySession(ctx, route.Names, duration, timeout)
+}
+
+// renderWaitingPage renders the route's theme for a session that is not ready.
+func renderWaitingPage(s *api.ServeStrategy, route config.Route, host string, session *sablier.SessionState) ([]byte, error) {
+	themeName := s.StrategyConfig.Dynamic.DefaultTheme
+	if route.Theme != "" {
+		themeName = route.Theme
+	}
+	displayName := route.DisplayName
+	if displayName == "" {
+		displayName = host
+	}
+
+	buf := new(bytes.Buffer)
+	err := s.Theme.Render(themeName, theme.Options{
+		DisplayName:      displayName,
+		ShowDetails:      s.StrategyConfig.Dynamic.ShowDetailsByDefault,
+		SessionDuration:  api.ThemeSessionDuration(s, session),
+		RefreshFrequency: s.StrategyConfig.Dynamic.DefaultRefreshFrequency,
+		InstanceStates:   api.ThemeInstances(session),
+		StatusURL:        api.StatusURL(s, route.Names, route.Group),
+	}, buf)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", themeName, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// retryAfter returns the Retry-After header value of the waiting page.
+func retryAfter(s *api.ServeStrategy) string {
+	return strconv.Itoa(int(s.StrategyConfig.Dynamic.DefaultRefreshFrequency.Seconds()))
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/internal/api/apitest"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func notReadySession() *sablier.SessionState {
+	return &sablier.SessionState{Instances: map[string]sablier.InstanceInfoWithError{
+		"test": {Instance: sablier.InstanceInfo{Name: "test", Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}},
+	}}
+}
+
+// proxyServer serves the built-in reverse proxy in front of upstream, with a
+// single route for whoami.example.com needing the "test" instance.
+func proxyServer(t *testing.T, upstream http.Handler, route config.Route) (*httptest.Server, *apitest.MockSablier) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	up := httptest.NewServer(upstream)
+	t.Cleanup(up.Close)
+
+	route.Host = "whoami.example.com"
+	route.Names = []string{"test"}
+	route.Upstream = up.URL
+
+	s := testStrategy(t)
+	m := apitest.NewMockSablier(gomock.NewController(t))
+	s.Sablier = m
+	s.Routing = config.Routing{Routes: []config.Route{route}}
+
+	srv := httptest.NewServer(setupProxyRouter(slogt.New(t), config.Tracing{ServiceName: "sablier"}, s))
+	t.Cleanup(srv.Close)
+	return srv, m
+}
+
+func proxyRequest(t *testing.T, srv *httptest.Server, method, path, accept string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, nil)
+	assert.NilError(t, err)
+	req.Host = "whoami.example.com"
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := srv.Client().Do(req)
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestProxy_ForwardsWhenReady(t *testing.T) {
+	srv, m := proxyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Host+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Forwarded-Host"))
+	}), config.Route{SessionDuration: time.Hour})
+	m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, time.Hour).Return(readySession(), nil)
+
+	resp := proxyRequest(t, srv, http.MethodGet, "/hello?x=1", "text/html")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, "whoami.example.com /hello?x=1 whoami.example.com", string(body))
+}
+
+func TestProxy_WaitingPageWhileStarting(t *testing.T) {
+	srv, m := proxyServer(t, http.NotFoundHandler(), config.Route{})
+	m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, gomock.Any()).Return(notReadySession(), nil)
+
+	resp := proxyRequest(t, srv, http.MethodGet, "/", "text/html,application/xhtml+xml")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "text/html", resp.Header.Get("Content-Type"))
+	assert.Equal(t, api.SablierStatusNotReady, resp.Header.Get(api.SablierStatusHeader))
+}
+
+func TestProxy_NonPageRequestsWaitForReadiness(t *testing.T) {
+	srv, m := proxyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}), config.Route{Timeout: 10 * time.Second})
+	m.EXPECT().RequestReadySession(gomock.Any(), []string{"test"}, gomock.Any(), 10*time.Second).Return(readySession(), nil)
+
+	resp := proxyRequest(t, srv, http.MethodPost, "/api/items", "application/json")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestProxy_Errors(t *testing.T) {
+	t.Run("no route", func(t *testing.T) {
+		srv, _ := proxyServer(t, http.NotFoundHandler(), config.Route{})
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		assert.NilError(t, err)
+		req.Host = "unknown.example.com"
+		resp, err := srv.Client().Do(req)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		srv, m := proxyServer(t, http.NotFoundHandler(), config.Route{Strategy: config.RouteStrategyBlocking})
+		m.EXPECT().RequestReadySession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sablier.ErrTimeout{Duration: time.Second})
+		resp := proxyRequest(t, srv, http.MethodGet, "/", "text/html")
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	})
+	t.Run("upstream failure", func(t *testing.T) {
+		srv, m := proxyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Drop the connection without answering.
+			if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		}), config.Route{})
+		m.EXPECT().RequestSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil)
+		resp := proxyRequest(t, srv, http.MethodGet, "/", "text/html")
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+}
+
+func TestProxy_StreamsAndRenewsSession(t *testing.T) {
+	release := make(chan struct{})
+	srv, m := proxyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "first\n")
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = io.WriteString(w, "second\n")
+	}), config.Route{SessionDuration: 20 * time.Millisecond})
+
+	var renewals atomic.Int32
+	m.EXPECT().RequestSession(gomock.Any(), []string{"test"}, 20*time.Millisecond).DoAndReturn(
+		func(context.Context, []string, time.Duration) (*sablier.SessionState, error) {
+			renewals.Add(1)
+			return readySession(), nil
+		}).MinTimes(3)
+
+	resp := proxyRequest(t, srv, http.MethodGet, "/events", "text/html")
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NilError(t, err)
+	assert.Equal(t, "first\n", line, "the first chunk must arrive before the upstream finishes")
+
+	// The session keeps being renewed while the response streams.
+	deadline := time.Now().Add(5 * time.Second)
+	for renewals.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(release)
+	rest, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, "second\n", string(rest))
+}
+
+func TestProxy_WebSocketUpgrade(t *testing.T) {
+	srv, m := proxyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close() //nolint:errcheck
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		_ = buf.Flush()
+		// Echo one line back.
+		line, _ := buf.ReadString('\n')
+		_, _ = buf.WriteString("echo " + line)
+		_ = buf.Flush()
+	}), config.Route{})
+	// Upgrades cannot be answered with the waiting page, they wait instead.
+	m.EXPECT().RequestReadySession(gomock.Any(), []string{"test"}, gomock.Any(), gomock.Any()).Return(readySession(), nil)
+	m.EXPECT().RequestSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(readySession(), nil).AnyTimes()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	assert.NilError(t, err)
+	defer conn.Close() //nolint:errcheck
+	_, err = io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: whoami.example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nAccept: text/html\r\n\r\n")
+	assert.NilError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	_, err = io.WriteString(conn, "hello\n")
+	assert.NilError(t, err)
+	line, err := reader.ReadString('\n')
+	assert.NilError(t, err)
+	assert.Equal(t, "echo hello\n", line)
+}
+
+func TestAcceptsWaitingPage(t *testing.T) {
+	page := httptest.NewRequest(http.MethodGet, "/", nil)
+	page.Header.Set("Accept", "text/html,application/xhtml+xml")
+	assert.Assert(t, acceptsWaitingPage(page))
+
+	xhr := httptest.NewRequest(http.MethodGet, "/", nil)
+	xhr.Header.Set("Accept", "application/json")
+	assert.Assert(t, !acceptsWaitingPage(xhr))
+
+	post := httptest.NewRequest(http.MethodPost, "/", nil)
+	post.Header.Set("Accept", "text/html")
+	assert.Assert(t, !acceptsWaitingPage(post))
+}
@@ -9,22 +9,40 @@ import (
 	"github.com/sablierapp/sablier/pkg/metrics"
 )
 
-func registerRoutes(ctx context.Context, router *gin.Engine, serverConf config.Server, s *api.ServeStrategy) {
+func registerRoutes(ctx context.Context, router *gin.Engine, serverConf config.Server, auth *authenticator, s *api.ServeStrategy) {
 	router.RedirectTrailingSlash = true
 
 	base := router.Group(serverConf.BasePath)
 
+	// The health endpoint stays reachable without credentials for probes.
 	api.Healthcheck(base, ctx)
 
+	protected := auth.protect(base)
+
 	// Register /metrics only when a real PromRecorder is in use.
 	if rec, ok := s.Metrics.(*metrics.PromRecorder); ok {
-		base.GET("/metrics", gin.WrapH(metrics.NewHandler(rec)))
+		auth.scoped(protected, config.ScopeMetrics, targetNone).GET("/metrics", gin.WrapH(metrics.NewHandler(rec)))
 	}
 
-	APIv1 := base.Group("/api")
-	api.StartDynamic(APIv1, s)
-	api.StartBlocking(APIv1, s)
-	api.StartPoke(APIv1, s)
-	api.ListThemes(APIv1, s)
-	api.InstanceEvents(APIv1, s)
+	APIv1 := protected.Group("/api")
+	api.StartDynamic(auth.scoped(APIv1, config.ScopeStrategies, targetRequest), s)
+	api.SessionStatus(auth.scoped(auth.statusStream(base.Group("/api"), s.StatusTokens), config.ScopeStrategies, targetRequest), s)
+	api.StartBlocking(auth.scoped(APIv1, config.ScopeStrategies, targetRequest), s)
+	api.StartPoke(auth.scoped(APIv1, config.ScopeStrategies, targetRequest), s)
+	// The routing table decides which instances a forward-auth call starts,
+	// so the caller must be granted all of them.
+	api.StartForwardAuth(auth.scoped(APIv1, config.ScopeStrategies, targetAll), s)
+	api.ListThemes(auth.scoped(APIv1, config.ScopeThemes, targetNone), s)
+	api.InstanceEvents(auth.scoped(APIv1, config.ScopeEvents, targetAll), s)
+	api.ListInstances(auth.scoped(APIv1, config.ScopeInstances, targetAll), s)
+	api.GetInstance(auth.scoped(APIv1, config.ScopeInstances, targetInstance), s)
+	api.ListSessions(auth.scoped(APIv1, config.ScopeSessions, targetAll), s)
+	api.ListGroups(auth.scoped(APIv1, config.ScopeGroups, targetAll), s)
+	api.ExpireSession(auth.scoped(APIv1, config.ScopeSessions, targetInstance), s)
+	api.ExtendSession(auth.scoped(APIv1, config.ScopeSessions, targetInstance), s)
+	api.Usage(auth.scoped(APIv1, config.ScopeSessions, targetAll), s)
+	// Pinning keeps instances running: it needs the sessions write scope,
+	// not the instances and groups listing ones.
+	api.PinInstance(auth.scoped(APIv1, config.ScopeSessions, targetInstance), s)
+	api.PinGroup(auth.scoped(APIv1, config.ScopeSessions, targetGroup), s)
 }
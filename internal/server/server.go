@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
 
 	"github.com/sablierapp/sablier/internal/api"
 	"github.com/sablierapp/sablier/pkg/config"
@@ -24,7 +27,7 @@ func setupRouter(ctx context.Context, logger *slog.Logger, serverConf config.Ser
 	r.Use(StructuredLogger(logger))
 	r.Use(gin.Recovery())
 
-	registerRoutes(ctx, r, serverConf, s)
+	registerRoutes(ctx, r, serverConf, newAuthenticator(logger, serverConf.Auth), s)
 
 	return r
 }
@@ -37,13 +40,18 @@ const (
 	drainGrace      = 5 * time.Second
 )
 
-// Start runs the HTTP server until ctx is cancelled, then drains in-flight
-// requests before returning. It returns nil after a clean shutdown, or the
-// fatal serve error (e.g. the port is already in use) so the caller can
+// Start runs the HTTP server, and the built-in reverse proxy and the Envoy
+// ext_authz gRPC server when enabled, until ctx is cancelled, then drains
+// in-flight requests before returning. It returns nil after a clean shutdown,
+// or the fatal serve error (e.g. the port is already in use) so the caller can
 // terminate the process instead of running on without a listener.
 func Start(ctx context.Context, logger *slog.Logger, serverConf config.Server, tracingConf config.Tracing, s *api.ServeStrategy) error {
 	start := time.Now()
 
+	if err := serverConf.IsValid(); err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+
 	if logger.Enabled(ctx, slog.LevelDebug) {
 		gin.SetMode(gin.DebugMode)
 	} else {
@@ -53,26 +61,102 @@ func Start(ctx context.Context, logger *slog.Logger, serverConf config.Server, t
 	r := setupRouter(ctx, logger, serverConf, tracingConf, s)
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", serverConf.Port),
 		Handler: r,
 	}
+	if serverConf.TLS.Enabled() {
+		tlsConf, err := newTLSConfig(logger, serverConf.TLS)
+		if err != nil {
+			return fmt.Errorf("server: %w", err)
+		}
+		server.TLSConfig = tlsConf
+	}
+
+	l, err := listen(serverConf)
+	if err != nil {
+		// The listener could not bind (port in use, bad socket path, ...).
+		return fmt.Errorf("server: %w", err)
+	}
 
 	logger.Info("starting ",
-		slog.String("listen", server.Addr),
+		slog.String("listen", listenAddr(serverConf)),
+		slog.Bool("tls", serverConf.TLS.Enabled()),
 		slog.Duration("startup", time.Since(start)),
 		slog.String("mode", gin.Mode()),
 	)
 
-	errC := make(chan error, 1)
+	errC := make(chan error, 3)
 	go func() {
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if server.TLSConfig != nil {
+			// The certificate comes from TLSConfig.GetCertificate.
+			err = server.ServeTLS(l, "", "")
+		} else {
+			err = server.Serve(l)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errC <- err
 		}
 	}()
 
+	servers := []*http.Server{server}
+	if serverConf.Proxy.Enabled() {
+		proxyServer := &http.Server{Handler: setupProxyRouter(logger, tracingConf, s)}
+		pl, err := net.Listen("tcp", fmt.Sprintf(":%d", serverConf.Proxy.Port))
+		if err != nil {
+			_ = server.Close()
+			return fmt.Errorf("server: proxy: %w", err)
+		}
+		logger.Info("starting reverse proxy",
+			slog.String("listen", pl.Addr().String()),
+			slog.Int("routes", len(s.Routing.Routes)),
+		)
+		go func() {
+			if err := proxyServer.Serve(pl); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errC <- fmt.Errorf("proxy: %w", err)
+			}
+		}()
+		servers = append(servers, proxyServer)
+	}
+
+	var extAuthzServer *grpc.Server
+	if serverConf.ExtAuthz.Enabled() {
+		extAuthzServer, err = newExtAuthzServer(logger, s, serverConf.ExtAuthz)
+		if err != nil {
+			for _, srv := range servers {
+				_ = srv.Close()
+			}
+			return fmt.Errorf("server: ext_authz: %w", err)
+		}
+		el, err := net.Listen("tcp", fmt.Sprintf(":%d", serverConf.ExtAuthz.Port))
+		if err != nil {
+			for _, srv := range servers {
+				_ = srv.Close()
+			}
+			return fmt.Errorf("server: ext_authz: %w", err)
+		}
+		logger.Info("starting Envoy ext_authz gRPC server",
+			slog.String("listen", el.Addr().String()),
+			slog.Bool("tls", serverConf.ExtAuthz.TLS.Enabled()),
+			slog.Bool("client_certificates", serverConf.ExtAuthz.TLS.ClientCAFile != ""),
+		)
+		if serverConf.ExtAuthz.TLS.ClientCAFile == "" {
+			logger.Warn("the ext_authz gRPC server does not authenticate its clients: anyone reaching its port can start instances; set server.ext-authz.tls.client-ca-file or keep the port private to the proxy")
+		}
+		go func() {
+			if err := extAuthzServer.Serve(el); err != nil {
+				errC <- fmt.Errorf("ext_authz: %w", err)
+			}
+		}()
+	}
+
 	select {
 	case err := <-errC:
-		// ListenAndServe failed outright (port in use, bad address, ...).
+		for _, srv := range servers {
+			_ = srv.Close()
+		}
+		if extAuthzServer != nil {
+			extAuthzServer.Stop()
+		}
 		return fmt.Errorf("server: %w", err)
 	case <-ctx.Done():
 	}
@@ -86,11 +170,31 @@ func Start(ctx context.Context, logger *slog.Logger, serverConf config.Server, t
 	logger.Info("server: shutting down, draining in-flight requests", slog.Duration("drain_timeout", drain))
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), drain)
 	defer cancel()
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("server: drain did not complete in time, closing remaining connections", slog.Any("error", err))
-		_ = server.Close()
-		return nil
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Go(func() {
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logger.Error("server: drain did not complete in time, closing remaining connections", slog.Any("error", err))
+				_ = srv.Close()
+			}
+		})
+	}
+	if extAuthzServer != nil {
+		wg.Go(func() {
+			stopped := make(chan struct{})
+			go func() {
+				extAuthzServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				logger.Error("server: ext_authz drain did not complete in time, closing remaining connections")
+				extAuthzServer.Stop()
+			}
+		})
 	}
+	wg.Wait()
 	logger.Info("server: shutdown complete")
 	return nil
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// Auth endpoints a credential can be scoped to. Each names an area of the
+// HTTP API; ScopeAll grants every one of them.
+const (
+	ScopeAll        = "*"
+	ScopeStrategies = "strategies"
+	ScopeEvents     = "events"
+	ScopeThemes     = "themes"
+	ScopeInstances  = "instances"
+	ScopeSessions   = "sessions"
+	ScopeGroups     = "groups"
+	ScopeMetrics    = "metrics"
+)
+
+// AuthEndpoints lists the endpoint scopes a credential can be granted.
+func AuthEndpoints() []string {
+	return []string{ScopeStrategies, ScopeEvents, ScopeThemes, ScopeInstances, ScopeSessions, ScopeGroups, ScopeMetrics}
+}
+
+// Auth holds the HTTP API authentication configuration. When at least one
+// token or client is configured, every route except /health requires a
+// credential, and each credential is limited to its scopes.
+// Auth is configured via the YAML configuration file only;
+// there is no corresponding CLI flag or environment variable.
+type Auth struct {
+	// Tokens are the bearer tokens accepted in the Authorization header.
+	Tokens []AuthToken
+
+	// Clients are the TLS client certificates accepted for mutual TLS.
+	// A client is recognised once the server verified its certificate.
+	Clients []AuthClient
+}
+
+// AuthToken is a bearer token and what it grants.
+type AuthToken struct {
+	// Name identifies the token in logs. Required and unique.
+	Name string
+
+	// Token is the secret the client sends as "Authorization: Bearer <token>". Required.
+	Token string
+
+	// Scopes limits what the token grants.
+	Scopes AuthScopes
+}
+
+// AuthClient is a TLS client certificate identity and what it grants.
+type AuthClient struct {
+	// Subject is matched against the verified certificate's common name and
+	// its DNS, URI and email subject alternative names. Required and unique.
+	Subject string
+
+	// Scopes limits what the client grants.
+	Scopes AuthScopes
+}
+
+// AuthScopes limits what a credential grants. An empty list grants nothing.
+type AuthScopes struct {
+	// Endpoints lists the API areas the credential can call: strategies,
+	// events, themes, instances, sessions, groups and metrics, or "*" for all.
+	Endpoints []string
+
+	// Instances lists the instance names the credential can act on, as glob
+	// patterns (e.g. "whoami-*"), or "*" for all.
+	Instances []string
+
+	// Groups lists the group names the credential can act on, as glob
+	// patterns, or "*" for all.
+	Groups []string
+}
+
+func NewAuthConfig() Auth {
+	return Auth{}
+}
+
+// Enabled reports whether the API requires authentication.
+func (a Auth) Enabled() bool {
+	return len(a.Tokens) > 0 || len(a.Clients) > 0
+}
+
+func (a Auth) IsValid() error {
+	names := make(map[string]bool, len(a.Tokens))
+	for i, t := range a.Tokens {
+		if t.Name == "" {
+			return fmt.Errorf("server.auth.tokens[%d]: name is required", i)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("server.auth.tokens[%d]: duplicate name %q", i, t.Name)
+		}
+		names[t.Name] = true
+		if t.Token == "" {
+			return fmt.Errorf("server.auth.tokens[%d] (%s): token is required", i, t.Name)
+		}
+		if err := t.Scopes.IsValid(); err != nil {
+			return fmt.Errorf("server.auth.tokens[%d] (%s): %w", i, t.Name, err)
+		}
+	}
+
+	subjects := make(map[string]bool, len(a.Clients))
+	for i, c := range a.Clients {
+		if c.Subject == "" {
+			return fmt.Errorf("server.auth.clients[%d]: subject is required", i)
+		}
+		if subjects[c.Subject] {
+			return fmt.Errorf("server.auth.clients[%d]: duplicate subject %q", i, c.Subject)
+		}
+		subjects[c.Subject] = true
+		if err := c.Scopes.IsValid(); err != nil {
+			return fmt.Errorf("server.auth.clients[%d] (%s): %w", i, c.Subject, err)
+		}
+	}
+	return nil
+}
+
+func (s AuthScopes) IsValid() error {
+	for _, e := range s.Endpoints {
+		if e != ScopeAll && !slices.Contains(AuthEndpoints(), e) {
+			return fmt.Errorf("unknown endpoint scope %q, expected one of %v or %q", e, AuthEndpoints(), ScopeAll)
+		}
+	}
+	for _, p := range slices.Concat(s.Instances, s.Groups) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// AllowsEndpoint reports whether the scopes grant the given endpoint.
+func (s AuthScopes) AllowsEndpoint(endpoint string) bool {
+	return slices.Contains(s.Endpoints, ScopeAll) || slices.Contains(s.Endpoints, endpoint)
+}
+
+// AllowsInstance reports whether the scopes grant the given instance.
+func (s AuthScopes) AllowsInstance(name string) bool {
+	return matchAny(s.Instances, name)
+}
+
+// AllowsGroup reports whether the scopes grant the given group.
+func (s AuthScopes) AllowsGroup(name string) bool {
+	return matchAny(s.Groups, name)
+}
+
+// AllowsEverything reports whether the scopes grant every instance and every
+// group, as required by the endpoints that list or stream all of them.
+func (s AuthScopes) AllowsEverything() bool {
+	return slices.Contains(s.Instances, ScopeAll) && slices.Contains(s.Groups, ScopeAll)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		// "*" also matches names containing a "/" (e.g. Kubernetes names).
+		if p == ScopeAll {
+			return true
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
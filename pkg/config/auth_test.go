@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAuth_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    Auth
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			auth: NewAuthConfig(),
+		},
+		{
+			name: "token and client",
+			auth: Auth{
+				Tokens:  []AuthToken{{Name: "traefik", Token: "secret", Scopes: AuthScopes{Endpoints: []string{"strategies"}, Groups: []string{"blog-*"}}}},
+				Clients: []AuthClient{{Subject: "admin.example.com", Scopes: AuthScopes{Endpoints: []string{"*"}}}},
+			},
+		},
+		{
+			name:    "token without name",
+			auth:    Auth{Tokens: []AuthToken{{Token: "secret"}}},
+			wantErr: "server.auth.tokens[0]: name is required",
+		},
+		{
+			name:    "token without secret",
+			auth:    Auth{Tokens: []AuthToken{{Name: "traefik"}}},
+			wantErr: "server.auth.tokens[0] (traefik): token is required",
+		},
+		{
+			name:    "duplicate token name",
+			auth:    Auth{Tokens: []AuthToken{{Name: "traefik", Token: "a"}, {Name: "traefik", Token: "b"}}},
+			wantErr: `server.auth.tokens[1]: duplicate name "traefik"`,
+		},
+		{
+			name:    "unknown endpoint",
+			auth:    Auth{Tokens: []AuthToken{{Name: "traefik", Token: "a", Scopes: AuthScopes{Endpoints: []string{"admin"}}}}},
+			wantErr: `server.auth.tokens[0] (traefik): unknown endpoint scope "admin"`,
+		},
+		{
+			name:    "invalid pattern",
+			auth:    Auth{Clients: []AuthClient{{Subject: "proxy", Scopes: AuthScopes{Instances: []string{"web-["}}}}},
+			wantErr: `server.auth.clients[0] (proxy): invalid pattern "web-["`,
+		},
+		{
+			name:    "client without subject",
+			auth:    Auth{Clients: []AuthClient{{}}},
+			wantErr: "server.auth.clients[0]: subject is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthScopes_Allows(t *testing.T) {
+	scopes := AuthScopes{
+		Endpoints: []string{ScopeStrategies},
+		Instances: []string{"blog-*"},
+		Groups:    []string{"blog"},
+	}
+	assert.Assert(t, scopes.AllowsEndpoint(ScopeStrategies))
+	assert.Assert(t, !scopes.AllowsEndpoint(ScopeEvents))
+	assert.Assert(t, scopes.AllowsInstance("blog-web"))
+	assert.Assert(t, !scopes.AllowsInstance("db"))
+	assert.Assert(t, scopes.AllowsGroup("blog"))
+	assert.Assert(t, !scopes.AllowsGroup("blog-admin"))
+	assert.Assert(t, !scopes.AllowsEverything())
+
+	all := AuthScopes{Instances: []string{ScopeAll}, Groups: []string{ScopeAll}}
+	assert.Assert(t, all.AllowsInstance("deployment/default/whoami"))
+	assert.Assert(t, all.AllowsEverything())
+	assert.Assert(t, !AuthScopes{}.AllowsInstance("whoami"))
+}
@@ -0,0 +1,72 @@
+package config
+
+import "fmt"
+
+const (
+	CapacityPolicyEvict = "evict"
+	CapacityPolicyQueue = "queue"
+)
+
+var capacityPolicies = []string{CapacityPolicyEvict, CapacityPolicyQueue}
+
+// Capacity limits the number of instances active at once, an instance being
+// active while it holds a session.
+type Capacity struct {
+	// MaxActive is the maximum number of instances active at once. Zero means
+	// no limit.
+	// Env: SABLIER_CAPACITY_MAX_ACTIVE
+	// CLI: --capacity.max-active
+	// Default: 0
+	// Since: NEXT_RELEASE
+	MaxActive int
+
+	// Policy is what a request starting an instance over capacity does: "evict"
+	// stops the least recently used active instances to make room, "queue"
+	// waits until enough sessions end.
+	// Env: SABLIER_CAPACITY_POLICY
+	// CLI: --capacity.policy
+	// Default: "evict"
+	// Since: NEXT_RELEASE
+	Policy string
+
+	// Groups limits the number of active instances of groups, on top of
+	// MaxActive. Groups are configured via the YAML configuration file only.
+	Groups []GroupCapacity
+}
+
+// GroupCapacity is the maximum number of instances of a group active at once.
+type GroupCapacity struct {
+	Group     string
+	MaxActive int `mapstructure:"max-active"`
+}
+
+func NewCapacityConfig() Capacity {
+	return Capacity{
+		Policy: CapacityPolicyEvict,
+	}
+}
+
+func (c Capacity) IsValid() error {
+	if c.MaxActive < 0 {
+		return fmt.Errorf("capacity.max-active must not be negative, got %d", c.MaxActive)
+	}
+	switch c.Policy {
+	case CapacityPolicyEvict, CapacityPolicyQueue:
+	default:
+		return fmt.Errorf("unrecognized capacity policy %s. policies available: %v", c.Policy, capacityPolicies)
+	}
+	seen := map[string]struct{}{}
+	for i, g := range c.Groups {
+		if g.Group == "" {
+			return fmt.Errorf("capacity.groups[%d]: group must not be empty", i)
+		}
+		if g.MaxActive <= 0 {
+			return fmt.Errorf("capacity.groups[%d]: max-active must be positive, got %d", i, g.MaxActive)
+		}
+		if _, ok := seen[g.Group]; ok {
+			return fmt.Errorf("capacity.groups[%d]: group %q is limited twice", i, g.Group)
+		}
+		seen[g.Group] = struct{}{}
+	}
+	return nil
+}
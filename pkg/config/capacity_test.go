@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCapacity_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity Capacity
+		wantErr  string
+	}{
+		{name: "default", capacity: NewCapacityConfig()},
+		{name: "limits", capacity: Capacity{MaxActive: 5, Policy: CapacityPolicyQueue, Groups: []GroupCapacity{{Group: "gpu", MaxActive: 1}}}},
+		{name: "negative max active", capacity: Capacity{MaxActive: -1, Policy: CapacityPolicyEvict}, wantErr: "capacity.max-active must not be negative, got -1"},
+		{name: "unknown policy", capacity: Capacity{Policy: "drop"}, wantErr: "unrecognized capacity policy drop. policies available: [evict queue]"},
+		{name: "group without name", capacity: Capacity{Policy: CapacityPolicyEvict, Groups: []GroupCapacity{{MaxActive: 1}}}, wantErr: "capacity.groups[0]: group must not be empty"},
+		{name: "group without limit", capacity: Capacity{Policy: CapacityPolicyEvict, Groups: []GroupCapacity{{Group: "gpu"}}}, wantErr: "capacity.groups[0]: max-active must be positive, got 0"},
+		{name: "group limited twice", capacity: Capacity{Policy: CapacityPolicyEvict, Groups: []GroupCapacity{{Group: "gpu", MaxActive: 1}, {Group: "gpu", MaxActive: 2}}}, wantErr: `capacity.groups[1]: group "gpu" is limited twice`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.capacity.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
@@ -9,6 +9,16 @@ type Config struct {
 	Strategy Strategy
 	Webhooks Webhooks
 	Tracing  Tracing
+	Routing  Routing
+	L4       L4
+
+	LeaderElection LeaderElection
+	RunningHours   RunningHours
+	Capacity       Capacity
+	Reload         Reload
+
+	Groups   []Group
+	Policies []Policy
 }
 
 func NewConfig() Config {
@@ -21,5 +31,12 @@ func NewConfig() Config {
 		Strategy: NewStrategyConfig(),
 		Webhooks: NewWebhooksConfig(),
 		Tracing:  NewTracingConfig(),
+		Routing:  NewRoutingConfig(),
+		L4:       NewL4Config(),
+
+		LeaderElection: NewLeaderElectionConfig(),
+		RunningHours:   NewRunningHoursConfig(),
+		Capacity:       NewCapacityConfig(),
+		Reload:         NewReloadConfig(),
 	}
 }
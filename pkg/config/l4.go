@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// L4 configures the TCP and UDP listeners that wake non-HTTP workloads
+// (databases, game servers, SSH, ...) when their own clients connect.
+// L4 is configured via the YAML configuration file only;
+// there is no corresponding CLI flag or environment variable.
+type L4 struct {
+	Listeners []Listener
+}
+
+// Listener accepts connections on Listen, starts the instances or group it
+// maps to, holds the client until they are ready, then forwards the traffic
+// to Backend. Traffic flowing through the listener keeps the session alive.
+type Listener struct {
+	// Name identifies the listener in logs and metrics. Defaults to Listen.
+	Name string
+
+	// Protocol is "tcp" or "udp". Defaults to "tcp".
+	Protocol string
+
+	// Listen is the address to listen on, e.g. ":5432".
+	Listen string
+
+	// Backend is the address traffic is forwarded to once the instances are
+	// ready, e.g. "postgres:5432".
+	Backend string
+
+	// Names lists the instances the listener needs. Mutually exclusive with Group.
+	Names []string
+
+	// Group is the group the listener needs. Mutually exclusive with Names.
+	Group string
+
+	// SessionDuration is the session duration of the instances of this
+	// listener. When unset, the session duration label of each instance
+	// applies, falling back to sessions.default-duration.
+	SessionDuration time.Duration `mapstructure:"session-duration"`
+
+	// Timeout overrides strategy.blocking.default-timeout: how long a client
+	// is held while the instances start before it is disconnected.
+	Timeout time.Duration
+
+	// IdleTimeout is how long a UDP flow (the datagrams from one client
+	// address) is kept without traffic. Defaults to 1m. Ignored for TCP.
+	IdleTimeout time.Duration `mapstructure:"idle-timeout"`
+
+	// MaxConnections is the number of connections (UDP flows) the listener
+	// holds or forwards at once. Connections beyond it are closed on accept,
+	// and the datagrams of new flows are dropped. Defaults to 1024.
+	MaxConnections int `mapstructure:"max-connections"`
+}
+
+const (
+	ListenerProtocolTCP = "tcp"
+	ListenerProtocolUDP = "udp"
+)
+
+func NewL4Config() L4 {
+	return L4{}
+}
+
+func (l L4) IsValid() error {
+	names := make(map[string]int, len(l.Listeners))
+	for i, listener := range l.Listeners {
+		if err := listener.IsValid(); err != nil {
+			return fmt.Errorf("l4.listeners[%d]: %w", i, err)
+		}
+		name := listener.DisplayName()
+		if j, ok := names[name]; ok {
+			return fmt.Errorf("l4.listeners[%d]: name %q is already used by l4.listeners[%d]", i, name, j)
+		}
+		names[name] = i
+	}
+	return nil
+}
+
+func (l Listener) IsValid() error {
+	if len(l.Names) == 0 && l.Group == "" {
+		return fmt.Errorf("names or group must be set")
+	}
+	if len(l.Names) > 0 && l.Group != "" {
+		return fmt.Errorf("names and group are mutually exclusive")
+	}
+	switch l.Protocol {
+	case "", ListenerProtocolTCP, ListenerProtocolUDP:
+	default:
+		return fmt.Errorf("protocol must be one of [%s, %s], got %q", ListenerProtocolTCP, ListenerProtocolUDP, l.Protocol)
+	}
+	if _, _, err := net.SplitHostPort(l.Listen); err != nil {
+		return fmt.Errorf("listen must be a host:port address, got %q", l.Listen)
+	}
+	if host, port, err := net.SplitHostPort(l.Backend); err != nil || host == "" || port == "" {
+		return fmt.Errorf("backend must be a host:port address, got %q", l.Backend)
+	}
+	if l.SessionDuration < 0 {
+		return fmt.Errorf("session-duration must not be negative, got %s", l.SessionDuration)
+	}
+	if l.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", l.Timeout)
+	}
+	if l.IdleTimeout < 0 {
+		return fmt.Errorf("idle-timeout must not be negative, got %s", l.IdleTimeout)
+	}
+	if l.MaxConnections < 0 {
+		return fmt.Errorf("max-connections must not be negative, got %d", l.MaxConnections)
+	}
+	return nil
+}
+
+// DisplayName returns the name of the listener, defaulting to its address.
+func (l Listener) DisplayName() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.Listen
+}
+
+// Network returns the protocol of the listener, defaulting to "tcp".
+func (l Listener) Network() string {
+	if l.Protocol == "" {
+		return ListenerProtocolTCP
+	}
+	return l.Protocol
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestL4_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		listener Listener
+		wantErr  string
+	}{
+		{name: "tcp", listener: Listener{Listen: ":5432", Backend: "postgres:5432", Names: []string{"postgres"}}},
+		{name: "udp group", listener: Listener{Protocol: ListenerProtocolUDP, Listen: "0.0.0.0:19132", Backend: "minecraft:19132", Group: "games"}},
+		{name: "no target", listener: Listener{Listen: ":5432", Backend: "postgres:5432"}, wantErr: "names or group must be set"},
+		{name: "both targets", listener: Listener{Listen: ":5432", Backend: "postgres:5432", Names: []string{"postgres"}, Group: "db"}, wantErr: "names and group are mutually exclusive"},
+		{name: "unknown protocol", listener: Listener{Protocol: "sctp", Listen: ":5432", Backend: "postgres:5432", Group: "db"}, wantErr: "protocol must be one of"},
+		{name: "port only", listener: Listener{Listen: "5432", Backend: "postgres:5432", Group: "db"}, wantErr: "listen must be a host:port address"},
+		{name: "backend without host", listener: Listener{Listen: ":5432", Backend: ":5432", Group: "db"}, wantErr: "backend must be a host:port address"},
+		{name: "negative idle timeout", listener: Listener{Listen: ":5432", Backend: "postgres:5432", Group: "db", IdleTimeout: -1}, wantErr: "idle-timeout must not be negative"},
+		{name: "negative max connections", listener: Listener{Listen: ":5432", Backend: "postgres:5432", Group: "db", MaxConnections: -1}, wantErr: "max-connections must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := L4{Listeners: []Listener{tt.listener}}.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, "l4.listeners[0]: "+tt.wantErr)
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := L4{Listeners: []Listener{
+			{Listen: ":5432", Backend: "postgres:5432", Group: "db"},
+			{Listen: ":5432", Backend: "replica:5432", Group: "db"},
+		}}.IsValid()
+		assert.ErrorContains(t, err, `l4.listeners[1]: name ":5432" is already used by l4.listeners[0]`)
+	})
+}
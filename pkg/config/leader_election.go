@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	LeaderElectionBackendKubernetes = "kubernetes"
+	LeaderElectionBackendValkey     = "valkey"
+)
+
+var leaderElectionBackends = []string{LeaderElectionBackendKubernetes, LeaderElectionBackendValkey}
+
+// serviceAccountNamespaceFile holds the namespace of the pod in Kubernetes.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// LeaderElection holds the leader election configuration used to run several
+// Sablier replicas side by side. Every replica serves strategy requests, but
+// only the elected leader runs the background loops that stop and start
+// instances on their own: session expirations, running hours, anti-affinity,
+// externally started instances and the stop of unregistered instances.
+type LeaderElection struct {
+	// Enabled turns leader election on. It requires a session storage shared by
+	// every replica (storage.valkey or storage.kubernetes).
+	// Env: SABLIER_LEADER_ELECTION_ENABLED
+	// CLI: --leader-election.enabled
+	// Default: false
+	// Since: NEXT_RELEASE
+	Enabled bool
+
+	// Backend selects where the leader lock is kept.
+	// Accepted values: "kubernetes" (a coordination.k8s.io Lease), "valkey"
+	// (a key in the session storage).
+	// Env: SABLIER_LEADER_ELECTION_BACKEND
+	// CLI: --leader-election.backend
+	// Default: "kubernetes"
+	// Since: NEXT_RELEASE
+	Backend string
+
+	// Identity names this replica in the lock. It must be unique across replicas.
+	// Env: SABLIER_LEADER_ELECTION_IDENTITY
+	// CLI: --leader-election.identity
+	// Default: "" (the hostname)
+	// Since: NEXT_RELEASE
+	Identity string
+
+	// LeaseDuration is how long the lock stays valid without being renewed.
+	// Followers wait this long before taking over from a leader that died.
+	// Env: SABLIER_LEADER_ELECTION_LEASE_DURATION
+	// CLI: --leader-election.lease-duration
+	// Default: 15s
+	// Since: NEXT_RELEASE
+	LeaseDuration time.Duration
+
+	// RenewDeadline is how long the leader keeps leading without renewing the
+	// lock. Past it, the leader steps down. It must be lower than LeaseDuration.
+	// Env: SABLIER_LEADER_ELECTION_RENEW_DEADLINE
+	// CLI: --leader-election.renew-deadline
+	// Default: 10s
+	// Since: NEXT_RELEASE
+	RenewDeadline time.Duration
+
+	// RetryPeriod is how often the leader renews the lock and followers try to
+	// acquire it. It must be lower than RenewDeadline.
+	// Env: SABLIER_LEADER_ELECTION_RETRY_PERIOD
+	// CLI: --leader-election.retry-period
+	// Default: 2s
+	// Since: NEXT_RELEASE
+	RetryPeriod time.Duration
+
+	Kubernetes LeaderElectionKubernetes
+	Valkey     LeaderElectionValkey
+}
+
+// LeaderElectionKubernetes configures the Lease used by the "kubernetes" backend.
+// Sablier needs get, create and update on leases in the namespace.
+type LeaderElectionKubernetes struct {
+	// Namespace of the Lease.
+	// Env: SABLIER_LEADER_ELECTION_KUBERNETES_NAMESPACE
+	// CLI: --leader-election.kubernetes.namespace
+	// Default: "" (the namespace of the Sablier pod, or "default")
+	// Since: NEXT_RELEASE
+	Namespace string
+
+	// Name of the Lease.
+	// Env: SABLIER_LEADER_ELECTION_KUBERNETES_NAME
+	// CLI: --leader-election.kubernetes.name
+	// Default: "sablier"
+	// Since: NEXT_RELEASE
+	Name string
+}
+
+// LeaderElectionValkey configures the key used by the "valkey" backend. The
+// key is kept in the database of the session storage.
+type LeaderElectionValkey struct {
+	// Key holding the identity of the leader. It is not prefixed with
+	// storage.valkey.key-prefix; give each Sablier deployment its own key.
+	// Env: SABLIER_LEADER_ELECTION_VALKEY_KEY
+	// CLI: --leader-election.valkey.key
+	// Default: "sablier:leader"
+	// Since: NEXT_RELEASE
+	Key string
+}
+
+func NewLeaderElectionConfig() LeaderElection {
+	return LeaderElection{
+		Enabled:       false,
+		Backend:       LeaderElectionBackendKubernetes,
+		Identity:      "",
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+		Kubernetes: LeaderElectionKubernetes{
+			Namespace: "",
+			Name:      "sablier",
+		},
+		Valkey: LeaderElectionValkey{
+			Key: "sablier:leader",
+		},
+	}
+}
+
+// ReplicaIdentity returns the configured identity, or the hostname, which is
+// the pod name in Kubernetes.
+func (le LeaderElection) ReplicaIdentity() string {
+	if le.Identity != "" {
+		return le.Identity
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// LeaseNamespace returns the configured namespace, or the namespace Sablier
+// runs in when it runs in a Kubernetes pod, and "default" otherwise.
+func (k LeaderElectionKubernetes) LeaseNamespace() string {
+	if k.Namespace != "" {
+		return k.Namespace
+	}
+	return podNamespace()
+}
+
+// podNamespace returns the namespace Sablier runs in when it runs in a
+// Kubernetes pod, and "default" otherwise.
+func podNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if b, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(b)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
+
+// IsValid checks the leader election settings against the storage they
+// require. A disabled election is always valid.
+func (le LeaderElection) IsValid(storage Storage) error {
+	if !le.Enabled {
+		return nil
+	}
+	if !storage.Shared() {
+		return fmt.Errorf("leader-election.enabled requires a storage shared by every replica (storage.valkey or storage.kubernetes)")
+	}
+	switch le.Backend {
+	case LeaderElectionBackendKubernetes:
+		if le.Kubernetes.Name == "" {
+			return fmt.Errorf("leader-election.kubernetes.name must not be empty")
+		}
+	case LeaderElectionBackendValkey:
+		if !storage.Valkey.Enabled() {
+			return fmt.Errorf("leader-election.backend valkey requires storage.valkey.addresses")
+		}
+		if le.Valkey.Key == "" {
+			return fmt.Errorf("leader-election.valkey.key must not be empty")
+		}
+	default:
+		return fmt.Errorf("unrecognized leader election backend %s. backends available: %v", le.Backend, leaderElectionBackends)
+	}
+	if le.ReplicaIdentity() == "" {
+		return fmt.Errorf("leader-election.identity must be set when the hostname is unknown")
+	}
+	if le.RetryPeriod <= 0 {
+		return fmt.Errorf("leader-election.retry-period must be positive, got %s", le.RetryPeriod)
+	}
+	if le.RenewDeadline <= le.RetryPeriod {
+		return fmt.Errorf("leader-election.renew-deadline (%s) must be greater than leader-election.retry-period (%s)", le.RenewDeadline, le.RetryPeriod)
+	}
+	if le.LeaseDuration <= le.RenewDeadline {
+		return fmt.Errorf("leader-election.lease-duration (%s) must be greater than leader-election.renew-deadline (%s)", le.LeaseDuration, le.RenewDeadline)
+	}
+	// The Lease records its duration in whole seconds.
+	if le.Backend == LeaderElectionBackendKubernetes && le.LeaseDuration < time.Second {
+		return fmt.Errorf("leader-election.lease-duration must be at least 1s with the kubernetes backend, got %s", le.LeaseDuration)
+	}
+	return nil
+}
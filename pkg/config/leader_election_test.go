@@ -0,0 +1,129 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLeaderElection_IsValid(t *testing.T) {
+	shared := Storage{Valkey: Valkey{Addresses: []string{"valkey:6379"}}}
+	enabled := func(mutate func(*LeaderElection)) LeaderElection {
+		le := NewLeaderElectionConfig()
+		le.Enabled = true
+		le.Identity = "sablier-0"
+		if mutate != nil {
+			mutate(&le)
+		}
+		return le
+	}
+
+	tests := []struct {
+		name     string
+		election LeaderElection
+		storage  Storage
+		wantErr  string
+	}{
+		{
+			name:     "disabled by default",
+			election: NewLeaderElectionConfig(),
+			storage:  NewStorageConfig(),
+		},
+		{
+			name:     "kubernetes backend",
+			election: enabled(nil),
+			storage:  shared,
+		},
+		{
+			name:     "valkey backend",
+			election: enabled(func(le *LeaderElection) { le.Backend = LeaderElectionBackendValkey }),
+			storage:  shared,
+		},
+		{
+			name:     "requires a shared storage",
+			election: enabled(nil),
+			storage:  Storage{File: "/tmp/state.json"},
+			wantErr:  "leader-election.enabled requires a storage shared by every replica (storage.valkey or storage.kubernetes)",
+		},
+		{
+			name:     "kubernetes storage",
+			election: enabled(nil),
+			storage:  Storage{Kubernetes: StorageKubernetes{Enabled: true, NamePrefix: "sablier-session-"}},
+		},
+		{
+			name:     "valkey backend requires the valkey storage",
+			election: enabled(func(le *LeaderElection) { le.Backend = LeaderElectionBackendValkey }),
+			storage:  Storage{Kubernetes: StorageKubernetes{Enabled: true, NamePrefix: "sablier-session-"}},
+			wantErr:  "leader-election.backend valkey requires storage.valkey.addresses",
+		},
+		{
+			name:     "unknown backend",
+			election: enabled(func(le *LeaderElection) { le.Backend = "etcd" }),
+			storage:  shared,
+			wantErr:  "unrecognized leader election backend etcd. backends available: [kubernetes valkey]",
+		},
+		{
+			name:     "empty lease name",
+			election: enabled(func(le *LeaderElection) { le.Kubernetes.Name = "" }),
+			storage:  shared,
+			wantErr:  "leader-election.kubernetes.name must not be empty",
+		},
+		{
+			name: "empty valkey key",
+			election: enabled(func(le *LeaderElection) {
+				le.Backend = LeaderElectionBackendValkey
+				le.Valkey.Key = ""
+			}),
+			storage: shared,
+			wantErr: "leader-election.valkey.key must not be empty",
+		},
+		{
+			name:     "renew deadline not greater than retry period",
+			election: enabled(func(le *LeaderElection) { le.RenewDeadline = 2 * time.Second }),
+			storage:  shared,
+			wantErr:  "leader-election.renew-deadline (2s) must be greater than leader-election.retry-period (2s)",
+		},
+		{
+			name:     "lease duration not greater than renew deadline",
+			election: enabled(func(le *LeaderElection) { le.LeaseDuration = 5 * time.Second }),
+			storage:  shared,
+			wantErr:  "leader-election.lease-duration (5s) must be greater than leader-election.renew-deadline (10s)",
+		},
+		{
+			name: "sub-second lease with the kubernetes backend",
+			election: enabled(func(le *LeaderElection) {
+				le.LeaseDuration = 500 * time.Millisecond
+				le.RenewDeadline = 300 * time.Millisecond
+				le.RetryPeriod = 100 * time.Millisecond
+			}),
+			storage: shared,
+			wantErr: "leader-election.lease-duration must be at least 1s with the kubernetes backend, got 500ms",
+		},
+		{
+			name:     "non positive retry period",
+			election: enabled(func(le *LeaderElection) { le.RetryPeriod = 0 }),
+			storage:  shared,
+			wantErr:  "leader-election.retry-period must be positive, got 0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.election.IsValid(tt.storage)
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLeaderElection_Defaults(t *testing.T) {
+	le := LeaderElection{Identity: "sablier-0", Kubernetes: LeaderElectionKubernetes{Namespace: "sablier"}}
+	assert.Equal(t, le.ReplicaIdentity(), "sablier-0")
+	assert.Equal(t, le.Kubernetes.LeaseNamespace(), "sablier")
+
+	t.Setenv("POD_NAMESPACE", "from-env")
+	assert.Equal(t, LeaderElectionKubernetes{}.LeaseNamespace(), "from-env")
+}
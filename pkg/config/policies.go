@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Group assigns the instances it matches to a group, in addition to the
+// groups of their sablier.group label.
+// Groups are configured via the YAML configuration file only;
+// there is no corresponding CLI flag or environment variable.
+type Group struct {
+	// Name is the group the matched instances join. Required.
+	Name string
+
+	// Match selects the instances of the group.
+	Match InstanceMatch
+}
+
+// Policy applies settings to the instances it matches, as if they carried the
+// corresponding sablier.* labels. A label set on the instance wins over a
+// policy, and a later policy wins over an earlier one.
+// Policies are configured via the YAML configuration file only;
+// there is no corresponding CLI flag or environment variable.
+type Policy struct {
+	// Name identifies the policy in logs and errors.
+	Name string
+
+	// Match selects the instances the policy applies to.
+	Match InstanceMatch
+
+	// Groups the matched instances join, in addition to the groups of their
+	// sablier.group label.
+	Groups []string
+
+	// SessionDuration is the session duration of requests that do not ask for
+	// one, like sablier.session-duration.
+	SessionDuration time.Duration `mapstructure:"session-duration"`
+
+	// DependsOn lists the instances to start first, as "name" or
+	// "name:condition" with a Docker Compose condition such as
+	// service_healthy. Defaults to service_started.
+	DependsOn []string `mapstructure:"depends-on"`
+
+	// RunningHours is the daily keep-warm window, like sablier.running-hours.
+	RunningHours string `mapstructure:"running-hours"`
+
+	// RunningDays restricts RunningHours to weekdays, like sablier.running-days.
+	RunningDays string `mapstructure:"running-days"`
+
+	// Schedule is the cron keep-warm schedule, like sablier.schedule.
+	Schedule string
+
+	// Timezone is the time zone of the keep-warm windows, like sablier.timezone.
+	Timezone string
+
+	// Scale holds the idle and active resource profiles, like the
+	// sablier.idle.* and sablier.active.* labels.
+	Scale PolicyScale
+}
+
+// PolicyScale holds the resource profiles of scale mode.
+type PolicyScale struct {
+	Idle   PolicyResources
+	Active PolicyResources
+}
+
+// PolicyResources is a resource profile. Zero values leave the corresponding
+// label unset.
+type PolicyResources struct {
+	Replicas int
+	CPU      string
+	Memory   string
+}
+
+// InstanceMatch selects instances. An instance matches when it satisfies
+// every criterion set, and at least one must be.
+type InstanceMatch struct {
+	// Names are glob patterns, such as "preview-*", one of which the instance
+	// name must match.
+	Names []string
+
+	// Regex is a regular expression the instance name must match.
+	Regex string
+
+	// Labels is a label selector, such as "team=ci,tier": every listed label
+	// must be set on the instance, with the given value when there is one.
+	Labels string
+}
+
+func ValidateGroups(groups []Group) error {
+	for i, g := range groups {
+		if err := g.IsValid(); err != nil {
+			return fmt.Errorf("groups[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func ValidatePolicies(policies []Policy) error {
+	for i, p := range policies {
+		if err := p.IsValid(); err != nil {
+			return fmt.Errorf("policies[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (g Group) IsValid() error {
+	if strings.TrimSpace(g.Name) == "" {
+		return fmt.Errorf("name must be set")
+	}
+	return g.Match.IsValid()
+}
+
+func (p Policy) IsValid() error {
+	if err := p.Match.IsValid(); err != nil {
+		return err
+	}
+	if p.SessionDuration < 0 {
+		return fmt.Errorf("session-duration must not be negative, got %s", p.SessionDuration)
+	}
+	for _, dep := range p.DependsOn {
+		name, _, _ := strings.Cut(dep, ":")
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("depends-on entry %q has no instance name", dep)
+		}
+	}
+	if p.Scale.Idle.Replicas < 0 || p.Scale.Active.Replicas < 0 {
+		return fmt.Errorf("scale replicas must not be negative")
+	}
+	return nil
+}
+
+func (m InstanceMatch) IsValid() error {
+	if len(m.Names) == 0 && m.Regex == "" && strings.TrimSpace(m.Labels) == "" {
+		return fmt.Errorf("match must set names, regex or labels")
+	}
+	for _, pattern := range m.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+	}
+	if m.Regex != "" {
+		if _, err := regexp.Compile(m.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", m.Regex, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   Group
+		wantErr string
+	}{
+		{name: "names", group: Group{Name: "previews", Match: InstanceMatch{Names: []string{"preview-*"}}}},
+		{name: "labels", group: Group{Name: "ci", Match: InstanceMatch{Labels: "team=ci"}}},
+		{name: "no name", group: Group{Match: InstanceMatch{Regex: "^ci-"}}, wantErr: "groups[0]: name must be set"},
+		{name: "no match", group: Group{Name: "ci"}, wantErr: "groups[0]: match must set names, regex or labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroups([]Group{tt.group})
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePolicies(t *testing.T) {
+	match := InstanceMatch{Names: []string{"web"}}
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{name: "valid", policy: Policy{Match: match, SessionDuration: time.Hour, DependsOn: []string{"db:service_healthy", "cache"}}},
+		{name: "invalid glob", policy: Policy{Match: InstanceMatch{Names: []string{"web-["}}}, wantErr: `policies[0]: invalid name pattern "web-["`},
+		{name: "invalid regex", policy: Policy{Match: InstanceMatch{Regex: "("}}, wantErr: `policies[0]: invalid regex "("`},
+		{name: "negative session duration", policy: Policy{Match: match, SessionDuration: -time.Second}, wantErr: "session-duration must not be negative"},
+		{name: "dependency without name", policy: Policy{Match: match, DependsOn: []string{":service_healthy"}}, wantErr: "has no instance name"},
+		{name: "negative replicas", policy: Policy{Match: match, Scale: PolicyScale{Idle: PolicyResources{Replicas: -1}}}, wantErr: "scale replicas must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePolicies([]Policy{tt.policy})
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"slices"
+	"time"
 )
 
 // Provider holds the provider configurations.
@@ -58,10 +59,11 @@ type Provider struct {
 	// Since: v1.13.0
 	VerifyEnabledOnExpiration bool
 
-	Kubernetes Kubernetes
-	Podman     Podman
-	Docker     Docker
-	ProxmoxLXC ProxmoxLXC
+	Kubernetes   Kubernetes
+	Podman       Podman
+	Docker       Docker
+	ProxmoxLXC   ProxmoxLXC
+	InspectCache InspectCache
 }
 
 type Kubernetes struct {
@@ -148,6 +150,29 @@ type Docker struct {
 	HonorRestartPolicy bool
 }
 
+// InspectCache holds the configuration of the cache in front of the provider
+// instance inspections.
+type InspectCache struct {
+	// TTL is how long an instance inspection is served from memory. Entries are
+	// dropped as soon as the provider reports a change of the instance, so the
+	// TTL only bounds the staleness of changes the provider does not report.
+	// Set to 0 to inspect the provider on every request.
+	// Env: SABLIER_PROVIDER_INSPECT_CACHE_TTL
+	// CLI: --provider.inspect-cache.ttl
+	// Default: 0 (disabled)
+	// Since: NEXT_RELEASE
+	TTL time.Duration
+
+	// UnreliableEventsTTL caps TTL when the provider events can miss changes,
+	// for providers that declare so (Proxmox VE LXC) or once the event stream of
+	// the provider stopped.
+	// Env: SABLIER_PROVIDER_INSPECT_CACHE_UNRELIABLE_EVENTS_TTL
+	// CLI: --provider.inspect-cache.unreliable-events-ttl
+	// Default: 2s
+	// Since: NEXT_RELEASE
+	UnreliableEventsTTL time.Duration
+}
+
 // ProxmoxLXC holds the Proxmox VE LXC provider configuration.
 type ProxmoxLXC struct {
 	// URL is the Proxmox VE REST API base URL (e.g. "https://proxmox:8006/api2/json").
@@ -200,6 +225,9 @@ func NewProviderConfig() Provider {
 			Strategy: "stop",
 		},
 		ProxmoxLXC: ProxmoxLXC{},
+		InspectCache: InspectCache{
+			UnreliableEventsTTL: 2 * time.Second,
+		},
 	}
 }
 
@@ -207,6 +235,9 @@ func (provider Provider) IsValid() error {
 	if provider.AutoStopExternallyStarted && provider.AutoWarmExternallyStarted {
 		return fmt.Errorf("provider.auto-stop-externally-started and provider.auto-warm-externally-started are mutually exclusive")
 	}
+	if err := provider.InspectCache.IsValid(); err != nil {
+		return err
+	}
 	for _, p := range providers {
 		if p == provider.Name {
 			// Validate Docker-specific settings when using Docker provider
@@ -234,6 +265,16 @@ func (docker Docker) IsValid() error {
 	return fmt.Errorf("unrecognized docker strategy %s. strategies available: %v", docker.Strategy, dockerStrategies)
 }
 
+func (c InspectCache) IsValid() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("provider.inspect-cache.ttl must not be negative")
+	}
+	if c.UnreliableEventsTTL < 0 {
+		return fmt.Errorf("provider.inspect-cache.unreliable-events-ttl must not be negative")
+	}
+	return nil
+}
+
 func (p ProxmoxLXC) IsValid() error {
 	if p.URL == "" {
 		return fmt.Errorf("proxmox_lxc provider requires a URL")
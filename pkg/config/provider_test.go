@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -205,4 +206,46 @@ func TestNewProviderConfig_Defaults(t *testing.T) {
 	assert.Equal(t, c.Kubernetes.Burst, 10)
 	assert.Equal(t, c.Kubernetes.Delimiter, "_")
 	assert.Equal(t, c.Podman.Uri, "unix:///run/podman/podman.sock")
+	assert.Equal(t, c.InspectCache.TTL, time.Duration(0))
+	assert.Equal(t, c.InspectCache.UnreliableEventsTTL, 2*time.Second)
+}
+
+func TestInspectCache_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   InspectCache
+		wantErr error
+	}{
+		{
+			name:    "disabled",
+			cache:   InspectCache{},
+			wantErr: nil,
+		},
+		{
+			name:    "enabled",
+			cache:   InspectCache{TTL: time.Minute, UnreliableEventsTTL: 2 * time.Second},
+			wantErr: nil,
+		},
+		{
+			name:    "negative ttl",
+			cache:   InspectCache{TTL: -time.Second},
+			wantErr: fmt.Errorf("provider.inspect-cache.ttl must not be negative"),
+		},
+		{
+			name:    "negative unreliable events ttl",
+			cache:   InspectCache{TTL: time.Minute, UnreliableEventsTTL: -time.Second},
+			wantErr: fmt.Errorf("provider.inspect-cache.unreliable-events-ttl must not be negative"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cache.IsValid()
+			if tt.wantErr != nil {
+				assert.Error(t, err, tt.wantErr.Error())
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
 }
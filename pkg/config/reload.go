@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reload holds the configuration of the reloading of the groups and policies
+// of the configuration file.
+type Reload struct {
+	// Interval is how often the configuration file is checked for changes to
+	// its groups and policies. Zero disables the reloading.
+	// Env: SABLIER_RELOAD_INTERVAL
+	// CLI: --reload.interval
+	// Default: 10s
+	// Since: NEXT_RELEASE
+	Interval time.Duration
+}
+
+func NewReloadConfig() Reload {
+	return Reload{
+		Interval: 10 * time.Second,
+	}
+}
+
+func (r Reload) IsValid() error {
+	if r.Interval < 0 {
+		return fmt.Errorf("reload.interval must not be negative, got %s", r.Interval)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReload_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		reload  Reload
+		wantErr string
+	}{
+		{name: "default", reload: NewReloadConfig()},
+		{name: "disabled", reload: Reload{}},
+		{name: "negative interval", reload: Reload{Interval: -time.Second}, wantErr: "reload.interval must not be negative, got -1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reload.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
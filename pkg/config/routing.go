@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Routing maps incoming requests to the instances or group they need, for the
+// endpoints that receive the original request rather than explicit names: the
+// forward-auth strategy and the built-in reverse proxy.
+// Routing is configured via the YAML configuration file only;
+// there is no corresponding CLI flag or environment variable.
+type Routing struct {
+	// Routes is the routing table. A request matches the route with the most
+	// specific host (exact before wildcard), then the longest path prefix.
+	Routes []Route
+}
+
+// Route maps a host and path prefix to instance names or a group, and tells
+// how to answer while they are starting.
+type Route struct {
+	// Host is the requested host name, without port. "*.example.com" matches
+	// any subdomain of example.com, and "" or "*" matches any host.
+	Host string
+
+	// Path is the path prefix the route applies to. Defaults to "/".
+	Path string
+
+	// Names lists the instances the route needs. Mutually exclusive with Group.
+	Names []string
+
+	// Group is the group the route needs. Mutually exclusive with Names.
+	Group string
+
+	// Strategy is "dynamic" (answer with the waiting page while starting) or
+	// "blocking" (hold the request until ready). Defaults to "dynamic".
+	Strategy string
+
+	// SessionDuration overrides sessions.default-duration for this route.
+	SessionDuration time.Duration `mapstructure:"session-duration"`
+
+	// Theme overrides strategy.dynamic.default-theme for this route.
+	Theme string
+
+	// DisplayName is shown on the waiting page. Defaults to the host.
+	DisplayName string `mapstructure:"display-name"`
+
+	// Timeout overrides strategy.blocking.default-timeout for this route.
+	Timeout time.Duration
+
+	// Upstream is the base URL (e.g. http://whoami:80) the built-in reverse
+	// proxy forwards the route's requests to once its instances are ready.
+	// Routes without an upstream are only served by the forward-auth strategy.
+	Upstream string
+}
+
+const (
+	RouteStrategyDynamic  = "dynamic"
+	RouteStrategyBlocking = "blocking"
+)
+
+func NewRoutingConfig() Routing {
+	return Routing{}
+}
+
+func (r Routing) IsValid() error {
+	for i, route := range r.Routes {
+		if err := route.IsValid(); err != nil {
+			return fmt.Errorf("routing.routes[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r Route) IsValid() error {
+	if len(r.Names) == 0 && r.Group == "" {
+		return fmt.Errorf("names or group must be set")
+	}
+	if len(r.Names) > 0 && r.Group != "" {
+		return fmt.Errorf("names and group are mutually exclusive")
+	}
+	if r.Path != "" && !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("path must start with /, got %q", r.Path)
+	}
+	if r.Host != "*" && strings.Contains(strings.TrimPrefix(r.Host, "*."), "*") {
+		return fmt.Errorf("host wildcard is only supported as the first label (*.example.com), got %q", r.Host)
+	}
+	switch r.Strategy {
+	case "", RouteStrategyDynamic, RouteStrategyBlocking:
+	default:
+		return fmt.Errorf("strategy must be one of [%s, %s], got %q", RouteStrategyDynamic, RouteStrategyBlocking, r.Strategy)
+	}
+	if r.SessionDuration < 0 {
+		return fmt.Errorf("session-duration must not be negative, got %s", r.SessionDuration)
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", r.Timeout)
+	}
+	if r.Upstream != "" {
+		u, err := url.Parse(r.Upstream)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("upstream must be an absolute http or https URL, got %q", r.Upstream)
+		}
+	}
+	return nil
+}
+
+// Match returns the route for the given host and path.
+func (r Routing) Match(host, path string) (Route, bool) {
+	host = strings.ToLower(host)
+	if path == "" {
+		path = "/"
+	}
+
+	best, bestHost, bestPath := -1, 0, -1
+	for i, route := range r.Routes {
+		hostRank := route.hostRank(host)
+		if hostRank == 0 {
+			continue
+		}
+		prefix := route.Path
+		if prefix == "" {
+			prefix = "/"
+		}
+		if !matchPathPrefix(prefix, path) {
+			continue
+		}
+		if hostRank > bestHost || (hostRank == bestHost && len(prefix) > bestPath) {
+			best, bestHost, bestPath = i, hostRank, len(prefix)
+		}
+	}
+	if best < 0 {
+		return Route{}, false
+	}
+	return r.Routes[best], true
+}
+
+// hostRank ranks how specifically the route matches host: 3 for an exact
+// host, 2 for a wildcard subdomain, 1 for any host, 0 when it does not match.
+func (r Route) hostRank(host string) int {
+	pattern := strings.ToLower(r.Host)
+	switch {
+	case pattern == "" || pattern == "*":
+		return 1
+	case strings.HasPrefix(pattern, "*."):
+		if strings.HasSuffix(host, pattern[1:]) && len(host) > len(pattern)-1 {
+			return 2
+		}
+		return 0
+	case pattern == host:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// matchPathPrefix matches whole path segments: "/app" matches "/app" and
+// "/app/x" but not "/application".
+func matchPathPrefix(prefix, path string) bool {
+	if prefix == "/" || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
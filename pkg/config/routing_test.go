@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRouting_Match(t *testing.T) {
+	routing := Routing{Routes: []Route{
+		{Host: "*", Group: "fallback"},
+		{Host: "*.example.com", Group: "wildcard"},
+		{Host: "app.example.com", Group: "app"},
+		{Host: "app.example.com", Path: "/admin", Group: "admin"},
+		{Host: "app.example.com", Path: "/admin/reports/", Group: "reports"},
+	}}
+
+	tests := []struct {
+		host string
+		path string
+		want string
+	}{
+		{host: "app.example.com", path: "/", want: "app"},
+		{host: "APP.example.com", path: "", want: "app"},
+		{host: "app.example.com", path: "/admin", want: "admin"},
+		{host: "app.example.com", path: "/admin/users", want: "admin"},
+		{host: "app.example.com", path: "/administration", want: "app"},
+		{host: "app.example.com", path: "/admin/reports/2026", want: "reports"},
+		{host: "api.example.com", path: "/admin", want: "wildcard"},
+		{host: "example.com", path: "/", want: "fallback"},
+		{host: "other.org", path: "/", want: "fallback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			route, ok := routing.Match(tt.host, tt.path)
+			assert.Assert(t, ok)
+			assert.Equal(t, tt.want, route.Group)
+		})
+	}
+
+	_, ok := Routing{Routes: []Route{{Host: "app.example.com", Group: "app"}}}.Match("other.org", "/")
+	assert.Assert(t, !ok)
+}
+
+func TestRouting_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   Route
+		wantErr string
+	}{
+		{name: "names", route: Route{Host: "app.example.com", Names: []string{"app"}}},
+		{name: "any host", route: Route{Host: "*", Group: "app"}},
+		{name: "group with blocking strategy", route: Route{Host: "*.example.com", Path: "/api", Group: "app", Strategy: RouteStrategyBlocking}},
+		{name: "no target", route: Route{Host: "app.example.com"}, wantErr: "names or group must be set"},
+		{name: "both targets", route: Route{Names: []string{"app"}, Group: "app"}, wantErr: "names and group are mutually exclusive"},
+		{name: "relative path", route: Route{Group: "app", Path: "admin"}, wantErr: "path must start with /"},
+		{name: "inner wildcard", route: Route{Group: "app", Host: "app.*.com"}, wantErr: "host wildcard is only supported as the first label"},
+		{name: "upstream", route: Route{Group: "app", Upstream: "http://app:8080"}},
+		{name: "relative upstream", route: Route{Group: "app", Upstream: "app:8080"}, wantErr: "upstream must be an absolute http or https URL"},
+		{name: "unknown strategy", route: Route{Group: "app", Strategy: "poke"}, wantErr: "strategy must be one of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Routing{Routes: []Route{tt.route}}.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, "routing.routes[0]: "+tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+// RunningHours holds the configuration of the keep-warm windows of the
+// sablier.running-hours and sablier.schedule labels.
+type RunningHours struct {
+	// HolidaysFile is an iCalendar (.ics) file of exception dates, such as public
+	// holidays, on which no keep-warm window opens. Instances still start on
+	// request. The file is reloaded when it changes.
+	// Env: SABLIER_RUNNING_HOURS_HOLIDAYS_FILE
+	// CLI: --running-hours.holidays-file
+	// Default: ""
+	// Since: NEXT_RELEASE
+	HolidaysFile string
+}
+
+func NewRunningHoursConfig() RunningHours {
+	return RunningHours{}
+}
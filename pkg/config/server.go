@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // Server holds the HTTP server configuration.
 type Server struct {
 	// Port is the TCP port the Sablier server listens on.
@@ -17,9 +22,181 @@ type Server struct {
 	// Since: v1.0.0
 	BasePath string
 
+	// Socket is the path of a Unix domain socket to listen on instead of the
+	// TCP port, so a reverse proxy on the same host can reach Sablier without
+	// an exposed port. A stale socket file left by a previous run is replaced.
+	// Env: SABLIER_SERVER_SOCKET
+	// CLI: --server.socket
+	// Default: "" (listen on the TCP port)
+	// Since: NEXT_RELEASE
+	Socket string
+
+	// SocketMode is the octal file mode applied to the Unix domain socket.
+	// The reverse proxy must be able to write to the socket to connect.
+	// Env: SABLIER_SERVER_SOCKET_MODE
+	// CLI: --server.socket-mode
+	// Default: "0660"
+	// Since: NEXT_RELEASE
+	SocketMode string
+
+	TLS TLS
+
+	Proxy Proxy
+
+	ExtAuthz ExtAuthz
+
 	Metrics MetricsConfig
+
+	Auth Auth
+}
+
+// TLS holds the HTTPS configuration of the server. Setting a certificate and
+// key serves HTTPS instead of plain HTTP, on the TCP port or the Unix socket.
+type TLS struct {
+	// CertFile is the path to the PEM-encoded server certificate (and chain).
+	// The certificate and key are reloaded when either file changes, so a
+	// renewed certificate is served without a restart.
+	// Env: SABLIER_SERVER_TLS_CERT_FILE
+	// CLI: --server.tls.cert-file
+	// Default: "" (plain HTTP)
+	// Since: NEXT_RELEASE
+	CertFile string
+
+	// KeyFile is the path to the PEM-encoded private key of CertFile.
+	// Env: SABLIER_SERVER_TLS_KEY_FILE
+	// CLI: --server.tls.key-file
+	// Default: ""
+	// Since: NEXT_RELEASE
+	KeyFile string
+
+	// MinVersion is the minimum TLS version accepted: "1.2" or "1.3".
+	// Env: SABLIER_SERVER_TLS_MIN_VERSION
+	// CLI: --server.tls.min-version
+	// Default: "1.2"
+	// Since: NEXT_RELEASE
+	MinVersion string
+
+	// ClientCAFile is the path to the PEM-encoded CA bundle used to verify
+	// client certificates. Verified clients can authenticate against the API
+	// with their certificate (see server.auth.clients).
+	// Env: SABLIER_SERVER_TLS_CLIENT_CA_FILE
+	// CLI: --server.tls.client-ca-file
+	// Default: "" (client certificates are not requested)
+	// Since: NEXT_RELEASE
+	ClientCAFile string
+
+	// ClientAuth controls client certificate verification when ClientCAFile is
+	// set: "verify-if-given" accepts clients without a certificate (they may
+	// still use a bearer token), "require" rejects them during the handshake.
+	// Env: SABLIER_SERVER_TLS_CLIENT_AUTH
+	// CLI: --server.tls.client-auth
+	// Default: "verify-if-given"
+	// Since: NEXT_RELEASE
+	ClientAuth string
+}
+
+// Proxy holds the built-in reverse proxy configuration. The proxy serves the
+// routes of routing.routes that have an upstream, starting their instances on
+// demand, so Sablier can front them without a separate reverse proxy.
+type Proxy struct {
+	// Port is the TCP port the reverse proxy listens on. Set to 0 to disable it.
+	// Env: SABLIER_SERVER_PROXY_PORT
+	// CLI: --server.proxy.port
+	// Default: 0 (disabled)
+	// Since: NEXT_RELEASE
+	Port int
 }
 
+// Enabled reports whether the built-in reverse proxy is started.
+func (p Proxy) Enabled() bool {
+	return p.Port > 0
+}
+
+// ExtAuthz holds the Envoy external authorization (ext_authz) gRPC server
+// configuration. Envoy and Istio ask this server whether a request may
+// proceed, and Sablier answers from the session of the instances it needs.
+// The server.auth tokens and scopes do not apply to it: any client reaching
+// the port can start instances, unless TLS requires a client certificate.
+type ExtAuthz struct {
+	// Port is the TCP port the ext_authz gRPC server listens on. Set to 0 to
+	// disable it.
+	// Env: SABLIER_SERVER_EXT_AUTHZ_PORT
+	// CLI: --server.ext-authz.port
+	// Default: 0 (disabled)
+	// Since: NEXT_RELEASE
+	Port int
+
+	TLS ExtAuthzTLS
+}
+
+// Enabled reports whether the ext_authz gRPC server is started.
+func (e ExtAuthz) Enabled() bool {
+	return e.Port > 0
+}
+
+// ExtAuthzTLS holds the TLS configuration of the ext_authz gRPC server.
+type ExtAuthzTLS struct {
+	// CertFile is the path to the PEM-encoded server certificate (and chain).
+	// Setting a certificate and key serves the ext_authz API over TLS. They are
+	// reloaded when either file changes.
+	// Env: SABLIER_SERVER_EXT_AUTHZ_TLS_CERT_FILE
+	// CLI: --server.ext-authz.tls.cert-file
+	// Default: "" (plaintext)
+	// Since: NEXT_RELEASE
+	CertFile string
+
+	// KeyFile is the path to the PEM-encoded private key of CertFile.
+	// Env: SABLIER_SERVER_EXT_AUTHZ_TLS_KEY_FILE
+	// CLI: --server.ext-authz.tls.key-file
+	// Default: ""
+	// Since: NEXT_RELEASE
+	KeyFile string
+
+	// ClientCAFile is the path to the PEM-encoded CA bundle used to verify
+	// client certificates. When set, only the clients presenting a certificate
+	// signed by it, such as Envoy, are served.
+	// Env: SABLIER_SERVER_EXT_AUTHZ_TLS_CLIENT_CA_FILE
+	// CLI: --server.ext-authz.tls.client-ca-file
+	// Default: "" (client certificates are not requested)
+	// Since: NEXT_RELEASE
+	ClientCAFile string
+}
+
+// Enabled reports whether the ext_authz gRPC server serves TLS.
+func (t ExtAuthzTLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// ServerTLS returns t as the TLS configuration of a server requiring a client
+// certificate when ClientCAFile is set.
+func (t ExtAuthzTLS) ServerTLS() TLS {
+	return TLS{
+		CertFile:     t.CertFile,
+		KeyFile:      t.KeyFile,
+		MinVersion:   "1.2",
+		ClientCAFile: t.ClientCAFile,
+		ClientAuth:   ClientAuthRequire,
+	}
+}
+
+func (t ExtAuthzTLS) IsValid() error {
+	if !t.Enabled() {
+		if t.ClientCAFile != "" {
+			return fmt.Errorf("server.ext-authz.tls.client-ca-file requires server.ext-authz.tls.cert-file and server.ext-authz.tls.key-file")
+		}
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("server.ext-authz.tls.cert-file and server.ext-authz.tls.key-file must be set together")
+	}
+	return nil
+}
+
+const (
+	ClientAuthVerifyIfGiven = "verify-if-given"
+	ClientAuthRequire       = "require"
+)
+
 // MetricsConfig controls the Prometheus metrics endpoint.
 type MetricsConfig struct {
 	// Enabled exposes a Prometheus-compatible /metrics endpoint when true.
@@ -32,8 +209,87 @@ type MetricsConfig struct {
 
 func NewServerConfig() Server {
 	return Server{
-		Port:     10000,
-		BasePath: "/",
-		Metrics:  MetricsConfig{Enabled: false},
+		Port:       10000,
+		BasePath:   "/",
+		SocketMode: "0660",
+		TLS:        NewTLSConfig(),
+		Metrics:    MetricsConfig{Enabled: false},
+		Auth:       NewAuthConfig(),
+	}
+}
+
+func NewTLSConfig() TLS {
+	return TLS{
+		MinVersion: "1.2",
+		ClientAuth: ClientAuthVerifyIfGiven,
+	}
+}
+
+// Enabled reports whether the server serves HTTPS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+func (server Server) IsValid() error {
+	if server.Socket != "" {
+		if _, err := server.ParseSocketMode(); err != nil {
+			return err
+		}
+	}
+	if err := server.TLS.IsValid(); err != nil {
+		return err
+	}
+	if server.Proxy.Port < 0 {
+		return fmt.Errorf("server.proxy.port must not be negative, got %d", server.Proxy.Port)
+	}
+	if server.Proxy.Enabled() && server.Socket == "" && server.Proxy.Port == server.Port {
+		return fmt.Errorf("server.proxy.port must differ from server.port, both are %d", server.Port)
+	}
+	if server.ExtAuthz.Port < 0 {
+		return fmt.Errorf("server.ext-authz.port must not be negative, got %d", server.ExtAuthz.Port)
+	}
+	if server.ExtAuthz.Enabled() {
+		if server.Socket == "" && server.ExtAuthz.Port == server.Port {
+			return fmt.Errorf("server.ext-authz.port must differ from server.port, both are %d", server.Port)
+		}
+		if server.ExtAuthz.Port == server.Proxy.Port {
+			return fmt.Errorf("server.ext-authz.port must differ from server.proxy.port, both are %d", server.Proxy.Port)
+		}
+	}
+	if err := server.ExtAuthz.TLS.IsValid(); err != nil {
+		return err
+	}
+	return server.Auth.IsValid()
+}
+
+// ParseSocketMode returns SocketMode as a file mode.
+func (server Server) ParseSocketMode() (uint32, error) {
+	mode, err := strconv.ParseUint(server.SocketMode, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("server.socket-mode must be an octal file mode such as 0660, got %q", server.SocketMode)
+	}
+	return uint32(mode), nil
+}
+
+func (t TLS) IsValid() error {
+	if !t.Enabled() {
+		if t.ClientCAFile != "" {
+			return fmt.Errorf("server.tls.client-ca-file requires server.tls.cert-file and server.tls.key-file")
+		}
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("server.tls.cert-file and server.tls.key-file must be set together")
+	}
+	switch t.MinVersion {
+	case "1.2", "1.3":
+	default:
+		return fmt.Errorf("server.tls.min-version must be one of [1.2, 1.3], got %q", t.MinVersion)
+	}
+	switch t.ClientAuth {
+	case ClientAuthVerifyIfGiven, ClientAuthRequire:
+	default:
+		return fmt.Errorf("server.tls.client-auth must be one of [%s, %s], got %q", ClientAuthVerifyIfGiven, ClientAuthRequire, t.ClientAuth)
 	}
+	return nil
 }
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestServer_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  func(s *Server)
+		wantErr string
+	}{
+		{
+			name:   "default",
+			server: func(s *Server) {},
+		},
+		{
+			name: "tls and socket",
+			server: func(s *Server) {
+				s.Socket = "/run/sablier.sock"
+				s.TLS.CertFile = "tls.crt"
+				s.TLS.KeyFile = "tls.key"
+				s.TLS.ClientCAFile = "ca.crt"
+				s.TLS.ClientAuth = ClientAuthRequire
+			},
+		},
+		{
+			name:    "invalid socket mode",
+			server:  func(s *Server) { s.Socket = "/run/sablier.sock"; s.SocketMode = "rw" },
+			wantErr: "server.socket-mode must be an octal file mode",
+		},
+		{
+			name:    "cert without key",
+			server:  func(s *Server) { s.TLS.CertFile = "tls.crt" },
+			wantErr: "server.tls.cert-file and server.tls.key-file must be set together",
+		},
+		{
+			name:    "client CA without certificate",
+			server:  func(s *Server) { s.TLS.ClientCAFile = "ca.crt" },
+			wantErr: "server.tls.client-ca-file requires",
+		},
+		{
+			name:    "unsupported min version",
+			server:  func(s *Server) { s.TLS.CertFile, s.TLS.KeyFile, s.TLS.MinVersion = "tls.crt", "tls.key", "1.0" },
+			wantErr: "server.tls.min-version must be one of",
+		},
+		{
+			name:    "unknown client auth",
+			server:  func(s *Server) { s.TLS.CertFile, s.TLS.KeyFile, s.TLS.ClientAuth = "tls.crt", "tls.key", "maybe" },
+			wantErr: "server.tls.client-auth must be one of",
+		},
+		{
+			name:    "negative proxy port",
+			server:  func(s *Server) { s.Proxy.Port = -1 },
+			wantErr: "server.proxy.port must not be negative",
+		},
+		{
+			name:    "proxy on the API port",
+			server:  func(s *Server) { s.Proxy.Port = s.Port },
+			wantErr: "server.proxy.port must differ from server.port",
+		},
+		{
+			name:   "proxy next to the API socket",
+			server: func(s *Server) { s.Socket = "/run/sablier.sock"; s.Proxy.Port = s.Port },
+		},
+		{
+			name:    "negative ext_authz port",
+			server:  func(s *Server) { s.ExtAuthz.Port = -1 },
+			wantErr: "server.ext-authz.port must not be negative",
+		},
+		{
+			name:    "ext_authz on the proxy port",
+			server:  func(s *Server) { s.Proxy.Port, s.ExtAuthz.Port = 8080, 8080 },
+			wantErr: "server.ext-authz.port must differ from server.proxy.port",
+		},
+		{
+			name:   "ext_authz",
+			server: func(s *Server) { s.ExtAuthz.Port = 9191 },
+		},
+		{
+			name: "ext_authz over mTLS",
+			server: func(s *Server) {
+				s.ExtAuthz.Port = 9191
+				s.ExtAuthz.TLS = ExtAuthzTLS{CertFile: "tls.crt", KeyFile: "tls.key", ClientCAFile: "ca.crt"}
+			},
+		},
+		{
+			name:    "ext_authz certificate without key",
+			server:  func(s *Server) { s.ExtAuthz.TLS.CertFile = "tls.crt" },
+			wantErr: "must be set together",
+		},
+		{
+			name:    "ext_authz client CA without certificate",
+			server:  func(s *Server) { s.ExtAuthz.TLS.ClientCAFile = "ca.crt" },
+			wantErr: "client-ca-file requires",
+		},
+		{
+			name:    "invalid auth",
+			server:  func(s *Server) { s.Auth.Tokens = []AuthToken{{Name: "traefik"}} },
+			wantErr: "token is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServerConfig()
+			tt.server(&s)
+			err := s.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
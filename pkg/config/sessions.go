@@ -1,6 +1,9 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Sessions holds the session lifecycle configuration.
 type Sessions struct {
@@ -19,6 +22,14 @@ type Sessions struct {
 	// Default: 20s
 	// Since: v1.0.0
 	ExpirationInterval time.Duration
+
+	// MaxDuration caps every session duration, whether it comes from the request,
+	// the sablier.session-duration label or DefaultDuration. Zero means no cap.
+	// Env: SABLIER_SESSIONS_MAX_DURATION
+	// CLI: --sessions.max-duration
+	// Default: 0
+	// Since: NEXT_RELEASE
+	MaxDuration time.Duration
 }
 
 func NewSessionsConfig() Sessions {
@@ -27,3 +38,13 @@ func NewSessionsConfig() Sessions {
 		ExpirationInterval: 20 * time.Second,
 	}
 }
+
+func (sessions Sessions) IsValid() error {
+	if sessions.MaxDuration < 0 {
+		return fmt.Errorf("sessions.max-duration must not be negative, got %s", sessions.MaxDuration)
+	}
+	if sessions.MaxDuration > 0 && sessions.DefaultDuration > sessions.MaxDuration {
+		return fmt.Errorf("sessions.default-duration %s exceeds sessions.max-duration %s", sessions.DefaultDuration, sessions.MaxDuration)
+	}
+	return nil
+}
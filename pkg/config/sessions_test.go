@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSessions_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions Sessions
+		wantErr  string
+	}{
+		{name: "default", sessions: NewSessionsConfig()},
+		{name: "max duration", sessions: Sessions{DefaultDuration: 5 * time.Minute, MaxDuration: time.Hour}},
+		{name: "default equals max duration", sessions: Sessions{DefaultDuration: time.Hour, MaxDuration: time.Hour}},
+		{name: "negative max duration", sessions: Sessions{MaxDuration: -time.Hour}, wantErr: "sessions.max-duration must not be negative, got -1h0m0s"},
+		{name: "default exceeds max duration", sessions: Sessions{DefaultDuration: 2 * time.Hour, MaxDuration: time.Hour}, wantErr: "sessions.default-duration 2h0m0s exceeds sessions.max-duration 1h0m0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sessions.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
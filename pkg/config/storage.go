@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
 // Storage holds the state persistence configuration.
 type Storage struct {
 	// File is the path to a file where Sablier persists its session state across restarts.
@@ -9,10 +14,208 @@ type Storage struct {
 	// Default: "" (stateless)
 	// Since: v1.0.0
 	File string
+
+	// FlushInterval is how often the state file is rewritten regardless of
+	// changes. Changes are already written shortly after they happen (see
+	// FlushDelay); the periodic flush is a safety net. Set to 0 to disable it.
+	// Env: SABLIER_STORAGE_FLUSH_INTERVAL
+	// CLI: --storage.flush-interval
+	// Default: 1m
+	// Since: NEXT_RELEASE
+	FlushInterval time.Duration
+
+	// FlushDelay is how long Sablier waits after a session change before
+	// writing the state file, so a burst of changes results in a single write.
+	// It bounds how many changes a crash (e.g. SIGKILL or OOM kill) can lose.
+	// Env: SABLIER_STORAGE_FLUSH_DELAY
+	// CLI: --storage.flush-delay
+	// Default: 1s
+	// Since: NEXT_RELEASE
+	FlushDelay time.Duration
+
+	Valkey     Valkey
+	Kubernetes StorageKubernetes
+	Database   Database
+}
+
+// Valkey holds the Valkey (or Redis-compatible) session store configuration.
+// Setting at least one address selects the Valkey backend instead of the
+// in-memory store, so sessions survive a Sablier restart or redeploy without a
+// state file. The server must publish keyspace notifications for expired keys
+// (notify-keyspace-events including "Kx"); Sablier enables them at startup
+// when it is allowed to, and refuses to start otherwise.
+type Valkey struct {
+	// Addresses is the list of Valkey nodes to connect to, as host:port pairs.
+	// Leave empty to keep sessions in memory.
+	// Env: SABLIER_STORAGE_VALKEY_ADDRESSES
+	// CLI: --storage.valkey.addresses
+	// Default: [] (in-memory store)
+	// Since: NEXT_RELEASE
+	Addresses []string
+
+	// Username is the ACL user used to authenticate against Valkey.
+	// Env: SABLIER_STORAGE_VALKEY_USERNAME
+	// CLI: --storage.valkey.username
+	// Default: ""
+	// Since: NEXT_RELEASE
+	Username string
+
+	// Password is the password used to authenticate against Valkey.
+	// Env: SABLIER_STORAGE_VALKEY_PASSWORD
+	// CLI: --storage.valkey.password
+	// Default: ""
+	// Since: NEXT_RELEASE
+	Password string
+
+	// DB is the logical database index sessions are stored in.
+	// Env: SABLIER_STORAGE_VALKEY_DB
+	// CLI: --storage.valkey.db
+	// Default: 0
+	// Since: NEXT_RELEASE
+	DB int
+
+	// TLS connects to Valkey over TLS.
+	// Env: SABLIER_STORAGE_VALKEY_TLS
+	// CLI: --storage.valkey.tls
+	// Default: false
+	// Since: NEXT_RELEASE
+	TLS bool
+
+	// TLSInsecure disables TLS certificate verification when connecting to Valkey.
+	// Enable only for self-signed certificates in trusted networks.
+	// Env: SABLIER_STORAGE_VALKEY_TLS_INSECURE
+	// CLI: --storage.valkey.tls-insecure
+	// Default: false
+	// Since: NEXT_RELEASE
+	TLSInsecure bool
+
+	// KeyPrefix is prepended to every session key, so several Sablier
+	// deployments (or other applications) can share one database.
+	// Env: SABLIER_STORAGE_VALKEY_KEY_PREFIX
+	// CLI: --storage.valkey.key-prefix
+	// Default: ""
+	// Since: NEXT_RELEASE
+	KeyPrefix string
+}
+
+// StorageKubernetes holds the Kubernetes session store configuration. Each
+// session is kept in a ConfigMap of the namespace, so sessions survive a
+// Sablier restart on clusters without Valkey or a persistent volume. Sablier
+// needs get, list, watch, create, update and delete on configmaps in the
+// namespace.
+type StorageKubernetes struct {
+	// Enabled selects the Kubernetes backend instead of the in-memory store.
+	// Env: SABLIER_STORAGE_KUBERNETES_ENABLED
+	// CLI: --storage.kubernetes.enabled
+	// Default: false
+	// Since: NEXT_RELEASE
+	Enabled bool
+
+	// Namespace the session ConfigMaps are kept in.
+	// Env: SABLIER_STORAGE_KUBERNETES_NAMESPACE
+	// CLI: --storage.kubernetes.namespace
+	// Default: "" (the namespace of the Sablier pod, or "default")
+	// Since: NEXT_RELEASE
+	Namespace string
+
+	// NamePrefix starts the name of every session ConfigMap, so several
+	// Sablier deployments can share one namespace.
+	// Env: SABLIER_STORAGE_KUBERNETES_NAME_PREFIX
+	// CLI: --storage.kubernetes.name-prefix
+	// Default: "sablier-session-"
+	// Since: NEXT_RELEASE
+	NamePrefix string
+}
+
+// Database holds the embedded database session store configuration. Sessions
+// are kept in a single SQLite file along with the history of their lifecycle:
+// when each instance was requested and by which strategy, when it became
+// ready, and when and why it was stopped. The history backs the usage report
+// (GET /api/usage and sablier usage).
+type Database struct {
+	// Path is the database file. Setting it selects the database backend
+	// instead of the in-memory store. The file is locked while Sablier runs.
+	// Env: SABLIER_STORAGE_DATABASE_PATH
+	// CLI: --storage.database.path
+	// Default: "" (in-memory store)
+	// Since: NEXT_RELEASE
+	Path string
+
+	// HistoryRetention is how long lifecycle events are kept. Set to 0 to keep
+	// them forever.
+	// Env: SABLIER_STORAGE_DATABASE_HISTORY_RETENTION
+	// CLI: --storage.database.history-retention
+	// Default: 720h
+	// Since: NEXT_RELEASE
+	HistoryRetention time.Duration
 }
 
 func NewStorageConfig() Storage {
 	return Storage{
-		File: "",
+		File:          "",
+		FlushInterval: time.Minute,
+		FlushDelay:    time.Second,
+		Kubernetes: StorageKubernetes{
+			NamePrefix: "sablier-session-",
+		},
+		Database: Database{
+			HistoryRetention: 30 * 24 * time.Hour,
+		},
+	}
+}
+
+// SessionNamespace returns the configured namespace, or the namespace Sablier
+// runs in when it runs in a Kubernetes pod, and "default" otherwise.
+func (k StorageKubernetes) SessionNamespace() string {
+	if k.Namespace != "" {
+		return k.Namespace
+	}
+	return podNamespace()
+}
+
+// Shared reports whether the sessions are kept outside of Sablier, where
+// several replicas can share them.
+func (storage Storage) Shared() bool {
+	return storage.Valkey.Enabled() || storage.Kubernetes.Enabled
+}
+
+// Enabled reports whether the database backend is selected.
+func (d Database) Enabled() bool {
+	return d.Path != ""
+}
+
+// Enabled reports whether the Valkey backend is selected.
+func (v Valkey) Enabled() bool {
+	return len(v.Addresses) > 0
+}
+
+func (storage Storage) IsValid() error {
+	if storage.File != "" && storage.Valkey.Enabled() {
+		return fmt.Errorf("storage.file and storage.valkey.addresses are mutually exclusive")
+	}
+	if storage.Kubernetes.Enabled && (storage.File != "" || storage.Valkey.Enabled()) {
+		return fmt.Errorf("storage.kubernetes.enabled is mutually exclusive with storage.file and storage.valkey.addresses")
+	}
+	if storage.Database.Enabled() && (storage.File != "" || storage.Valkey.Enabled() || storage.Kubernetes.Enabled) {
+		return fmt.Errorf("storage.database.path is mutually exclusive with storage.file, storage.valkey.addresses and storage.kubernetes.enabled")
+	}
+	if storage.Database.HistoryRetention < 0 {
+		return fmt.Errorf("storage.database.history-retention must not be negative, got %s", storage.Database.HistoryRetention)
+	}
+	if storage.Kubernetes.Enabled && storage.Kubernetes.NamePrefix == "" {
+		return fmt.Errorf("storage.kubernetes.name-prefix must not be empty")
+	}
+	if storage.FlushInterval < 0 {
+		return fmt.Errorf("storage.flush-interval must not be negative, got %s", storage.FlushInterval)
+	}
+	if storage.FlushDelay < 0 {
+		return fmt.Errorf("storage.flush-delay must not be negative, got %s", storage.FlushDelay)
+	}
+	if storage.Valkey.DB < 0 {
+		return fmt.Errorf("storage.valkey.db must not be negative, got %d", storage.Valkey.DB)
+	}
+	if storage.Valkey.TLSInsecure && !storage.Valkey.TLS {
+		return fmt.Errorf("storage.valkey.tls-insecure requires storage.valkey.tls")
 	}
+	return nil
 }
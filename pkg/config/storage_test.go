@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStorage_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage Storage
+		wantErr string
+	}{
+		{
+			name:    "default in-memory storage",
+			storage: NewStorageConfig(),
+		},
+		{
+			name:    "file storage",
+			storage: Storage{File: "/tmp/state.json"},
+		},
+		{
+			name:    "valkey storage",
+			storage: Storage{Valkey: Valkey{Addresses: []string{"valkey:6379"}, TLS: true, TLSInsecure: true}},
+		},
+		{
+			name: "file and valkey are mutually exclusive",
+			storage: Storage{
+				File:   "/tmp/state.json",
+				Valkey: Valkey{Addresses: []string{"valkey:6379"}},
+			},
+			wantErr: "storage.file and storage.valkey.addresses are mutually exclusive",
+		},
+		{
+			name:    "kubernetes storage",
+			storage: Storage{Kubernetes: StorageKubernetes{Enabled: true, NamePrefix: "sablier-session-"}},
+		},
+		{
+			name: "kubernetes and file are mutually exclusive",
+			storage: Storage{
+				File:       "/tmp/state.json",
+				Kubernetes: StorageKubernetes{Enabled: true, NamePrefix: "sablier-session-"},
+			},
+			wantErr: "storage.kubernetes.enabled is mutually exclusive with storage.file and storage.valkey.addresses",
+		},
+		{
+			name:    "kubernetes without name prefix",
+			storage: Storage{Kubernetes: StorageKubernetes{Enabled: true}},
+			wantErr: "storage.kubernetes.name-prefix must not be empty",
+		},
+		{
+			name:    "database storage",
+			storage: Storage{Database: Database{Path: "/var/lib/sablier/sablier.db"}},
+		},
+		{
+			name: "database and valkey are mutually exclusive",
+			storage: Storage{
+				Valkey:   Valkey{Addresses: []string{"valkey:6379"}},
+				Database: Database{Path: "/var/lib/sablier/sablier.db"},
+			},
+			wantErr: "storage.database.path is mutually exclusive with storage.file, storage.valkey.addresses and storage.kubernetes.enabled",
+		},
+		{
+			name:    "negative history retention",
+			storage: Storage{Database: Database{Path: "/var/lib/sablier/sablier.db", HistoryRetention: -time.Hour}},
+			wantErr: "storage.database.history-retention must not be negative, got -1h0m0s",
+		},
+		{
+			name:    "negative database",
+			storage: Storage{Valkey: Valkey{Addresses: []string{"valkey:6379"}, DB: -1}},
+			wantErr: "storage.valkey.db must not be negative, got -1",
+		},
+		{
+			name:    "tls-insecure without tls",
+			storage: Storage{Valkey: Valkey{Addresses: []string{"valkey:6379"}, TLSInsecure: true}},
+			wantErr: "storage.valkey.tls-insecure requires storage.valkey.tls",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.storage.IsValid()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
@@ -35,6 +35,15 @@ type DynamicStrategy struct {
 	// Default: 5s
 	// Since: v1.0.0
 	DefaultRefreshFrequency time.Duration
+
+	// PublicURL is the URL at which browsers reach Sablier, including the base path.
+	// When set, waiting pages subscribe to the session status stream under this URL
+	// and reload as soon as the session is ready instead of waiting for the next refresh.
+	// Env: SABLIER_STRATEGY_DYNAMIC_PUBLIC_URL
+	// CLI: --strategy.dynamic.public-url
+	// Default: "" (waiting pages only refresh)
+	// Since: NEXT_RELEASE
+	PublicURL string
 }
 
 // BlockingStrategy holds configuration for the blocking strategy.
@@ -48,7 +57,8 @@ type BlockingStrategy struct {
 	DefaultTimeout time.Duration
 
 	// DefaultRefreshFrequency is how often the blocking strategy polls instance readiness
-	// while waiting for workloads to start.
+	// while waiting for workloads to start. Each waited-on instance is polled once,
+	// however many requests wait on it, on top of the provider events.
 	// Env: SABLIER_STRATEGY_BLOCKING_DEFAULT_REFRESH_FREQUENCY
 	// CLI: --strategy.blocking.default-refresh-frequency
 	// Default: 5s
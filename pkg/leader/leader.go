@@ -0,0 +1,150 @@
+// Package leader elects one leader among the Sablier replicas that share a
+// session storage. Every replica serves strategy requests; the leader alone
+// runs the background loops that would otherwise act once per replica, such as
+// stopping expired instances.
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+)
+
+// Election elects one leader among the replicas.
+type Election interface {
+	// IsLeader reports whether this replica currently leads.
+	IsLeader() bool
+	// Run competes for the leadership until ctx is done. Each time this
+	// replica becomes the leader, lead runs with a context that is canceled
+	// when it stops leading. lead must return once its context is done.
+	Run(ctx context.Context, lead func(ctx context.Context))
+}
+
+var _ Election = (*Elector)(nil)
+
+// Lock is the shared lock the replicas compete for, by an Elector.
+type Lock interface {
+	// TryAcquire takes the lock for identity for ttl, or extends it when
+	// identity already holds it. It reports false when another identity holds
+	// a lock that has not expired yet.
+	TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error)
+	// Release gives the lock up, so another replica can take over without
+	// waiting for it to expire. It does nothing when identity does not hold it.
+	Release(ctx context.Context, identity string) error
+}
+
+// Elector keeps trying to acquire the lock and runs the leader work while it
+// holds it.
+type Elector struct {
+	lock     Lock
+	identity string
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	leader atomic.Bool
+	l      *slog.Logger
+	now    func() time.Time
+}
+
+// New creates an Elector competing for lock as identity, with the timings of conf.
+func New(logger *slog.Logger, lock Lock, identity string, conf config.LeaderElection) *Elector {
+	return &Elector{
+		lock:          lock,
+		identity:      identity,
+		leaseDuration: conf.LeaseDuration,
+		renewDeadline: conf.RenewDeadline,
+		retryPeriod:   conf.RetryPeriod,
+		l:             logger.With(slog.String("identity", identity)),
+		now:           time.Now,
+	}
+}
+
+// IsLeader reports whether this replica currently leads.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run competes for the lock every retry period until ctx is done. Each time
+// this replica becomes the leader, lead runs with a context that is canceled
+// when it loses the lock, or cannot renew it within the renew deadline. The
+// lead function must return once its context is done: Run waits for it before
+// competing again, and releases the lock when ctx is done.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	ticker := time.NewTicker(e.retryPeriod)
+	defer ticker.Stop()
+
+	var stepDown func()
+	var renewed time.Time
+	for {
+		acquired, err := e.tryAcquire(ctx)
+		switch {
+		case acquired:
+			renewed = e.now()
+			if stepDown == nil {
+				stepDown = e.startLeading(ctx, lead)
+			}
+		case err != nil && stepDown != nil && e.now().Sub(renewed) < e.renewDeadline:
+			e.l.WarnContext(ctx, "leader election: cannot renew the lock, retrying", slog.Any("error", err))
+		case err != nil:
+			e.l.WarnContext(ctx, "leader election: cannot acquire the lock", slog.Any("error", err))
+			fallthrough
+		default:
+			// Another replica holds the lock, or this one could not renew it in
+			// time: it must stop leading before its lock expires.
+			if stepDown != nil {
+				stepDown()
+				stepDown = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if stepDown != nil {
+				stepDown()
+				e.release(ctx)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) tryAcquire(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.retryPeriod)
+	defer cancel()
+	return e.lock.TryAcquire(ctx, e.identity, e.leaseDuration)
+}
+
+// startLeading runs lead in the background and returns the function that
+// stops it.
+func (e *Elector) startLeading(ctx context.Context, lead func(ctx context.Context)) func() {
+	e.l.InfoContext(ctx, "leader election: became the leader")
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	e.leader.Store(true)
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+	return func() {
+		e.leader.Store(false)
+		cancel()
+		<-done
+		e.l.InfoContext(ctx, "leader election: stopped leading")
+	}
+}
+
+// release hands the lock over on shutdown, so a follower takes over at its
+// next retry instead of after the lease duration.
+func (e *Elector) release(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.retryPeriod)
+	defer cancel()
+	if err := e.lock.Release(ctx, e.identity); err != nil {
+		e.l.WarnContext(ctx, "leader election: cannot release the lock", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,168 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/config"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+// memoryLock is a Lock shared by the electors of a test, with an optional
+// error injected in every call.
+type memoryLock struct {
+	mu       sync.Mutex
+	holder   string
+	expires  time.Time
+	err      error
+	released []string
+}
+
+func (m *memoryLock) TryAcquire(_ context.Context, identity string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.holder != "" && m.holder != identity && time.Now().Before(m.expires) {
+		return false, nil
+	}
+	m.holder = identity
+	m.expires = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *memoryLock) Release(_ context.Context, identity string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, identity)
+	if m.holder == identity {
+		m.holder = ""
+	}
+	return nil
+}
+
+func (m *memoryLock) fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+func testConfig() config.LeaderElection {
+	return config.LeaderElection{
+		LeaseDuration: 300 * time.Millisecond,
+		RenewDeadline: 200 * time.Millisecond,
+		RetryPeriod:   20 * time.Millisecond,
+	}
+}
+
+// leading runs lead until its context is done, reporting whether it runs.
+type leading struct {
+	mu      sync.Mutex
+	running bool
+	terms   int
+}
+
+func (l *leading) lead(ctx context.Context) {
+	l.mu.Lock()
+	l.running = true
+	l.terms++
+	l.mu.Unlock()
+	<-ctx.Done()
+	l.mu.Lock()
+	l.running = false
+	l.mu.Unlock()
+}
+
+func (l *leading) isRunning() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running
+}
+
+func TestElector_OneLeaderAtATime(t *testing.T) {
+	lock := &memoryLock{}
+	first := New(slogt.New(t), lock, "first", testConfig())
+	second := New(slogt.New(t), lock, "second", testConfig())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	firstDone := make(chan struct{})
+	var firstLead, secondLead leading
+	go func() {
+		defer close(firstDone)
+		first.Run(ctx, firstLead.lead)
+	}()
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if first.IsLeader() && firstLead.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("first replica is not leading yet")
+	})
+
+	secondCtx, secondCancel := context.WithCancel(t.Context())
+	defer secondCancel()
+	go second.Run(secondCtx, secondLead.lead)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Assert(t, !second.IsLeader())
+	assert.Assert(t, !secondLead.isRunning())
+
+	// Shutting the leader down releases the lock: the follower takes over
+	// without waiting for the lease to expire.
+	cancel()
+	<-firstDone
+	assert.Assert(t, !first.IsLeader())
+	assert.Assert(t, !firstLead.isRunning())
+	assert.DeepEqual(t, lock.released, []string{"first"})
+
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if second.IsLeader() && secondLead.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("second replica is not leading yet")
+	}, poll.WithTimeout(testConfig().LeaseDuration))
+}
+
+func TestElector_StepsDownPastRenewDeadline(t *testing.T) {
+	lock := &memoryLock{}
+	e := New(slogt.New(t), lock, "sablier", testConfig())
+
+	var l leading
+	go e.Run(t.Context(), l.lead)
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if e.IsLeader() {
+			return poll.Success()
+		}
+		return poll.Continue("not leading yet")
+	})
+
+	lock.fail(errors.New("connection refused"))
+
+	// A renewal failure alone does not interrupt the leader.
+	time.Sleep(testConfig().RetryPeriod * 3)
+	assert.Assert(t, e.IsLeader())
+
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if !e.IsLeader() && !l.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("still leading")
+	}, poll.WithTimeout(testConfig().RenewDeadline*2))
+
+	// Leading resumes once the lock can be acquired again.
+	lock.fail(nil)
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if e.IsLeader() && l.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("not leading again yet")
+	})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	assert.Equal(t, l.terms, 2)
+}
@@ -0,0 +1,119 @@
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/sablierapp/sablier/pkg/config"
+)
+
+var _ Election = (*LeaseElector)(nil)
+
+// LeaseElector competes for a coordination.k8s.io Lease through the client-go
+// leader election. The Lease is created on first use. Expiration is judged
+// from when this replica last saw the Lease change, not from the renew time
+// written by the holder, so the replicas' clocks do not need to be in sync.
+type LeaseElector struct {
+	config leaderelection.LeaderElectionConfig
+
+	leader atomic.Bool
+	l      *slog.Logger
+}
+
+// NewLeaseElector creates an elector competing for the Lease namespace/name as
+// identity, with the timings of conf.
+func NewLeaseElector(logger *slog.Logger, client kubernetes.Interface, namespace, name, identity string, conf config.LeaderElection) (*LeaseElector, error) {
+	e := &LeaseElector{
+		config: leaderelection.LeaderElectionConfig{
+			Lock: &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Client:     client.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+			},
+			LeaseDuration: conf.LeaseDuration,
+			RenewDeadline: conf.RenewDeadline,
+			RetryPeriod:   conf.RetryPeriod,
+			// Hand the Lease over on shutdown, so a follower takes over at its
+			// next retry instead of after the lease duration.
+			ReleaseOnCancel: true,
+			Name:            name,
+		},
+		l: logger.With(slog.String("identity", identity)),
+	}
+	// Validate the timings now rather than when Run starts competing.
+	if _, err := leaderelection.NewLeaderElector(e.term(context.Background(), func(context.Context) {})); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// IsLeader reports whether this replica currently leads.
+func (e *LeaseElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run competes for the Lease until ctx is done. Each time this replica becomes
+// the leader, lead runs with a context that is canceled when it loses the
+// Lease, or cannot renew it within the renew deadline. Run waits for lead to
+// return before competing again, and releases the Lease when ctx is done.
+func (e *LeaseElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(e.term(ctx, lead))
+		if err != nil {
+			// The configuration was validated by NewLeaseElector.
+			e.l.ErrorContext(ctx, "leader election: cannot compete for the lease", slog.Any("error", err))
+			return
+		}
+		elector.Run(ctx)
+	}
+}
+
+// term returns the configuration of one leader election run, whose callbacks
+// run lead while this replica holds the Lease. client-go starts leading in
+// the background and only waits for the end of the renewals, so the stop
+// callback waits for lead to return, and the start callback gives up when the
+// run already stopped.
+func (e *LeaseElector) term(ctx context.Context, lead func(ctx context.Context)) leaderelection.LeaderElectionConfig {
+	var (
+		mu      sync.Mutex
+		started bool
+		stopped bool
+		done    = make(chan struct{})
+	)
+	config := e.config
+	config.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(leaderCtx context.Context) {
+			defer close(done)
+			mu.Lock()
+			if stopped {
+				mu.Unlock()
+				return
+			}
+			started = true
+			e.leader.Store(true)
+			mu.Unlock()
+			e.l.InfoContext(ctx, "leader election: became the leader")
+			lead(leaderCtx)
+		},
+		OnStoppedLeading: func() {
+			mu.Lock()
+			stopped = true
+			wasStarted := started
+			mu.Unlock()
+			if !wasStarted {
+				return
+			}
+			e.leader.Store(false)
+			<-done
+			e.l.InfoContext(ctx, "leader election: stopped leading")
+		},
+	}
+	return config
+}
@@ -0,0 +1,87 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/config"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// leaseConfig returns timings for the Lease tests. client-go writes the lease
+// duration in whole seconds, too coarse for testConfig.
+func leaseConfig() config.LeaderElection {
+	return config.LeaderElection{
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   50 * time.Millisecond,
+	}
+}
+
+func TestNewLeaseElector_InvalidTimings(t *testing.T) {
+	conf := leaseConfig()
+	conf.RenewDeadline = conf.LeaseDuration
+
+	_, err := NewLeaseElector(slogt.New(t), fake.NewSimpleClientset(), "sablier", "sablier", "first", conf)
+	assert.ErrorContains(t, err, "leaseDuration must be greater than renewDeadline")
+}
+
+func TestLeaseElector_OneLeaderAtATime(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	first, err := NewLeaseElector(slogt.New(t), client, "sablier", "sablier", "first", leaseConfig())
+	assert.NilError(t, err)
+	second, err := NewLeaseElector(slogt.New(t), client, "sablier", "sablier", "second", leaseConfig())
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	firstDone := make(chan struct{})
+	var firstLead, secondLead leading
+	go func() {
+		defer close(firstDone)
+		first.Run(ctx, firstLead.lead)
+	}()
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if first.IsLeader() && firstLead.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("first replica is not leading yet")
+	})
+
+	lease, err := client.CoordinationV1().Leases("sablier").Get(t.Context(), "sablier", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *lease.Spec.HolderIdentity, "first")
+
+	secondCtx, secondCancel := context.WithCancel(t.Context())
+	secondDone := make(chan struct{})
+	defer func() {
+		secondCancel()
+		<-secondDone
+	}()
+	go func() {
+		defer close(secondDone)
+		second.Run(secondCtx, secondLead.lead)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Assert(t, !second.IsLeader())
+	assert.Assert(t, !secondLead.isRunning())
+
+	// Shutting the leader down releases the Lease: the follower takes over
+	// without waiting for it to expire.
+	cancel()
+	<-firstDone
+	assert.Assert(t, !first.IsLeader())
+	assert.Assert(t, !firstLead.isRunning())
+
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		if second.IsLeader() && secondLead.isRunning() {
+			return poll.Success()
+		}
+		return poll.Continue("second replica is not leading yet")
+	}, poll.WithTimeout(leaseConfig().LeaseDuration))
+}
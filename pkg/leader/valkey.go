@@ -0,0 +1,53 @@
+package leader
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+var _ Lock = (*ValkeyLock)(nil)
+
+// acquireScript sets the key to the identity when it is free, and extends it
+// when the identity already holds it.
+var acquireScript = valkey.NewLuaScript(`
+local holder = redis.call('GET', KEYS[1])
+if holder == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+if holder == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// releaseScript deletes the key when the identity holds it.
+var releaseScript = valkey.NewLuaScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ValkeyLock keeps the lock in a Valkey key holding the identity of the
+// leader, which expires when the leader stops renewing it.
+type ValkeyLock struct {
+	Client valkey.Client
+	Key    string
+}
+
+func (l *ValkeyLock) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	ok, err := acquireScript.Exec(ctx, l.Client, []string{l.Key}, []string{identity, strconv.FormatInt(ttl.Milliseconds(), 10)}).AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
+
+func (l *ValkeyLock) Release(ctx context.Context, identity string) error {
+	return releaseScript.Exec(ctx, l.Client, []string{l.Key}, []string{identity}).Error()
+}
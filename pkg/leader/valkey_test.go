@@ -0,0 +1,78 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcvalkey "github.com/testcontainers/testcontainers-go/modules/valkey"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/valkey-io/valkey-go"
+	"gotest.tools/v3/assert"
+)
+
+func setupValkey(t *testing.T) valkey.Client {
+	t.Helper()
+	ctx := context.Background()
+	c, err := tcvalkey.Run(ctx, "valkey/valkey:7.2.5",
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("6379/tcp")),
+	)
+	testcontainers.CleanupContainer(t, c)
+	assert.NilError(t, err)
+
+	uri, err := c.ConnectionString(ctx)
+	assert.NilError(t, err)
+
+	options, err := valkey.ParseURL(uri)
+	assert.NilError(t, err)
+
+	client, err := valkey.NewClient(options)
+	assert.NilError(t, err)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestValkeyLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	ctx := t.Context()
+	client := setupValkey(t)
+	lock := &ValkeyLock{Client: client, Key: "sablier:leader"}
+
+	ok, err := lock.TryAcquire(ctx, "first", time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+
+	ok, err = lock.TryAcquire(ctx, "second", time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	// Renewing extends the expiry.
+	ok, err = lock.TryAcquire(ctx, "first", 10*time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	pttl, err := client.Do(ctx, client.B().Pttl().Key("sablier:leader").Build()).AsInt64()
+	assert.NilError(t, err)
+	assert.Assert(t, pttl > 1000)
+
+	// Only the holder releases the lock.
+	assert.NilError(t, lock.Release(ctx, "second"))
+	ok, err = lock.TryAcquire(ctx, "second", time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	assert.NilError(t, lock.Release(ctx, "first"))
+	ok, err = lock.TryAcquire(ctx, "second", time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+
+	// An expired lock is free.
+	time.Sleep(1500 * time.Millisecond)
+	ok, err = lock.TryAcquire(ctx, "first", time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+}
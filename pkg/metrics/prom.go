@@ -22,6 +22,13 @@ type PromRecorder struct {
 	instanceReadyDuration  *prometheus.HistogramVec
 	instanceActiveDuration *prometheus.CounterVec
 
+	listenerConnections       *prometheus.CounterVec
+	listenerActiveConnections *prometheus.GaugeVec
+	listenerWaitDuration      *prometheus.HistogramVec
+	listenerBytes             *prometheus.CounterVec
+	listenerDrops             *prometheus.CounterVec
+	inspectCache              *prometheus.CounterVec
+
 	activeMu        sync.RWMutex
 	activeInstances map[string]struct{}
 	activeSince     map[string]time.Time // protected by activeMu
@@ -89,6 +96,50 @@ func NewPromRecorder() *PromRecorder {
 		[]string{"instance"},
 	)
 
+	r.listenerConnections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sablier_listener_connections_total",
+			Help: "Total number of connections (UDP flows) closed by the TCP/UDP listeners, by listener and result (forwarded, timeout, unavailable, backend_error, canceled).",
+		},
+		[]string{"listener", "result"},
+	)
+	r.listenerActiveConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sablier_listener_active_connections",
+			Help: "Number of connections (UDP flows) currently open on the TCP/UDP listeners, waiting or forwarding, by listener.",
+		},
+		[]string{"listener"},
+	)
+	r.listenerWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "sablier_listener_wait_duration_seconds",
+			Help:    "Time a TCP/UDP listener client was held until the instances were ready (seconds).",
+			Buckets: histogramBuckets,
+		},
+		[]string{"listener"},
+	)
+	r.listenerBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sablier_listener_bytes_total",
+			Help: "Total number of bytes forwarded by the TCP/UDP listeners, by listener and direction (in: client to backend, out: backend to client).",
+		},
+		[]string{"listener", "direction"},
+	)
+	r.listenerDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sablier_listener_dropped_total",
+			Help: "Total number of connections and datagrams dropped by the TCP/UDP listeners, by listener and reason (max_connections: the listener held max-connections already, queue_full: a UDP flow queued too many datagrams while its instances started).",
+		},
+		[]string{"listener", "reason"},
+	)
+	r.inspectCache = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sablier_inspect_cache_requests_total",
+			Help: "Total number of instance inspections answered by the inspect cache, by result (hit, miss).",
+		},
+		[]string{"result"},
+	)
+
 	reg.MustRegister(
 		r.sessionRequests,
 		r.instanceStartFailures,
@@ -96,6 +147,12 @@ func NewPromRecorder() *PromRecorder {
 		r.instanceStartDuration,
 		r.instanceReadyDuration,
 		r.instanceActiveDuration,
+		r.listenerConnections,
+		r.listenerActiveConnections,
+		r.listenerWaitDuration,
+		r.listenerBytes,
+		r.listenerDrops,
+		r.inspectCache,
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 	)
@@ -124,6 +181,31 @@ func (r *PromRecorder) RecordInstanceStartEnd(instance string, dur time.Duration
 	r.instanceStartDuration.WithLabelValues(instance).Observe(dur.Seconds())
 }
 
+func (r *PromRecorder) RecordListenerConnectionOpen(listener string) {
+	r.listenerActiveConnections.WithLabelValues(listener).Inc()
+}
+
+func (r *PromRecorder) RecordListenerConnectionClose(listener, result string) {
+	r.listenerActiveConnections.WithLabelValues(listener).Dec()
+	r.listenerConnections.WithLabelValues(listener, result).Inc()
+}
+
+func (r *PromRecorder) RecordListenerWait(listener string, dur time.Duration) {
+	r.listenerWaitDuration.WithLabelValues(listener).Observe(dur.Seconds())
+}
+
+func (r *PromRecorder) RecordListenerBytes(listener, direction string, n int64) {
+	r.listenerBytes.WithLabelValues(listener, direction).Add(float64(n))
+}
+
+func (r *PromRecorder) RecordListenerDrop(listener, reason string) {
+	r.listenerDrops.WithLabelValues(listener, reason).Inc()
+}
+
+func (r *PromRecorder) RecordInspectCache(result string) {
+	r.inspectCache.WithLabelValues(result).Inc()
+}
+
 func (r *PromRecorder) RecordReadyWaitBegin(instance string) {
 	r.readyMu.Lock()
 	defer r.readyMu.Unlock()
@@ -276,3 +276,41 @@ func TestPromRecorder_ActiveSeconds_AccumulatesAcrossMultipleCycles(t *testing.T
 		t.Errorf("expected accumulated active seconds across 3 cycles, got %v", got)
 	}
 }
+
+func TestPromRecorder_Listener(t *testing.T) {
+	r := metrics.NewPromRecorder()
+
+	r.RecordListenerConnectionOpen("postgres")
+	r.RecordListenerConnectionOpen("postgres")
+	r.RecordListenerWait("postgres", 2*time.Second)
+	r.RecordListenerBytes("postgres", "in", 100)
+	r.RecordListenerBytes("postgres", "in", 20)
+	r.RecordListenerBytes("postgres", "out", 300)
+	r.RecordListenerConnectionClose("postgres", "forwarded")
+	r.RecordListenerDrop("postgres", "max_connections")
+
+	mustCounter(t, r, "sablier_listener_dropped_total", map[string]string{"listener": "postgres", "reason": "max_connections"}, 1)
+	mustCounter(t, r, "sablier_listener_connections_total", map[string]string{"listener": "postgres", "result": "forwarded"}, 1)
+	mustCounter(t, r, "sablier_listener_bytes_total", map[string]string{"listener": "postgres", "direction": "in"}, 120)
+	mustCounter(t, r, "sablier_listener_bytes_total", map[string]string{"listener": "postgres", "direction": "out"}, 300)
+	mustHistogramCount(t, r, "sablier_listener_wait_duration_seconds", map[string]string{"listener": "postgres"}, 1)
+
+	m := findMetric(t, r, "sablier_listener_active_connections", map[string]string{"listener": "postgres"})
+	if m == nil {
+		t.Fatal("gauge sablier_listener_active_connections not found")
+	}
+	if got := m.GetGauge().GetValue(); got != 1 {
+		t.Errorf("sablier_listener_active_connections = %v, want 1", got)
+	}
+}
+
+func TestPromRecorder_InspectCache(t *testing.T) {
+	r := metrics.NewPromRecorder()
+
+	r.RecordInspectCache("hit")
+	r.RecordInspectCache("hit")
+	r.RecordInspectCache("miss")
+
+	mustCounter(t, r, "sablier_inspect_cache_requests_total", map[string]string{"result": "hit"}, 2)
+	mustCounter(t, r, "sablier_inspect_cache_requests_total", map[string]string{"result": "miss"}, 1)
+}
@@ -16,6 +16,12 @@ type Recorder interface {
 	RecordActiveInstance(instance string)
 	RecordInactiveInstance(instance string)
 	RecordInstanceStop(instance, reason string)
+	RecordListenerConnectionOpen(listener string)
+	RecordListenerConnectionClose(listener, result string)
+	RecordListenerWait(listener string, dur time.Duration)
+	RecordListenerBytes(listener, direction string, n int64)
+	RecordListenerDrop(listener, reason string)
+	RecordInspectCache(result string)
 }
 
 // Noop is the zero-overhead default recorder.
@@ -30,3 +36,9 @@ func (Noop) DiscardReadyWait(string)                      {}
 func (Noop) RecordActiveInstance(string)                  {}
 func (Noop) RecordInactiveInstance(string)                {}
 func (Noop) RecordInstanceStop(string, string)            {}
+func (Noop) RecordListenerConnectionOpen(string)          {}
+func (Noop) RecordListenerConnectionClose(string, string) {}
+func (Noop) RecordListenerWait(string, time.Duration)     {}
+func (Noop) RecordListenerBytes(string, string, int64)    {}
+func (Noop) RecordListenerDrop(string, string)            {}
+func (Noop) RecordInspectCache(string)                    {}
@@ -19,6 +19,12 @@ func TestNoopRecorderImplementsAllMethods(t *testing.T) {
 	r.RecordActiveInstance("nginx")
 	r.RecordInactiveInstance("nginx")
 	r.RecordInstanceStop("nginx", "expired")
+	r.RecordListenerConnectionOpen("postgres")
+	r.RecordListenerWait("postgres", time.Second)
+	r.RecordListenerBytes("postgres", "in", 42)
+	r.RecordListenerConnectionClose("postgres", "forwarded")
+	r.RecordListenerDrop("postgres", "max_connections")
+	r.RecordInspectCache("hit")
 
 	_ = errors.Is(nil, nil)
 }
@@ -0,0 +1,38 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+var _ sablier.InstanceActivity = (*Provider)(nil)
+
+// InstanceNetworkActivity sums the network counters of every interface of the
+// container from a single stats sample. A container without networking reports
+// no traffic.
+func (p *Provider) InstanceNetworkActivity(ctx context.Context, name string) (sablier.NetworkActivity, error) {
+	res, err := p.Client.ContainerStats(ctx, name, client.ContainerStatsOptions{})
+	if err != nil {
+		return sablier.NetworkActivity{}, fmt.Errorf("cannot get container stats: %w", err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return sablier.NetworkActivity{}, fmt.Errorf("cannot decode container stats: %w", err)
+	}
+
+	var activity sablier.NetworkActivity
+	for _, n := range stats.Networks {
+		activity.RxBytes += n.RxBytes
+		activity.TxBytes += n.TxBytes
+		activity.RxPackets += n.RxPackets
+		activity.TxPackets += n.TxPackets
+	}
+	return activity, nil
+}
@@ -0,0 +1,61 @@
+package docker
+
+// Unit tests for InstanceNetworkActivity.
+// These run without a real Docker daemon.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+// stubStatsClient answers ContainerStats with a fixed body.
+type stubStatsClient struct {
+	client.APIClient
+	body    string
+	err     error
+	options client.ContainerStatsOptions
+}
+
+func (s *stubStatsClient) ContainerStats(_ context.Context, _ string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
+	s.options = options
+	if s.err != nil {
+		return client.ContainerStatsResult{}, s.err
+	}
+	return client.ContainerStatsResult{Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func TestInstanceNetworkActivity(t *testing.T) {
+	t.Run("sums every interface", func(t *testing.T) {
+		stub := &stubStatsClient{body: `{"networks": {
+			"eth0": {"rx_bytes": 100, "tx_bytes": 200, "rx_packets": 1, "tx_packets": 2},
+			"eth1": {"rx_bytes": 10, "tx_bytes": 20, "rx_packets": 3, "tx_packets": 4}
+		}}`}
+		p := &Provider{Client: stub, l: slog.New(slog.DiscardHandler)}
+
+		activity, err := p.InstanceNetworkActivity(t.Context(), "web")
+		assert.NilError(t, err)
+		assert.Equal(t, activity, sablier.NetworkActivity{RxBytes: 110, TxBytes: 220, RxPackets: 4, TxPackets: 6})
+		assert.Assert(t, !stub.options.Stream, "a single sample is enough")
+	})
+	t.Run("no networking", func(t *testing.T) {
+		p := &Provider{Client: &stubStatsClient{body: `{}`}, l: slog.New(slog.DiscardHandler)}
+
+		activity, err := p.InstanceNetworkActivity(t.Context(), "web")
+		assert.NilError(t, err)
+		assert.Equal(t, activity, sablier.NetworkActivity{})
+	})
+	t.Run("error", func(t *testing.T) {
+		p := &Provider{Client: &stubStatsClient{err: errors.New("no such container")}, l: slog.New(slog.DiscardHandler)}
+
+		_, err := p.InstanceNetworkActivity(t.Context(), "web")
+		assert.ErrorContains(t, err, "no such container")
+	})
+}
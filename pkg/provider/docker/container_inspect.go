@@ -136,7 +136,7 @@ func (p *Provider) InstanceInspect(ctx context.Context, name string) (sablier.In
 	}
 
 	labels := spec.Container.Config.Labels
-	sablier.PopulateEnabledAndGroup(&info, labels)
+	p.Policies.PopulateEnabledAndGroup(&info, labels)
 
 	info.Provider = sablier.ProviderDocker
 	info.Docker = &sablier.DockerContainerInfo{
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+var _ sablier.InstanceInspectMany = (*Provider)(nil)
+
+// InstanceInspectMany lists the named containers in one call. The list only
+// summarizes each container, so the ones it does not fully describe are left
+// out, to be inspected one by one: exited containers (exit code and restart
+// policy) and, on daemons that do not report the health in the list, running
+// containers.
+func (p *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	filters := client.Filters{}
+	for _, name := range names {
+		filters.Add("name", name)
+	}
+
+	containers, err := p.Client.ContainerList(ctx, client.ContainerListOptions{
+		All:     true,
+		Filters: filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list containers: %w", err)
+	}
+
+	// The name filter matches substrings: keep the exact matches only.
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	infos := make(map[string]sablier.InstanceInfo, len(names))
+	for _, c := range containers.Items {
+		if len(c.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(c.Names[0], "/")
+		if !wanted[name] {
+			continue
+		}
+		info, ok := p.summaryInfo(name, c)
+		if !ok {
+			continue
+		}
+		infos[name] = info
+	}
+
+	p.l.DebugContext(ctx, "containers inspected", slog.Int("requested", len(names)), slog.Int("answered", len(infos)))
+
+	return infos, nil
+}
+
+// summaryInfo maps a container summary the same way InstanceInspect maps the
+// full container. It reports false when the summary is not enough.
+func (p *Provider) summaryInfo(name string, c container.Summary) (sablier.InstanceInfo, bool) {
+	var info sablier.InstanceInfo
+	switch c.State {
+	case container.StateCreated, container.StatePaused, container.StateRestarting, container.StateRemoving:
+		info = sablier.InstanceInfo{
+			Name:            name,
+			CurrentReplicas: 0,
+			DesiredReplicas: 1,
+			Status:          sablier.InstanceStatusStarting,
+		}
+	case container.StateRunning:
+		if c.Health == nil {
+			return sablier.InstanceInfo{}, false
+		}
+		switch c.Health.Status {
+		case container.Healthy, container.NoHealthcheck:
+			info = sablier.InstanceInfo{
+				Name:            name,
+				CurrentReplicas: 1,
+				DesiredReplicas: 1,
+				Status:          sablier.InstanceStatusReady,
+			}
+		case container.Unhealthy:
+			info = sablier.InstanceInfo{
+				Name:            name,
+				CurrentReplicas: 0,
+				DesiredReplicas: 1,
+				Status:          sablier.InstanceStatusStarting,
+				Message:         "container is running but not healthy yet",
+			}
+		default: // container.Starting
+			info = sablier.InstanceInfo{
+				Name:            name,
+				CurrentReplicas: 0,
+				DesiredReplicas: 1,
+				Status:          sablier.InstanceStatusStarting,
+			}
+		}
+	case container.StateDead:
+		info = sablier.InstanceInfo{
+			Name:            name,
+			CurrentReplicas: 0,
+			DesiredReplicas: 1,
+			Status:          sablier.InstanceStatusError,
+			Message:         "container in \"dead\" state cannot be restarted",
+		}
+	default:
+		return sablier.InstanceInfo{}, false
+	}
+
+	p.Policies.PopulateEnabledAndGroup(&info, c.Labels)
+
+	info.Provider = sablier.ProviderDocker
+	info.Docker = &sablier.DockerContainerInfo{
+		ID:     c.ID,
+		Image:  c.Image,
+		Labels: c.Labels,
+	}
+
+	return info, true
+}
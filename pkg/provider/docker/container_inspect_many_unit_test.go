@@ -0,0 +1,52 @@
+package docker
+
+// Unit tests for InstanceInspectMany.
+// These run without a real Docker daemon.
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+// stubListClient answers ContainerList with a fixed list of containers and
+// records the filters it was called with.
+type stubListClient struct {
+	client.APIClient
+	items   []container.Summary
+	filters client.Filters
+}
+
+func (s *stubListClient) ContainerList(_ context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	s.filters = options.Filters
+	return client.ContainerListResult{Items: s.items}, nil
+}
+
+func TestInstanceInspectMany(t *testing.T) {
+	stub := &stubListClient{items: []container.Summary{
+		{ID: "1", Names: []string{"/web"}, State: container.StateRunning, Health: &container.HealthSummary{Status: container.Healthy}, Labels: map[string]string{"sablier.enable": "true", "sablier.group": "blog"}},
+		{ID: "2", Names: []string{"/db"}, State: container.StateRunning, Health: &container.HealthSummary{Status: container.Starting}},
+		{ID: "3", Names: []string{"/cache"}, State: container.StateExited},
+		{ID: "4", Names: []string{"/legacy"}, State: container.StateRunning},
+		{ID: "5", Names: []string{"/web-old"}, State: container.StateDead},
+	}}
+	p := &Provider{Client: stub, l: slog.New(slog.DiscardHandler)}
+
+	infos, err := p.InstanceInspectMany(t.Context(), []string{"web", "db", "cache", "legacy"})
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, stub.filters, client.Filters{"name": {"web": true, "db": true, "cache": true, "legacy": true}})
+	// Exited containers and running ones without a health summary need a full
+	// inspection; web-old only matched the name filter.
+	assert.Equal(t, len(infos), 2)
+	assert.Equal(t, infos["web"].Status, sablier.InstanceStatusReady)
+	assert.Equal(t, infos["web"].Enabled, "true")
+	assert.DeepEqual(t, infos["web"].Groups, []string{"blog"})
+	assert.Equal(t, infos["web"].Docker.ID, "1")
+	assert.Equal(t, infos["db"].Status, sablier.InstanceStatusStarting)
+}
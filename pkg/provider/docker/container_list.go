@@ -29,22 +29,23 @@ func (p *Provider) InstanceList(ctx context.Context, options provider.InstanceLi
 
 	instances := make([]sablier.InstanceConfiguration, 0, len(containers.Items))
 	for _, c := range containers.Items {
-		instance := containerToInstance(c)
+		instance := p.containerToInstance(c)
 		instances = append(instances, instance)
 	}
 
 	return instances, nil
 }
 
-func containerToInstance(c container.Summary) sablier.InstanceConfiguration {
+func (p *Provider) containerToInstance(c container.Summary) sablier.InstanceConfiguration {
+	name := strings.TrimPrefix(c.Names[0], "/") // Containers name are reported with a leading slash
 	enabled := c.Labels[sablier.LabelEnable]
 	var groups []string
 	if enabled == "true" {
-		groups = sablier.ParseGroups(c.Labels[sablier.LabelGroup])
+		groups = p.Policies.GroupsFromLabels(name, c.Labels)
 	}
 
 	return sablier.InstanceConfiguration{
-		Name:    strings.TrimPrefix(c.Names[0], "/"), // Containers name are reported with a leading slash
+		Name:    name,
 		Groups:  groups,
 		Enabled: enabled,
 	}
@@ -69,7 +70,7 @@ func (p *Provider) InstanceGroups(ctx context.Context) (map[string][]string, err
 	groups := make(map[string][]string)
 	for _, c := range containers.Items {
 		name := strings.TrimPrefix(c.Names[0], "/")
-		for _, groupName := range sablier.ParseGroups(c.Labels[sablier.LabelGroup]) {
+		for _, groupName := range p.Policies.GroupsFromLabels(name, c.Labels) {
 			groups[groupName] = append(groups[groupName], name)
 		}
 	}
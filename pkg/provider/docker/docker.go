@@ -35,6 +35,10 @@ type Provider struct {
 	// Deprecated: transitional flag for backward compatibility; honoring the
 	// restart policy will become the default in v2.
 	HonorRestartPolicy bool
+
+	// Policies are the groups and policies of the configuration file, applied
+	// to the labels of the instances. Nil applies none.
+	Policies *sablier.PolicySet
 }
 
 func New(ctx context.Context, cli *client.Client, logger *slog.Logger, strategy string) (*Provider, error) {
@@ -20,6 +20,10 @@ type Provider struct {
 
 	l      *slog.Logger
 	tracer trace.Tracer
+
+	// Policies are the groups and policies of the configuration file, applied
+	// to the labels of the instances. Nil applies none.
+	Policies *sablier.PolicySet
 }
 
 func New(ctx context.Context, cli *client.Client, logger *slog.Logger) (*Provider, error) {
@@ -17,6 +17,10 @@ func (p *Provider) InstanceInspect(ctx context.Context, name string) (sablier.In
 		return sablier.InstanceInfo{}, err
 	}
 
+	return p.serviceInfo(service)
+}
+
+func (p *Provider) serviceInfo(service *swarm.Service) (sablier.InstanceInfo, error) {
 	if service.Spec.Mode.Replicated == nil {
 		return sablier.InstanceInfo{}, errors.New("swarm service is not in \"replicated\" mode")
 	}
@@ -49,7 +53,7 @@ func (p *Provider) InstanceInspect(ctx context.Context, name string) (sablier.In
 	}
 
 	labels := service.Spec.Labels
-	sablier.PopulateEnabledAndGroup(&info, labels)
+	p.Policies.PopulateEnabledAndGroup(&info, labels)
 
 	var image string
 	if service.Spec.TaskTemplate.ContainerSpec != nil {
@@ -0,0 +1,48 @@
+package dockerswarm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+var _ sablier.InstanceInspectMany = (*Provider)(nil)
+
+// InstanceInspectMany lists the named services in one call. Services that are
+// not in replicated mode are left out, for InstanceInspect to report them.
+func (p *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	filters := client.Filters{}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		filters.Add("name", name)
+		wanted[name] = true
+	}
+
+	services, err := p.Client.ServiceList(ctx, client.ServiceListOptions{
+		Filters: filters,
+		Status:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing services: %w", err)
+	}
+
+	infos := make(map[string]sablier.InstanceInfo, len(names))
+	for _, service := range services.Items {
+		// The "name" filter is a substring match.
+		if !wanted[service.Spec.Name] {
+			continue
+		}
+		info, err := p.serviceInfo(&service)
+		if err != nil {
+			continue
+		}
+		infos[service.Spec.Name] = info
+	}
+
+	p.l.DebugContext(ctx, "services inspected", slog.Int("requested", len(names)), slog.Int("answered", len(infos)))
+
+	return infos, nil
+}
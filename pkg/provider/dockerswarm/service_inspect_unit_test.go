@@ -7,6 +7,7 @@ import (
 
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/client"
+	"github.com/sablierapp/sablier/pkg/sablier"
 	"go.opentelemetry.io/otel"
 	"gotest.tools/v3/assert"
 )
@@ -74,3 +75,49 @@ func TestInstanceInspect_ExactMatchAmongSubstringMatches(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, "demo", info.Name)
 }
+
+func TestInstanceInspectMany(t *testing.T) {
+	t.Parallel()
+
+	replicas := uint64(1)
+	p := newUnitProvider(
+		swarm.Service{
+			ID: "1",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "web", Labels: map[string]string{"sablier.enable": "true"}},
+				Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			},
+			ServiceStatus: &swarm.ServiceStatus{DesiredTasks: 1, RunningTasks: 1},
+		},
+		swarm.Service{
+			ID: "2",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "db"},
+				Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			},
+			ServiceStatus: &swarm.ServiceStatus{},
+		},
+		swarm.Service{
+			ID: "3",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "worker"},
+				Mode:        swarm.ServiceMode{Global: &swarm.GlobalService{}},
+			},
+			ServiceStatus: &swarm.ServiceStatus{},
+		},
+		swarm.Service{
+			ID:   "4",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web-old"}},
+		},
+	)
+
+	infos, err := p.InstanceInspectMany(context.Background(), []string{"web", "db", "worker"})
+	assert.NilError(t, err)
+
+	// The global service is left out for InstanceInspect to report it, and
+	// web-old only matched the name filter.
+	assert.Equal(t, len(infos), 2)
+	assert.Equal(t, infos["web"].Status, sablier.InstanceStatusReady)
+	assert.Equal(t, infos["web"].Swarm.ID, "1")
+	assert.Equal(t, infos["db"].Status, sablier.InstanceStatusStopped)
+}
@@ -46,7 +46,7 @@ func (p *Provider) serviceToInstance(s swarm.Service) (i sablier.InstanceConfigu
 	enabled := s.Spec.Labels[sablier.LabelEnable]
 	var groups []string
 	if enabled == "true" {
-		groups = sablier.ParseGroups(s.Spec.Labels[sablier.LabelGroup])
+		groups = p.Policies.GroupsFromLabels(s.Spec.Name, s.Spec.Labels)
 	}
 
 	return sablier.InstanceConfiguration{
@@ -73,7 +73,7 @@ func (p *Provider) InstanceGroups(ctx context.Context) (map[string][]string, err
 
 	groups := make(map[string][]string)
 	for _, service := range services.Items {
-		for _, groupName := range sablier.ParseGroups(service.Spec.Labels[sablier.LabelGroup]) {
+		for _, groupName := range p.Policies.GroupsFromLabels(service.Spec.Name, service.Spec.Labels) {
 			groups[groupName] = append(groups[groupName], service.Spec.Name)
 		}
 	}
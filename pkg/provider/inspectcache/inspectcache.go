@@ -0,0 +1,225 @@
+// Package inspectcache serves provider instance inspections from memory.
+package inspectcache
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/metrics"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+var (
+	_ sablier.Provider            = (*Provider)(nil)
+	_ sablier.InstanceInspectMany = (*Provider)(nil)
+	_ sablier.InstanceActivity    = (*Provider)(nil)
+	_ sablier.InspectCache        = (*Provider)(nil)
+)
+
+// Provider wraps a sablier.Provider and answers InstanceInspect from memory.
+//
+// An entry is dropped as soon as the wrapped provider reports an event about
+// the instance, or when Sablier starts or stops it, and expires after the TTL
+// otherwise. When the provider declares its events unreliable, or once its
+// event stream stopped, the shorter unreliable events TTL applies. Errors and
+// starting instances are never cached: they are expected to change without
+// notice. InstanceInspectMany is served the same way, and every other method
+// is passed through, InstanceNetworkActivity included.
+type Provider struct {
+	sablier.Provider
+
+	l   *slog.Logger
+	rec metrics.Recorder
+	now func() time.Time
+
+	unreliableTTL time.Duration
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]entry
+	// epoch changes on every invalidation, so that an inspection that raced
+	// with one is not cached.
+	epoch uint64
+}
+
+type entry struct {
+	info    sablier.InstanceInfo
+	expires time.Time
+}
+
+// New wraps p and follows its events until ctx is done.
+func New(ctx context.Context, logger *slog.Logger, p sablier.Provider, conf config.InspectCache, rec metrics.Recorder) *Provider {
+	c := &Provider{
+		Provider:      p,
+		l:             logger.With(slog.String("component", "inspect-cache")),
+		rec:           rec,
+		now:           time.Now,
+		unreliableTTL: min(conf.TTL, conf.UnreliableEventsTTL),
+		ttl:           conf.TTL,
+		entries:       map[string]entry{},
+	}
+	if u, ok := p.(sablier.UnreliableEvents); ok && u.UnreliableEvents() {
+		c.ttl = c.unreliableTTL
+	}
+
+	stream := p.InstanceEvents(ctx, provider.InstanceEventsOptions{})
+	if stream.Events == nil {
+		// Nothing will ever invalidate an entry.
+		c.ttl = c.unreliableTTL
+		return c
+	}
+	go c.watch(ctx, stream)
+	return c
+}
+
+func (c *Provider) InstanceInspect(ctx context.Context, name string) (sablier.InstanceInfo, error) {
+	c.mu.Lock()
+	e, ok := c.entries[name]
+	if ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		c.rec.RecordInspectCache("hit")
+		return e.info, nil
+	}
+	delete(c.entries, name)
+	epoch := c.epoch
+	c.mu.Unlock()
+	c.rec.RecordInspectCache("miss")
+
+	info, err := c.Provider.InstanceInspect(ctx, name)
+	if err != nil || info.Status == sablier.InstanceStatusStarting {
+		return info, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.epoch == epoch && c.ttl > 0 {
+		c.entries[name] = entry{info: info, expires: c.now().Add(c.ttl)}
+	}
+	return info, nil
+}
+
+// InstanceInspectMany answers from memory, and inspects the other instances in
+// one call when the wrapped provider supports it. Instances it cannot answer
+// for are left out, for Sablier to inspect them with InstanceInspect.
+func (c *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	results := make(map[string]sablier.InstanceInfo, len(names))
+	misses := make([]string, 0, len(names))
+	c.mu.Lock()
+	now := c.now()
+	for _, name := range names {
+		if e, ok := c.entries[name]; ok && now.Before(e.expires) {
+			results[name] = e.info
+			continue
+		}
+		delete(c.entries, name)
+		misses = append(misses, name)
+	}
+	epoch := c.epoch
+	c.mu.Unlock()
+	for range results {
+		c.rec.RecordInspectCache("hit")
+	}
+
+	p, ok := c.Provider.(sablier.InstanceInspectMany)
+	if !ok || len(misses) == 0 {
+		return results, nil
+	}
+	infos, err := p.InstanceInspectMany(ctx, misses)
+	if err != nil {
+		// The hits are still valid; the misses are inspected one by one.
+		return results, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, info := range infos {
+		c.rec.RecordInspectCache("miss")
+		results[name] = info
+		if c.epoch == epoch && c.ttl > 0 && info.Status != sablier.InstanceStatusStarting {
+			c.entries[name] = entry{info: info, expires: c.now().Add(c.ttl)}
+		}
+	}
+	return results, nil
+}
+
+func (c *Provider) InstanceStart(ctx context.Context, name string) error {
+	defer c.invalidate(name)
+	return c.Provider.InstanceStart(ctx, name)
+}
+
+func (c *Provider) InstanceStop(ctx context.Context, name string) error {
+	defer c.invalidate(name)
+	return c.Provider.InstanceStop(ctx, name)
+}
+
+func (c *Provider) invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, name)
+	c.epoch++
+}
+
+// FlushInspections drops every entry, so that the next inspections reflect a
+// configuration change.
+func (c *Provider) FlushInspections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.epoch++
+}
+
+// watch drops the entries of the instances the provider reports a change
+// about. Once the stream stopped, no change is reported anymore: the cache is
+// flushed and falls back to the unreliable events TTL.
+func (c *Provider) watch(ctx context.Context, stream sablier.InstanceEventStream) {
+	events, errs := stream.Events, stream.Err
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					c.degrade(ctx, nil)
+				}
+				return
+			}
+			c.invalidate(event.Info.Name)
+		case err, ok := <-errs:
+			if ok && err != nil {
+				c.degrade(ctx, err)
+				return
+			}
+			errs = nil
+		}
+	}
+}
+
+func (c *Provider) degrade(ctx context.Context, err error) {
+	c.l.WarnContext(ctx, "instance events stopped, inspections are cached with the unreliable events ttl",
+		slog.Any("error", err), slog.Duration("ttl", c.unreliableTTL))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = c.unreliableTTL
+	clear(c.entries)
+	c.epoch++
+}
+
+// Unwrap returns the wrapped provider.
+func (c *Provider) Unwrap() sablier.Provider {
+	return c.Provider
+}
+
+// InstanceNetworkActivity passes through to the wrapped provider, which may
+// not support it.
+func (c *Provider) InstanceNetworkActivity(ctx context.Context, name string) (sablier.NetworkActivity, error) {
+	p, ok := c.Provider.(sablier.InstanceActivity)
+	if !ok {
+		return sablier.NetworkActivity{}, sablier.ErrActivityUnsupported{}
+	}
+	return p.InstanceNetworkActivity(ctx, name)
+}
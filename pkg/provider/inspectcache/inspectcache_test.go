@@ -0,0 +1,249 @@
+package inspectcache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/metrics"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/provider/inspectcache"
+	"github.com/sablierapp/sablier/pkg/provider/providertest"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+type countingRecorder struct {
+	metrics.Noop
+	mu      sync.Mutex
+	results map[string]int
+}
+
+func (r *countingRecorder) RecordInspectCache(result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[result]++
+}
+
+func (r *countingRecorder) count(result string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results[result]
+}
+
+func ready(name string) sablier.InstanceInfo {
+	return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}
+}
+
+func stopped(name string) sablier.InstanceInfo {
+	return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusStopped, DesiredReplicas: 1}
+}
+
+func setup(t *testing.T, conf config.InspectCache) (*inspectcache.Provider, *providertest.MockProvider, chan sablier.InstanceEvent, chan error, *countingRecorder) {
+	t.Helper()
+	m := providertest.NewMockProvider(gomock.NewController(t))
+	events := make(chan sablier.InstanceEvent)
+	errs := make(chan error, 1)
+	m.EXPECT().InstanceEvents(gomock.Any(), provider.InstanceEventsOptions{}).
+		Return(sablier.InstanceEventStream{Events: events, Err: errs})
+	rec := &countingRecorder{results: map[string]int{}}
+	return inspectcache.New(t.Context(), slogt.New(t), m, conf, rec), m, events, errs, rec
+}
+
+func TestInstanceInspect_ServesFromMemory(t *testing.T) {
+	c, m, _, _, rec := setup(t, config.InspectCache{TTL: time.Minute, UnreliableEventsTTL: time.Second})
+	m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil).Times(1)
+
+	for range 3 {
+		info, err := c.InstanceInspect(t.Context(), "nginx")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, info, ready("nginx"))
+	}
+	assert.Equal(t, rec.count("miss"), 1)
+	assert.Equal(t, rec.count("hit"), 2)
+}
+
+func TestInstanceInspect_EventInvalidates(t *testing.T) {
+	c, m, events, _, _ := setup(t, config.InspectCache{TTL: time.Minute, UnreliableEventsTTL: time.Second})
+	gomock.InOrder(
+		m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(stopped("nginx"), nil),
+		m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil),
+	)
+
+	info, err := c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Status, sablier.InstanceStatusStopped)
+
+	// The channel is unbuffered: once sent, the next event can only be sent
+	// after the first one was handled.
+	events <- sablier.InstanceEvent{Type: provider.InstanceEventStarted, Info: sablier.InstanceInfo{Name: "nginx"}}
+	events <- sablier.InstanceEvent{Type: provider.InstanceEventUpdated, Info: sablier.InstanceInfo{Name: "other"}}
+
+	info, err = c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Status, sablier.InstanceStatusReady)
+}
+
+func TestInstanceInspect_StartAndStopInvalidate(t *testing.T) {
+	c, m, _, _, _ := setup(t, config.InspectCache{TTL: time.Minute, UnreliableEventsTTL: time.Second})
+	m.EXPECT().InstanceStart(gomock.Any(), "nginx").Return(nil)
+	m.EXPECT().InstanceStop(gomock.Any(), "nginx").Return(nil)
+	m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil).Times(3)
+
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, c.InstanceStart(t.Context(), "nginx"))
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, c.InstanceStop(t.Context(), "nginx"))
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+}
+
+func TestFlushInspections(t *testing.T) {
+	c, m, _, _, _ := setup(t, config.InspectCache{TTL: time.Minute, UnreliableEventsTTL: time.Second})
+	m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil).Times(2)
+
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	c.FlushInspections()
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+}
+
+func TestInstanceInspect_ErrorsAndStartingAreNotCached(t *testing.T) {
+	c, m, _, _, _ := setup(t, config.InspectCache{TTL: time.Minute, UnreliableEventsTTL: time.Second})
+	gomock.InOrder(
+		m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(sablier.InstanceInfo{}, errors.New("unavailable")),
+		m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusStarting}, nil),
+		m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil),
+	)
+
+	_, err := c.InstanceInspect(t.Context(), "nginx")
+	assert.Error(t, err, "unavailable")
+	info, err := c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Status, sablier.InstanceStatusStarting)
+	info, err = c.InstanceInspect(t.Context(), "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Status, sablier.InstanceStatusReady)
+}
+
+func TestInstanceInspect_TTLExpires(t *testing.T) {
+	c, m, _, _, _ := setup(t, config.InspectCache{TTL: 20 * time.Millisecond, UnreliableEventsTTL: time.Millisecond})
+	m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil).Times(2)
+
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	time.Sleep(30 * time.Millisecond)
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+}
+
+func TestInstanceInspect_StreamErrorFallsBackToUnreliableTTL(t *testing.T) {
+	c, m, _, errs, rec := setup(t, config.InspectCache{TTL: time.Hour, UnreliableEventsTTL: 0})
+	m.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready("nginx"), nil).MinTimes(2)
+
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	errs <- errors.New("stream lost")
+
+	// Once the stream error is handled, the cache is flushed and nothing is
+	// cached for longer than the unreliable events TTL.
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		_, _ = c.InstanceInspect(t.Context(), "nginx")
+		if rec.count("miss") > 1 {
+			return poll.Success()
+		}
+		return poll.Continue("the stream error is not handled yet")
+	}, poll.WithDelay(10*time.Millisecond))
+	_, _ = c.InstanceInspect(t.Context(), "nginx")
+	assert.Equal(t, rec.count("miss"), 3)
+}
+
+type unreliableProvider struct {
+	*providertest.MockProvider
+}
+
+func (unreliableProvider) UnreliableEvents() bool { return true }
+
+func TestNew_UnreliableEventsProvider(t *testing.T) {
+	m := providertest.NewMockProvider(gomock.NewController(t))
+	m.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{Events: make(chan sablier.InstanceEvent)})
+	m.EXPECT().InstanceInspect(gomock.Any(), "ct").Return(ready("ct"), nil).Times(2)
+
+	c := inspectcache.New(t.Context(), slogt.New(t), unreliableProvider{m}, config.InspectCache{TTL: time.Hour, UnreliableEventsTTL: 20 * time.Millisecond}, metrics.Noop{})
+
+	_, _ = c.InstanceInspect(t.Context(), "ct")
+	_, _ = c.InstanceInspect(t.Context(), "ct")
+	time.Sleep(30 * time.Millisecond)
+	_, _ = c.InstanceInspect(t.Context(), "ct")
+}
+
+func TestNew_PassesThrough(t *testing.T) {
+	m := providertest.NewMockProvider(gomock.NewController(t))
+	m.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{})
+	m.EXPECT().InstanceGroups(gomock.Any()).Return(map[string][]string{"blog": {"blog"}}, nil)
+
+	c := inspectcache.New(context.Background(), slogt.New(t), m, config.InspectCache{TTL: time.Hour}, metrics.Noop{})
+
+	groups, err := c.InstanceGroups(t.Context())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, groups, map[string][]string{"blog": {"blog"}})
+}
+
+type batchProvider struct {
+	*providertest.MockProvider
+	*providertest.MockInstanceInspectMany
+}
+
+func TestInstanceInspectMany_ServesHitsAndBatchesMisses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := batchProvider{providertest.NewMockProvider(ctrl), providertest.NewMockInstanceInspectMany(ctrl)}
+	p.MockProvider.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{Events: make(chan sablier.InstanceEvent)})
+	rec := &countingRecorder{results: map[string]int{}}
+	c := inspectcache.New(t.Context(), slogt.New(t), p, config.InspectCache{TTL: time.Minute}, rec)
+
+	p.MockProvider.EXPECT().InstanceInspect(gomock.Any(), "web").Return(ready("web"), nil)
+	_, err := c.InstanceInspect(t.Context(), "web")
+	assert.NilError(t, err)
+
+	// web is a hit, db is answered by the batch and cache is left out.
+	p.MockInstanceInspectMany.EXPECT().InstanceInspectMany(gomock.Any(), []string{"db", "cache"}).
+		Return(map[string]sablier.InstanceInfo{"db": stopped("db")}, nil)
+	infos, err := c.InstanceInspectMany(t.Context(), []string{"web", "db", "cache"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, infos, map[string]sablier.InstanceInfo{"web": ready("web"), "db": stopped("db")})
+	assert.Equal(t, rec.count("hit"), 1)
+	assert.Equal(t, rec.count("miss"), 2)
+
+	// db is now cached too.
+	infos, err = c.InstanceInspectMany(t.Context(), []string{"web", "db"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(infos), 2)
+	assert.Equal(t, rec.count("hit"), 3)
+}
+
+type activityProvider struct {
+	*providertest.MockProvider
+	*providertest.MockInstanceActivity
+}
+
+func TestInstanceNetworkActivity_PassesThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := activityProvider{providertest.NewMockProvider(ctrl), providertest.NewMockInstanceActivity(ctrl)}
+	p.MockProvider.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{Events: make(chan sablier.InstanceEvent)})
+	c := inspectcache.New(t.Context(), slogt.New(t), p, config.InspectCache{TTL: time.Minute}, metrics.Noop{})
+
+	p.MockInstanceActivity.EXPECT().InstanceNetworkActivity(gomock.Any(), "web").Return(sablier.NetworkActivity{RxBytes: 42}, nil)
+	activity, err := c.InstanceNetworkActivity(t.Context(), "web")
+	assert.NilError(t, err)
+	assert.Equal(t, activity, sablier.NetworkActivity{RxBytes: 42})
+}
+
+func TestInstanceNetworkActivity_Unsupported(t *testing.T) {
+	c, _, _, _, _ := setup(t, config.InspectCache{TTL: time.Minute})
+
+	_, err := c.InstanceNetworkActivity(t.Context(), "web")
+	assert.ErrorType(t, err, sablier.ErrActivityUnsupported{})
+}
@@ -24,6 +24,10 @@ var cnpgClusterGVR = schema.GroupVersionResource{
 // See https://cloudnative-pg.io/documentation/current/declarative_hibernation/
 const cnpgHibernationAnnotation = "cnpg.io/hibernation"
 
+// cnpgClusterLabel is set by CloudNativePG on the pods of a Cluster, to its
+// name.
+const cnpgClusterLabel = "cnpg.io/cluster"
+
 const (
 	cnpgHibernationOn  = "on"
 	cnpgHibernationOff = "off"
@@ -50,13 +50,13 @@ func (p *Provider) ClusterList(ctx context.Context) ([]sablier.InstanceConfigura
 func (p *Provider) clusterToInstance(u *unstructured.Unstructured) sablier.InstanceConfiguration {
 	config := sablierConfig(u.GetLabels(), u.GetAnnotations())
 	enabled := config[sablier.LabelEnable]
+	parsed := ClusterName(u.GetNamespace(), u.GetName(), ParseOptions{Delimiter: p.delimiter})
+
 	var groups []string
 	if enabled == "true" {
-		groups = sablier.ParseGroups(config[sablier.LabelGroup])
+		groups = p.Policies.GroupsFromLabels(parsed.Original, config)
 	}
 
-	parsed := ClusterName(u.GetNamespace(), u.GetName(), ParseOptions{Delimiter: p.delimiter})
-
 	return sablier.InstanceConfiguration{
 		Name:    parsed.Original,
 		Groups:  groups,
@@ -75,7 +75,7 @@ func (p *Provider) ClusterGroups(ctx context.Context) (map[string][]string, erro
 		u := &items[i]
 		parsed := ClusterName(u.GetNamespace(), u.GetName(), ParseOptions{Delimiter: p.delimiter})
 		config := sablierConfig(u.GetLabels(), u.GetAnnotations())
-		for _, groupName := range sablier.ParseGroups(config[sablier.LabelGroup]) {
+		for _, groupName := range p.Policies.GroupsFromLabels(parsed.Original, config) {
 			groups[groupName] = append(groups[groupName], parsed.Original)
 		}
 	}
@@ -104,7 +104,7 @@ func (p *Provider) deploymentEventHandler(ctx context.Context, instance chan<- s
 					Labels:    d.Labels,
 				},
 			}
-			sablier.PopulateEnabledAndGroup(&info, sablierConfig(d.Labels, d.Annotations))
+			p.Policies.PopulateEnabledAndGroup(&info, sablierConfig(d.Labels, d.Annotations))
 			if wantRemoved {
 				instance <- sablier.InstanceEvent{Type: provider.InstanceEventRemoved, Info: info}
 			}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/sablierapp/sablier/pkg/sablier"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,6 +17,10 @@ func (p *Provider) DeploymentInspect(ctx context.Context, config ParsedName) (sa
 
 	p.l.DebugContext(ctx, "deployment inspected", "deployment", config.Name, "namespace", config.Namespace, "replicas", d.Status.Replicas, "readyReplicas", d.Status.ReadyReplicas, "availableReplicas", d.Status.AvailableReplicas)
 
+	return p.deploymentInfo(d, config), nil
+}
+
+func (p *Provider) deploymentInfo(d *appsv1.Deployment, config ParsedName) sablier.InstanceInfo {
 	var info sablier.InstanceInfo
 	ready := *d.Spec.Replicas != 0 && *d.Spec.Replicas == d.Status.ReadyReplicas
 	if p.readyOnFirstReplica {
@@ -46,7 +51,7 @@ func (p *Provider) DeploymentInspect(ctx context.Context, config ParsedName) (sa
 		}
 	}
 
-	sablier.PopulateEnabledAndGroup(&info, sablierConfig(d.Labels, d.Annotations))
+	p.Policies.PopulateEnabledAndGroup(&info, sablierConfig(d.Labels, d.Annotations))
 
 	var image string
 	if len(d.Spec.Template.Spec.Containers) > 0 {
@@ -64,5 +69,5 @@ func (p *Provider) DeploymentInspect(ctx context.Context, config ParsedName) (sa
 		Labels:    labels,
 	}
 
-	return info, nil
+	return info
 }
@@ -34,13 +34,13 @@ func (p *Provider) DeploymentList(ctx context.Context) ([]sablier.InstanceConfig
 func (p *Provider) deploymentToInstance(d *v1.Deployment) sablier.InstanceConfiguration {
 	config := sablierConfig(d.Labels, d.Annotations)
 	enabled := config[sablier.LabelEnable]
+	parsed := DeploymentName(d, ParseOptions{Delimiter: p.delimiter})
+
 	var groups []string
 	if enabled == "true" {
-		groups = sablier.ParseGroups(config[sablier.LabelGroup])
+		groups = p.Policies.GroupsFromLabels(parsed.Original, config)
 	}
 
-	parsed := DeploymentName(d, ParseOptions{Delimiter: p.delimiter})
-
 	return sablier.InstanceConfiguration{
 		Name:    parsed.Original,
 		Groups:  groups,
@@ -66,7 +66,7 @@ func (p *Provider) DeploymentGroups(ctx context.Context) (map[string][]string, e
 	for _, deployment := range deployments.Items {
 		parsed := DeploymentName(&deployment, ParseOptions{Delimiter: p.delimiter})
 		config := sablierConfig(deployment.Labels, deployment.Annotations)
-		for _, groupName := range sablier.ParseGroups(config[sablier.LabelGroup]) {
+		for _, groupName := range p.Policies.GroupsFromLabels(parsed.Original, config) {
 			groups[groupName] = append(groups[groupName], parsed.Original)
 		}
 	}
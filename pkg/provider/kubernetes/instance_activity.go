@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ sablier.InstanceActivity = (*Provider)(nil)
+
+// kubeletSummary is the part of the kubelet stats summary
+// (/api/v1/nodes/{node}/proxy/stats/summary) Sablier reads.
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Network *struct {
+			networkStats
+			Interfaces []networkStats `json:"interfaces"`
+		} `json:"network"`
+	} `json:"pods"`
+}
+
+type networkStats struct {
+	RxBytes *uint64 `json:"rxBytes"`
+	TxBytes *uint64 `json:"txBytes"`
+}
+
+// InstanceNetworkActivity sums the network counters of the running pods of the
+// workload, from the stats summary of the kubelets running them. Sablier
+// needs to get nodes/proxy. The kubelet reports no packet counts.
+func (p *Provider) InstanceNetworkActivity(ctx context.Context, name string) (sablier.NetworkActivity, error) {
+	parsed, err := ParseName(name, ParseOptions{Delimiter: p.delimiter})
+	if err != nil {
+		return sablier.NetworkActivity{}, err
+	}
+	selector, err := p.podSelector(ctx, parsed)
+	if err != nil {
+		return sablier.NetworkActivity{}, err
+	}
+	pods, err := p.Client.CoreV1().Pods(parsed.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return sablier.NetworkActivity{}, fmt.Errorf("cannot list pods: %w", err)
+	}
+
+	// One summary per node, for the pods it runs.
+	nodes := map[string]map[string]struct{}{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" {
+			continue
+		}
+		if nodes[pod.Spec.NodeName] == nil {
+			nodes[pod.Spec.NodeName] = map[string]struct{}{}
+		}
+		nodes[pod.Spec.NodeName][pod.Name] = struct{}{}
+	}
+
+	var activity sablier.NetworkActivity
+	for node, names := range nodes {
+		summaryOf := p.statsSummary
+		if summaryOf == nil {
+			summaryOf = p.nodeStatsSummary
+		}
+		raw, err := summaryOf(ctx, node)
+		if err != nil {
+			return sablier.NetworkActivity{}, fmt.Errorf("cannot get the stats summary of node %s: %w", node, err)
+		}
+		var summary kubeletSummary
+		if err := json.Unmarshal(raw, &summary); err != nil {
+			return sablier.NetworkActivity{}, fmt.Errorf("cannot decode the stats summary of node %s: %w", node, err)
+		}
+		for _, pod := range summary.Pods {
+			if _, ok := names[pod.PodRef.Name]; !ok || pod.PodRef.Namespace != parsed.Namespace || pod.Network == nil {
+				continue
+			}
+			interfaces := pod.Network.Interfaces
+			if len(interfaces) == 0 {
+				interfaces = []networkStats{pod.Network.networkStats}
+			}
+			for _, i := range interfaces {
+				if i.RxBytes != nil {
+					activity.RxBytes += *i.RxBytes
+				}
+				if i.TxBytes != nil {
+					activity.TxBytes += *i.TxBytes
+				}
+			}
+		}
+	}
+	return activity, nil
+}
+
+// podSelector returns the label selector of the pods of the workload.
+func (p *Provider) podSelector(ctx context.Context, parsed ParsedName) (string, error) {
+	var selector *metav1.LabelSelector
+	switch parsed.Kind {
+	case "deployment":
+		d, err := p.Client.AppsV1().Deployments(parsed.Namespace).Get(ctx, parsed.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting deployment: %w", err)
+		}
+		selector = d.Spec.Selector
+	case "statefulset":
+		ss, err := p.Client.AppsV1().StatefulSets(parsed.Namespace).Get(ctx, parsed.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting statefulset: %w", err)
+		}
+		selector = ss.Spec.Selector
+	case KindCNPGCluster:
+		return cnpgClusterLabel + "=" + parsed.Name, nil
+	default:
+		return "", fmt.Errorf("unsupported kind \"%s\" must be one of \"deployment\", \"statefulset\", \"cnpgcluster\"", parsed.Kind)
+	}
+	if selector == nil {
+		return "", fmt.Errorf("%s %s/%s has no pod selector", parsed.Kind, parsed.Namespace, parsed.Name)
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid pod selector: %w", err)
+	}
+	return s.String(), nil
+}
+
+// nodeStatsSummary gets the stats summary of node through the API server.
+func (p *Provider) nodeStatsSummary(ctx context.Context, node string) ([]byte, error) {
+	return p.Client.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", node, "proxy", "stats", "summary").
+		DoRaw(ctx)
+}
@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func runningPod(name, node string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestInstanceNetworkActivity(t *testing.T) {
+	t.Parallel()
+
+	web := map[string]string{"app": "web"}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: web}},
+		},
+		runningPod("web-a", "node-1", web),
+		runningPod("web-b", "node-2", web),
+		runningPod("api", "node-1", map[string]string{"app": "api"}),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-pending", Namespace: "default", Labels: web},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	)
+	summaries := map[string]string{
+		"node-1": `{"pods": [
+			{"podRef": {"name": "web-a", "namespace": "default"}, "network": {"rxBytes": 100, "txBytes": 10, "interfaces": [{"name": "eth0", "rxBytes": 100, "txBytes": 10}, {"name": "eth1", "rxBytes": 5, "txBytes": 1}]}},
+			{"podRef": {"name": "api", "namespace": "default"}, "network": {"rxBytes": 1000, "txBytes": 1000}}
+		]}`,
+		"node-2": `{"pods": [
+			{"podRef": {"name": "web-b", "namespace": "default"}, "network": {"rxBytes": 20, "txBytes": 2}},
+			{"podRef": {"name": "web-b", "namespace": "other"}, "network": {"rxBytes": 1000, "txBytes": 1000}}
+		]}`,
+	}
+	var nodes []string
+	p := &Provider{Client: client, delimiter: "_", l: slog.New(slog.DiscardHandler)}
+	p.statsSummary = func(_ context.Context, node string) ([]byte, error) {
+		nodes = append(nodes, node)
+		summary, ok := summaries[node]
+		if !ok {
+			return nil, fmt.Errorf("unknown node %s", node)
+		}
+		return []byte(summary), nil
+	}
+
+	activity, err := p.InstanceNetworkActivity(context.Background(), "deployment_default_web_1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, activity, sablier.NetworkActivity{RxBytes: 125, TxBytes: 13})
+	assert.Equal(t, len(nodes), 2)
+}
+
+func TestInstanceNetworkActivity_UnknownWorkload(t *testing.T) {
+	t.Parallel()
+
+	p := &Provider{Client: fake.NewSimpleClientset(), delimiter: "_", l: slog.New(slog.DiscardHandler)}
+	_, err := p.InstanceNetworkActivity(context.Background(), "statefulset_default_db_1")
+	assert.ErrorContains(t, err, "error getting statefulset")
+}
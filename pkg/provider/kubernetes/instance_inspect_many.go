@@ -0,0 +1,69 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ sablier.InstanceInspectMany = (*Provider)(nil)
+
+// InstanceInspectMany lists the Sablier-enabled deployments and statefulsets
+// once per namespace and kind of the named instances, instead of getting them
+// one by one. Workloads the label selector does not return, CloudNativePG
+// clusters and names that do not parse are left out, for InstanceInspect to
+// report them.
+func (p *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	type scope struct{ kind, namespace string }
+	wanted := map[scope]map[string][]ParsedName{}
+	for _, name := range names {
+		parsed, err := ParseName(name, ParseOptions{Delimiter: p.delimiter})
+		if err != nil || (parsed.Kind != "deployment" && parsed.Kind != "statefulset") {
+			continue
+		}
+		sc := scope{kind: parsed.Kind, namespace: parsed.Namespace}
+		if wanted[sc] == nil {
+			wanted[sc] = map[string][]ParsedName{}
+		}
+		// Several names can target the same workload with different replicas.
+		wanted[sc][parsed.Name] = append(wanted[sc][parsed.Name], parsed)
+	}
+
+	opts := metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{
+			MatchLabels: map[string]string{sablier.LabelEnable: "true"},
+		}),
+	}
+
+	infos := make(map[string]sablier.InstanceInfo, len(names))
+	for sc, workloads := range wanted {
+		switch sc.kind {
+		case "deployment":
+			list, err := p.Client.AppsV1().Deployments(sc.namespace).List(ctx, opts)
+			if err != nil {
+				return nil, fmt.Errorf("error listing deployments: %w", err)
+			}
+			for i := range list.Items {
+				for _, parsed := range workloads[list.Items[i].Name] {
+					infos[parsed.Original] = p.deploymentInfo(&list.Items[i], parsed)
+				}
+			}
+		case "statefulset":
+			list, err := p.Client.AppsV1().StatefulSets(sc.namespace).List(ctx, opts)
+			if err != nil {
+				return nil, fmt.Errorf("error listing statefulsets: %w", err)
+			}
+			for i := range list.Items {
+				for _, parsed := range workloads[list.Items[i].Name] {
+					infos[parsed.Original] = p.statefulSetInfo(&list.Items[i], parsed)
+				}
+			}
+		}
+	}
+
+	p.l.DebugContext(ctx, "workloads inspected", "requested", len(names), "answered", len(infos))
+
+	return infos, nil
+}
@@ -0,0 +1,59 @@
+package kubernetes
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInstanceInspectMany(t *testing.T) {
+	t.Parallel()
+
+	one, zero := int32(1), int32(0)
+	enabled := map[string]string{sablier.LabelEnable: "true"}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: enabled},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: enabled},
+			Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data", Labels: enabled},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &one},
+		},
+	)
+	p := &Provider{Client: client, delimiter: "_", l: slog.New(slog.DiscardHandler)}
+
+	infos, err := p.InstanceInspectMany(context.Background(), []string{
+		"deployment_default_web_1",
+		"deployment_default_api_1",
+		"deployment_default_unlabeled_1",
+		"statefulset_data_db_1",
+		"cnpgcluster_data_pg_1",
+		"invalid",
+	})
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(infos), 3)
+	assert.Equal(t, infos["deployment_default_web_1"].Status, sablier.InstanceStatusReady)
+	assert.Equal(t, infos["deployment_default_web_1"].Kubernetes.Kind, "deployment")
+	assert.Equal(t, infos["deployment_default_api_1"].Status, sablier.InstanceStatusStopped)
+	assert.Equal(t, infos["statefulset_data_db_1"].Status, sablier.InstanceStatusStarting)
+
+	// One list per namespace and kind.
+	assert.Equal(t, len(client.Actions()), 2)
+}
@@ -26,6 +26,13 @@ type Provider struct {
 	readyOnFirstReplica bool
 	l                   *slog.Logger
 	tracer              trace.Tracer
+	// statsSummary gets the kubelet stats summary of a node. Nil gets it
+	// through the API server node proxy.
+	statsSummary func(ctx context.Context, node string) ([]byte, error)
+
+	// Policies are the groups and policies of the configuration file, applied
+	// to the labels of the instances. Nil applies none.
+	Policies *sablier.PolicySet
 }
 
 func New(ctx context.Context, client *k8s.Clientset, dynamicClient dynamic.Interface, logger *slog.Logger, config providerConfig.Kubernetes) (*Provider, error) {
@@ -105,7 +105,7 @@ func (p *Provider) statefulSetEventHandler(ctx context.Context, instance chan<-
 					Labels:    ss.Labels,
 				},
 			}
-			sablier.PopulateEnabledAndGroup(&info, sablierConfig(ss.Labels, ss.Annotations))
+			p.Policies.PopulateEnabledAndGroup(&info, sablierConfig(ss.Labels, ss.Annotations))
 			if wantRemoved {
 				instance <- sablier.InstanceEvent{Type: provider.InstanceEventRemoved, Info: info}
 			}
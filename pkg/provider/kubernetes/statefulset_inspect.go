@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sablierapp/sablier/pkg/sablier"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,6 +14,10 @@ func (p *Provider) StatefulSetInspect(ctx context.Context, config ParsedName) (s
 		return sablier.InstanceInfo{}, err
 	}
 
+	return p.statefulSetInfo(ss, config), nil
+}
+
+func (p *Provider) statefulSetInfo(ss *appsv1.StatefulSet, config ParsedName) sablier.InstanceInfo {
 	var info sablier.InstanceInfo
 	ready := *ss.Spec.Replicas != 0 && *ss.Spec.Replicas == ss.Status.ReadyReplicas
 	if p.readyOnFirstReplica {
@@ -43,7 +48,7 @@ func (p *Provider) StatefulSetInspect(ctx context.Context, config ParsedName) (s
 		}
 	}
 
-	sablier.PopulateEnabledAndGroup(&info, sablierConfig(ss.Labels, ss.Annotations))
+	p.Policies.PopulateEnabledAndGroup(&info, sablierConfig(ss.Labels, ss.Annotations))
 
 	var image string
 	if len(ss.Spec.Template.Spec.Containers) > 0 {
@@ -61,5 +66,5 @@ func (p *Provider) StatefulSetInspect(ctx context.Context, config ParsedName) (s
 		Labels:    labels,
 	}
 
-	return info, nil
+	return info
 }
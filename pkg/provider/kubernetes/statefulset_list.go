@@ -34,13 +34,13 @@ func (p *Provider) StatefulSetList(ctx context.Context) ([]sablier.InstanceConfi
 func (p *Provider) statefulSetToInstance(ss *v1.StatefulSet) sablier.InstanceConfiguration {
 	config := sablierConfig(ss.Labels, ss.Annotations)
 	enabled := config[sablier.LabelEnable]
+	parsed := StatefulSetName(ss, ParseOptions{Delimiter: p.delimiter})
+
 	var groups []string
 	if enabled == "true" {
-		groups = sablier.ParseGroups(config[sablier.LabelGroup])
+		groups = p.Policies.GroupsFromLabels(parsed.Original, config)
 	}
 
-	parsed := StatefulSetName(ss, ParseOptions{Delimiter: p.delimiter})
-
 	return sablier.InstanceConfiguration{
 		Name:    parsed.Original,
 		Groups:  groups,
@@ -65,7 +65,7 @@ func (p *Provider) StatefulSetGroups(ctx context.Context) (map[string][]string,
 	for _, ss := range statefulSets.Items {
 		parsed := StatefulSetName(&ss, ParseOptions{Delimiter: p.delimiter})
 		config := sablierConfig(ss.Labels, ss.Annotations)
-		for _, groupName := range sablier.ParseGroups(config[sablier.LabelGroup]) {
+		for _, groupName := range p.Policies.GroupsFromLabels(parsed.Original, config) {
 			groups[groupName] = append(groups[groupName], parsed.Original)
 		}
 	}
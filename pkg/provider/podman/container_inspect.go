@@ -7,12 +7,35 @@ import (
 	"github.com/sablierapp/sablier/pkg/sablier"
 )
 
+var _ sablier.InstanceInspectMany = (*Provider)(nil)
+
 func (p *Provider) InstanceInspect(ctx context.Context, name string) (sablier.InstanceInfo, error) {
 	info, err := p.Provider.InstanceInspect(ctx, name)
 	if err != nil {
 		return info, err
 	}
 
+	return podmanInfo(name, info)
+}
+
+// InstanceInspectMany lists the containers through the Docker provider and
+// reports them as Podman containers.
+func (p *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	infos, err := p.Provider.InstanceInspectMany(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, info := range infos {
+		if infos[name], err = podmanInfo(name, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return infos, nil
+}
+
+func podmanInfo(name string, info sablier.InstanceInfo) (sablier.InstanceInfo, error) {
 	if info.Docker == nil {
 		return sablier.InstanceInfo{}, fmt.Errorf("podman: docker provider did not populate Docker field for %q", name)
 	}
@@ -143,3 +143,155 @@ func (mr *MockProviderMockRecorder) InstanceStop(ctx, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceStop", reflect.TypeOf((*MockProvider)(nil).InstanceStop), ctx, name)
 }
+
+// MockUnreliableEvents is a mock of UnreliableEvents interface.
+type MockUnreliableEvents struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnreliableEventsMockRecorder
+	isgomock struct{}
+}
+
+// MockUnreliableEventsMockRecorder is the mock recorder for MockUnreliableEvents.
+type MockUnreliableEventsMockRecorder struct {
+	mock *MockUnreliableEvents
+}
+
+// NewMockUnreliableEvents creates a new mock instance.
+func NewMockUnreliableEvents(ctrl *gomock.Controller) *MockUnreliableEvents {
+	mock := &MockUnreliableEvents{ctrl: ctrl}
+	mock.recorder = &MockUnreliableEventsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnreliableEvents) EXPECT() *MockUnreliableEventsMockRecorder {
+	return m.recorder
+}
+
+// UnreliableEvents mocks base method.
+func (m *MockUnreliableEvents) UnreliableEvents() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreliableEvents")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UnreliableEvents indicates an expected call of UnreliableEvents.
+func (mr *MockUnreliableEventsMockRecorder) UnreliableEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreliableEvents", reflect.TypeOf((*MockUnreliableEvents)(nil).UnreliableEvents))
+}
+
+// MockInstanceInspectMany is a mock of InstanceInspectMany interface.
+type MockInstanceInspectMany struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceInspectManyMockRecorder
+	isgomock struct{}
+}
+
+// MockInstanceInspectManyMockRecorder is the mock recorder for MockInstanceInspectMany.
+type MockInstanceInspectManyMockRecorder struct {
+	mock *MockInstanceInspectMany
+}
+
+// NewMockInstanceInspectMany creates a new mock instance.
+func NewMockInstanceInspectMany(ctrl *gomock.Controller) *MockInstanceInspectMany {
+	mock := &MockInstanceInspectMany{ctrl: ctrl}
+	mock.recorder = &MockInstanceInspectManyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstanceInspectMany) EXPECT() *MockInstanceInspectManyMockRecorder {
+	return m.recorder
+}
+
+// InstanceInspectMany mocks base method.
+func (m *MockInstanceInspectMany) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceInspectMany", ctx, names)
+	ret0, _ := ret[0].(map[string]sablier.InstanceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceInspectMany indicates an expected call of InstanceInspectMany.
+func (mr *MockInstanceInspectManyMockRecorder) InstanceInspectMany(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceInspectMany", reflect.TypeOf((*MockInstanceInspectMany)(nil).InstanceInspectMany), ctx, names)
+}
+
+// MockInstanceActivity is a mock of InstanceActivity interface.
+type MockInstanceActivity struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceActivityMockRecorder
+	isgomock struct{}
+}
+
+// MockInstanceActivityMockRecorder is the mock recorder for MockInstanceActivity.
+type MockInstanceActivityMockRecorder struct {
+	mock *MockInstanceActivity
+}
+
+// NewMockInstanceActivity creates a new mock instance.
+func NewMockInstanceActivity(ctrl *gomock.Controller) *MockInstanceActivity {
+	mock := &MockInstanceActivity{ctrl: ctrl}
+	mock.recorder = &MockInstanceActivityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstanceActivity) EXPECT() *MockInstanceActivityMockRecorder {
+	return m.recorder
+}
+
+// InstanceNetworkActivity mocks base method.
+func (m *MockInstanceActivity) InstanceNetworkActivity(ctx context.Context, name string) (sablier.NetworkActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceNetworkActivity", ctx, name)
+	ret0, _ := ret[0].(sablier.NetworkActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceNetworkActivity indicates an expected call of InstanceNetworkActivity.
+func (mr *MockInstanceActivityMockRecorder) InstanceNetworkActivity(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceNetworkActivity", reflect.TypeOf((*MockInstanceActivity)(nil).InstanceNetworkActivity), ctx, name)
+}
+
+// MockInspectCache is a mock of InspectCache interface.
+type MockInspectCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockInspectCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockInspectCacheMockRecorder is the mock recorder for MockInspectCache.
+type MockInspectCacheMockRecorder struct {
+	mock *MockInspectCache
+}
+
+// NewMockInspectCache creates a new mock instance.
+func NewMockInspectCache(ctrl *gomock.Controller) *MockInspectCache {
+	mock := &MockInspectCache{ctrl: ctrl}
+	mock.recorder = &MockInspectCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInspectCache) EXPECT() *MockInspectCacheMockRecorder {
+	return m.recorder
+}
+
+// FlushInspections mocks base method.
+func (m *MockInspectCache) FlushInspections() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FlushInspections")
+}
+
+// FlushInspections indicates an expected call of FlushInspections.
+func (mr *MockInspectCacheMockRecorder) FlushInspections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushInspections", reflect.TypeOf((*MockInspectCache)(nil).FlushInspections))
+}
@@ -0,0 +1,79 @@
+package proxmoxlxc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	proxmox "github.com/luthermonson/go-proxmox"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+var _ sablier.InstanceInspectMany = (*Provider)(nil)
+
+// InstanceInspectMany reads the status of every container of the cluster with
+// a single /cluster/resources call. It only answers for the containers that
+// are not running: the readiness of a running container depends on its network
+// interfaces, which InstanceInspect checks one container at a time. Containers
+// with a pending start task and names that match several containers are left
+// out as well.
+func (p *Provider) InstanceInspectMany(ctx context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+	// Built directly rather than with client.Cluster, which first queries the
+	// cluster status.
+	cluster := (&proxmox.Cluster{}).New(p.client)
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list cluster resources: %w", err)
+	}
+
+	// Index the containers the same ways resolve finds them.
+	matches := make(map[string][]*proxmox.ClusterResource)
+	for _, r := range resources {
+		if r.Type != "lxc" {
+			continue
+		}
+		vmid := strconv.FormatUint(r.VMID, 10)
+		matches[r.Name] = append(matches[r.Name], r)
+		matches[vmid] = append(matches[vmid], r)
+		matches[r.Node+"/"+vmid] = append(matches[r.Node+"/"+vmid], r)
+	}
+
+	p.mu.RLock()
+	pending := make(map[string]bool, len(p.pendingTasks))
+	for name := range p.pendingTasks {
+		pending[name] = true
+	}
+	p.mu.RUnlock()
+
+	infos := make(map[string]sablier.InstanceInfo, len(names))
+	for _, name := range names {
+		if pending[name] || len(matches[name]) != 1 {
+			continue
+		}
+		r := matches[name][0]
+		switch r.Status {
+		case "running":
+			continue
+		case "stopped":
+			infos[name] = sablier.InstanceInfo{
+				Name:            r.Name,
+				CurrentReplicas: 0,
+				DesiredReplicas: p.desiredReplicas,
+				Status:          sablier.InstanceStatusStopped,
+			}
+		default:
+			infos[name] = sablier.InstanceInfo{
+				Name:            r.Name,
+				CurrentReplicas: 0,
+				DesiredReplicas: p.desiredReplicas,
+				Status:          sablier.InstanceStatusError,
+				Message:         fmt.Sprintf("container status %q not handled", r.Status),
+			}
+		}
+	}
+
+	p.l.DebugContext(ctx, "containers inspected", slog.Int("requested", len(names)), slog.Int("answered", len(infos)))
+
+	return infos, nil
+}
@@ -0,0 +1,42 @@
+package proxmoxlxc_test
+
+import (
+	"testing"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/provider/proxmoxlxc"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+func TestProxmoxLXCProvider_InstanceInspectMany(t *testing.T) {
+	t.Parallel()
+
+	server := proxmoxlxc.MockServer(t, []string{"pve1", "pve2"}, []proxmoxlxc.TestContainer{
+		{VMID: 100, Name: "web", Status: "running", Tags: "sablier", Node: "pve1"},
+		{VMID: 101, Name: "db", Status: "stopped", Tags: "sablier", Node: "pve1"},
+		{VMID: 102, Name: "backup", Status: "unknown", Tags: "sablier", Node: "pve2"},
+		{VMID: 103, Name: "dup", Status: "stopped", Node: "pve1"},
+		{VMID: 104, Name: "dup", Status: "stopped", Node: "pve2"},
+	})
+	defer server.Close()
+
+	p, err := proxmoxlxc.New(t.Context(), proxmoxlxc.NewTestClient(server.URL), slogt.New(t))
+	assert.NilError(t, err)
+
+	infos, err := p.InstanceInspectMany(t.Context(), []string{"web", "db", "102", "dup", "pve2/104", "missing"})
+	assert.NilError(t, err)
+
+	// Running containers, ambiguous and unknown names are left out for
+	// InstanceInspect.
+	assert.DeepEqual(t, infos, map[string]sablier.InstanceInfo{
+		"db": {Name: "db", DesiredReplicas: 1, Status: sablier.InstanceStatusStopped},
+		"102": {
+			Name:            "backup",
+			DesiredReplicas: 1,
+			Status:          sablier.InstanceStatusError,
+			Message:         "container status \"unknown\" not handled",
+		},
+		"pve2/104": {Name: "dup", DesiredReplicas: 1, Status: sablier.InstanceStatusStopped},
+	})
+}
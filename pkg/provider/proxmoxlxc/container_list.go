@@ -24,7 +24,7 @@ func (p *Provider) InstanceList(ctx context.Context, options provider.InstanceLi
 		}
 		instances = append(instances, sablier.InstanceConfiguration{
 			Name:    d.ref.name,
-			Groups:  extractGroups(d.tags),
+			Groups:  p.instanceGroups(d.ref.name, d.tags),
 			Enabled: "true",
 		})
 	}
@@ -42,7 +42,7 @@ func (p *Provider) InstanceGroups(ctx context.Context) (map[string][]string, err
 
 	groups := make(map[string][]string)
 	for _, d := range discovered {
-		for _, groupName := range extractGroups(d.tags) {
+		for _, groupName := range p.instanceGroups(d.ref.name, d.tags) {
 			groups[groupName] = append(groups[groupName], d.ref.name)
 		}
 	}
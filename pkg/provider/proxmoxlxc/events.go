@@ -14,6 +14,14 @@ import (
 // before InstanceEvents gives up and reports a terminal error.
 const maxConsecutivePollErrors = 5
 
+var _ sablier.UnreliableEvents = (*Provider)(nil)
+
+// UnreliableEvents reports that the events are polled: they only carry the
+// started and stopped transitions seen between two scans.
+func (p *Provider) UnreliableEvents() bool {
+	return true
+}
+
 // InstanceEvents polls Proxmox for status changes and emits events when
 // instances transition from running to stopped (or vice versa). Proxmox VE
 // does not provide a real-time event stream, so polling is used.
@@ -49,6 +49,10 @@ type Provider struct {
 	mu           sync.RWMutex
 	cache        map[string]containerRef // hostname or VMID string → ref
 	pendingTasks map[string]*pendingTask // instance name → start task awaiting completion
+
+	// Policies are the groups and policies of the configuration file, applied
+	// to the labels of the instances. Nil applies none.
+	Policies *sablier.PolicySet
 }
 
 // New creates a new Proxmox LXC provider and verifies the connection.
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	proxmox "github.com/luthermonson/go-proxmox"
+	"github.com/sablierapp/sablier/pkg/sablier"
 )
 
 const (
@@ -42,3 +43,14 @@ func extractGroups(tags []string) []string {
 	}
 	return groups
 }
+
+// instanceGroups returns the groups of the container name: the groups of its
+// "sablier-group-<name>" tags and the groups the configured policies assign
+// to it.
+func (p *Provider) instanceGroups(name string, tags []string) []string {
+	labels := map[string]string{}
+	if slices.ContainsFunc(tags, func(t string) bool { return strings.HasPrefix(t, groupPrefix) }) {
+		labels[sablier.LabelGroup] = strings.Join(extractGroups(tags), ",")
+	}
+	return p.Policies.GroupsFromLabels(name, labels)
+}
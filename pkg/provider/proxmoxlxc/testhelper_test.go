@@ -80,6 +80,23 @@ func mockServer(t *testing.T, nodes []string, containers []testContainer) *httpt
 		writeJSON(t, w, ns)
 	})
 
+	// GET /api2/json/cluster/resources?type=vm
+	mux.HandleFunc("GET /api2/json/cluster/resources", func(w http.ResponseWriter, r *http.Request) {
+		resources := make([]map[string]any, 0, len(containers))
+		for _, c := range containers {
+			resources = append(resources, map[string]any{
+				"id":     fmt.Sprintf("lxc/%d", c.VMID),
+				"type":   "lxc",
+				"vmid":   c.VMID,
+				"name":   c.Name,
+				"status": c.Status,
+				"tags":   c.Tags,
+				"node":   c.Node,
+			})
+		}
+		writeJSON(t, w, resources)
+	})
+
 	// Per-node container list and operations
 	for _, nodeName := range nodes {
 		node := nodeName // capture
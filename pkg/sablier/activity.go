@@ -0,0 +1,196 @@
+package sablier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/provider"
+)
+
+// ActivitySource is where Sablier observes the activity of an instance,
+// besides the requests of its session.
+type ActivitySource string
+
+// ActivityNetwork observes the network traffic of the instance.
+const ActivityNetwork ActivitySource = "network"
+
+// ParseActivitySource parses the value of the sablier.idle.activity label.
+func ParseActivitySource(v string) (ActivitySource, error) {
+	if ActivitySource(v) != ActivityNetwork {
+		return "", fmt.Errorf("unknown activity source %q, must be %q", v, ActivityNetwork)
+	}
+	return ActivityNetwork, nil
+}
+
+// tracksActivity reports whether the session of instance is kept alive by its
+// traffic. A fixed session ends at its deadline whatever the traffic.
+func tracksActivity(instance InstanceInfo) bool {
+	return instance.Config != nil && instance.Config.IdleActivity == ActivityNetwork && instance.SessionDeadline == nil
+}
+
+// supportsActivity reports whether p, or the providers it wraps, can report
+// the network activity of its instances.
+func supportsActivity(p Provider) bool {
+	for {
+		if _, ok := p.(InstanceActivity); !ok {
+			return false
+		}
+		wrapper, ok := p.(interface{ Unwrap() Provider })
+		if !ok {
+			return true
+		}
+		p = wrapper.Unwrap()
+	}
+}
+
+// CheckActivitySupport fails when a policy or an enabled instance keeps
+// sessions alive with the traffic of the instances (sablier.idle.activity)
+// while the provider cannot report it, so the label is not silently ignored.
+func (s *Sablier) CheckActivitySupport(ctx context.Context) error {
+	if supportsActivity(s.provider) {
+		return nil
+	}
+	if p := s.policies.Load(); p != nil {
+		for _, policy := range p.Policies {
+			if policy.Labels[LabelIdleActivity] != "" {
+				return fmt.Errorf("policy %q sets %s: %w", policy.Name, LabelIdleActivity, ErrActivityUnsupported{})
+			}
+		}
+	}
+
+	instances, err := s.provider.InstanceList(ctx, provider.InstanceListOptions{All: true})
+	if err != nil {
+		s.l.WarnContext(ctx, "cannot list instances to check their activity source", slog.Any("error", err))
+		return nil
+	}
+	for _, inst := range instances {
+		if !inst.IsEnabled() {
+			continue
+		}
+		info, err := s.provider.InstanceInspect(ctx, inst.Name)
+		if err != nil {
+			s.l.WarnContext(ctx, "cannot inspect instance to check its activity source", slog.String("instance", inst.Name), slog.Any("error", err))
+			continue
+		}
+		if info.Config != nil && info.Config.IdleActivity != "" {
+			return fmt.Errorf("instance %q is labelled %s: %w", inst.Name, LabelIdleActivity, ErrActivityUnsupported{})
+		}
+	}
+	return nil
+}
+
+// WatchActivity samples the network traffic of the instances whose session
+// is kept alive by it, and renews the session of those that had traffic since
+// the previous sample, as a request would.
+func (s *Sablier) WatchActivity(ctx context.Context) {
+	ticker := time.NewTicker(s.ActivityRefreshFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.l.InfoContext(ctx, "stop watching instance activity", slog.Any("reason", ctx.Err()))
+			return
+		case <-ticker.C:
+			s.reconcileActivity(ctx)
+		}
+	}
+}
+
+func (s *Sablier) reconcileActivity(ctx context.Context) {
+	var tracked []InstanceSession
+	err := s.sessions.Range(ctx, func(info InstanceInfo, expiresAt time.Time) {
+		if tracksActivity(info) {
+			tracked = append(tracked, InstanceSession{Instance: info, ExpiresAt: expiresAt})
+		}
+	})
+	if err != nil {
+		s.l.ErrorContext(ctx, "activity reconciliation failed to list sessions", slog.Any("error", err))
+		return
+	}
+
+	names := make(map[string]struct{}, len(tracked))
+	for _, session := range tracked {
+		name := session.Instance.Name
+		names[name] = struct{}{}
+		active, err := s.sampleActivity(ctx, name)
+		if err != nil {
+			s.l.WarnContext(ctx, "cannot sample the activity of the instance", slog.String("instance", name), slog.Any("error", err))
+			continue
+		}
+		if !active {
+			continue
+		}
+
+		duration := s.sessionDuration(session.Instance, 0)
+		if time.Until(session.ExpiresAt) >= duration {
+			continue
+		}
+		if err := s.sessions.Put(ctx, session.Instance, duration); err != nil {
+			s.l.ErrorContext(ctx, "cannot renew the session of an active instance", slog.String("instance", name), slog.Any("error", err))
+			continue
+		}
+		s.l.DebugContext(ctx, "instance has traffic, session renewed", slog.String("instance", name), slog.Duration("expiration", duration))
+	}
+
+	// Forget the instances whose session ended.
+	s.activityMu.Lock()
+	for name := range s.activity {
+		if _, ok := names[name]; !ok {
+			delete(s.activity, name)
+		}
+	}
+	s.activityMu.Unlock()
+}
+
+// sampleActivity samples the network traffic of name and reports whether it
+// changed since the previous sample. The first sample of an instance is never
+// active: there is nothing to compare it to.
+func (s *Sablier) sampleActivity(ctx context.Context, name string) (bool, error) {
+	p, ok := s.provider.(InstanceActivity)
+	if !ok {
+		return false, ErrActivityUnsupported{}
+	}
+	sample, err := p.InstanceNetworkActivity(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+	previous, sampled := s.activity[name]
+	s.activity[name] = sample
+	return sampled && sample != previous, nil
+}
+
+// tracksActivityOf reports whether WatchActivity samples the traffic of name.
+func (s *Sablier) tracksActivityOf(name string) bool {
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+	_, ok := s.activity[name]
+	return ok
+}
+
+// activeOnExpiration reports whether name, whose session just expired, had
+// traffic since the last sample of WatchActivity. A failed sample counts as
+// no traffic, so an instance is never kept up because its traffic cannot be
+// observed.
+func (s *Sablier) activeOnExpiration(ctx context.Context, name string) bool {
+	active, err := s.sampleActivity(ctx, name)
+	if err != nil {
+		s.l.WarnContext(ctx, "cannot sample the activity of the expired instance, stopping it", slog.String("instance", name), slog.Any("error", err))
+	}
+	if !active {
+		s.forgetActivity(name)
+	}
+	return active
+}
+
+// forgetActivity stops tracking the traffic of name, whose session ended.
+func (s *Sablier) forgetActivity(name string) {
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+	delete(s.activity, name)
+}
@@ -0,0 +1,246 @@
+package sablier
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"gotest.tools/v3/assert"
+)
+
+// fakeActivityProvider reports the network traffic set in samples.
+type fakeActivityProvider struct {
+	*fakeAAProvider
+	samplesMu sync.Mutex
+	samples   map[string]NetworkActivity
+	err       error
+}
+
+func (f *fakeActivityProvider) InstanceNetworkActivity(_ context.Context, name string) (NetworkActivity, error) {
+	f.samplesMu.Lock()
+	defer f.samplesMu.Unlock()
+	return f.samples[name], f.err
+}
+
+func (f *fakeActivityProvider) traffic(name string) {
+	f.samplesMu.Lock()
+	defer f.samplesMu.Unlock()
+	sample := f.samples[name]
+	sample.RxBytes += 100
+	sample.RxPackets++
+	f.samples[name] = sample
+}
+
+// renewalStore records the instances whose session was put.
+type renewalStore struct {
+	*fakeAAStore
+	putsMu sync.Mutex
+	puts   []string
+}
+
+func (r *renewalStore) Put(ctx context.Context, v InstanceInfo, d time.Duration) error {
+	r.putsMu.Lock()
+	r.puts = append(r.puts, v.Name)
+	r.putsMu.Unlock()
+	return r.fakeAAStore.Put(ctx, v, d)
+}
+
+func (r *renewalStore) renewals() []string {
+	r.putsMu.Lock()
+	defer r.putsMu.Unlock()
+	return slices.Clone(r.puts)
+}
+
+func setupActivity(t *testing.T) (*Sablier, *renewalStore, *fakeActivityProvider) {
+	t.Helper()
+	store := &renewalStore{fakeAAStore: newFakeAAStore()}
+	provider := &fakeActivityProvider{fakeAAProvider: &fakeAAProvider{}, samples: map[string]NetworkActivity{}}
+	return New(slogt.New(t), store, provider), store, provider
+}
+
+func tracked(name string) InstanceInfo {
+	return InstanceInfo{Name: name, Status: InstanceStatusReady, Config: &InstanceConfig{IdleActivity: ActivityNetwork}}
+}
+
+func TestParseActivitySource(t *testing.T) {
+	source, err := ParseActivitySource("network")
+	assert.NilError(t, err)
+	assert.Equal(t, source, ActivityNetwork)
+
+	_, err = ParseActivitySource("cpu")
+	assert.ErrorContains(t, err, `unknown activity source "cpu"`)
+}
+
+func TestTracksActivity(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	fixed := tracked("whoami")
+	fixed.SessionDeadline = &deadline
+
+	assert.Assert(t, tracksActivity(tracked("whoami")))
+	assert.Assert(t, !tracksActivity(InstanceInfo{Name: "whoami"}))
+	assert.Assert(t, !tracksActivity(InstanceInfo{Name: "whoami", Config: &InstanceConfig{}}))
+	assert.Assert(t, !tracksActivity(fixed), "a fixed session ends at its deadline")
+}
+
+func TestReconcileActivity_RenewsOnlyOnTraffic(t *testing.T) {
+	s, store, p := setupActivity(t)
+	ctx := t.Context()
+	_ = store.fakeAAStore.Put(ctx, tracked("web"), time.Minute)
+	_ = store.fakeAAStore.Put(ctx, tracked("idle"), time.Minute)
+	_ = store.fakeAAStore.Put(ctx, InstanceInfo{Name: "untracked"}, time.Minute)
+
+	// The first sample has nothing to compare to.
+	s.reconcileActivity(ctx)
+	assert.Assert(t, len(store.renewals()) == 0)
+	assert.Assert(t, s.tracksActivityOf("web"))
+	assert.Assert(t, s.tracksActivityOf("idle"))
+	assert.Assert(t, !s.tracksActivityOf("untracked"))
+
+	p.traffic("web")
+	s.reconcileActivity(ctx)
+	assert.DeepEqual(t, store.renewals(), []string{"web"})
+
+	// No traffic since the previous sample.
+	s.reconcileActivity(ctx)
+	assert.DeepEqual(t, store.renewals(), []string{"web"})
+}
+
+func TestReconcileActivity_ForgetsEndedSessions(t *testing.T) {
+	s, store, _ := setupActivity(t)
+	ctx := t.Context()
+	_ = store.fakeAAStore.Put(ctx, tracked("web"), time.Minute)
+
+	s.reconcileActivity(ctx)
+	assert.Assert(t, s.tracksActivityOf("web"))
+
+	_ = store.Delete(ctx, "web")
+	s.reconcileActivity(ctx)
+	assert.Assert(t, !s.tracksActivityOf("web"))
+}
+
+func TestReconcileActivity_UnsupportedProvider(t *testing.T) {
+	s, st, _ := setupAntiAffinity(t)
+	ctx := t.Context()
+	_ = st.Put(ctx, tracked("web"), time.Minute)
+
+	s.reconcileActivity(ctx)
+	assert.Assert(t, !s.tracksActivityOf("web"))
+}
+
+func TestOnInstanceExpired_KeepsAnInstanceWithTraffic(t *testing.T) {
+	s, _, p := setupActivity(t)
+	ctx := t.Context()
+	p.inspect = map[string]InstanceInfo{"web": tracked("web")}
+	_, err := s.sampleActivity(ctx, "web")
+	assert.NilError(t, err)
+
+	p.traffic("web")
+	s.OnInstanceExpired(ctx)("web")
+
+	assert.Assert(t, eventually(func() bool {
+		_, err := s.sessions.Get(ctx, "web")
+		return err == nil
+	}), "expected the session to be re-created")
+	assert.Assert(t, len(p.snapshotStopped()) == 0)
+	assert.Assert(t, s.tracksActivityOf("web"))
+}
+
+func TestOnInstanceExpired_StopsAnInstanceWithoutTraffic(t *testing.T) {
+	s, _, p := setupActivity(t)
+	ctx := t.Context()
+	_, err := s.sampleActivity(ctx, "web")
+	assert.NilError(t, err)
+
+	s.OnInstanceExpired(ctx)("web")
+
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStopped(), "web") }))
+	assert.Assert(t, !s.tracksActivityOf("web"))
+}
+
+func TestExpireSession_IgnoresTraffic(t *testing.T) {
+	s, store, p := setupActivity(t)
+	ctx := t.Context()
+	_ = store.fakeAAStore.Put(ctx, tracked("web"), time.Minute)
+	_, err := s.sampleActivity(ctx, "web")
+	assert.NilError(t, err)
+
+	p.traffic("web")
+	assert.NilError(t, s.ExpireSession(ctx, "web"))
+
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStopped(), "web") }))
+	assert.Assert(t, len(store.renewals()) == 0)
+	assert.Assert(t, !hasSession(store.fakeAAStore, "web"))
+	assert.Assert(t, !s.tracksActivityOf("web"))
+}
+
+func TestOnInstanceExpired_StopsWhenTrafficCannotBeSampled(t *testing.T) {
+	s, _, p := setupActivity(t)
+	ctx := t.Context()
+	_, err := s.sampleActivity(ctx, "web")
+	assert.NilError(t, err)
+
+	p.samplesMu.Lock()
+	p.err = errors.New("stats unavailable")
+	p.samplesMu.Unlock()
+	s.OnInstanceExpired(ctx)("web")
+
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStopped(), "web") }))
+}
+
+func TestCheckActivitySupport(t *testing.T) {
+	enabled := []InstanceConfiguration{{Name: "whoami", Enabled: "true"}, {Name: "nginx", Enabled: "true"}}
+
+	t.Run("provider reporting activity", func(t *testing.T) {
+		s, _, p := setupActivity(t)
+		p.list = enabled
+		p.inspect = map[string]InstanceInfo{"whoami": tracked("whoami")}
+		assert.NilError(t, s.CheckActivitySupport(context.Background()))
+	})
+	t.Run("no instance tracks activity", func(t *testing.T) {
+		p := &fakeAAProvider{list: enabled}
+		s := New(slogt.New(t), newFakeAAStore(), p)
+		assert.NilError(t, s.CheckActivitySupport(context.Background()))
+	})
+	t.Run("instance labelled without provider support", func(t *testing.T) {
+		p := &fakeAAProvider{list: enabled, inspect: map[string]InstanceInfo{"nginx": tracked("nginx")}}
+		s := New(slogt.New(t), newFakeAAStore(), p)
+		err := s.CheckActivitySupport(context.Background())
+		assert.ErrorIs(t, err, ErrActivityUnsupported{})
+		assert.ErrorContains(t, err, `instance "nginx" is labelled sablier.idle.activity`)
+	})
+	t.Run("policy setting the label without provider support", func(t *testing.T) {
+		s := New(slogt.New(t), newFakeAAStore(), &fakeAAProvider{})
+		s.WithPolicies(NewPolicySet(&Policies{Policies: []Policy{{Name: "keep-alive", Labels: map[string]string{LabelIdleActivity: "network"}}}}))
+		err := s.CheckActivitySupport(context.Background())
+		assert.ErrorContains(t, err, `policy "keep-alive" sets sablier.idle.activity`)
+	})
+	t.Run("disabled instances are ignored", func(t *testing.T) {
+		p := &fakeAAProvider{
+			list:    []InstanceConfiguration{{Name: "nginx", Enabled: "false"}},
+			inspect: map[string]InstanceInfo{"nginx": tracked("nginx")},
+		}
+		s := New(slogt.New(t), newFakeAAStore(), p)
+		assert.NilError(t, s.CheckActivitySupport(context.Background()))
+	})
+	t.Run("wrapped provider without support", func(t *testing.T) {
+		p := &unwrappingProvider{fakeActivityProvider: &fakeActivityProvider{fakeAAProvider: &fakeAAProvider{list: enabled, inspect: map[string]InstanceInfo{"nginx": tracked("nginx")}}}}
+		p.wrapped = p.fakeAAProvider
+		s := New(slogt.New(t), newFakeAAStore(), p)
+		assert.ErrorIs(t, s.CheckActivitySupport(context.Background()), ErrActivityUnsupported{})
+	})
+}
+
+// unwrappingProvider passes the activity through to a wrapped provider, as
+// the inspect cache does.
+type unwrappingProvider struct {
+	*fakeActivityProvider
+	wrapped Provider
+}
+
+func (u *unwrappingProvider) Unwrap() Provider {
+	return u.wrapped
+}
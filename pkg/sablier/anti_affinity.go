@@ -168,14 +168,23 @@ func (s *Sablier) reconcileAntiAffinity(ctx context.Context) {
 		}
 	}
 
+	// Followers leave the instances to the leader, which reconciles the same
+	// shared sessions.
+	leader := s.isLeader()
 	for instance, suppress := range desiredSuppress {
 		_, alreadySuppressed := s.suppressed[instance]
 		switch {
+		case !leader:
 		case suppress && !alreadySuppressed:
 			s.suppressForAntiAffinity(ctx, instance)
 		case !suppress && alreadySuppressed:
 			s.restoreFromAntiAffinity(ctx, instance)
 		}
+		if !suppress {
+			// A blocking request held by the anti-affinity can start it now;
+			// no provider change would tell it so.
+			s.readiness.wake(instance)
+		}
 	}
 }
 
@@ -208,7 +217,9 @@ func (s *Sablier) suppressForAntiAffinity(ctx context.Context, instance string)
 			slog.String("instance", instance), slog.Any("error", err))
 	}
 	s.suppressed[instance] = struct{}{}
-	s.metrics.RecordInstanceStop(instance, "anti-affinity")
+	s.capacityFreed(instance)
+	s.metrics.RecordInstanceStop(instance, StopReasonAntiAffinity)
+	s.recordLifecycle(ctx, LifecycleEvent{Instance: instance, Kind: LifecycleStopped, Reason: StopReasonAntiAffinity})
 }
 
 // restoreFromAntiAffinity brings a previously-suppressed instance back once none
@@ -223,7 +234,7 @@ func (s *Sablier) suppressForAntiAffinity(ctx context.Context, instance string)
 // Must be called with affinityMu held.
 func (s *Sablier) restoreFromAntiAffinity(ctx context.Context, instance string) {
 	s.l.InfoContext(ctx, "anti-affinity: restoring instance", slog.String("instance", instance))
-	if _, err := s.instanceRequest(ctx, instance, s.DefaultSessionDuration, false); err != nil {
+	if _, _, err := s.instanceRequest(ctx, instance, 0, false, nil); err != nil {
 		s.l.ErrorContext(ctx, "anti-affinity: cannot restore instance",
 			slog.String("instance", instance), slog.Any("error", err))
 		return
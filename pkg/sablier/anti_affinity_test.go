@@ -251,6 +251,25 @@ func TestReconcileAntiAffinity_RestoreOnlyWhenAllAntagonistsInactive(t *testing.
 	assert.Assert(t, !s.isSuppressed("nextcloud"))
 }
 
+func TestReconcileAntiAffinity_FollowerLeavesSuppressionToTheLeader(t *testing.T) {
+	s, st, p := setupAntiAffinity(t)
+	s.WithLeader(func() bool { return false })
+	ctx := context.Background()
+
+	s.SetGroups(map[string][]string{"streaming": {"plex"}})
+	s.SyncInstanceAntiAffinity("nextcloud", []string{"streaming"})
+
+	st.session("plex")
+	st.session("nextcloud")
+
+	s.reconcileAntiAffinity(ctx)
+
+	assert.Assert(t, !slices.Contains(p.snapshotStopped(), "nextcloud"), "a follower must not force the dependent idle")
+	assert.Assert(t, !s.isSuppressed("nextcloud"))
+	_, err := st.Get(ctx, "nextcloud")
+	assert.NilError(t, err)
+}
+
 func TestReconcileAntiAffinity_NoOpWhenNoAntiAffinity(t *testing.T) {
 	s, st, p := setupAntiAffinity(t)
 	ctx := context.Background()
@@ -569,7 +588,7 @@ func TestInstanceRequest_HeldByAntiAffinity(t *testing.T) {
 	s.SyncInstanceAntiAffinity("nextcloud", []string{"streaming"})
 	st.session("plex") // antagonist active
 
-	info, err := s.instanceRequest(ctx, "nextcloud", time.Minute, false)
+	info, _, err := s.instanceRequest(ctx, "nextcloud", time.Minute, false, nil)
 	assert.NilError(t, err)
 
 	assert.Equal(t, info.Status, InstanceStatusNotReady)
@@ -49,7 +49,8 @@ func (s *Sablier) stopFunc(ctx context.Context, name string) func() error {
 			s.l.ErrorContext(ctx, "failed to stop instance", slog.String("instance", name), slog.Any("error", err))
 			return err
 		}
-		s.metrics.RecordInstanceStop(name, "unregistered")
+		s.metrics.RecordInstanceStop(name, StopReasonUnregistered)
+		s.recordLifecycle(ctx, LifecycleEvent{Instance: name, Kind: LifecycleStopped, Reason: StopReasonUnregistered})
 		s.l.InfoContext(ctx, "stopped unregistered instance", slog.String("instance", name), slog.String("reason", "instance is enabled but not started by Sablier"))
 		return nil
 	}
@@ -111,7 +112,8 @@ func (s *Sablier) WatchAndStopExternallyStarted(ctx context.Context) {
 			if err := s.provider.InstanceStop(ctx, info.Info.Name); err != nil {
 				s.l.ErrorContext(ctx, "failed to stop externally-started instance", slog.String("instance", info.Info.Name), slog.Any("error", err))
 			} else {
-				s.metrics.RecordInstanceStop(info.Info.Name, "externally-started")
+				s.metrics.RecordInstanceStop(info.Info.Name, StopReasonExternallyStarted)
+				s.recordLifecycle(ctx, LifecycleEvent{Instance: info.Info.Name, Kind: LifecycleStopped, Reason: StopReasonExternallyStarted})
 			}
 		case err, ok := <-errC:
 			if !ok {
@@ -0,0 +1,437 @@
+package sablier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// capacityQueueTimeout is how long a queued instance keeps its place in the
+// queue without being requested again, so the requests that gave up do not
+// hold the queue forever.
+const capacityQueueTimeout = time.Minute
+
+// Capacity limits the number of instances active at once, an instance being
+// active while it holds a session.
+type Capacity struct {
+	// MaxActive is the maximum number of active instances. Zero means no
+	// limit.
+	MaxActive int
+	// Groups is the maximum number of active instances of each group.
+	Groups map[string]int
+	// Queue makes the requests over capacity wait until enough sessions end,
+	// instead of stopping the least recently used instances to make room.
+	Queue bool
+}
+
+// limited reports whether c limits anything.
+func (c Capacity) limited() bool {
+	return c.MaxActive > 0 || len(c.Groups) > 0
+}
+
+// capacityLimiter enforces a Capacity on the requests opening a session.
+type capacityLimiter struct {
+	Capacity
+
+	// mu serialises admissions, so concurrent requests cannot all take the
+	// last free place.
+	mu sync.Mutex
+	// changes counts the admissions ended and the sessions ended or deleted,
+	// so an admission can tell whether the sessions it listed without holding
+	// mu are still current.
+	changes uint64
+	// lastUsed is when each active instance was last requested, to evict the
+	// least recently used ones first. Instances active before Sablier started
+	// have no entry and are evicted first.
+	lastUsed map[string]time.Time
+	// admitted are the instances let in whose session is not stored yet. They
+	// count as active, and are never evicted.
+	admitted map[string]struct{}
+	// renewing are the instances whose expired session is being re-created,
+	// pinned ones or ones kept alive by their traffic. They never stopped:
+	// they count as active, are never evicted, and skip the admission.
+	renewing map[string]struct{}
+	// queued are the instances waiting for capacity.
+	queued map[string]queuedInstance
+	// evictions are closed once the instances evicted to make room for an
+	// admitted instance are stopped, which its start waits for.
+	evictions map[string]chan struct{}
+}
+
+type queuedInstance struct {
+	// since is when the instance entered the queue, its place in it.
+	since time.Time
+	// seen is when the instance was last requested.
+	seen time.Time
+}
+
+// capacityScope is a limit on the instances of a group, or on every instance
+// when group is empty.
+type capacityScope struct {
+	group   string
+	limit   int
+	members []string
+}
+
+func (sc capacityScope) contains(name string) bool {
+	return sc.group == "" || slices.Contains(sc.members, name)
+}
+
+func (sc capacityScope) String() string {
+	if sc.group == "" {
+		return fmt.Sprintf("the capacity of %d active instances is reached", sc.limit)
+	}
+	return fmt.Sprintf("the capacity of %d active instances of group %q is reached", sc.limit, sc.group)
+}
+
+// WithCapacity limits the number of instances active at once. A request
+// opening a session over capacity stops the least recently used instances
+// or, with Capacity.Queue, is reported as queued until enough sessions end.
+func (s *Sablier) WithCapacity(c Capacity) {
+	if !c.limited() {
+		s.capacity = nil
+		return
+	}
+	s.capacity = &capacityLimiter{
+		Capacity:  c,
+		lastUsed:  map[string]time.Time{},
+		admitted:  map[string]struct{}{},
+		renewing:  map[string]struct{}{},
+		queued:    map[string]queuedInstance{},
+		evictions: map[string]chan struct{}{},
+	}
+}
+
+// admitCapacity lets name open a session when it fits the capacity, first
+// evicting the least recently used instances when it does not. It returns the
+// queued state of name instead when the queue policy is set, or when not
+// enough instances can be evicted. An admitted instance counts as active until
+// releaseCapacity, called once its session is stored or its request failed.
+// Only the leader evicts: the requests served by the other replicas are
+// queued. An instance whose session is renewed is always admitted.
+func (s *Sablier) admitCapacity(ctx context.Context, name string) (*InstanceInfo, error) {
+	c := s.capacity
+	if c == nil {
+		return nil, nil
+	}
+
+	active, err := s.activeSessions(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot count the active instances: %w", err)
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.renewing[name]; ok {
+		return nil, nil
+	}
+
+	for _, held := range []map[string]struct{}{c.admitted, c.renewing} {
+		for n := range held {
+			if _, ok := active[n]; !ok {
+				active[n] = time.Time{}
+			}
+		}
+	}
+	delete(active, name)
+
+	now := time.Now()
+	for queued, q := range c.queued {
+		if now.Sub(q.seen) > capacityQueueTimeout {
+			delete(c.queued, queued)
+		}
+	}
+	mine, wasQueued := c.queued[name]
+
+	var victims []string
+	for _, sc := range s.capacityScopes(name) {
+		count := 0
+		for n := range active {
+			if sc.contains(n) && !slices.Contains(victims, n) {
+				count++
+			}
+		}
+		if c.Queue {
+			// The instances queued earlier get in first.
+			for n, q := range c.queued {
+				if n != name && sc.contains(n) && (!wasQueued || q.since.Before(mine.since)) {
+					count++
+				}
+			}
+		}
+		need := count - sc.limit + 1
+		if need <= 0 {
+			continue
+		}
+		if !c.Queue && s.isLeader() {
+			candidates := s.evictionCandidates(c, active, sc, victims)
+			if len(candidates) >= need {
+				victims = append(victims, candidates[:need]...)
+				continue
+			}
+		}
+
+		if !wasQueued {
+			mine.since = now
+		}
+		mine.seen = now
+		c.queued[name] = mine
+		s.l.DebugContext(ctx, "capacity reached, instance queued", slog.String("instance", name), slog.String("reason", sc.String()))
+		return &InstanceInfo{
+			Name:            name,
+			CurrentReplicas: 0,
+			DesiredReplicas: 1,
+			Status:          InstanceStatusQueued,
+			Message:         "queued: " + sc.String(),
+		}, nil
+	}
+
+	delete(c.queued, name)
+	c.admitted[name] = struct{}{}
+	c.lastUsed[name] = now
+	if len(victims) == 0 {
+		return nil, nil
+	}
+
+	// The sessions are deleted before the admission is released, so the
+	// evicted instances no longer count as active for the next requests.
+	// Deleting a session does not call the expiration callback: the
+	// instances are stopped below instead.
+	var wg sync.WaitGroup
+	stopCtx := context.WithoutCancel(ctx)
+	for _, victim := range victims {
+		if err := s.sessions.Delete(ctx, victim); err != nil {
+			s.l.WarnContext(ctx, "capacity: cannot delete the session of an evicted instance", slog.String("instance", victim), slog.Any("error", err))
+		}
+		delete(c.lastUsed, victim)
+		wg.Go(func() { s.evict(stopCtx, victim, name) })
+	}
+	c.changes++
+	done := make(chan struct{})
+	c.evictions[name] = done
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return nil, nil
+}
+
+// activeSessions lists the instances holding a session with their expiration,
+// and returns with c.mu held unless it fails. The store is listed without
+// holding c.mu, so a slow store does not serialise the requests. The listing
+// is done again when an admission ended or a session ended meanwhile, and
+// under c.mu after a few attempts.
+func (s *Sablier) activeSessions(ctx context.Context, c *capacityLimiter) (map[string]time.Time, error) {
+	const attempts = 3
+	list := func() (map[string]time.Time, error) {
+		active := map[string]time.Time{}
+		err := s.sessions.Range(ctx, func(info InstanceInfo, expiresAt time.Time) {
+			active[info.Name] = expiresAt
+		})
+		return active, err
+	}
+	for range attempts {
+		c.mu.Lock()
+		changes := c.changes
+		c.mu.Unlock()
+
+		active, err := list()
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.changes == changes {
+			return active, nil
+		}
+		c.mu.Unlock()
+	}
+	c.mu.Lock()
+	active, err := list()
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	return active, nil
+}
+
+// capacityScopes returns the limits that apply to name.
+func (s *Sablier) capacityScopes(name string) []capacityScope {
+	var scopes []capacityScope
+	if s.capacity.MaxActive > 0 {
+		scopes = append(scopes, capacityScope{limit: s.capacity.MaxActive})
+	}
+	for _, group := range s.groups.GroupsOf(name) {
+		limit, ok := s.capacity.Groups[group]
+		if !ok {
+			continue
+		}
+		members, _ := s.groups.Get(group)
+		scopes = append(scopes, capacityScope{group: group, limit: limit, members: members})
+	}
+	return scopes
+}
+
+// evictionCandidates returns the active instances of sc that can be evicted,
+// the least recently used first. Instances being admitted, renewed or started,
+// pinned instances and the ones already chosen as victims are left alone.
+//
+// Must be called with c.mu held.
+func (s *Sablier) evictionCandidates(c *capacityLimiter, active map[string]time.Time, sc capacityScope, victims []string) []string {
+	var candidates []string
+	for n := range active {
+		if !sc.contains(n) || slices.Contains(victims, n) {
+			continue
+		}
+		if _, ok := c.admitted[n]; ok {
+			continue
+		}
+		if _, ok := c.renewing[n]; ok {
+			continue
+		}
+		if s.pinRemaining(n) > 0 || s.isStarting(n) {
+			continue
+		}
+		candidates = append(candidates, n)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !c.lastUsed[a].Equal(c.lastUsed[b]) {
+			return c.lastUsed[a].Before(c.lastUsed[b])
+		}
+		if !active[a].Equal(active[b]) {
+			return active[a].Before(active[b])
+		}
+		return a < b
+	})
+	return candidates
+}
+
+// isStarting reports whether a start of name is in progress.
+func (s *Sablier) isStarting(name string) bool {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	ps, ok := s.pendingStarts[name]
+	if !ok {
+		return false
+	}
+	select {
+	case <-ps.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// evict stops an instance whose session was deleted to make room for
+// admitted, the way an expired instance is stopped.
+func (s *Sablier) evict(ctx context.Context, name string, admitted string) {
+	s.l.InfoContext(ctx, "capacity reached, evicting the least recently used instance", slog.String("instance", name), slog.String("for", admitted))
+	stopInstance(ctx, s.provider, s.metrics, s.l, s.verifyEnabledOnExpiration, name, StopReasonEvicted)
+	s.recordLifecycle(ctx, LifecycleEvent{Instance: name, Kind: LifecycleStopped, Reason: StopReasonEvicted})
+	// The evicted session may have been the last one keeping a group active.
+	s.triggerAntiAffinityReconcile(ctx)
+}
+
+// waitEvictions waits until the instances evicted to admit name are stopped,
+// or ctx is done.
+func (s *Sablier) waitEvictions(ctx context.Context, name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	done, ok := c.evictions[name]
+	delete(c.evictions, name)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// releaseCapacity ends the admission of name, once its session is stored or
+// its request failed.
+func (s *Sablier) releaseCapacity(name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.admitted, name)
+	c.changes++
+}
+
+// holdCapacity keeps name active while its expired session is renewed, until
+// releaseRenewal.
+func (s *Sablier) holdCapacity(name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.renewing[name] = struct{}{}
+}
+
+// releaseRenewal ends the hold of name, once its session is renewed or it is
+// stopped.
+func (s *Sablier) releaseRenewal(name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.renewing, name)
+	c.changes++
+}
+
+// touchCapacity records that name was requested, for the least recently used
+// eviction.
+func (s *Sablier) touchCapacity(name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastUsed[name] = time.Now()
+}
+
+// capacityFreed wakes the blocking requests waiting on the queued instances
+// once the session of name ended.
+func (s *Sablier) capacityFreed(name string) {
+	c := s.capacity
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.lastUsed, name)
+	c.changes++
+	queued := make([]string, 0, len(c.queued))
+	for n := range c.queued {
+		queued = append(queued, n)
+	}
+	c.mu.Unlock()
+	for _, n := range queued {
+		s.readiness.wake(n)
+	}
+}
+
+// hasQueuedInstances reports whether an instance of session waits for
+// capacity.
+func hasQueuedInstances(session *SessionState) bool {
+	for _, v := range session.Instances {
+		if !v.Optional && v.Instance.Status == InstanceStatusQueued {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+package sablier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/metrics"
+	"gotest.tools/v3/assert"
+)
+
+// stopRecorder records the stop reasons reported to metrics.
+type stopRecorder struct {
+	metrics.Noop
+	mu    sync.Mutex
+	stops []string
+}
+
+func (r *stopRecorder) RecordInstanceStop(instance, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stops = append(r.stops, instance+"/"+reason)
+}
+
+func (r *stopRecorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.stops)
+}
+
+func setupCapacity(t *testing.T, c Capacity) (*Sablier, *fakeAAStore, *fakeAAProvider) {
+	t.Helper()
+	s, st, p := setupAntiAffinity(t)
+	s.WithCapacity(c)
+	return s, st, p
+}
+
+func request(t *testing.T, s *Sablier, name string) InstanceInfo {
+	t.Helper()
+	info, err := s.InstanceRequest(context.Background(), name, time.Minute)
+	assert.NilError(t, err)
+	// Instances being started are never evicted: let the start complete.
+	assert.Assert(t, eventually(func() bool { return !s.isStarting(name) }))
+	return info
+}
+
+func hasSession(st *fakeAAStore, name string) bool {
+	_, err := st.Get(context.Background(), name)
+	return err == nil
+}
+
+func TestCapacity_EvictsLeastRecentlyUsed(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 2})
+	rec := &stopRecorder{}
+	s.WithMetrics(rec)
+
+	request(t, s, "a")
+	request(t, s, "b")
+	// a is used again, so b is now the least recently used.
+	request(t, s, "a")
+
+	info := request(t, s, "c")
+	assert.Equal(t, info.Status, InstanceStatusStarting)
+
+	assert.Assert(t, eventually(func() bool { return len(rec.snapshot()) > 0 }))
+	assert.DeepEqual(t, p.snapshotStopped(), []string{"b"})
+	assert.DeepEqual(t, rec.snapshot(), []string{"b/" + StopReasonEvicted})
+	assert.Assert(t, !hasSession(st, "b"))
+	assert.Assert(t, hasSession(st, "a"))
+	assert.Assert(t, hasSession(st, "c"))
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStarted(), "c") }))
+}
+
+func TestCapacity_ExistingSessionsAreNotLimited(t *testing.T) {
+	s, _, p := setupCapacity(t, Capacity{MaxActive: 1})
+
+	request(t, s, "a")
+	info := request(t, s, "a")
+
+	assert.Assert(t, info.Status != InstanceStatusQueued)
+	assert.Equal(t, len(p.snapshotStopped()), 0)
+}
+
+func TestCapacity_QueuesUntilCapacityFrees(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 1, Queue: true})
+
+	request(t, s, "a")
+
+	info := request(t, s, "b")
+	assert.Equal(t, info.Status, InstanceStatusQueued)
+	assert.Equal(t, info.Message, "queued: the capacity of 1 active instances is reached")
+	assert.Assert(t, !hasSession(st, "b"))
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStarted(), "a") }))
+	assert.Assert(t, !slices.Contains(p.snapshotStarted(), "b"))
+
+	assert.NilError(t, s.RemoveInstance(context.Background(), "a"))
+
+	info = request(t, s, "b")
+	assert.Equal(t, info.Status, InstanceStatusStarting)
+	assert.Assert(t, hasSession(st, "b"))
+	assert.Equal(t, len(p.snapshotStopped()), 0)
+}
+
+func TestCapacity_QueueIsFirstInFirstOut(t *testing.T) {
+	s, _, _ := setupCapacity(t, Capacity{MaxActive: 1, Queue: true})
+
+	request(t, s, "a")
+	assert.Equal(t, request(t, s, "b").Status, InstanceStatusQueued)
+	assert.Equal(t, request(t, s, "c").Status, InstanceStatusQueued)
+
+	assert.NilError(t, s.RemoveInstance(context.Background(), "a"))
+
+	// b was queued first: c waits for it.
+	assert.Equal(t, request(t, s, "c").Status, InstanceStatusQueued)
+	assert.Equal(t, request(t, s, "b").Status, InstanceStatusStarting)
+	assert.Equal(t, request(t, s, "c").Status, InstanceStatusQueued)
+}
+
+func TestCapacity_GroupLimit(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{Groups: map[string]int{"gpu": 1}})
+	s.SetGroups(map[string][]string{"gpu": {"x", "y"}, "default": {"w"}})
+
+	request(t, s, "x")
+	request(t, s, "w")
+
+	info := request(t, s, "y")
+	assert.Equal(t, info.Status, InstanceStatusStarting)
+
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStopped(), "x") }))
+	assert.DeepEqual(t, p.snapshotStopped(), []string{"x"})
+	assert.Assert(t, hasSession(st, "w"))
+}
+
+func TestCapacity_PinnedInstancesAreNotEvicted(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 1})
+	p.inspect = map[string]InstanceInfo{"a": {Name: "a", Enabled: "true"}}
+
+	_, _, err := s.PinInstance(context.Background(), "a", time.Hour)
+	assert.NilError(t, err)
+
+	info := request(t, s, "b")
+	assert.Equal(t, info.Status, InstanceStatusQueued)
+	assert.Assert(t, hasSession(st, "a"))
+	assert.Equal(t, len(p.snapshotStopped()), 0)
+}
+
+func TestCapacity_ConcurrentRequests(t *testing.T) {
+	s, st, _ := setupCapacity(t, Capacity{MaxActive: 1, Queue: true})
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			_, err := s.InstanceRequest(context.Background(), fmt.Sprintf("i%d", i), time.Minute)
+			assert.Check(t, err)
+		})
+	}
+	wg.Wait()
+	for i := range 10 {
+		assert.Assert(t, eventually(func() bool { return !s.isStarting(fmt.Sprintf("i%d", i)) }))
+	}
+
+	active := 0
+	_ = st.Range(context.Background(), func(InstanceInfo, time.Time) { active++ })
+	assert.Equal(t, active, 1)
+}
+
+func TestCapacity_ExpirationWakesQueuedRequests(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 1, Queue: true})
+	s.BlockingRefreshFrequency = time.Hour
+
+	request(t, s, "a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.RequestReadySession(ctx, []string{"b"}, time.Minute, 5*time.Second)
+	}()
+	assert.Assert(t, eventually(func() bool {
+		s.capacity.mu.Lock()
+		defer s.capacity.mu.Unlock()
+		_, ok := s.capacity.queued["b"]
+		return ok
+	}))
+
+	assert.NilError(t, st.Delete(context.Background(), "a"))
+	s.OnInstanceExpired(context.Background())("a")
+
+	assert.Assert(t, eventually(func() bool { return hasSession(st, "b") }), "the queued request should be admitted")
+	assert.Assert(t, eventually(func() bool { return !s.isStarting("b") }))
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStopped(), "a") }))
+	cancel()
+	<-done
+}
+
+func TestCapacity_RenewalWhileFull(t *testing.T) {
+	for _, queue := range []bool{false, true} {
+		t.Run(fmt.Sprintf("queue=%t", queue), func(t *testing.T) {
+			s, st, p := setupCapacity(t, Capacity{MaxActive: 1, Queue: queue})
+			p.inspect = map[string]InstanceInfo{"a": {Name: "a", Enabled: "true", Status: InstanceStatusReady}}
+
+			_, _, err := s.PinInstance(context.Background(), "a", time.Hour)
+			assert.NilError(t, err)
+			assert.Assert(t, eventually(func() bool { return !s.isStarting("a") }))
+
+			// The session of the pinned instance expires while another one
+			// takes the only place.
+			assert.NilError(t, st.Delete(context.Background(), "a"))
+			assert.NilError(t, st.Put(context.Background(), InstanceInfo{Name: "b", Status: InstanceStatusReady}, time.Hour))
+			s.OnInstanceExpired(context.Background())("a")
+
+			assert.Assert(t, eventually(func() bool { return hasSession(st, "a") }), "the pinned instance should be renewed")
+			assert.Assert(t, hasSession(st, "b"), "the renewal should not evict")
+			assert.Equal(t, len(p.snapshotStopped()), 0)
+			s.capacity.mu.Lock()
+			defer s.capacity.mu.Unlock()
+			assert.Equal(t, len(s.capacity.queued), 0)
+		})
+	}
+}
+
+func TestCapacity_FollowersDoNotEvict(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 1})
+
+	request(t, s, "a")
+	s.WithLeader(func() bool { return false })
+
+	info := request(t, s, "b")
+	assert.Equal(t, info.Status, InstanceStatusQueued)
+	assert.Assert(t, hasSession(st, "a"))
+	assert.Equal(t, len(p.snapshotStopped()), 0)
+}
+
+func TestCapacity_EvictionVerifiesEnabled(t *testing.T) {
+	s, st, p := setupCapacity(t, Capacity{MaxActive: 1})
+	s.WithVerifyEnabledOnExpiration(true)
+	rec := &stopRecorder{}
+	s.WithMetrics(rec)
+
+	request(t, s, "a")
+	// a is no longer managed by Sablier: it loses its session, but is not
+	// stopped.
+	p.mu.Lock()
+	p.inspect = map[string]InstanceInfo{"a": {Name: "a", Enabled: "false"}}
+	p.mu.Unlock()
+
+	info := request(t, s, "b")
+	assert.Equal(t, info.Status, InstanceStatusStarting)
+	assert.Assert(t, eventually(func() bool { return slices.Contains(p.snapshotStarted(), "b") }))
+	assert.Assert(t, !hasSession(st, "a"))
+	assert.Equal(t, len(p.snapshotStopped()), 0)
+	assert.Equal(t, len(rec.snapshot()), 0)
+}
+
+// slowRangeStore blocks Range until release is closed.
+type slowRangeStore struct {
+	*fakeAAStore
+	ranging chan struct{}
+	release chan struct{}
+}
+
+func (s *slowRangeStore) Range(ctx context.Context, f func(InstanceInfo, time.Time)) error {
+	select {
+	case s.ranging <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return s.fakeAAStore.Range(ctx, f)
+}
+
+func TestCapacity_ListsSessionsWithoutHoldingTheLimiter(t *testing.T) {
+	st := &slowRangeStore{fakeAAStore: newFakeAAStore(), ranging: make(chan struct{}, 1), release: make(chan struct{})}
+	s := New(slogt.New(t), st, &fakeAAProvider{})
+	s.WithCapacity(Capacity{MaxActive: 1, Queue: true})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.InstanceRequest(context.Background(), "a", time.Minute)
+	}()
+	<-st.ranging
+
+	// The other requests are not held by the store listing.
+	touched := make(chan struct{})
+	go func() {
+		s.touchCapacity("b")
+		s.releaseCapacity("b")
+		close(touched)
+	}()
+	select {
+	case <-touched:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the limiter is held while the sessions are listed")
+	}
+
+	close(st.release)
+	<-done
+	assert.Assert(t, eventually(func() bool { return !s.isStarting("a") }))
+	assert.Assert(t, hasSession(st.fakeAAStore, "a"))
+}
@@ -0,0 +1,192 @@
+package sablier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week. Each field is the set of values it matches.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record an unrestricted field: as in cron, a day
+	// matches either field when both are restricted, and the restricted one
+	// otherwise.
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week 7 is Sunday too.
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// parseCron parses a five-field cron expression, with lists (1,15), ranges
+// (1-5), steps (*/15, 8-18/2) and month and weekday names (jan, mon).
+func parseCron(v string) (cronSpec, error) {
+	fields := strings.Fields(v)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("cron expression %q must have 5 fields: minute hour day-of-month month day-of-week", v)
+	}
+
+	var spec cronSpec
+	var err error
+	if spec.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return cronSpec{}, err
+	}
+	if spec.hour, err = cronHour.parse(fields[1]); err != nil {
+		return cronSpec{}, err
+	}
+	if spec.dom, err = cronDom.parse(fields[2]); err != nil {
+		return cronSpec{}, err
+	}
+	if spec.month, err = cronMonth.parse(fields[3]); err != nil {
+		return cronSpec{}, err
+	}
+	if spec.dow, err = cronDow.parse(fields[4]); err != nil {
+		return cronSpec{}, err
+	}
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1 << 0
+	}
+	spec.domStar = strings.HasPrefix(fields[2], "*")
+	spec.dowStar = strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+func (f cronField) parse(v string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(v, ",") {
+		bits, err := f.parseRange(part)
+		if err != nil {
+			return 0, err
+		}
+		set |= bits
+	}
+	return set, nil
+}
+
+// parseRange parses one entry of a list: "*", "n", "a-b", each with an
+// optional "/step".
+func (f cronField) parseRange(v string) (uint64, error) {
+	rng, stepStr, hasStep := strings.Cut(v, "/")
+	step := 1
+	if hasStep {
+		s, err := strconv.Atoi(stepStr)
+		if err != nil || s <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepStr, f.name)
+		}
+		step = s
+	}
+
+	var lo, hi int
+	switch {
+	case rng == "*":
+		lo, hi = f.min, f.max
+	case strings.Contains(rng, "-"):
+		a, b, _ := strings.Cut(rng, "-")
+		var err error
+		if lo, err = f.value(a); err != nil {
+			return 0, err
+		}
+		if hi, err = f.value(b); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q in %s field", rng, f.name)
+		}
+	default:
+		n, err := f.value(rng)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = n, n
+		if hasStep {
+			hi = f.max
+		}
+	}
+
+	var bits uint64
+	for i := lo; i <= hi; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func (f cronField) value(v string) (int, error) {
+	if n, ok := f.names[strings.ToLower(v)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", v, f.name)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", n, f.min, f.max, f.name)
+	}
+	return n, nil
+}
+
+func (c cronSpec) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dow
+	case c.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first time at or after t, to the minute, that matches the
+// expression. It gives up after five years, which only an expression that
+// never matches, like February 30th, reaches.
+func (c cronSpec) next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	at := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	if at.Before(t) {
+		at = at.Add(time.Minute)
+	}
+	limit := at.AddDate(5, 0, 0)
+	for at.Before(limit) {
+		var skip time.Time
+		switch {
+		case c.month&(1<<int(at.Month())) == 0:
+			skip = time.Date(at.Year(), at.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(at):
+			skip = time.Date(at.Year(), at.Month(), at.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<at.Hour()) == 0:
+			skip = time.Date(at.Year(), at.Month(), at.Day(), at.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<at.Minute()) == 0:
+			skip = at.Add(time.Minute)
+		default:
+			return at, true
+		}
+		// A daylight saving transition can normalize the skip back in time.
+		if !skip.After(at) {
+			skip = at.Add(time.Hour)
+		}
+		at = skip
+	}
+	return time.Time{}, false
+}
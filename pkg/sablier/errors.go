@@ -63,3 +63,27 @@ type ErrInstanceNotManaged struct {
 func (e ErrInstanceNotManaged) Error() string {
 	return fmt.Sprintf("instance %s is not managed by sablier", e.Name)
 }
+
+type ErrSessionNotFound struct {
+	Name string
+}
+
+func (e ErrSessionNotFound) Error() string {
+	return fmt.Sprintf("no active session for instance %s", e.Name)
+}
+
+// ErrHistoryDisabled is returned by Usage when no store records the lifecycle
+// of the sessions.
+type ErrHistoryDisabled struct{}
+
+func (ErrHistoryDisabled) Error() string {
+	return "session history is disabled, it requires the database storage"
+}
+
+// ErrActivityUnsupported is returned when the provider cannot report the
+// network activity of its instances.
+type ErrActivityUnsupported struct{}
+
+func (ErrActivityUnsupported) Error() string {
+	return "the provider cannot report the network activity of instances"
+}
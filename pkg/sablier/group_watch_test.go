@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/neilotoole/slogt"
 	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/provider/providertest"
 	"github.com/sablierapp/sablier/pkg/sablier"
 	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
@@ -490,3 +492,40 @@ func pollFor(t *testing.T, check func() bool, timeout time.Duration) bool {
 func containsInstance(groups map[string][]string, group, instance string) bool {
 	return slices.Contains(groups[group], instance)
 }
+
+type inspectCacheProvider struct {
+	*providertest.MockProvider
+	*providertest.MockInspectCache
+}
+
+func TestReloadPolicies_FlushesInspectionsAndRefreshesGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := providertest.NewMockProvider(ctrl)
+	cache := providertest.NewMockInspectCache(ctrl)
+	s := sablier.New(slogt.New(t), nil, inspectCacheProvider{MockProvider: p, MockInspectCache: cache})
+	s.SetGroups(map[string][]string{"default": {"web"}})
+	// The provider parses the labels with the policies Sablier reloads.
+	ps := sablier.NewPolicySet(nil)
+	s.WithPolicies(ps)
+
+	sel, err := sablier.ParseInstanceSelector([]string{"web"}, "", "")
+	assert.NilError(t, err)
+	policies := &sablier.Policies{Groups: []sablier.GroupPolicy{{Group: "team-a", Selector: sel}}}
+
+	gomock.InOrder(
+		cache.EXPECT().FlushInspections(),
+		p.EXPECT().InstanceGroups(gomock.Any()).DoAndReturn(func(context.Context) (map[string][]string, error) {
+			// The provider computes the groups with the new policies.
+			groups := map[string][]string{}
+			for _, g := range ps.GroupsFromLabels("web", map[string]string{sablier.LabelEnable: "true"}) {
+				groups[g] = append(groups[g], "web")
+			}
+			return groups, nil
+		}),
+	)
+	s.ReloadPolicies(t.Context(), policies)
+
+	assert.Equal(t, ps.Load(), policies)
+	assert.Assert(t, containsInstance(s.Groups(), "team-a", "web"))
+	assert.Assert(t, !containsInstance(s.Groups(), "default", "web"))
+}
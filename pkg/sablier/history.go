@@ -0,0 +1,91 @@
+package sablier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LifecycleKind is a step in the lifecycle of a session.
+type LifecycleKind string
+
+const (
+	// LifecycleRequested is recorded when a request opens a new session.
+	LifecycleRequested LifecycleKind = "requested"
+	// LifecycleReady is recorded when the instance of a session becomes ready.
+	LifecycleReady LifecycleKind = "ready"
+	// LifecycleStopped is recorded when Sablier stops an instance.
+	LifecycleStopped LifecycleKind = "stopped"
+)
+
+// Reasons an instance is stopped for, as recorded in LifecycleEvent.Reason.
+const (
+	StopReasonExpired           = "expired"
+	StopReasonAntiAffinity      = "anti-affinity"
+	StopReasonUnregistered      = "unregistered"
+	StopReasonExternallyStarted = "externally-started"
+	StopReasonEvicted           = "evicted"
+	StopReasonManual            = "manual"
+)
+
+// LifecycleEvent is one step in the lifecycle of a session.
+type LifecycleEvent struct {
+	Instance string        `json:"instance"`
+	Kind     LifecycleKind `json:"kind"`
+	At       time.Time     `json:"at"`
+	// Strategy is the strategy that requested the session. Requested events
+	// only; empty when the session was not opened by a strategy request (pins,
+	// running hours, anti-affinity restoration).
+	Strategy string `json:"strategy,omitempty"`
+	// ColdStart reports whether the instance was not ready yet when the session
+	// was requested. Requested events only.
+	ColdStart bool `json:"coldStart,omitempty"`
+	// Reason the instance was stopped for. Stopped events only.
+	Reason string `json:"reason,omitempty"`
+}
+
+// History keeps the lifecycle of the sessions after they ended, so the usage of
+// the instances can be reported over time.
+type History interface {
+	RecordLifecycle(context.Context, LifecycleEvent) error
+	// LifecycleEvents calls f for every event recorded before until, in
+	// chronological order.
+	LifecycleEvents(ctx context.Context, until time.Time, f func(LifecycleEvent)) error
+}
+
+// WithHistory records the lifecycle of the sessions into h. Without a history,
+// Usage reports ErrHistoryDisabled.
+func (s *Sablier) WithHistory(h History) {
+	s.history = h
+}
+
+// recordLifecycle records e into the history, if any. A failure is logged and
+// never fails the operation being recorded.
+func (s *Sablier) recordLifecycle(ctx context.Context, e LifecycleEvent) {
+	if s.history == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	if err := s.history.RecordLifecycle(ctx, e); err != nil {
+		s.l.WarnContext(ctx, "cannot record session lifecycle",
+			slog.String("instance", e.Instance),
+			slog.String("kind", string(e.Kind)),
+			slog.Any("error", err),
+		)
+	}
+}
+
+type strategyKey struct{}
+
+// WithStrategy returns a context recording that the sessions requested with it
+// were requested by the given strategy.
+func WithStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, strategyKey{}, strategy)
+}
+
+func strategyFrom(ctx context.Context) string {
+	strategy, _ := ctx.Value(strategyKey{}).(string)
+	return strategy
+}
@@ -0,0 +1,222 @@
+package sablier
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Holidays is a set of dates on which no keep-warm window opens, parsed from
+// the all-day events of an iCalendar file.
+type Holidays struct {
+	dates  map[string]struct{}
+	yearly map[string]struct{}
+}
+
+const (
+	holidayDate   = "2006-01-02"
+	holidayYearly = "01-02"
+)
+
+// ParseHolidays reads the events of an iCalendar (.ics) stream. Every date
+// from the DTSTART of an event up to its DTEND (exclusive, as in iCalendar)
+// is a holiday; an event without DTEND lasts its DTSTART day. Events repeated
+// with RRULE:FREQ=YEARLY recur every year. Times are ignored: an event is a
+// holiday on the date it is written with.
+func ParseHolidays(r io.Reader) (*Holidays, error) {
+	h := &Holidays{dates: map[string]struct{}{}, yearly: map[string]struct{}{}}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Long lines are folded: a continuation starts with a space or a tab.
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var inEvent bool
+	var start, end time.Time
+	var rule string
+	for i, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end, rule = true, time.Time{}, time.Time{}, ""
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			date, err := parseICalDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if strings.EqualFold(name, "DTSTART") {
+				start = date
+			} else {
+				end = date
+			}
+		case "RRULE":
+			if inEvent {
+				rule = strings.ToUpper(value)
+			}
+		case "END":
+			if !inEvent || !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			if err := h.add(start, end, rule); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+	return h, nil
+}
+
+func (h *Holidays) add(start, end time.Time, rule string) error {
+	if !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	set := h.dates
+	layout := holidayDate
+	if rule != "" {
+		if !strings.Contains(rule, "FREQ=YEARLY") {
+			return fmt.Errorf("unsupported RRULE %q: only FREQ=YEARLY is supported", rule)
+		}
+		set, layout = h.yearly, holidayYearly
+	}
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		set[d.Format(layout)] = struct{}{}
+	}
+	return nil
+}
+
+// parseICalDate parses the date of a DATE (20261225) or DATE-TIME
+// (20261225T000000Z) value.
+func parseICalDate(v string) (time.Time, error) {
+	if len(v) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	date, err := time.Parse("20060102", v[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	return date, nil
+}
+
+// Contains reports whether the date of t, in the location of t, is a holiday.
+// A nil Holidays contains no date.
+func (h *Holidays) Contains(t time.Time) bool {
+	if h == nil {
+		return false
+	}
+	if _, ok := h.dates[t.Format(holidayDate)]; ok {
+		return true
+	}
+	_, ok := h.yearly[t.Format(holidayYearly)]
+	return ok
+}
+
+// holidaysCheckInterval is how often the holidays file is checked for
+// changes.
+const holidaysCheckInterval = 10 * time.Second
+
+// holidayCalendar serves the holidays of a file, reloading it when it changes
+// so the calendar can be updated without a restart.
+type holidayCalendar struct {
+	path string
+	l    *slog.Logger
+
+	mu       sync.Mutex
+	holidays *Holidays
+	modTime  time.Time
+	checked  time.Time
+}
+
+// WithHolidays loads the iCalendar file at path and keeps the running-hours
+// and schedule windows closed on its dates. The file is reloaded when it
+// changes.
+func (s *Sablier) WithHolidays(path string) error {
+	c := &holidayCalendar{path: path, l: s.l}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.reloadLocked(); err != nil {
+		return err
+	}
+	s.holidays = c
+	return nil
+}
+
+// current returns the holidays, or nil when no calendar is configured. It
+// reloads the file when it was modified since the last load; a failed reload
+// keeps the previous holidays.
+func (c *holidayCalendar) current(ctx context.Context) *Holidays {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.checked) < holidaysCheckInterval {
+		return c.holidays
+	}
+	c.checked = time.Now()
+
+	// os.Stat follows symlinks, so ConfigMap volumes, which swap a symlinked
+	// directory on update, are seen as changed too.
+	info, err := os.Stat(c.path)
+	if err != nil {
+		c.l.WarnContext(ctx, "cannot read the holidays file, keeping the previous holidays", slog.String("path", c.path), slog.Any("error", err))
+		return c.holidays
+	}
+	if info.ModTime().Equal(c.modTime) {
+		return c.holidays
+	}
+	// Remember the attempt so a broken file is reported once, and retried as
+	// soon as it changes again.
+	c.modTime = info.ModTime()
+	if err := c.reloadLocked(); err != nil {
+		c.l.WarnContext(ctx, "cannot reload the holidays file, keeping the previous holidays", slog.String("path", c.path), slog.Any("error", err))
+		return c.holidays
+	}
+	c.l.InfoContext(ctx, "holidays file reloaded", slog.String("path", c.path))
+	return c.holidays
+}
+
+func (c *holidayCalendar) reloadLocked() error {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return fmt.Errorf("reading holidays file: %w", err)
+	}
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("reading holidays file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	holidays, err := ParseHolidays(f)
+	if err != nil {
+		return fmt.Errorf("parsing holidays file %s: %w", c.path, err)
+	}
+	c.holidays, c.modTime, c.checked = holidays, info.ModTime(), time.Now()
+	return nil
+}
@@ -0,0 +1,84 @@
+package sablier_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+const holidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:christmas\r\n" +
+	"DTSTART;VALUE=DATE:20261225\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:Christmas\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:easter\r\n" +
+	"DTSTART;VALUE=DATE:20260403\r\n" +
+	"DTEND;VALUE=DATE:20260407\r\n" +
+	"SUMMARY:Easter week\r\n" +
+	" end\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:offsite\r\n" +
+	"DTSTART;TZID=Europe/Paris:20260915T090000\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseHolidays(t *testing.T) {
+	holidays, err := sablier.ParseHolidays(strings.NewReader(holidaysICS))
+	assert.NilError(t, err)
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "yearly event", date: date(2026, time.December, 25), want: true},
+		{name: "yearly event recurs", date: date(2031, time.December, 25), want: true},
+		{name: "first day of a range", date: date(2026, time.April, 3), want: true},
+		{name: "last day of a range", date: date(2026, time.April, 6), want: true},
+		{name: "end of a range is exclusive", date: date(2026, time.April, 7)},
+		{name: "range does not recur", date: date(2027, time.April, 4)},
+		{name: "date-time event", date: date(2026, time.September, 15), want: true},
+		{name: "ordinary day", date: date(2026, time.December, 24)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, holidays.Contains(tt.date), tt.want)
+		})
+	}
+}
+
+func TestParseHolidays_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		err  string
+	}{
+		{name: "invalid date", ics: "BEGIN:VEVENT\nDTSTART:2026-12-25\nEND:VEVENT\n", err: `line 2: invalid date "2026-12-25"`},
+		{name: "missing start", ics: "BEGIN:VEVENT\nSUMMARY:Nothing\nEND:VEVENT\n", err: "line 3: event without DTSTART"},
+		{name: "unsupported rule", ics: "BEGIN:VEVENT\nDTSTART:20261225\nRRULE:FREQ=WEEKLY\nEND:VEVENT\n", err: `unsupported RRULE "FREQ=WEEKLY"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sablier.ParseHolidays(strings.NewReader(tt.ics))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestHolidaysContains_Nil(t *testing.T) {
+	var holidays *sablier.Holidays
+	assert.Assert(t, !holidays.Contains(time.Now()))
+}
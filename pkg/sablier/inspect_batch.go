@@ -0,0 +1,82 @@
+package sablier
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type inspection struct {
+	info InstanceInfo
+	err  error
+}
+
+// inspectBatch inspects the instances of a session request together, for
+// providers implementing InstanceInspectMany. The first instance of the
+// request that needs an inspection inspects them all in one provider call;
+// the others reuse its result. A session whose instances are all ready in the
+// store never calls the provider.
+type inspectBatch struct {
+	s       *Sablier
+	names   []string
+	once    sync.Once
+	results map[string]InstanceInfo
+}
+
+// newInspectBatch returns nil, which inspects every instance on its own, when
+// the provider cannot inspect several instances in one call or there is only
+// one to inspect.
+func (s *Sablier) newInspectBatch(names []string) *inspectBatch {
+	if _, ok := s.provider.(InstanceInspectMany); !ok || len(names) < 2 {
+		return nil
+	}
+	b := &inspectBatch{s: s, names: make([]string, 0, len(names))}
+	for _, name := range names {
+		b.names = append(b.names, strings.TrimPrefix(name, OptionalPrefix))
+	}
+	return b
+}
+
+// inspect returns the state of name from the batch, or from fallback when the
+// provider did not answer for it.
+func (b *inspectBatch) inspect(ctx context.Context, name string, fallback func(context.Context, string) (InstanceInfo, error)) (InstanceInfo, error) {
+	if b == nil {
+		return fallback(ctx, name)
+	}
+	b.once.Do(func() {
+		b.results = b.s.inspectMany(ctx, b.names)
+		// Keep the readiness watcher in sync, as for single inspections.
+		for name, info := range b.results {
+			b.s.readiness.record(name, info, nil)
+		}
+	})
+	if info, ok := b.results[name]; ok {
+		return info, nil
+	}
+	return fallback(ctx, name)
+}
+
+// inspectMany inspects names in one provider call. It returns no result when
+// the call fails, so that every instance is inspected on its own.
+func (s *Sablier) inspectMany(ctx context.Context, names []string) map[string]InstanceInfo {
+	p, ok := s.provider.(InstanceInspectMany)
+	if !ok {
+		return nil
+	}
+	ctx, span := s.tracer.Start(ctx, "sablier.instance.inspect_many",
+		trace.WithAttributes(attribute.StringSlice("instances", names)))
+	defer span.End()
+	results, err := p.InstanceInspectMany(ctx, names)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.l.WarnContext(ctx, "batch inspect failed, inspecting instances one by one", slog.Any("names", names), slog.Any("error", err))
+		return nil
+	}
+	return results
+}
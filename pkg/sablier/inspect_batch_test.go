@@ -0,0 +1,132 @@
+package sablier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/provider/providertest"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store/inmemory"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+// batchProvider is a provider implementing InstanceInspectMany.
+type batchProvider struct {
+	*providertest.MockProvider
+	*providertest.MockInstanceInspectMany
+}
+
+func setupBatchSablier(t *testing.T, store sablier.Store) (*sablier.Sablier, *batchProvider) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	p := &batchProvider{
+		MockProvider:            providertest.NewMockProvider(ctrl),
+		MockInstanceInspectMany: providertest.NewMockInstanceInspectMany(ctrl),
+	}
+	p.MockProvider.EXPECT().InstanceDependencies(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	p.MockProvider.EXPECT().InstanceEvents(gomock.Any(), provider.InstanceEventsOptions{}).Return(sablier.InstanceEventStream{}).AnyTimes()
+	return sablier.New(slogt.New(t), store, p), p
+}
+
+// expectStarts expects n asynchronous starts, done once the returned group is.
+func expectStarts(p *batchProvider, n int) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	p.MockProvider.EXPECT().InstanceStart(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, string) error {
+		wg.Done()
+		return nil
+	}).Times(n)
+	return &wg
+}
+
+func stoppedInstance(name string) sablier.InstanceInfo {
+	return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusStopped, DesiredReplicas: 1}
+}
+
+func TestRequestSession_InspectsInOneCall(t *testing.T) {
+	s, p := setupBatchSablier(t, inmemory.NewInMemory())
+
+	p.MockInstanceInspectMany.EXPECT().
+		InstanceInspectMany(gomock.Any(), gomock.InAnyOrder([]string{"web", "db", "cache"})).
+		Return(map[string]sablier.InstanceInfo{"web": stoppedInstance("web"), "db": stoppedInstance("db")}, nil)
+	// The provider did not answer for cache: it is inspected on its own.
+	p.MockProvider.EXPECT().InstanceInspect(gomock.Any(), "cache").Return(stoppedInstance("cache"), nil)
+	started := expectStarts(p, 3)
+
+	session, err := s.RequestSession(t.Context(), []string{"web", "db", "optional:cache"}, time.Minute)
+	assert.NilError(t, err)
+	assert.Equal(t, len(session.Instances), 3)
+	for _, v := range session.Instances {
+		assert.NilError(t, v.Error)
+		assert.Equal(t, v.Instance.Status, sablier.InstanceStatusStarting)
+	}
+	started.Wait()
+}
+
+func TestRequestSession_FallsBackWhenBatchFails(t *testing.T) {
+	s, p := setupBatchSablier(t, inmemory.NewInMemory())
+
+	p.MockInstanceInspectMany.EXPECT().InstanceInspectMany(gomock.Any(), gomock.Any()).Return(nil, errors.New("list failed"))
+	p.MockProvider.EXPECT().InstanceInspect(gomock.Any(), "web").Return(stoppedInstance("web"), nil)
+	p.MockProvider.EXPECT().InstanceInspect(gomock.Any(), "db").Return(stoppedInstance("db"), nil)
+	started := expectStarts(p, 2)
+
+	_, err := s.RequestSession(t.Context(), []string{"web", "db"}, time.Minute)
+	assert.NilError(t, err)
+	started.Wait()
+}
+
+func TestRequestSession_SingleInstanceIsNotBatched(t *testing.T) {
+	s, p := setupBatchSablier(t, inmemory.NewInMemory())
+
+	p.MockProvider.EXPECT().InstanceInspect(gomock.Any(), "web").Return(stoppedInstance("web"), nil)
+	started := expectStarts(p, 1)
+
+	_, err := s.RequestSession(t.Context(), []string{"web"}, time.Minute)
+	assert.NilError(t, err)
+	started.Wait()
+}
+
+func TestRequestReadySession_PollsInOneCall(t *testing.T) {
+	ready := func(name string) sablier.InstanceInfo {
+		return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}
+	}
+	starting := func(name string) sablier.InstanceInfo {
+		return sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusStarting, DesiredReplicas: 1}
+	}
+
+	// The session already exists: nothing is started, and every inspection,
+	// from the request or the readiness watcher, goes through the batch.
+	store := inmemory.NewInMemory()
+	for _, name := range []string{"web", "db"} {
+		assert.NilError(t, store.Put(t.Context(), starting(name), time.Minute))
+	}
+	s, p := setupBatchSablier(t, store)
+	s.BlockingRefreshFrequency = 10 * time.Millisecond
+
+	var calls atomic.Int32
+	p.MockInstanceInspectMany.EXPECT().InstanceInspectMany(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, names []string) (map[string]sablier.InstanceInfo, error) {
+			n := calls.Add(1)
+			infos := map[string]sablier.InstanceInfo{}
+			for _, name := range names {
+				if n < 3 {
+					infos[name] = starting(name)
+				} else {
+					infos[name] = ready(name)
+				}
+			}
+			return infos, nil
+		}).MinTimes(3)
+
+	session, err := s.RequestReadySession(t.Context(), []string{"web", "db"}, time.Minute, 5*time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, session.IsReady())
+}
@@ -25,6 +25,11 @@ const (
 	// anti-affinity antagonist group. It carries a Message explaining why, is
 	// never persisted or seen by providers, and is treated as not ready.
 	InstanceStatusNotReady InstanceStatus = "not-ready"
+	// InstanceStatusQueued marks an instance that is not started until the
+	// capacity of active instances frees up (see Sablier.WithCapacity). Like
+	// InstanceStatusNotReady, it carries a Message explaining why, is never
+	// persisted or seen by providers, and is treated as not ready.
+	InstanceStatusQueued InstanceStatus = "queued"
 )
 
 // ProviderType identifies the infrastructure provider that manages an instance.
@@ -59,6 +64,11 @@ type InstanceInfo struct {
 	// It is set internally by Sablier and is never populated by a provider.
 	ReadyAt *time.Time `json:"readyAt,omitempty"`
 
+	// SessionDeadline is the absolute time a fixed-mode session ends at, set
+	// by its first request. Requests never extend it. It is set internally by
+	// Sablier and is never populated by a provider.
+	SessionDeadline *time.Time `json:"sessionDeadline,omitempty"`
+
 	// RunningHours is a daily keep-warm window in local time, parsed from
 	// the sablier.running-hours label (format: HH:MM-HH:MM).
 	RunningHours string `json:"runningHours,omitempty"`
@@ -68,6 +78,11 @@ type InstanceInfo struct {
 	// Empty means the window applies every day.
 	RunningDays string `json:"runningDays,omitempty"`
 
+	// NextWindow is the open window of the sablier.schedule label, or the next
+	// one to open when none is, skipping the dates of the holidays file. It is
+	// computed when the instance API is called.
+	NextWindow *ScheduleWindow `json:"nextWindow,omitempty"`
+
 	// ReadyOnStart indicates the instance should be considered ready as soon as
 	// the start is dispatched, without waiting for health.
 	// Controlled by the sablier.ready-on-start Label
@@ -395,9 +410,16 @@ func ParseAntiAffinity(label string) []string {
 // mirrors the result into the flat legacy fields, which stay byte-identical
 // on the wire (API responses and persisted session records). All parsing
 // lives in InstanceConfigFromLabels; this is only the compatibility adapter
-// providers call from their Inspect implementations.
+// providers call from their Inspect implementations, without any policies.
+// See PolicySet.PopulateEnabledAndGroup.
 func PopulateEnabledAndGroup(info *InstanceInfo, labels map[string]string) {
-	cfg := InstanceConfigFromLabels(labels, slog.Default().With(slog.String("instance", info.Name)))
+	(*PolicySet)(nil).PopulateEnabledAndGroup(info, labels)
+}
+
+// PopulateEnabledAndGroup is PopulateEnabledAndGroup with the policies of ps
+// applied to the labels.
+func (ps *PolicySet) PopulateEnabledAndGroup(info *InstanceInfo, labels map[string]string) {
+	cfg := ps.InstanceConfigFromLabels(info.Name, labels, slog.Default().With(slog.String("instance", info.Name)))
 	info.Config = &cfg
 
 	// Legacy flat fields. Enabled keeps the raw label value (not the parsed
@@ -1,6 +1,7 @@
 package sablier
 
 import (
+	"errors"
 	"log/slog"
 	"strconv"
 	"time"
@@ -19,9 +20,13 @@ type InstanceConfig struct {
 	// Enabled reports whether the instance opted into Sablier management
 	// (sablier.enable set to exactly "true").
 	Enabled bool `json:"enabled,omitempty"`
-	// Groups the instance belongs to (sablier.group). Only populated for
-	// enabled instances; defaults to ["default"] when the label is absent.
+	// Groups the instance belongs to (sablier.group, and the groups the
+	// configured policies assign). Only populated for enabled instances;
+	// defaults to ["default"] when there are none.
 	Groups []string `json:"groups,omitempty"`
+	// DependsOn are the dependencies the configured policies add to the ones
+	// the provider reports. Only populated for enabled instances.
+	DependsOn []InstanceDependency `json:"dependsOn,omitempty"`
 	// ReadyAfter is the settling delay after the instance first reports ready
 	// (sablier.ready-after). Zero means no extra wait.
 	ReadyAfter time.Duration `json:"readyAfter,omitempty"`
@@ -36,6 +41,24 @@ type InstanceConfig struct {
 	// RunningDays restricts RunningHours to specific weekdays
 	// (sablier.running-days). Empty means every day.
 	RunningDays string `json:"runningDays,omitempty"`
+	// Schedule is the validated cron keep-warm schedule (sablier.schedule),
+	// kept in its string form like RunningHours; parse it with ParseSchedule.
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is the IANA time zone the keep-warm windows are evaluated in
+	// (sablier.timezone). Empty means the local time zone.
+	Timezone string `json:"timezone,omitempty"`
+	// SessionDuration is the session duration of requests that do not ask for
+	// one (sablier.session-duration). Zero means sessions.default-duration.
+	SessionDuration time.Duration `json:"sessionDuration,omitempty"`
+	// SessionDurationMax caps the requested session duration
+	// (sablier.session-duration-max). Zero means no cap.
+	SessionDurationMax time.Duration `json:"sessionDurationMax,omitempty"`
+	// SessionMode is the session mode of the instance (sablier.session-mode).
+	// Empty means sliding.
+	SessionMode SessionMode `json:"sessionMode,omitempty"`
+	// IdleActivity keeps the session alive while the instance has traffic
+	// (sablier.idle.activity). Empty means only requests renew the session.
+	IdleActivity ActivitySource `json:"idleActivity,omitempty"`
 	// AntiAffinity lists the groups this instance backs off from
 	// (sablier.anti-affinity).
 	AntiAffinity []string `json:"antiAffinity,omitempty"`
@@ -47,8 +70,17 @@ type InstanceConfig struct {
 // InstanceConfigFromLabels parses every sablier.* label into a typed
 // InstanceConfig. Invalid values are logged through l and ignored, keeping
 // the previous per-label warn-and-skip behavior. A nil logger falls back to
-// slog.Default().
+// slog.Default(). No policies apply: see PolicySet.InstanceConfigFromLabels.
 func InstanceConfigFromLabels(labels map[string]string, l *slog.Logger) InstanceConfig {
+	return (*PolicySet)(nil).InstanceConfigFromLabels("", labels, l)
+}
+
+// InstanceConfigFromLabels parses every sablier.* label of the instance name
+// into a typed InstanceConfig. The policies of ps are applied to enabled
+// instances first: a label of the instance wins over the policies selecting
+// it. Invalid values are logged through l and ignored, keeping the previous
+// per-label warn-and-skip behavior. A nil logger falls back to slog.Default().
+func (ps *PolicySet) InstanceConfigFromLabels(name string, labels map[string]string, l *slog.Logger) InstanceConfig {
 	if l == nil {
 		l = slog.Default()
 	}
@@ -56,7 +88,9 @@ func InstanceConfigFromLabels(labels map[string]string, l *slog.Logger) Instance
 	var cfg InstanceConfig
 	cfg.Enabled = labels[LabelEnable] == "true"
 	if cfg.Enabled {
-		cfg.Groups = ParseGroups(labels[LabelGroup])
+		var groups []string
+		labels, groups, cfg.DependsOn = ps.Load().resolve(name, labels)
+		cfg.Groups = mergeGroups(labels[LabelGroup], groups)
 	}
 	if v := labels[LabelReadyAfter]; v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
@@ -88,6 +122,26 @@ func InstanceConfigFromLabels(labels map[string]string, l *slog.Logger) Instance
 			)
 		}
 	}
+	if v := labels[LabelSchedule]; v != "" {
+		if _, err := ParseSchedule(v); err == nil {
+			cfg.Schedule = v
+		} else {
+			l.Warn("invalid sablier.schedule label value, ignoring",
+				slog.String("value", v),
+				slog.Any("error", err),
+			)
+		}
+	}
+	if v := labels[LabelTimezone]; v != "" {
+		if _, err := time.LoadLocation(v); err == nil {
+			cfg.Timezone = v
+		} else {
+			l.Warn("invalid sablier.timezone label value, ignoring",
+				slog.String("value", v),
+				slog.Any("error", err),
+			)
+		}
+	}
 	if v := labels[LabelReadyOnStart]; v != "" {
 		b, err := strconv.ParseBool(v)
 		if err != nil {
@@ -99,6 +153,28 @@ func InstanceConfigFromLabels(labels map[string]string, l *slog.Logger) Instance
 			cfg.ReadyOnStart = b
 		}
 	}
+	cfg.SessionDuration = parseSessionDurationLabel(labels, LabelSessionDuration, l)
+	cfg.SessionDurationMax = parseSessionDurationLabel(labels, LabelSessionDurationMax, l)
+	if v := labels[LabelSessionMode]; v != "" {
+		if mode, err := ParseSessionMode(v); err == nil {
+			cfg.SessionMode = mode
+		} else {
+			l.Warn("invalid sablier.session-mode label value, ignoring",
+				slog.String("value", v),
+				slog.Any("error", err),
+			)
+		}
+	}
+	if v := labels[LabelIdleActivity]; v != "" {
+		if source, err := ParseActivitySource(v); err == nil {
+			cfg.IdleActivity = source
+		} else {
+			l.Warn("invalid sablier.idle.activity label value, ignoring",
+				slog.String("value", v),
+				slog.Any("error", err),
+			)
+		}
+	}
 	if v := labels[LabelAntiAffinity]; v != "" {
 		cfg.AntiAffinity = ParseAntiAffinity(v)
 	}
@@ -112,3 +188,24 @@ func InstanceConfigFromLabels(labels map[string]string, l *slog.Logger) Instance
 	}
 	return cfg
 }
+
+// parseSessionDurationLabel parses the positive duration of the label key.
+// An invalid or non-positive value is logged through l and ignored.
+func parseSessionDurationLabel(labels map[string]string, key string, l *slog.Logger) time.Duration {
+	v := labels[key]
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err == nil && d <= 0 {
+		err = errors.New("must be positive")
+	}
+	if err != nil {
+		l.Warn("invalid "+key+" label value, ignoring",
+			slog.String("value", v),
+			slog.Any("error", err),
+		)
+		return 0
+	}
+	return d
+}
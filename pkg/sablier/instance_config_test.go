@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/neilotoole/slogt"
 	"gotest.tools/v3/assert"
 )
 
@@ -66,6 +67,69 @@ func TestInstanceConfigFromLabels(t *testing.T) {
 			},
 			want: InstanceConfig{Enabled: true, Groups: []string{"default"}},
 		},
+		{
+			name: "session durations",
+			labels: map[string]string{
+				LabelEnable:             "true",
+				LabelSessionDuration:    "30m",
+				LabelSessionDurationMax: "2h",
+			},
+			want: InstanceConfig{
+				Enabled:            true,
+				Groups:             []string{"default"},
+				SessionDuration:    30 * time.Minute,
+				SessionDurationMax: 2 * time.Hour,
+			},
+		},
+		{
+			name:   "session mode",
+			labels: map[string]string{LabelEnable: "true", LabelSessionMode: "fixed"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}, SessionMode: SessionModeFixed},
+		},
+		{
+			name:   "invalid session mode is ignored",
+			labels: map[string]string{LabelEnable: "true", LabelSessionMode: "forever"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
+		{
+			name:   "schedule",
+			labels: map[string]string{LabelEnable: "true", LabelSchedule: "30 8 * * mon-fri 30m; 0 2 * * sun 2h"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}, Schedule: "30 8 * * mon-fri 30m; 0 2 * * sun 2h"},
+		},
+		{
+			name:   "invalid schedule is ignored",
+			labels: map[string]string{LabelEnable: "true", LabelSchedule: "every day"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
+		{
+			name:   "timezone",
+			labels: map[string]string{LabelEnable: "true", LabelTimezone: "America/New_York"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}, Timezone: "America/New_York"},
+		},
+		{
+			name:   "unknown timezone is ignored",
+			labels: map[string]string{LabelEnable: "true", LabelTimezone: "Mars/Olympus_Mons"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
+		{
+			name:   "idle activity",
+			labels: map[string]string{LabelEnable: "true", LabelIdleActivity: "network"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}, IdleActivity: ActivityNetwork},
+		},
+		{
+			name:   "invalid idle activity is ignored",
+			labels: map[string]string{LabelEnable: "true", LabelIdleActivity: "cpu"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
+		{
+			name: "invalid or non-positive session durations are ignored",
+			labels: map[string]string{
+				LabelEnable:             "true",
+				LabelSessionDuration:    "forever",
+				LabelSessionDurationMax: "0s",
+			},
+			want: InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
 		{
 			name: "scale config only when a non-default scale label is present",
 			labels: map[string]string{
@@ -137,3 +201,21 @@ func TestPopulateEnabledAndGroup_WireCompat(t *testing.T) {
 	assert.Assert(t, ok, "config must be serialized additively")
 	assert.Equal(t, true, cfg["enabled"])
 }
+
+func TestInspectInstance_NextWindow(t *testing.T) {
+	s := New(slogt.New(t), nil, nil)
+	info := InstanceInfo{Name: "web"}
+	PopulateEnabledAndGroup(&info, map[string]string{
+		LabelEnable:   "true",
+		LabelSchedule: "0 2 * * * 2h",
+	})
+	// The window is computed when served, not when the instance is inspected
+	// and cached.
+	assert.Assert(t, info.NextWindow == nil)
+
+	s.withNextWindow(t.Context(), &info, time.Now())
+	assert.Assert(t, info.NextWindow != nil)
+	assert.Equal(t, info.NextWindow.Start.Hour(), 2)
+	assert.Equal(t, info.NextWindow.End.Sub(info.NextWindow.Start), 2*time.Hour)
+	assert.Assert(t, info.NextWindow.End.After(time.Now()))
+}
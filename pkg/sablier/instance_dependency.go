@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -86,7 +87,8 @@ func (s *Sablier) startWithDependencies(ctx context.Context, name string) error
 // and, recursively, of every dependency reachable from it. name is recorded
 // before its dependencies are walked so a cyclic configuration terminates
 // instead of recursing forever; the cycle is reported later by
-// dependencyGraphCycle.
+// dependencyGraphCycle. When the configured policies declare dependencies, the
+// ones they add to an instance (InstanceConfig.DependsOn) are merged in.
 func (s *Sablier) resolveDependencyGraph(ctx context.Context, name string, graph map[string][]InstanceDependency) error {
 	if _, ok := graph[name]; ok {
 		return nil
@@ -96,6 +98,19 @@ func (s *Sablier) resolveDependencyGraph(ctx context.Context, name string, graph
 	if err != nil {
 		return fmt.Errorf("cannot resolve dependencies of %q: %w", name, err)
 	}
+	if s.policies.Load().hasDependencies() {
+		info, err := s.provider.InstanceInspect(ctx, name)
+		if err != nil {
+			return fmt.Errorf("cannot resolve dependencies of %q: %w", name, err)
+		}
+		if info.Config != nil {
+			for _, dep := range info.Config.DependsOn {
+				if !slices.ContainsFunc(deps, func(d InstanceDependency) bool { return d.Name == dep.Name }) {
+					deps = append(deps, dep)
+				}
+			}
+		}
+	}
 	graph[name] = deps
 
 	for _, dep := range deps {
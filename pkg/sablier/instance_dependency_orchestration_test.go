@@ -21,6 +21,10 @@ type fakeDepProvider struct {
 
 	// deps maps an instance to its direct dependencies.
 	deps map[string][]InstanceDependency
+	// labels are the labels InstanceInspect populates the config from.
+	labels map[string]map[string]string
+	// policies are applied to the labels.
+	policies *PolicySet
 	// status is the current status reported by InstanceInspect. Missing entries
 	// default to statusDefault.
 	status        map[string]InstanceStatus
@@ -42,6 +46,7 @@ type fakeDepProvider struct {
 func newFakeDepProvider() *fakeDepProvider {
 	return &fakeDepProvider{
 		deps:          map[string][]InstanceDependency{},
+		labels:        map[string]map[string]string{},
 		status:        map[string]InstanceStatus{},
 		statusDefault: InstanceStatusStopped,
 		onStart:       map[string]InstanceStatus{},
@@ -87,7 +92,11 @@ func (f *fakeDepProvider) InstanceInspect(_ context.Context, name string) (Insta
 	if !ok {
 		st = f.statusDefault
 	}
-	return InstanceInfo{Name: name, Status: st}, nil
+	info := InstanceInfo{Name: name, Status: st}
+	if labels, ok := f.labels[name]; ok {
+		f.policies.PopulateEnabledAndGroup(&info, labels)
+	}
+	return info, nil
 }
 
 func (f *fakeDepProvider) InstanceDependencies(_ context.Context, name string) ([]InstanceDependency, error) {
@@ -169,6 +178,34 @@ func TestStartWithDependencies_ChainStartsDepsFirst(t *testing.T) {
 	}
 }
 
+func TestStartWithDependencies_PolicyDependencies(t *testing.T) {
+	// The provider reports app -> cache, a policy adds app -> db.
+	fp := newFakeDepProvider()
+	fp.deps["app"] = []InstanceDependency{condStarted("cache")}
+	fp.labels["app"] = map[string]string{LabelEnable: "true"}
+	s := newDepSablier(t, fp)
+
+	sel, err := ParseInstanceSelector([]string{"app"}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp.policies = NewPolicySet(&Policies{Policies: []Policy{{Name: "db", Selector: sel, DependsOn: []InstanceDependency{condStarted("db"), condStarted("cache")}}}})
+	s.WithPolicies(fp.policies)
+
+	if err := s.startWithDependencies(context.Background(), "app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"app", "cache", "db"} {
+		if c := fp.count(name); c != 1 {
+			t.Fatalf("expected %s to start exactly once, started %d times (order %v)", name, c, fp.starts())
+		}
+	}
+	if got := fp.starts(); got[len(got)-1] != "app" {
+		t.Fatalf("expected app to start last, got %v", got)
+	}
+}
+
 func TestStartWithDependencies_DiamondStartsSharedDepOnce(t *testing.T) {
 	// app -> {web, worker} -> db. db must be started exactly once.
 	fp := newFakeDepProvider()
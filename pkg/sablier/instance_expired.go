@@ -19,12 +19,56 @@ func OnInstanceExpired(ctx context.Context, provider Provider, recorder metrics.
 func (s *Sablier) OnInstanceExpired(ctx context.Context) func(string) {
 	base := onInstanceExpired(ctx, s.provider, s.metrics, s.l, s.verifyEnabledOnExpiration)
 	return func(key string) {
-		base(key)
-		// The expired session may have been the last one keeping a group active;
-		// restore any instance we forced idle because of an anti-affinity to it.
-		// The store key is already gone by the time this callback runs, so the
-		// reconcile sees the group as inactive.
-		s.triggerAntiAffinityReconcile(ctx)
+		// The queued instances may now fit the capacity.
+		s.capacityFreed(key)
+		// Every replica sharing the store is told about the expiration; the
+		// leader alone stops the instance. The others only let the blocking
+		// requests an anti-affinity held go on.
+		if !s.isLeader() {
+			s.triggerAntiAffinityReconcile(ctx)
+			return
+		}
+		// A pinned instance must outlive its session; re-create the session for
+		// the rest of the pin instead of stopping the instance.
+		if remaining := s.pinRemaining(key); remaining > 0 {
+			s.holdCapacity(key)
+			go func() {
+				defer s.releaseRenewal(key)
+				if _, err := s.InstanceRequest(ctx, key, remaining); err != nil {
+					s.l.ErrorContext(ctx, "pinned instance expired but its session could not be renewed", slog.String("instance", key), slog.Any("error", err))
+				}
+			}()
+			return
+		}
+		stop := func() {
+			base(key)
+			s.recordLifecycle(ctx, LifecycleEvent{Instance: key, Kind: LifecycleStopped, Reason: StopReasonExpired})
+			// The expired session may have been the last one keeping a group
+			// active; restore any instance we forced idle because of an
+			// anti-affinity to it. The store key is already gone by the time
+			// this callback runs, so the reconcile sees the group as inactive.
+			s.triggerAntiAffinityReconcile(ctx)
+		}
+		// An instance kept alive by its traffic had no request for the whole
+		// session, but may still be in use: re-create the session if traffic
+		// flowed since the last sample instead of stopping it.
+		if s.tracksActivityOf(key) {
+			s.holdCapacity(key)
+			go func() {
+				defer s.releaseRenewal(key)
+				if !s.activeOnExpiration(ctx, key) {
+					stop()
+					return
+				}
+				s.l.InfoContext(ctx, "instance expired but has traffic, renewing its session", slog.String("instance", key))
+				if _, err := s.InstanceRequest(ctx, key, 0); err != nil {
+					s.l.ErrorContext(ctx, "active instance expired but its session could not be renewed, stopping it", slog.String("instance", key), slog.Any("error", err))
+					stop()
+				}
+			}()
+			return
+		}
+		stop()
 	}
 }
 
@@ -32,24 +76,31 @@ func onInstanceExpired(ctx context.Context, provider Provider, recorder metrics.
 	return func(_key string) {
 		go func(key string) {
 			logger.InfoContext(ctx, "instance expired", slog.String("instance", key))
-			if verifyEnabled {
-				info, err := provider.InstanceInspect(ctx, key)
-				if err != nil {
-					logger.WarnContext(ctx, "instance expired could not be inspected before stop", slog.String("instance", key), slog.Any("error", err))
-					return
-				}
-				if !info.IsEnabled() {
-					logger.WarnContext(ctx, "instance expired but is not managed by sablier, skipping stop", slog.String("instance", key))
-					return
-				}
-			}
-			err := provider.InstanceStop(ctx, key)
-			if err != nil {
-				logger.ErrorContext(ctx, "instance expired could not be stopped from provider", slog.String("instance", key), slog.Any("error", err))
-			}
-			recorder.RecordInstanceStop(key, "expired")
-			recorder.RecordInactiveInstance(key)
-			recorder.DiscardReadyWait(key)
+			stopInstance(ctx, provider, recorder, logger, verifyEnabled, key, StopReasonExpired)
 		}(_key)
 	}
 }
+
+// stopInstance stops an instance whose session ended for reason. With
+// verifyEnabled, an instance no longer labelled sablier.enable=true is left
+// running.
+func stopInstance(ctx context.Context, provider Provider, recorder metrics.Recorder, logger *slog.Logger, verifyEnabled bool, key string, reason string) {
+	if verifyEnabled {
+		info, err := provider.InstanceInspect(ctx, key)
+		if err != nil {
+			logger.WarnContext(ctx, "instance could not be inspected before stop", slog.String("instance", key), slog.String("reason", reason), slog.Any("error", err))
+			return
+		}
+		if !info.IsEnabled() {
+			logger.WarnContext(ctx, "instance is not managed by sablier, skipping stop", slog.String("instance", key), slog.String("reason", reason))
+			return
+		}
+	}
+	err := provider.InstanceStop(ctx, key)
+	if err != nil {
+		logger.ErrorContext(ctx, "instance could not be stopped from provider", slog.String("instance", key), slog.String("reason", reason), slog.Any("error", err))
+	}
+	recorder.RecordInstanceStop(key, reason)
+	recorder.RecordInactiveInstance(key)
+	recorder.DiscardReadyWait(key)
+}
@@ -110,6 +110,19 @@ func TestSablierOnInstanceExpired_VerifyEnabledOnExpirationSkipsUnlabeledInstanc
 	assertNoExpirationMetrics(t, rec)
 }
 
+func TestSablierOnInstanceExpired_FollowerLeavesTheStopToTheLeader(t *testing.T) {
+	manager, _, _, rec := setupSablierWithMetrics(t)
+	leader := false
+	manager.WithLeader(func() bool { return leader })
+	ctx := t.Context()
+
+	// The mock provider fails the test on any call.
+	manager.OnInstanceExpired(ctx)("nginx")
+	time.Sleep(50 * time.Millisecond)
+
+	assertNoExpirationMetrics(t, rec)
+}
+
 func assertNoExpirationMetrics(t *testing.T, rec metrics.Recorder) {
 	t.Helper()
 	fake := rec.(*fakeRecorder)
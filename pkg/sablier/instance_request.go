@@ -49,7 +49,7 @@ func (s *Sablier) consumePendingError(name string) (bool, error) {
 	}
 }
 
-func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled bool) (InstanceInfo, error) {
+func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled bool, batch *inspectBatch) (InstanceInfo, error) {
 	// First critical section: check whether a start is already in progress.
 	// We release the lock before doing the remote inspect to avoid holding a
 	// mutex across a potentially slow network call.
@@ -90,7 +90,7 @@ func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled
 	// the start still proceeds.
 	inspectCtx, inspectSpan := s.tracer.Start(ctx, "sablier.instance.inspect",
 		trace.WithAttributes(attribute.String("instance", name)))
-	info, err := s.provider.InstanceInspect(inspectCtx, name)
+	info, err := batch.inspect(inspectCtx, name, s.provider.InstanceInspect)
 	inspectSpan.RecordError(err)
 	if err != nil {
 		inspectSpan.SetStatus(codes.Error, err.Error())
@@ -106,6 +106,7 @@ func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled
 	if rejectUnlabeled && !info.IsEnabled() {
 		return InstanceInfo{}, ErrInstanceNotManaged{Name: name}
 	}
+	coldStart := info.Status != InstanceStatusReady
 	info.Status = InstanceStatusStarting
 
 	// Second critical section: register the pending entry. Re-check in case
@@ -138,6 +139,13 @@ func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled
 	s.pendingStarts[name] = ps
 	s.pendingMu.Unlock()
 
+	s.recordLifecycle(ctx, LifecycleEvent{
+		Instance:  name,
+		Kind:      LifecycleRequested,
+		Strategy:  strategyFrom(ctx),
+		ColdStart: coldStart,
+	})
+
 	// Begin metrics tracking BEFORE dispatching the goroutine.
 	// Idempotent — if a previous Begin was already recorded, it is preserved.
 	s.metrics.RecordReadyWaitBegin(name)
@@ -153,8 +161,12 @@ func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled
 
 	go func() {
 		defer cancel()
+		// Wake the blocking requests once the outcome can be read.
+		defer s.readiness.wake(name)
 		defer close(ps.done)
 		startedAt := time.Now()
+		// The instances evicted to make room must be stopped first.
+		s.waitEvictions(startCtx, name)
 		startCtx, startSpan := s.tracer.Start(startCtx, "sablier.instance.start",
 			trace.WithAttributes(attribute.String("instance", name)))
 		if err := s.startWithDependencies(startCtx, name); err != nil {
@@ -182,12 +194,16 @@ func (s *Sablier) requestStart(ctx context.Context, name string, rejectUnlabeled
 }
 
 func (s *Sablier) InstanceRequest(ctx context.Context, name string, duration time.Duration) (InstanceInfo, error) {
-	return s.instanceRequest(ctx, name, duration, false)
+	info, _, err := s.instanceRequest(ctx, name, duration, false, nil)
+	return info, err
 }
 
-func (s *Sablier) instanceRequest(ctx context.Context, name string, duration time.Duration, rejectUnlabeled bool) (InstanceInfo, error) {
+// instanceRequest requests a session for one instance and returns the
+// duration the session was given. batch, when not nil, shares the provider
+// inspections with the other instances of the session.
+func (s *Sablier) instanceRequest(ctx context.Context, name string, duration time.Duration, rejectUnlabeled bool, batch *inspectBatch) (InstanceInfo, time.Duration, error) {
 	if name == "" {
-		return InstanceInfo{}, errors.New("instance name cannot be empty")
+		return InstanceInfo{}, 0, errors.New("instance name cannot be empty")
 	}
 
 	// Anti-affinity: while one of this instance's antagonist groups holds an
@@ -204,7 +220,7 @@ func (s *Sablier) instanceRequest(ctx context.Context, name string, duration tim
 			DesiredReplicas: 1,
 			Status:          InstanceStatusNotReady,
 			Message:         fmt.Sprintf("paused while group %q is active (anti-affinity)", group),
-		}, nil
+		}, 0, nil
 	}
 
 	newSession := false
@@ -212,21 +228,33 @@ func (s *Sablier) instanceRequest(ctx context.Context, name string, duration tim
 	if errors.Is(err, store.ErrKeyNotFound) {
 		s.l.DebugContext(ctx, "request to start instance received", slog.String("instance", name))
 
-		state, err = s.requestStart(ctx, name, rejectUnlabeled)
+		// Capacity: a new session must fit the limit on active instances,
+		// evicting the least recently used ones or waiting in the queue.
+		var queued *InstanceInfo
+		queued, err = s.admitCapacity(ctx, name)
 		if err != nil {
-			return InstanceInfo{}, err
+			return InstanceInfo{}, 0, err
+		}
+		if queued != nil {
+			return *queued, 0, nil
+		}
+		defer s.releaseCapacity(name)
+
+		state, err = s.requestStart(ctx, name, rejectUnlabeled, batch)
+		if err != nil {
+			return InstanceInfo{}, 0, err
 		}
 		newSession = true
 
 		s.l.InfoContext(ctx, "request to start instance dispatched", slog.String("instance", name), slog.String("status", string(state.Status)), slog.Duration("expiration", duration))
 	} else if err != nil {
 		s.l.ErrorContext(ctx, "request to start instance failed", slog.String("instance", name), slog.Any("error", err))
-		return InstanceInfo{}, fmt.Errorf("cannot retrieve instance from store: %w", err)
+		return InstanceInfo{}, 0, fmt.Errorf("cannot retrieve instance from store: %w", err)
 	} else if state.Status != InstanceStatusReady {
 		// Check for a completed (possibly failed) async start before inspecting
 		pending, pendingErr := s.consumePendingError(name)
 		if pendingErr != nil {
-			return InstanceInfo{}, pendingErr
+			return InstanceInfo{}, 0, pendingErr
 		}
 
 		if pending {
@@ -234,36 +262,50 @@ func (s *Sablier) instanceRequest(ctx context.Context, name string, duration tim
 			s.l.DebugContext(ctx, "instance start still in progress, skipping inspect", slog.String("instance", name))
 		} else {
 			s.l.DebugContext(ctx, "request to check instance status received", slog.String("instance", name), slog.String("current_status", string(state.Status)))
-			inspectCtx, inspectSpan := s.tracer.Start(ctx, "sablier.instance.inspect",
-				trace.WithAttributes(attribute.String("instance", name)))
-			state, err = s.provider.InstanceInspect(inspectCtx, name)
-			inspectSpan.RecordError(err)
-			if err != nil {
-				inspectSpan.SetStatus(codes.Error, err.Error())
-			}
-			inspectSpan.End()
+			stored := state
+			state, err = batch.inspect(ctx, name, s.readiness.inspect)
 			if err != nil {
-				return InstanceInfo{}, err
+				return InstanceInfo{}, 0, err
 			}
+			// The provider knows nothing of the session: keep what the store
+			// recorded about it.
+			state.SessionDeadline = stored.SessionDeadline
+			state.ReadyAt = stored.ReadyAt
 			if state.Status == InstanceStatusReady {
 				s.metrics.RecordReadyWaitEnd(name)
 				// First transition to ready — stamp the time so ReadyAfter can be enforced.
 				now := time.Now()
 				state.ReadyAt = &now
+				s.recordLifecycle(ctx, LifecycleEvent{Instance: name, Kind: LifecycleReady, At: now})
 			}
 			s.l.DebugContext(ctx, "request to check instance status completed", slog.String("instance", name), slog.String("new_status", string(state.Status)))
 		}
 	}
 
+	duration = s.sessionDuration(state, duration)
+	if state.SessionDeadline != nil {
+		// A fixed session is never renewed: requests only refresh the
+		// instance state until the deadline.
+		duration = max(time.Until(*state.SessionDeadline), 0)
+	} else if fixedSession(ctx, state) {
+		deadline := time.Now().Add(duration)
+		state.SessionDeadline = &deadline
+	}
 	effectiveDuration := duration
-	if state.RunningHours != "" {
-		remaining, inWindow, runningHoursErr := runningHoursRemaining(state.RunningHours, state.RunningDays, time.Now())
-		if runningHoursErr != nil {
-			s.l.WarnContext(ctx, "invalid running-hours or running-days value in state, ignoring", slog.String("instance", name), slog.String("running-hours", state.RunningHours), slog.String("running-days", state.RunningDays), slog.Any("error", runningHoursErr))
-		} else if inWindow && remaining > effectiveDuration {
-			effectiveDuration = remaining
-			s.l.DebugContext(ctx, "running-hours window active, extending expiration", slog.String("instance", name), slog.Duration("expiration", effectiveDuration), slog.Duration("window_remaining", remaining))
-		}
+	if remaining, rule := s.keepWarm(ctx, state, time.Now()); remaining > effectiveDuration {
+		effectiveDuration = remaining
+		s.l.DebugContext(ctx, "keep-warm window open, extending expiration", slog.String("instance", name), slog.String("rule", rule), slog.Duration("expiration", effectiveDuration), slog.Duration("window_remaining", remaining))
+	}
+
+	if remaining := s.pinRemaining(name); remaining > effectiveDuration {
+		effectiveDuration = remaining
+		s.l.DebugContext(ctx, "instance pinned, extending expiration", slog.String("instance", name), slog.Duration("expiration", effectiveDuration))
+	}
+
+	if state.SessionDeadline != nil && effectiveDuration <= 0 {
+		// The deadline of a fixed session passed: leave the session to expire.
+		s.l.DebugContext(ctx, "fixed session past its deadline, not renewing", slog.String("instance", name), slog.Time("deadline", *state.SessionDeadline))
+		return state, 0, nil
 	}
 
 	s.l.DebugContext(ctx, "set expiration for instance", slog.String("instance", name), slog.Duration("expiration", effectiveDuration))
@@ -271,8 +313,9 @@ func (s *Sablier) instanceRequest(ctx context.Context, name string, duration tim
 	err = s.sessions.Put(ctx, state, effectiveDuration)
 	if err != nil {
 		s.l.ErrorContext(ctx, "could not put instance to store, will not expire", slog.Any("error", err), slog.String("instance", state.Name))
-		return InstanceInfo{}, fmt.Errorf("could not put instance to store: %w", err)
+		return InstanceInfo{}, 0, fmt.Errorf("could not put instance to store: %w", err)
 	}
+	s.touchCapacity(name)
 
 	// A brand-new session may have made this instance's group(s) active; force any
 	// instance that declared an anti-affinity against them to back off.
@@ -280,5 +323,5 @@ func (s *Sablier) instanceRequest(ctx context.Context, name string, duration tim
 		s.triggerAntiAffinityReconcile(ctx)
 	}
 
-	return state, nil
+	return state, effectiveDuration, nil
 }
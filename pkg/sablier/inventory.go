@@ -0,0 +1,82 @@
+package sablier
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/provider"
+)
+
+// InstanceSession is an active session: the instance info stored with it and
+// the absolute time at which it expires. PinnedUntil is set while the
+// instance is pinned (see PinInstance).
+type InstanceSession struct {
+	Instance    InstanceInfo
+	ExpiresAt   time.Time
+	PinnedUntil *time.Time
+}
+
+// ListInstances inspects every instance the provider reports as managed by
+// Sablier (sablier.enable=true), running or not, sorted by name. An instance
+// that cannot be inspected is returned with its error instead of failing the
+// whole listing.
+func (s *Sablier) ListInstances(ctx context.Context) ([]InstanceInfoWithError, error) {
+	list, err := s.provider.InstanceList(ctx, provider.InstanceListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]InstanceInfoWithError, 0, len(list))
+	for _, c := range list {
+		info, err := s.provider.InstanceInspect(ctx, c.Name)
+		if err != nil {
+			info = InstanceInfo{Name: c.Name, Groups: c.Groups, Enabled: c.Enabled}
+		} else {
+			s.withNextWindow(ctx, &info, time.Now())
+		}
+		instances = append(instances, InstanceInfoWithError{Instance: info, Error: err})
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Instance.Name < instances[j].Instance.Name
+	})
+	return instances, nil
+}
+
+// InspectInstance returns the current state of a managed instance. It returns
+// ErrInstanceNotManaged when the instance does not exist or does not carry
+// sablier.enable=true, so unmanaged workloads are not exposed.
+func (s *Sablier) InspectInstance(ctx context.Context, name string) (InstanceInfo, error) {
+	info, err := s.provider.InstanceInspect(ctx, name)
+	if err != nil {
+		// Providers report unknown instances with their own error types. Tell
+		// "not found" apart from a provider failure by checking the listing.
+		list, listErr := s.provider.InstanceList(ctx, provider.InstanceListOptions{All: true})
+		if listErr == nil && !slices.ContainsFunc(list, func(c InstanceConfiguration) bool { return c.Name == name }) {
+			return InstanceInfo{}, ErrInstanceNotManaged{Name: name}
+		}
+		return InstanceInfo{}, err
+	}
+	if !info.IsEnabled() {
+		return InstanceInfo{}, ErrInstanceNotManaged{Name: name}
+	}
+	s.withNextWindow(ctx, &info, time.Now())
+	return info, nil
+}
+
+// ListSessions returns every active session, sorted by instance name. Like
+// SessionsSnapshot it enumerates the store without renewing any session.
+func (s *Sablier) ListSessions(ctx context.Context) ([]InstanceSession, error) {
+	var sessions []InstanceSession
+	err := s.sessions.Range(ctx, func(info InstanceInfo, expiresAt time.Time) {
+		sessions = append(sessions, InstanceSession{Instance: info, ExpiresAt: expiresAt, PinnedUntil: s.pinnedUntil(info.Name)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Instance.Name < sessions[j].Instance.Name
+	})
+	return sessions, nil
+}
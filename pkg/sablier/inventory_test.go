@@ -0,0 +1,123 @@
+package sablier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSablier_ListInstances(t *testing.T) {
+	ctx := context.Background()
+	s, _, p := setupSablier(t)
+
+	p.EXPECT().InstanceList(ctx, provider.InstanceListOptions{All: true}).Return([]sablier.InstanceConfiguration{
+		{Name: "whoami", Groups: []string{"default"}, Enabled: "true"},
+		{Name: "broken", Groups: []string{"default"}, Enabled: "true"},
+	}, nil)
+	p.EXPECT().InstanceInspect(ctx, "whoami").Return(sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady, Enabled: "true"}, nil)
+	p.EXPECT().InstanceInspect(ctx, "broken").Return(sablier.InstanceInfo{}, errors.New("inspect failed"))
+
+	instances, err := s.ListInstances(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(instances, 2))
+
+	// Sorted by name; the failing instance keeps its listed configuration.
+	assert.Equal(t, instances[0].Instance.Name, "broken")
+	assert.ErrorContains(t, instances[0].Error, "inspect failed")
+	assert.DeepEqual(t, instances[0].Instance.Groups, []string{"default"})
+	assert.Equal(t, instances[1].Instance.Name, "whoami")
+	assert.NilError(t, instances[1].Error)
+	assert.Equal(t, instances[1].Instance.Status, sablier.InstanceStatusReady)
+}
+
+func TestSablier_ListInstances_ListError(t *testing.T) {
+	ctx := context.Background()
+	s, _, p := setupSablier(t)
+
+	p.EXPECT().InstanceList(ctx, gomock.Any()).Return(nil, errors.New("provider down"))
+
+	_, err := s.ListInstances(ctx)
+	assert.ErrorContains(t, err, "provider down")
+}
+
+func TestSablier_InspectInstance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("managed", func(t *testing.T) {
+		s, _, p := setupSablier(t)
+		info := sablier.InstanceInfo{
+			Name:   "whoami",
+			Status: sablier.InstanceStatusReady,
+			Config: &sablier.InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		}
+		p.EXPECT().InstanceInspect(ctx, "whoami").Return(info, nil)
+
+		got, err := s.InspectInstance(ctx, "whoami")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, info)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		s, _, p := setupSablier(t)
+		p.EXPECT().InstanceInspect(ctx, "db").Return(sablier.InstanceInfo{Name: "db", Status: sablier.InstanceStatusReady}, nil)
+
+		_, err := s.InspectInstance(ctx, "db")
+		_, ok := errors.AsType[sablier.ErrInstanceNotManaged](err)
+		assert.Assert(t, ok, "expected ErrInstanceNotManaged, got %v", err)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		s, _, p := setupSablier(t)
+		p.EXPECT().InstanceInspect(ctx, "ghost").Return(sablier.InstanceInfo{}, errors.New("no such container"))
+		p.EXPECT().InstanceList(ctx, provider.InstanceListOptions{All: true}).Return(nil, nil)
+
+		_, err := s.InspectInstance(ctx, "ghost")
+		_, ok := errors.AsType[sablier.ErrInstanceNotManaged](err)
+		assert.Assert(t, ok, "expected ErrInstanceNotManaged, got %v", err)
+	})
+
+	t.Run("provider failure", func(t *testing.T) {
+		s, _, p := setupSablier(t)
+		p.EXPECT().InstanceInspect(ctx, "whoami").Return(sablier.InstanceInfo{}, errors.New("timeout"))
+		p.EXPECT().InstanceList(ctx, gomock.Any()).Return([]sablier.InstanceConfiguration{{Name: "whoami", Enabled: "true"}}, nil)
+
+		_, err := s.InspectInstance(ctx, "whoami")
+		assert.ErrorContains(t, err, "timeout")
+	})
+}
+
+func TestSablier_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	s, store, _ := setupSablier(t)
+
+	now := time.Now()
+	store.EXPECT().Range(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+		f(sablier.InstanceInfo{Name: "whoami"}, now.Add(time.Minute))
+		f(sablier.InstanceInfo{Name: "nginx"}, now.Add(2*time.Minute))
+		return nil
+	})
+
+	sessions, err := s.ListSessions(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sessions, []sablier.InstanceSession{
+		{Instance: sablier.InstanceInfo{Name: "nginx"}, ExpiresAt: now.Add(2 * time.Minute)},
+		{Instance: sablier.InstanceInfo{Name: "whoami"}, ExpiresAt: now.Add(time.Minute)},
+	})
+}
+
+func TestSablier_ListSessions_RangeError(t *testing.T) {
+	ctx := context.Background()
+	s, store, _ := setupSablier(t)
+
+	store.EXPECT().Range(ctx, gomock.Any()).Return(errors.New("store down"))
+
+	_, err := s.ListSessions(ctx)
+	assert.ErrorContains(t, err, "store down")
+}
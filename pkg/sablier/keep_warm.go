@@ -0,0 +1,84 @@
+package sablier
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// The rules that open a keep-warm window, or keep it closed.
+const (
+	warmRuleRunningHours = "running-hours"
+	warmRuleRunningDays  = "running-days"
+	warmRuleSchedule     = "schedule"
+	warmRuleHoliday      = "holiday"
+)
+
+// locations caches the time zones of the sablier.timezone labels, which are
+// loaded from the time zone database on every keep-warm evaluation otherwise.
+var locations sync.Map
+
+// instanceLocation returns the time zone of the sablier.timezone label of
+// instance, or the local time zone.
+func instanceLocation(instance InstanceInfo) *time.Location {
+	if instance.Config == nil || instance.Config.Timezone == "" {
+		return time.Local
+	}
+	if loc, ok := locations.Load(instance.Config.Timezone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(instance.Config.Timezone)
+	if err != nil {
+		// The label is validated when parsed: only a time zone database
+		// missing at runtime gets here.
+		return time.Local
+	}
+	locations.Store(instance.Config.Timezone, loc)
+	return loc
+}
+
+// withNextWindow sets the NextWindow of info from its sablier.schedule label,
+// at now in the time zone of the instance, skipping the holidays.
+func (s *Sablier) withNextWindow(ctx context.Context, info *InstanceInfo, now time.Time) {
+	info.NextWindow = nil
+	if info.Config == nil || info.Config.Schedule == "" {
+		return
+	}
+	schedule, err := ParseSchedule(info.Config.Schedule)
+	if err != nil {
+		return
+	}
+	if window, ok := schedule.Next(now.In(instanceLocation(*info)), s.holidays.current(ctx)); ok {
+		info.NextWindow = &window
+	}
+}
+
+// keepWarm evaluates the running-hours and schedule windows of instance at
+// now, in the time zone of the instance. It returns how long the instance
+// must be kept warm, zero when no window is open, and the rule that opened
+// the window or, when none is open, the rule that kept it closed.
+func (s *Sablier) keepWarm(ctx context.Context, instance InstanceInfo, now time.Time) (time.Duration, string) {
+	now = now.In(instanceLocation(instance))
+	holidays := s.holidays.current(ctx)
+
+	var remaining time.Duration
+	var rule string
+	if instance.RunningHours != "" {
+		windowRemaining, windowRule, err := runningHoursRemaining(instance.RunningHours, instance.RunningDays, now, holidays)
+		if err != nil {
+			s.l.WarnContext(ctx, "invalid running-hours or running-days value, ignoring", slog.String("instance", instance.Name), slog.String("running-hours", instance.RunningHours), slog.String("running-days", instance.RunningDays), slog.Any("error", err))
+		} else {
+			remaining, rule = windowRemaining, windowRule
+		}
+	}
+	if instance.Config != nil && instance.Config.Schedule != "" {
+		windowRemaining, windowRule, err := scheduleRemaining(instance.Config.Schedule, now, holidays)
+		if err != nil {
+			s.l.WarnContext(ctx, "invalid schedule value, ignoring", slog.String("instance", instance.Name), slog.String("schedule", instance.Config.Schedule), slog.Any("error", err))
+		} else if windowRemaining > remaining || (remaining == 0 && windowRule != "") {
+			remaining, rule = windowRemaining, windowRule
+		}
+	}
+	return remaining, rule
+}
@@ -0,0 +1,141 @@
+package sablier
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"gotest.tools/v3/assert"
+)
+
+func TestKeepWarm(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NilError(t, err)
+	// 2026-01-05 is a Monday. 08:30 UTC is 09:30 in Paris.
+	monday := time.Date(2026, time.January, 5, 8, 30, 0, 0, time.UTC)
+	newYear := time.Date(2026, time.January, 1, 8, 30, 0, 0, time.UTC)
+
+	holidays := &Holidays{dates: map[string]struct{}{"2026-01-01": {}}, yearly: map[string]struct{}{}}
+	instance := func(cfg InstanceConfig, hours, days string) InstanceInfo {
+		return InstanceInfo{Name: "web", RunningHours: hours, RunningDays: days, Config: &cfg}
+	}
+
+	tests := []struct {
+		name          string
+		instance      InstanceInfo
+		now           time.Time
+		wantRemaining time.Duration
+		wantRule      string
+	}{
+		{name: "no window", instance: instance(InstanceConfig{}, "", ""), now: monday},
+		{name: "running hours in UTC", instance: instance(InstanceConfig{Timezone: "UTC"}, "08:00-09:00", ""), now: monday, wantRemaining: 30 * time.Minute, wantRule: warmRuleRunningHours},
+		{name: "running hours in the instance time zone", instance: instance(InstanceConfig{Timezone: "Europe/Paris"}, "09:00-10:00", ""), now: monday, wantRemaining: 30 * time.Minute, wantRule: warmRuleRunningHours},
+		{name: "outside the window in the instance time zone", instance: instance(InstanceConfig{Timezone: "Europe/Paris"}, "08:00-09:00", ""), now: monday},
+		{name: "suppressed by running days", instance: instance(InstanceConfig{Timezone: "UTC"}, "08:00-09:00", "Sat,Sun"), now: monday, wantRule: warmRuleRunningDays},
+		{name: "suppressed by a holiday", instance: instance(InstanceConfig{Timezone: "UTC"}, "08:00-09:00", ""), now: newYear, wantRule: warmRuleHoliday},
+		{name: "schedule", instance: instance(InstanceConfig{Timezone: "Europe/Paris", Schedule: "0 9 * * mon 2h"}, "", ""), now: monday, wantRemaining: 90 * time.Minute, wantRule: warmRuleSchedule},
+		{name: "schedule suppressed by a holiday", instance: instance(InstanceConfig{Timezone: "UTC", Schedule: "0 8 * * * 2h"}, "", ""), now: newYear, wantRule: warmRuleHoliday},
+		{name: "a window opened before the holiday stays open", instance: instance(InstanceConfig{Timezone: "UTC", Schedule: "0 23 * * * 2h; 0 0 * * * 3h"}, "", ""), now: newYear.Add(-8 * time.Hour), wantRemaining: 30 * time.Minute, wantRule: warmRuleSchedule},
+		{name: "the window ending last wins", instance: instance(InstanceConfig{Timezone: "UTC", Schedule: "0 8 * * * 2h"}, "08:00-09:00", ""), now: monday, wantRemaining: 90 * time.Minute, wantRule: warmRuleSchedule},
+		{name: "an open window wins over a suppressed one", instance: instance(InstanceConfig{Timezone: "UTC", Schedule: "0 8 * * * 2h"}, "08:00-09:00", "Sat,Sun"), now: monday, wantRemaining: 90 * time.Minute, wantRule: warmRuleSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(slogt.New(t), nil, nil)
+			s.holidays = &holidayCalendar{holidays: holidays, checked: time.Now().Add(time.Hour)}
+			remaining, rule := s.keepWarm(t.Context(), tt.instance, tt.now)
+			assert.Equal(t, remaining, tt.wantRemaining)
+			assert.Equal(t, rule, tt.wantRule)
+		})
+	}
+	assert.Equal(t, instanceLocation(instance(InstanceConfig{Timezone: "Europe/Paris"}, "", "")).String(), paris.String())
+	assert.Equal(t, instanceLocation(InstanceInfo{}), time.Local)
+}
+
+func TestWithNextWindow(t *testing.T) {
+	s := New(slogt.New(t), nil, nil)
+	s.holidays = &holidayCalendar{
+		holidays: &Holidays{dates: map[string]struct{}{"2026-01-01": {}}, yearly: map[string]struct{}{}},
+		checked:  time.Now().Add(time.Hour),
+	}
+	info := InstanceInfo{Name: "web", Config: &InstanceConfig{Timezone: "Europe/Paris", Schedule: "0 2 * * * 2h"}}
+
+	// 2025-12-31 12:00 UTC: the window of January 1st is skipped.
+	s.withNextWindow(t.Context(), &info, time.Date(2025, time.December, 31, 12, 0, 0, 0, time.UTC))
+	assert.Assert(t, info.NextWindow != nil)
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NilError(t, err)
+	assert.Assert(t, info.NextWindow.Start.Equal(time.Date(2026, time.January, 2, 2, 0, 0, 0, paris)))
+	assert.Equal(t, info.NextWindow.End.Sub(info.NextWindow.Start), 2*time.Hour)
+
+	none := InstanceInfo{Name: "web", Config: &InstanceConfig{}, NextWindow: info.NextWindow}
+	s.withNextWindow(t.Context(), &none, time.Now())
+	assert.Assert(t, none.NextWindow == nil)
+}
+
+func TestReconcileRunningHours_LogsTransitionsAtInfo(t *testing.T) {
+	var logs bytes.Buffer
+	now := time.Now().UTC()
+	hours := fmt.Sprintf("%s-%s", now.Add(-2*time.Minute).Format("15:04"), now.Add(5*time.Minute).Format("15:04"))
+	info := InstanceInfo{Name: "web", Enabled: "true", RunningHours: hours, Config: &InstanceConfig{Timezone: "UTC"}}
+	p := &fakeAAProvider{
+		list:    []InstanceConfiguration{{Name: "web", Enabled: "true"}},
+		inspect: map[string]InstanceInfo{"web": info},
+	}
+	s := New(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo})), newFakeAAStore(), p)
+	// The window may open the day before when it spans midnight.
+	dates := map[string]struct{}{now.Format(holidayDate): {}, now.AddDate(0, 0, -1).Format(holidayDate): {}}
+	s.holidays = &holidayCalendar{
+		holidays: &Holidays{dates: dates, yearly: map[string]struct{}{}},
+		checked:  time.Now().Add(time.Hour),
+	}
+
+	rules := make(map[string]string)
+	s.reconcileRunningHours(t.Context(), rules)
+	s.reconcileRunningHours(t.Context(), rules)
+
+	assert.Equal(t, strings.Count(logs.String(), "keep-warm window suppressed"), 1, logs.String())
+	assert.Assert(t, strings.Contains(logs.String(), "rule=holiday"), logs.String())
+}
+
+func TestWithHolidays_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	write := func(date string, mod time.Time) {
+		t.Helper()
+		assert.NilError(t, os.WriteFile(path, []byte("BEGIN:VEVENT\nDTSTART;VALUE=DATE:"+date+"\nEND:VEVENT\n"), 0o600))
+		assert.NilError(t, os.Chtimes(path, mod, mod))
+	}
+	christmas := time.Date(2026, time.December, 25, 12, 0, 0, 0, time.UTC)
+	newYear := time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	write("20261225", time.Now().Add(-time.Hour))
+	s := New(slogt.New(t), nil, nil)
+	assert.NilError(t, s.WithHolidays(path))
+	assert.Assert(t, s.holidays.current(t.Context()).Contains(christmas))
+
+	write("20270101", time.Now())
+	// Changes are picked up on the next check.
+	s.holidays.checked = time.Time{}
+	holidays := s.holidays.current(t.Context())
+	assert.Assert(t, !holidays.Contains(christmas))
+	assert.Assert(t, holidays.Contains(newYear))
+
+	// A broken file keeps the previous holidays.
+	assert.NilError(t, os.WriteFile(path, []byte("BEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\n"), 0o600))
+	assert.NilError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	s.holidays.checked = time.Time{}
+	assert.Assert(t, s.holidays.current(t.Context()).Contains(newYear))
+}
+
+func TestWithHolidays_InvalidFile(t *testing.T) {
+	s := New(slogt.New(t), nil, nil)
+	err := s.WithHolidays(filepath.Join(t.TempDir(), "missing.ics"))
+	assert.ErrorContains(t, err, "reading holidays file")
+	assert.Assert(t, s.holidays == nil)
+}
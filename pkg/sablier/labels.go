@@ -73,6 +73,71 @@ const (
 	// since: NEXT_RELEASE
 	LabelRunningDays = "sablier.running-days"
 
+	// LabelSchedule keeps the instance warm during windows that open at the
+	// times of a cron expression. Separate windows with `;`. Cron expressions
+	// are evaluated in local time.
+	//
+	// type: `;`-separated `<minute> <hour> <day-of-month> <month> <day-of-week> <duration>`
+	// example: "30 8 * * mon-fri 30m; 0 2 * * sun 2h"
+	// feature: /how-to-guides/lifecycle/schedule/
+	// providers: Kubernetes must set the value as an **annotation**.
+	// since: NEXT_RELEASE
+	LabelSchedule = "sablier.schedule"
+
+	// LabelTimezone is the time zone the `sablier.running-hours`,
+	// `sablier.running-days` and `sablier.schedule` windows are evaluated in.
+	//
+	// type: IANA time zone name
+	// default: the time zone of the Sablier process (`TZ`)
+	// example: "Europe/Paris"
+	// feature: /how-to-guides/lifecycle/running-hours/#timezone
+	// providers: Kubernetes must set a value with `/` as an **annotation**.
+	// since: NEXT_RELEASE
+	LabelTimezone = "sablier.timezone"
+
+	// LabelSessionDuration is the session duration of the instance when the
+	// request does not ask for one. Without it, requests without a duration
+	// get `sessions.default-duration`.
+	//
+	// type: Go duration
+	// default: `sessions.default-duration`
+	// example: "30m"
+	// feature: /how-to-guides/lifecycle/session-duration/
+	// since: NEXT_RELEASE
+	LabelSessionDuration = "sablier.session-duration"
+
+	// LabelSessionDurationMax caps the session duration of the instance,
+	// whatever duration the request asks for.
+	//
+	// type: Go duration
+	// example: "2h"
+	// feature: /how-to-guides/lifecycle/session-duration/
+	// since: NEXT_RELEASE
+	LabelSessionDurationMax = "sablier.session-duration-max"
+
+	// LabelSessionMode decides whether the requests of a session renew it. In
+	// `fixed` mode, the first request sets an absolute deadline that later
+	// requests do not extend, whatever mode they ask for.
+	//
+	// type: `sliding` or `fixed`
+	// default: "sliding"
+	// example: "fixed"
+	// feature: /how-to-guides/lifecycle/fixed-deadline/
+	// since: NEXT_RELEASE
+	LabelSessionMode = "sablier.session-mode"
+
+	// LabelIdleActivity keeps the session alive while the instance has traffic,
+	// even when no request renews it, e.g. for a long-lived WebSocket or an API
+	// used without the reverse proxy. The only source is `network`, the network
+	// byte and packet counters of the instance.
+	//
+	// type: `network`
+	// example: "network"
+	// feature: /how-to-guides/lifecycle/traffic-activity/
+	// providers: Docker, Podman and Kubernetes only.
+	// since: NEXT_RELEASE
+	LabelIdleActivity = "sablier.idle.activity"
+
 	// LabelAntiAffinity lists the group names this instance backs off from; it is
 	// forced idle while any listed group has an active session.
 	//
@@ -0,0 +1,247 @@
+package sablier
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// InstanceSelector selects instances by name or by labels. An instance is
+// selected when it matches every criterion set.
+type InstanceSelector struct {
+	// Names are glob patterns (path.Match syntax) one of which the name of the
+	// instance must match.
+	Names []string
+	// Regex must match the name of the instance.
+	Regex *regexp.Regexp
+	// Labels must all be set on the instance: with the given value, or with
+	// any value when the value is empty.
+	Labels map[string]string
+}
+
+// ParseInstanceSelector builds a selector from name globs, a name regular
+// expression and a label selector. The label selector is a comma-separated
+// list of key=value requirements, or bare keys that must only be set, for
+// example "team=ci,tier".
+func ParseInstanceSelector(names []string, regex string, labels string) (InstanceSelector, error) {
+	sel := InstanceSelector{Names: names}
+	for _, pattern := range names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return InstanceSelector{}, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+	}
+	if regex != "" {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return InstanceSelector{}, fmt.Errorf("invalid regex %q: %w", regex, err)
+		}
+		sel.Regex = re
+	}
+	for _, requirement := range strings.Split(labels, ",") {
+		requirement = strings.TrimSpace(requirement)
+		if requirement == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(requirement, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return InstanceSelector{}, fmt.Errorf("invalid label selector %q: empty key", labels)
+		}
+		if sel.Labels == nil {
+			sel.Labels = map[string]string{}
+		}
+		sel.Labels[key] = strings.TrimSpace(value)
+	}
+	if len(sel.Names) == 0 && sel.Regex == nil && len(sel.Labels) == 0 {
+		return InstanceSelector{}, fmt.Errorf("a selector needs names, a regex or labels")
+	}
+	return sel, nil
+}
+
+// Matches reports whether the instance name with labels is selected.
+func (sel InstanceSelector) Matches(name string, labels map[string]string) bool {
+	if len(sel.Names) > 0 && !slices.ContainsFunc(sel.Names, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}) {
+		return false
+	}
+	if sel.Regex != nil && !sel.Regex.MatchString(name) {
+		return false
+	}
+	for key, want := range sel.Labels {
+		got, ok := labels[key]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupPolicy adds the instances it selects to a group.
+type GroupPolicy struct {
+	Group    string
+	Selector InstanceSelector
+}
+
+// Policy applies settings to the instances it selects.
+type Policy struct {
+	// Name identifies the policy in logs.
+	Name     string
+	Selector InstanceSelector
+	// Groups the selected instances are added to.
+	Groups []string
+	// Labels are the sablier.* labels the policy sets on the selected
+	// instances. The labels of an instance win over them.
+	Labels map[string]string
+	// DependsOn are dependencies added to the ones the provider reports.
+	DependsOn []InstanceDependency
+}
+
+// Policies are the groups and policies of the configuration file. They apply
+// to every enabled instance, on top of its labels:
+//
+//   - A label set on the instance wins over the same label set by a policy,
+//     and a later policy wins over an earlier one.
+//   - The groups of the policies and group entries selecting the instance are
+//     added to the groups of its sablier.group label. The instance is in the
+//     default group only when it ends up in no group at all.
+//   - The dependencies of the policies are added to the ones the provider
+//     reports.
+type Policies struct {
+	Groups   []GroupPolicy
+	Policies []Policy
+}
+
+// PolicySet holds the Policies in effect. Sablier swaps them when the
+// configuration file is reloaded, and providers read them when they parse the
+// labels of their instances. A nil PolicySet holds no policies.
+type PolicySet struct {
+	current atomic.Pointer[Policies]
+}
+
+// NewPolicySet returns a PolicySet holding p.
+func NewPolicySet(p *Policies) *PolicySet {
+	ps := &PolicySet{}
+	ps.Store(p)
+	return ps
+}
+
+// Load returns the policies in effect, nil when there are none.
+func (ps *PolicySet) Load() *Policies {
+	if ps == nil {
+		return nil
+	}
+	return ps.current.Load()
+}
+
+// Store replaces the policies in effect. A nil p removes them. Instances pick
+// them up the next time they are inspected.
+func (ps *PolicySet) Store(p *Policies) {
+	ps.current.Store(p)
+}
+
+// resolve returns the labels of the instance with the labels of the policies
+// selecting it merged in, the groups assigned to it by the configuration and
+// its extra dependencies. A nil Policies returns labels unchanged.
+func (p *Policies) resolve(name string, labels map[string]string) (map[string]string, []string, []InstanceDependency) {
+	if p == nil {
+		return labels, nil, nil
+	}
+
+	var merged map[string]string
+	var groups []string
+	var deps []InstanceDependency
+	for _, g := range p.Groups {
+		if g.Selector.Matches(name, labels) {
+			groups = appendUnique(groups, g.Group)
+		}
+	}
+	for _, policy := range p.Policies {
+		if !policy.Selector.Matches(name, labels) {
+			continue
+		}
+		for _, g := range policy.Groups {
+			groups = appendUnique(groups, g)
+		}
+		if len(policy.Labels) > 0 {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			for k, v := range policy.Labels {
+				merged[k] = v
+			}
+		}
+		for _, dep := range policy.DependsOn {
+			if !slices.ContainsFunc(deps, func(d InstanceDependency) bool { return d.Name == dep.Name }) {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	if merged == nil {
+		return labels, groups, deps
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged, groups, deps
+}
+
+// hasDependencies reports whether any policy adds dependencies.
+func (p *Policies) hasDependencies() bool {
+	if p == nil {
+		return false
+	}
+	return slices.ContainsFunc(p.Policies, func(policy Policy) bool { return len(policy.DependsOn) > 0 })
+}
+
+// GroupsFromLabels returns the groups of the enabled instance name with
+// labels: the groups of its sablier.group label and the groups the policies
+// assign to it, or the default group when there are none.
+func (ps *PolicySet) GroupsFromLabels(name string, labels map[string]string) []string {
+	labels, extra, _ := ps.Load().resolve(name, labels)
+	return mergeGroups(labels[LabelGroup], extra)
+}
+
+// GroupsFromLabels returns the groups of the sablier.group label of an
+// enabled instance, or the default group when it has none. No policies
+// apply: see PolicySet.GroupsFromLabels.
+func GroupsFromLabels(labels map[string]string) []string {
+	return (*PolicySet)(nil).GroupsFromLabels("", labels)
+}
+
+// mergeGroups adds the groups assigned by the policies to the groups of the
+// sablier.group label value. The default group only applies when neither
+// assigns any.
+func mergeGroups(label string, extra []string) []string {
+	if strings.TrimSpace(label) == "" && len(extra) > 0 {
+		return slices.Clone(extra)
+	}
+	groups := ParseGroups(label)
+	for _, g := range extra {
+		groups = appendUnique(groups, g)
+	}
+	return groups
+}
+
+func appendUnique(s []string, v string) []string {
+	if slices.Contains(s, v) {
+		return s
+	}
+	return append(s, v)
+}
+
+// ReloadPolicies puts p in effect, then refreshes what was derived from the
+// previous policies: the inspections the provider cached and the group
+// registry.
+func (s *Sablier) ReloadPolicies(ctx context.Context, p *Policies) {
+	s.policies.Store(p)
+	if c, ok := s.provider.(InspectCache); ok {
+		c.FlushInspections()
+	}
+	s.reconcileGroups(ctx, "config reload")
+}
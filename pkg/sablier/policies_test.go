@@ -0,0 +1,163 @@
+package sablier
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func mustSelector(t *testing.T, names []string, regex string, labels string) InstanceSelector {
+	t.Helper()
+	sel, err := ParseInstanceSelector(names, regex, labels)
+	assert.NilError(t, err)
+	return sel
+}
+
+func TestParseInstanceSelector_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		regex   string
+		labels  string
+		wantErr string
+	}{
+		{name: "empty", wantErr: "a selector needs names, a regex or labels"},
+		{name: "blank labels", labels: " , ", wantErr: "a selector needs names, a regex or labels"},
+		{name: "invalid glob", names: []string{"web-["}, wantErr: `invalid name pattern "web-["`},
+		{name: "invalid regex", regex: "(", wantErr: `invalid regex "("`},
+		{name: "empty label key", labels: "=ci", wantErr: "empty key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInstanceSelector(tt.names, tt.regex, tt.labels)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestInstanceSelector_Matches(t *testing.T) {
+	labels := map[string]string{"team": "ci", "tier": "backend"}
+	tests := []struct {
+		name   string
+		sel    InstanceSelector
+		target string
+		want   bool
+	}{
+		{name: "glob", sel: mustSelector(t, []string{"preview-*"}, "", ""), target: "preview-42", want: true},
+		{name: "glob mismatch", sel: mustSelector(t, []string{"preview-*"}, "", ""), target: "web", want: false},
+		{name: "any glob", sel: mustSelector(t, []string{"web", "api-?"}, "", ""), target: "api-1", want: true},
+		{name: "regex", sel: mustSelector(t, nil, "^ci-[0-9]+$", ""), target: "ci-12", want: true},
+		{name: "regex mismatch", sel: mustSelector(t, nil, "^ci-[0-9]+$", ""), target: "ci-main", want: false},
+		{name: "label value", sel: mustSelector(t, nil, "", "team=ci"), target: "web", want: true},
+		{name: "label value mismatch", sel: mustSelector(t, nil, "", "team=ops"), target: "web", want: false},
+		{name: "label set", sel: mustSelector(t, nil, "", "tier"), target: "web", want: true},
+		{name: "label not set", sel: mustSelector(t, nil, "", "zone"), target: "web", want: false},
+		{name: "every criterion", sel: mustSelector(t, []string{"web*"}, "^web$", "team=ci, tier"), target: "web", want: true},
+		{name: "one criterion fails", sel: mustSelector(t, []string{"web*"}, "^webapp$", "team=ci"), target: "web", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.sel.Matches(tt.target, labels), tt.want)
+		})
+	}
+}
+
+func TestInstanceConfigFromLabels_Policies(t *testing.T) {
+	ps := NewPolicySet(&Policies{
+		Groups: []GroupPolicy{
+			{Group: "previews", Selector: mustSelector(t, []string{"preview-*"}, "", "")},
+		},
+		Policies: []Policy{
+			{
+				Name:     "ci",
+				Selector: mustSelector(t, nil, "", "team=ci"),
+				Groups:   []string{"ci"},
+				Labels: map[string]string{
+					LabelSessionDuration: "30m",
+					LabelRunningHours:    "08:00-18:00",
+					LabelIdleReplicas:    "1",
+				},
+				DependsOn: []InstanceDependency{{Name: "db", Condition: "service_healthy"}},
+			},
+			{
+				Name:     "short sessions",
+				Selector: mustSelector(t, []string{"preview-*"}, "", ""),
+				Labels:   map[string]string{LabelSessionDuration: "5m"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		labels map[string]string
+		want   InstanceConfig
+	}{
+		{
+			name:   "not selected",
+			target: "web",
+			labels: map[string]string{LabelEnable: "true"},
+			want:   InstanceConfig{Enabled: true, Groups: []string{"default"}},
+		},
+		{
+			name:   "disabled instances are left alone",
+			target: "preview-1",
+			labels: map[string]string{"team": "ci"},
+			want:   InstanceConfig{},
+		},
+		{
+			name:   "policy settings and groups replace the default group",
+			target: "web",
+			labels: map[string]string{LabelEnable: "true", "team": "ci"},
+			want: InstanceConfig{
+				Enabled:         true,
+				Groups:          []string{"ci"},
+				DependsOn:       []InstanceDependency{{Name: "db", Condition: "service_healthy"}},
+				SessionDuration: 30 * time.Minute,
+				RunningHours:    "08:00-18:00",
+				Scale:           &ScaleConfig{Idle: ResourceProfile{Replicas: 1}, Active: ResourceProfile{Replicas: 1}},
+			},
+		},
+		{
+			name:   "instance labels win and groups add up",
+			target: "web",
+			labels: map[string]string{LabelEnable: "true", "team": "ci", LabelGroup: "web", LabelSessionDuration: "1h"},
+			want: InstanceConfig{
+				Enabled:         true,
+				Groups:          []string{"web", "ci"},
+				DependsOn:       []InstanceDependency{{Name: "db", Condition: "service_healthy"}},
+				SessionDuration: time.Hour,
+				RunningHours:    "08:00-18:00",
+				Scale:           &ScaleConfig{Idle: ResourceProfile{Replicas: 1}, Active: ResourceProfile{Replicas: 1}},
+			},
+		},
+		{
+			name:   "later policies win",
+			target: "preview-1",
+			labels: map[string]string{LabelEnable: "true", "team": "ci"},
+			want: InstanceConfig{
+				Enabled:         true,
+				Groups:          []string{"previews", "ci"},
+				DependsOn:       []InstanceDependency{{Name: "db", Condition: "service_healthy"}},
+				SessionDuration: 5 * time.Minute,
+				RunningHours:    "08:00-18:00",
+				Scale:           &ScaleConfig{Idle: ResourceProfile{Replicas: 1}, Active: ResourceProfile{Replicas: 1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ps.InstanceConfigFromLabels(tt.target, tt.labels, nil)
+			assert.DeepEqual(t, tt.want, got)
+			if got.Enabled {
+				assert.DeepEqual(t, tt.want.Groups, ps.GroupsFromLabels(tt.target, tt.labels))
+			}
+		})
+	}
+}
+
+func TestGroupsFromLabels_WithoutPolicies(t *testing.T) {
+	assert.DeepEqual(t, []string{"default"}, GroupsFromLabels(map[string]string{LabelEnable: "true"}))
+	assert.DeepEqual(t, []string{"a", "b"}, GroupsFromLabels(map[string]string{LabelGroup: "a, b,a"}))
+}
@@ -47,3 +47,47 @@ type Provider interface {
 
 	InstanceEvents(ctx context.Context, opts provider.InstanceEventsOptions) InstanceEventStream
 }
+
+// UnreliableEvents is implemented by providers whose InstanceEvents stream can
+// miss instance changes, e.g. because it is derived from polling and only
+// reports some transitions. Consumers relying on events to invalidate what they
+// know about an instance must then bound its staleness themselves.
+type UnreliableEvents interface {
+	UnreliableEvents() bool
+}
+
+// InstanceInspectMany is implemented by providers that can inspect several
+// instances in one call, e.g. with a single filtered list. Sablier uses it for
+// the session and group requests and falls back to InstanceInspect for the
+// instances missing from the result, so a provider only answers for the
+// instances it can fully describe from that call. A returned error makes
+// Sablier inspect every instance one by one.
+type InstanceInspectMany interface {
+	InstanceInspectMany(ctx context.Context, names []string) (map[string]InstanceInfo, error)
+}
+
+// NetworkActivity is the cumulative network traffic of an instance. Sablier
+// only compares two samples of the same instance: any difference is traffic.
+type NetworkActivity struct {
+	RxBytes   uint64
+	TxBytes   uint64
+	RxPackets uint64
+	TxPackets uint64
+}
+
+// InstanceActivity is implemented by providers that can report the network
+// traffic of an instance. Sablier uses it to keep alive the sessions of the
+// instances labelled sablier.idle.activity=network while traffic flows. A
+// provider wrapping another one returns ErrActivityUnsupported when the
+// wrapped provider does not implement it, and exposes it with an
+// Unwrap() Provider method.
+type InstanceActivity interface {
+	InstanceNetworkActivity(ctx context.Context, name string) (NetworkActivity, error)
+}
+
+// InspectCache is implemented by providers that cache instance inspections.
+// An inspection carries the configuration of the instance, which depends on
+// the configured policies: Sablier flushes the cache when they change.
+type InspectCache interface {
+	FlushInspections()
+}
@@ -0,0 +1,257 @@
+package sablier
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/sablierapp/sablier/pkg/provider"
+)
+
+// readinessHub watches the instances blocking requests are waiting on, once
+// per instance however many requests wait on it. It follows the provider
+// events and polls every watched instance once per BlockingRefreshFrequency,
+// with a single call for providers implementing InstanceInspectMany, for the
+// readiness changes that are not reported as events. Waiting requests
+// are only woken when the observed state of one of their instances changes.
+type readinessHub struct {
+	s *Sablier
+
+	// inspects single-flights provider inspections, so requests woken by the
+	// same change share one InstanceInspect call per instance.
+	inspects singleflight.Group
+
+	mu sync.Mutex
+	// watched counts the waiting requests per instance.
+	watched map[string]int
+	// observed is the last known state of each watched instance.
+	observed map[string]observation
+	waiters  map[*readinessWaiter]struct{}
+	// stop ends the watch loop, which only runs while an instance is watched.
+	stop context.CancelFunc
+}
+
+// observation is what changes the readiness of an instance, compared to tell
+// whether waiting requests must re-evaluate their session.
+type observation struct {
+	status          InstanceStatus
+	currentReplicas int32
+	desiredReplicas int32
+	message         string
+	err             string
+}
+
+func observe(info InstanceInfo, err error) observation {
+	o := observation{
+		status:          info.Status,
+		currentReplicas: info.CurrentReplicas,
+		desiredReplicas: info.DesiredReplicas,
+		message:         info.Message,
+	}
+	if err != nil {
+		o.err = err.Error()
+	}
+	return o
+}
+
+// readinessWaiter is a request waiting on a set of instances. C receives a
+// value when one of them changed.
+type readinessWaiter struct {
+	names []string
+	C     chan struct{}
+}
+
+func newReadinessHub(s *Sablier) *readinessHub {
+	return &readinessHub{
+		s:        s,
+		watched:  map[string]int{},
+		observed: map[string]observation{},
+		waiters:  map[*readinessWaiter]struct{}{},
+	}
+}
+
+// wait registers a request waiting on the session instances, seeding their
+// observed state from the session so that only later changes wake it. The
+// returned function unregisters it.
+func (h *readinessHub) wait(session *SessionState) (*readinessWaiter, func()) {
+	w := &readinessWaiter{C: make(chan struct{}, 1)}
+	for name := range session.Instances {
+		w.names = append(w.names, name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for name, v := range session.Instances {
+		if h.watched[name] == 0 {
+			h.observed[name] = observe(v.Instance, v.Error)
+		}
+		h.watched[name]++
+	}
+	h.waiters[w] = struct{}{}
+	if h.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stop = cancel
+		// Subscribe before returning, so that no event is missed once the
+		// request waits.
+		stream := h.s.provider.InstanceEvents(ctx, provider.InstanceEventsOptions{})
+		go h.loop(ctx, stream)
+	}
+
+	return w, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.waiters, w)
+		for _, name := range w.names {
+			if h.watched[name]--; h.watched[name] <= 0 {
+				delete(h.watched, name)
+				delete(h.observed, name)
+			}
+		}
+		if len(h.waiters) == 0 && h.stop != nil {
+			h.stop()
+			h.stop = nil
+		}
+	}
+}
+
+func (h *readinessHub) loop(ctx context.Context, stream InstanceEventStream) {
+	ticker := time.NewTicker(h.s.BlockingRefreshFrequency)
+	defer ticker.Stop()
+
+	events, errs := stream.Events, stream.Err
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if slices.Contains(h.pendingNames(), event.Info.Name) {
+				_, _ = h.inspect(ctx, event.Info.Name)
+			}
+		case err, ok := <-errs:
+			if ok && err != nil {
+				h.s.l.WarnContext(ctx, "instance events stopped, readiness falls back to polling", slog.Any("error", err))
+				events = nil
+			}
+			errs = nil
+		case <-ticker.C:
+			h.poll(ctx)
+		}
+	}
+}
+
+// poll inspects the watched instances not observed ready yet, in one provider
+// call when the provider supports it.
+func (h *readinessHub) poll(ctx context.Context) {
+	names := h.pendingNames()
+	var results map[string]InstanceInfo
+	if len(names) > 1 {
+		results = h.s.inspectMany(ctx, names)
+	}
+	for _, name := range names {
+		if info, ok := results[name]; ok {
+			h.record(name, info, nil)
+			continue
+		}
+		_, _ = h.inspect(ctx, name)
+	}
+}
+
+// inspect asks the provider for the state of an instance, sharing the call
+// with concurrent inspections of the same instance, and records the result.
+func (h *readinessHub) inspect(ctx context.Context, name string) (InstanceInfo, error) {
+	v, err, _ := h.inspects.Do(name, func() (any, error) {
+		// Callers share the result: the first one giving up must not fail the
+		// others.
+		inspectCtx, span := h.s.tracer.Start(context.WithoutCancel(ctx), "sablier.instance.inspect",
+			trace.WithAttributes(attribute.String("instance", name)))
+		defer span.End()
+		info, err := h.s.provider.InstanceInspect(inspectCtx, name)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return info, err
+	})
+	info, _ := v.(InstanceInfo)
+	h.record(name, info, err)
+	return info, err
+}
+
+// record stores the state of a watched instance and wakes its waiters when it
+// changed.
+func (h *readinessHub) record(name string, info InstanceInfo, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.watched[name] == 0 {
+		return
+	}
+	o := observe(info, err)
+	if previous, ok := h.observed[name]; ok && previous == o {
+		return
+	}
+	h.observed[name] = o
+	h.wakeLocked(name)
+}
+
+// wake wakes the requests waiting on an instance, e.g. once its start
+// completed.
+func (h *readinessHub) wake(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.wakeLocked(name)
+}
+
+func (h *readinessHub) wakeLocked(name string) {
+	for w := range h.waiters {
+		if !slices.Contains(w.names, name) {
+			continue
+		}
+		select {
+		case w.C <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// pendingNames returns the watched instances not observed ready yet. Like the
+// session requests, the watcher stops inspecting an instance once it is ready.
+func (h *readinessHub) pendingNames() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	names := make([]string, 0, len(h.watched))
+	for name := range h.watched {
+		if h.observed[name].status != InstanceStatusReady {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// readyAfterDeadline returns when the first instance of the session that is
+// running but still within its sablier.ready-after grace period becomes
+// ready. No provider change marks that moment, so waiting requests set a
+// timer for it.
+func readyAfterDeadline(session *SessionState) (time.Time, bool) {
+	var deadline time.Time
+	for _, v := range session.Instances {
+		i := v.Instance
+		if v.Optional || v.Error != nil || i.Status != InstanceStatusReady || i.ReadyAt == nil || i.IsReady() {
+			continue
+		}
+		if at := i.ReadyAt.Add(i.ReadyAfter); deadline.IsZero() || at.Before(deadline) {
+			deadline = at
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
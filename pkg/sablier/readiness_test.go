@@ -0,0 +1,105 @@
+package sablier_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/slogt"
+	"github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/provider/providertest"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store/inmemory"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestRequestReadySession_WaitersShareInspections(t *testing.T) {
+	p := providertest.NewMockProvider(gomock.NewController(t))
+	p.EXPECT().InstanceDependencies(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	p.EXPECT().InstanceEvents(gomock.Any(), gomock.Any()).Return(sablier.InstanceEventStream{}).AnyTimes()
+
+	var started atomic.Bool
+	startCalled := make(chan struct{})
+	p.EXPECT().InstanceStart(gomock.Any(), "nginx").DoAndReturn(func(context.Context, string) error {
+		close(startCalled)
+		time.Sleep(200 * time.Millisecond)
+		started.Store(true)
+		return nil
+	})
+	var inspects atomic.Int32
+	p.EXPECT().InstanceInspect(gomock.Any(), "nginx").DoAndReturn(func(context.Context, string) (sablier.InstanceInfo, error) {
+		inspects.Add(1)
+		if started.Load() {
+			return sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}, nil
+		}
+		return sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusNotReady, DesiredReplicas: 1}, nil
+	}).AnyTimes()
+
+	s := sablier.New(slogt.New(t), inmemory.NewInMemory(), p)
+	s.BlockingRefreshFrequency = 10 * time.Millisecond
+
+	const waiters = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, waiters)
+	request := func() {
+		_, err := s.RequestReadySession(t.Context(), []string{"nginx"}, time.Minute, 10*time.Second)
+		errs <- err
+	}
+	wg.Go(request)
+	<-startCalled
+	for range waiters - 1 {
+		wg.Go(request)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NilError(t, err)
+	}
+
+	// Polling per request would inspect nginx about waiters x 20 times while
+	// it starts.
+	assert.Assert(t, inspects.Load() < waiters, "got %d inspections", inspects.Load())
+}
+
+func TestRequestReadySession_EventWakesWaiters(t *testing.T) {
+	p := providertest.NewMockProvider(gomock.NewController(t))
+	p.EXPECT().InstanceDependencies(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	events := make(chan sablier.InstanceEvent, 1)
+	p.EXPECT().InstanceEvents(gomock.Any(), provider.InstanceEventsOptions{}).Return(sablier.InstanceEventStream{Events: events})
+
+	var ready atomic.Bool
+	p.EXPECT().InstanceInspect(gomock.Any(), "nginx").DoAndReturn(func(context.Context, string) (sablier.InstanceInfo, error) {
+		if ready.Load() {
+			return sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusReady, CurrentReplicas: 1, DesiredReplicas: 1}, nil
+		}
+		return sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusNotReady, DesiredReplicas: 1}, nil
+	}).AnyTimes()
+
+	store := inmemory.NewInMemory()
+	// An existing session: the request inspects nginx without starting it.
+	assert.NilError(t, store.Put(t.Context(), sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusNotReady}, time.Minute))
+
+	s := sablier.New(slogt.New(t), store, p)
+	// Never polled: only the event can tell the request nginx is ready.
+	s.BlockingRefreshFrequency = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.RequestReadySession(t.Context(), []string{"nginx"}, time.Minute, 10*time.Second)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	ready.Store(true)
+	events <- sablier.InstanceEvent{Type: provider.InstanceEventStarted, Info: sablier.InstanceInfo{Name: "nginx"}}
+
+	select {
+	case err := <-done:
+		assert.NilError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event did not wake the waiting request")
+	}
+}
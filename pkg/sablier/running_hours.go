@@ -83,28 +83,35 @@ func (r RunningHours) WindowAt(now time.Time) (start time.Time, end time.Time, i
 	return start, end, !now.Before(start) && now.Before(end)
 }
 
-func runningHoursRemaining(hours, days string, now time.Time) (time.Duration, bool, error) {
+// runningHoursRemaining returns how long the running-hours window open at now
+// stays open, and the rule that opened it or kept it closed: running-hours
+// when open, running-days or holiday when the window was suppressed, and
+// empty outside the window.
+func runningHoursRemaining(hours, days string, now time.Time, holidays *Holidays) (time.Duration, string, error) {
 	rh, err := ParseRunningHours(hours)
 	if err != nil {
-		return 0, false, err
+		return 0, "", err
 	}
 	start, end, in := rh.WindowAt(now)
 	if !in {
-		return 0, false, nil
+		return 0, "", nil
 	}
 	if days != "" {
 		rd, err := ParseRunningDays(days)
 		if err != nil {
-			return 0, false, err
+			return 0, "", err
 		}
 		// Evaluate the day against the window's start day so overnight windows
 		// (e.g. Fri 22:00-06:00) are attributed to the day they began.
 		if !rd.Contains(start.Weekday()) {
-			return 0, false, nil
+			return 0, warmRuleRunningDays, nil
 		}
 	}
+	if holidays.Contains(start) {
+		return 0, warmRuleHoliday, nil
+	}
 	remaining := max(end.Sub(now), 0)
-	return remaining, true, nil
+	return remaining, warmRuleRunningHours, nil
 }
 
 // RunningDays is the set of weekdays on which a running-hours window applies.
@@ -9,13 +9,17 @@ import (
 )
 
 // WatchRunningHours keeps configured instances warm during their
-// sablier.running-hours window by periodically reconciling all managed
-// instances and creating/extending sessions until the window end.
+// sablier.running-hours and sablier.schedule windows by periodically
+// reconciling all managed instances and creating/extending sessions until the
+// window end.
 func (s *Sablier) WatchRunningHours(ctx context.Context) {
 	ticker := time.NewTicker(s.RunningHoursRefreshFrequency)
 	defer ticker.Stop()
 
-	s.reconcileRunningHours(ctx)
+	// rules is the rule of the last reconciliation of each instance with a
+	// window, so opening, suppressing and closing a window are logged once.
+	rules := make(map[string]string)
+	s.reconcileRunningHours(ctx, rules)
 
 	for {
 		select {
@@ -23,12 +27,12 @@ func (s *Sablier) WatchRunningHours(ctx context.Context) {
 			s.l.InfoContext(ctx, "stop watching running-hours windows", slog.Any("reason", ctx.Err()))
 			return
 		case <-ticker.C:
-			s.reconcileRunningHours(ctx)
+			s.reconcileRunningHours(ctx, rules)
 		}
 	}
 }
 
-func (s *Sablier) reconcileRunningHours(ctx context.Context) {
+func (s *Sablier) reconcileRunningHours(ctx context.Context, rules map[string]string) {
 	instances, err := s.provider.InstanceList(ctx, provider.InstanceListOptions{All: true})
 	if err != nil {
 		s.l.ErrorContext(ctx, "running-hours reconciliation failed to list instances", slog.Any("error", err))
@@ -36,6 +40,14 @@ func (s *Sablier) reconcileRunningHours(ctx context.Context) {
 	}
 
 	now := time.Now()
+	seen := make(map[string]struct{}, len(instances))
+	defer func() {
+		for name := range rules {
+			if _, ok := seen[name]; !ok {
+				delete(rules, name)
+			}
+		}
+	}()
 	for _, configured := range instances {
 		if !configured.IsEnabled() {
 			continue
@@ -46,17 +58,27 @@ func (s *Sablier) reconcileRunningHours(ctx context.Context) {
 			s.l.WarnContext(ctx, "running-hours reconciliation failed to inspect instance", slog.String("instance", configured.Name), slog.Any("error", err))
 			continue
 		}
-		if info.RunningHours == "" {
+		if info.RunningHours == "" && (info.Config == nil || info.Config.Schedule == "") {
 			continue
 		}
 
-		remaining, inWindow, err := runningHoursRemaining(info.RunningHours, info.RunningDays, now)
-		if err != nil {
-			s.l.WarnContext(ctx, "invalid running-hours or running-days value, skipping instance", slog.String("instance", info.Name), slog.String("running-hours", info.RunningHours), slog.String("running-days", info.RunningDays), slog.Any("error", err))
+		remaining, rule := s.keepWarm(ctx, info, now)
+		seen[info.Name] = struct{}{}
+		previous := rules[info.Name]
+		rules[info.Name] = rule
+		if remaining <= 0 {
+			switch {
+			case rule != "" && rule != previous:
+				s.l.InfoContext(ctx, "keep-warm window suppressed", slog.String("instance", info.Name), slog.String("rule", rule))
+			case rule == "" && previous != "" && previous != warmRuleHoliday:
+				s.l.InfoContext(ctx, "keep-warm window closed", slog.String("instance", info.Name), slog.String("rule", previous))
+			}
 			continue
 		}
-		if !inWindow || remaining <= 0 {
-			continue
+		if rule != previous {
+			s.l.InfoContext(ctx, "keep-warm window open", slog.String("instance", info.Name), slog.String("rule", rule), slog.Duration("remaining", remaining))
+		} else {
+			s.l.DebugContext(ctx, "keep-warm window open", slog.String("instance", info.Name), slog.String("rule", rule), slog.Duration("remaining", remaining))
 		}
 
 		_, err = s.InstanceRequest(ctx, info.Name, remaining)
@@ -89,6 +89,42 @@ func TestWatchRunningHours_StartsInstanceInsideWindow(t *testing.T) {
 	}
 }
 
+// TestWatchRunningHours_StartsInstanceInsideScheduleWindow verifies that a
+// sablier.schedule window keeps the instance warm until the window ends.
+func TestWatchRunningHours_StartsInstanceInsideScheduleWindow(t *testing.T) {
+	s, sessions, p := setupSablier(t)
+	s.RunningHoursRefreshFrequency = 24 * time.Hour
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	// Opened every minute for an hour: always inside a window ending in
+	// 59 to 60 minutes.
+	info := sablier.InstanceInfo{Name: "myapp", Status: sablier.InstanceStatusReady, Config: &sablier.InstanceConfig{Schedule: "* * * * * 1h"}}
+
+	p.EXPECT().InstanceList(gomock.Any(), provider.InstanceListOptions{All: true}).
+		Return([]sablier.InstanceConfiguration{{Name: "myapp", Enabled: "true"}}, nil)
+	p.EXPECT().InstanceInspect(gomock.Any(), "myapp").Return(info, nil)
+	sessions.EXPECT().Get(gomock.Any(), "myapp").Return(info, nil)
+
+	put := make(chan time.Duration, 1)
+	sessions.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ sablier.InstanceInfo, ttl time.Duration) error {
+			put <- ttl
+			return nil
+		})
+
+	startWatcher(t, s, ctx, cancel)
+
+	select {
+	case ttl := <-put:
+		if ttl < 58*time.Minute || ttl > time.Hour {
+			t.Fatalf("expected the session to last until the schedule window ends, got %s", ttl)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for session to be created/extended")
+	}
+}
+
 // TestWatchRunningHours_SkipsInstanceOutsideWindow verifies that when the
 // current time is outside the running-hours window, no InstanceRequest is made.
 func TestWatchRunningHours_SkipsInstanceOutsideWindow(t *testing.T) {
@@ -40,8 +40,32 @@ type Sablier struct {
 	// group members that both depend on the same database) never start it twice.
 	depStarts map[string]*depStart
 
-	// BlockingRefreshFrequency is the frequency at which the instances are checked
-	// against the provider. Defaults to 5 seconds.
+	// pinMu guards pins, the deadlines until which instances pinned through
+	// PinInstance or PinGroup must stay up. Requests never shorten a pinned
+	// session below its pin, and an expiration before the deadline re-creates
+	// the session instead of stopping the instance.
+	pinMu sync.Mutex
+	pins  map[string]time.Time
+
+	// activityMu guards activity, the last network traffic sample of the
+	// instances whose session is kept alive by their traffic.
+	activityMu sync.Mutex
+	activity   map[string]NetworkActivity
+
+	// holidays keeps the keep-warm windows closed on its dates. Nil when no
+	// holidays file is configured.
+	holidays *holidayCalendar
+
+	// capacity limits the number of instances active at once. Nil when no
+	// capacity is configured.
+	capacity *capacityLimiter
+
+	// readiness watches the instances blocking requests wait on.
+	readiness *readinessHub
+
+	// BlockingRefreshFrequency is the frequency at which the instances blocking
+	// requests wait on are checked against the provider, once per instance, for
+	// the changes its events do not report. Defaults to 5 seconds.
 	BlockingRefreshFrequency time.Duration
 
 	// InstanceStartTimeout is the maximum time allowed for an async InstanceStart
@@ -53,19 +77,40 @@ type Sablier struct {
 	ExternallyStartedScanInterval time.Duration
 
 	// DefaultSessionDuration is the session duration used when seeding sessions
-	// for externally started instances (WatchAndWarmExternallyStarted).
-	// Defaults to 5 minutes.
+	// for externally started instances (WatchAndWarmExternallyStarted), and
+	// for requests without a duration on instances without a
+	// sablier.session-duration label. Defaults to 5 minutes.
 	DefaultSessionDuration time.Duration
 
+	// MaxSessionDuration caps the duration of every requested session. Zero
+	// means no cap.
+	MaxSessionDuration time.Duration
+
 	// RunningHoursRefreshFrequency is how often running-hours windows are
 	// reconciled. Defaults to 30 seconds.
 	RunningHoursRefreshFrequency time.Duration
 
+	// ActivityRefreshFrequency is how often the traffic of the instances
+	// labelled sablier.idle.activity is sampled. Defaults to 30 seconds.
+	ActivityRefreshFrequency time.Duration
+
 	// rejectUnlabeledRequests blocks direct named requests unless sablier.enable=true.
 	rejectUnlabeledRequests bool
 
 	// verifyEnabledOnExpiration re-checks sablier.enable before stopping expired instances.
 	verifyEnabledOnExpiration bool
+	// policies are the groups and policies of the configuration file, shared
+	// with the provider.
+	policies *PolicySet
+
+	// leader reports whether this replica leads the replicas sharing the
+	// store. Only the leader acts on expirations and anti-affinities. Nil
+	// when Sablier runs alone.
+	leader func() bool
+
+	// history records the lifecycle of the sessions. Nil when no store
+	// keeps it.
+	history History
 
 	metrics metrics.Recorder
 	tracer  trace.Tracer
@@ -74,7 +119,7 @@ type Sablier struct {
 }
 
 func New(logger *slog.Logger, store Store, provider Provider) *Sablier {
-	return &Sablier{
+	s := &Sablier{
 		provider:                      provider,
 		sessions:                      store,
 		groups:                        newGroupRegistry(),
@@ -82,6 +127,9 @@ func New(logger *slog.Logger, store Store, provider Provider) *Sablier {
 		suppressed:                    map[string]struct{}{},
 		pendingStarts:                 map[string]*pendingStart{},
 		depStarts:                     map[string]*depStart{},
+		pins:                          map[string]time.Time{},
+		activity:                      map[string]NetworkActivity{},
+		policies:                      &PolicySet{},
 		l:                             logger,
 		metrics:                       metrics.Noop{},
 		tracer:                        otel.Tracer("github.com/sablierapp/sablier"),
@@ -90,7 +138,10 @@ func New(logger *slog.Logger, store Store, provider Provider) *Sablier {
 		ExternallyStartedScanInterval: 30 * time.Second,
 		DefaultSessionDuration:        5 * time.Minute,
 		RunningHoursRefreshFrequency:  30 * time.Second,
+		ActivityRefreshFrequency:      30 * time.Second,
 	}
+	s.readiness = newReadinessHub(s)
+	return s
 }
 
 // WithRejectUnlabeledRequests makes direct named requests require sablier.enable=true.
@@ -103,6 +154,22 @@ func (s *Sablier) WithVerifyEnabledOnExpiration(verify bool) {
 	s.verifyEnabledOnExpiration = verify
 }
 
+// WithPolicies makes Sablier apply the policies of ps, the set the provider
+// parses the labels of its instances with. ReloadPolicies replaces them.
+func (s *Sablier) WithPolicies(ps *PolicySet) {
+	s.policies = ps
+}
+
+// WithLeader makes Sablier act on session expirations and anti-affinities
+// only while isLeader reports true, for replicas sharing one store.
+func (s *Sablier) WithLeader(isLeader func() bool) {
+	s.leader = isLeader
+}
+
+func (s *Sablier) isLeader() bool {
+	return s.leader == nil || s.leader()
+}
+
 // WithMetrics installs a Recorder. Defaults to metrics.Noop until called.
 func (s *Sablier) WithMetrics(r metrics.Recorder) {
 	if r == nil {
@@ -181,5 +248,9 @@ func (s *Sablier) RemoveInstanceFromAllGroups(instance string) []string {
 }
 
 func (s *Sablier) RemoveInstance(ctx context.Context, name string) error {
-	return s.sessions.Delete(ctx, name)
+	if err := s.sessions.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.capacityFreed(name)
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/neilotoole/slogt"
 	"github.com/sablierapp/sablier/pkg/metrics"
+	"github.com/sablierapp/sablier/pkg/provider"
 	"github.com/sablierapp/sablier/pkg/provider/providertest"
 	"github.com/sablierapp/sablier/pkg/sablier"
 	"github.com/sablierapp/sablier/pkg/store/storetest"
@@ -23,6 +24,10 @@ func setupSablier(t *testing.T) (*sablier.Sablier, *storetest.MockStore, *provid
 	// Default: no dependencies for any instance. Individual tests can override
 	// this by registering a more-specific expectation after setupSablier returns.
 	p.EXPECT().InstanceDependencies(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	// Default: blocking requests follow no provider event and rely on polling.
+	// Event subscriptions of the background watchers filter on types and do
+	// not match this expectation.
+	p.EXPECT().InstanceEvents(gomock.Any(), provider.InstanceEventsOptions{}).Return(sablier.InstanceEventStream{}).AnyTimes()
 
 	m := sablier.New(slogt.New(t), s, p)
 	return m, s, p
@@ -64,6 +69,12 @@ func (f *fakeRecorder) RecordInactiveInstance(instance string) { f.record("activ
 func (f *fakeRecorder) RecordInstanceStop(instance, reason string) {
 	f.record("stop:" + instance + "/" + reason)
 }
+func (f *fakeRecorder) RecordListenerConnectionOpen(string)          {}
+func (f *fakeRecorder) RecordListenerConnectionClose(string, string) {}
+func (f *fakeRecorder) RecordListenerWait(string, time.Duration)     {}
+func (f *fakeRecorder) RecordListenerBytes(string, string, int64)    {}
+func (f *fakeRecorder) RecordListenerDrop(string, string)            {}
+func (f *fakeRecorder) RecordInspectCache(string)                    {}
 
 // setupSablierWithMetrics is like setupSablier but installs a fakeRecorder.
 func setupSablierWithMetrics(t *testing.T) (*sablier.Sablier, *storetest.MockStore, *providertest.MockProvider, *fakeRecorder) {
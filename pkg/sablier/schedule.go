@@ -0,0 +1,141 @@
+package sablier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule is a set of keep-warm windows. Each window opens at the times of a
+// cron expression and stays open for a duration.
+type Schedule []scheduleEntry
+
+type scheduleEntry struct {
+	cron     cronSpec
+	duration time.Duration
+}
+
+// ScheduleWindow is a window of a Schedule.
+type ScheduleWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ParseSchedule parses a semicolon-separated list of windows, each a
+// five-field cron expression followed by a duration, for example
+// "30 8 * * mon-fri 30m; 0 2 * * sun 2h".
+//
+// The cron expressions are evaluated in the location of the times given to
+// the Schedule methods: Sablier uses the time zone of the sablier.timezone
+// label of the instance, or the local time zone when it is not set.
+func ParseSchedule(v string) (Schedule, error) {
+	var schedule Schedule
+	for _, part := range strings.Split(v, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Fields(part)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid schedule %q: window %q must be a cron expression followed by a duration", v, part)
+		}
+		spec, err := parseCron(strings.Join(fields[:5], " "))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", v, err)
+		}
+		duration, err := time.ParseDuration(fields[5])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", v, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: duration %s must be positive", v, fields[5])
+		}
+		schedule = append(schedule, scheduleEntry{cron: spec, duration: duration})
+	}
+	if len(schedule) == 0 {
+		return nil, fmt.Errorf("invalid schedule %q: no window specified", v)
+	}
+	return schedule, nil
+}
+
+// maxHolidaySkips bounds the windows Next skips because they open on
+// holidays, so a calendar covering every date cannot loop for five years.
+const maxHolidaySkips = 1000
+
+// WindowAt returns the open window at now that ends last, if any. Windows
+// opening on a date of holidays stay closed; a nil holidays contains none.
+func (s Schedule) WindowAt(now time.Time, holidays *Holidays) (ScheduleWindow, bool) {
+	window, in, _ := s.windowAt(now, holidays)
+	return window, in
+}
+
+// windowAt is WindowAt, also reporting whether a window open at now was kept
+// closed by holidays.
+func (s Schedule) windowAt(now time.Time, holidays *Holidays) (window ScheduleWindow, in bool, suppressed bool) {
+	for _, e := range s {
+		for _, w := range e.windowsAt(now) {
+			if holidays.Contains(w.Start) {
+				suppressed = true
+				continue
+			}
+			if !in || w.End.After(window.End) {
+				window, in = w, true
+			}
+		}
+	}
+	return window, in, suppressed
+}
+
+// Next returns the open window at now, or the next one to open when none is.
+// Windows opening on a date of holidays are skipped.
+func (s Schedule) Next(now time.Time, holidays *Holidays) (ScheduleWindow, bool) {
+	if w, in := s.WindowAt(now, holidays); in {
+		return w, true
+	}
+	var window ScheduleWindow
+	var found bool
+	for _, e := range s {
+		start, ok := e.cron.next(now)
+		for skips := 0; ok && holidays.Contains(start); skips++ {
+			if skips == maxHolidaySkips {
+				ok = false
+				break
+			}
+			start, ok = e.cron.next(start.Add(time.Minute))
+		}
+		if ok && (!found || start.Before(window.Start)) {
+			window, found = ScheduleWindow{Start: start, End: start.Add(e.duration)}, true
+		}
+	}
+	return window, found
+}
+
+// windowsAt returns the windows of the entry open at now, in the order they
+// opened.
+func (e scheduleEntry) windowsAt(now time.Time) []ScheduleWindow {
+	var windows []ScheduleWindow
+	start, ok := e.cron.next(now.Add(-e.duration).Add(time.Nanosecond))
+	for ok && !start.After(now) {
+		windows = append(windows, ScheduleWindow{Start: start, End: start.Add(e.duration)})
+		start, ok = e.cron.next(start.Add(time.Minute))
+	}
+	return windows
+}
+
+// scheduleRemaining returns how long the schedule window open at now stays
+// open, and the rule that opened it or kept it closed: schedule when open,
+// holiday when the window was suppressed, and empty outside any window.
+func scheduleRemaining(schedule string, now time.Time, holidays *Holidays) (time.Duration, string, error) {
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		return 0, "", err
+	}
+	window, in, suppressed := s.windowAt(now, holidays)
+	if !in {
+		if suppressed {
+			return 0, warmRuleHoliday, nil
+		}
+		return 0, "", nil
+	}
+	return max(window.End.Sub(now), 0), warmRuleSchedule, nil
+}
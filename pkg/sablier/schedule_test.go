@@ -0,0 +1,171 @@
+package sablier_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{name: "single window", value: "30 8 * * 1-5 30m"},
+		{name: "several windows", value: "30 8 * * mon-fri 30m; 0 2 * * sun 2h"},
+		{name: "lists and steps", value: "0,30 8-18/2 1,15 jan-jun * 1h"},
+		{name: "trailing separator", value: "0 2 * * sun 2h;"},
+		{name: "sunday as 7", value: "0 2 * * 7 2h"},
+		{name: "empty", value: " ; ", err: "no window specified"},
+		{name: "missing duration", value: "30 8 * * 1-5", err: "must be a cron expression followed by a duration"},
+		{name: "invalid duration", value: "30 8 * * 1-5 forever", err: "invalid duration"},
+		{name: "non-positive duration", value: "30 8 * * 1-5 0s", err: "must be positive"},
+		{name: "minute out of range", value: "60 8 * * * 1h", err: "value 60 out of range [0-59] in minute field"},
+		{name: "unknown weekday", value: "0 8 * * funday 1h", err: `invalid value "funday" in day of week field`},
+		{name: "reversed range", value: "0 18-8 * * * 1h", err: `invalid range "18-8" in hour field`},
+		{name: "invalid step", value: "*/0 8 * * * 1h", err: `invalid step "0" in minute field`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sablier.ParseSchedule(tt.value)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestScheduleWindowAt(t *testing.T) {
+	// 2026-01-05 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	schedule, err := sablier.ParseSchedule("30 8 * * mon-fri 30m; 0 2 * * sun 2h")
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want sablier.ScheduleWindow
+		in   bool
+	}{
+		{name: "weekday window", now: at(5, 8, 45), want: sablier.ScheduleWindow{Start: at(5, 8, 30), End: at(5, 9, 0)}, in: true},
+		{name: "window start is inside", now: at(5, 8, 30), want: sablier.ScheduleWindow{Start: at(5, 8, 30), End: at(5, 9, 0)}, in: true},
+		{name: "window end is outside", now: at(5, 9, 0)},
+		{name: "before the window", now: at(5, 8, 29)},
+		{name: "weekday window on a saturday", now: at(10, 8, 45)},
+		{name: "sunday window", now: at(11, 3, 59), want: sablier.ScheduleWindow{Start: at(11, 2, 0), End: at(11, 4, 0)}, in: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, in := schedule.WindowAt(tt.now, nil)
+			assert.Equal(t, in, tt.in)
+			if tt.in {
+				assert.DeepEqual(t, window, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleWindowAt_OverlappingWindowsEndLast(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.January, 5, hour, minute, 0, 0, time.UTC)
+	}
+	// Every hour for 90 minutes, and 10:00 for 3 hours.
+	schedule, err := sablier.ParseSchedule("0 * * * * 90m; 0 10 * * * 3h")
+	assert.NilError(t, err)
+
+	window, in := schedule.WindowAt(at(9, 15), nil)
+	assert.Assert(t, in)
+	assert.DeepEqual(t, window, sablier.ScheduleWindow{Start: at(9, 0), End: at(10, 30)})
+
+	window, in = schedule.WindowAt(at(11, 15), nil)
+	assert.Assert(t, in)
+	assert.DeepEqual(t, window, sablier.ScheduleWindow{Start: at(10, 0), End: at(13, 0)})
+}
+
+func TestScheduleWindowAt_Holidays(t *testing.T) {
+	holidays, err := sablier.ParseHolidays(strings.NewReader(holidaysICS))
+	assert.NilError(t, err)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.December, day, hour, minute, 0, 0, time.UTC)
+	}
+	// 23:00 for 2 hours, and midnight for 3 hours: on Christmas, the window
+	// opened on the 24th stays open, the one opening on the 25th does not.
+	schedule, err := sablier.ParseSchedule("0 23 * * * 2h; 0 0 * * * 3h")
+	assert.NilError(t, err)
+
+	window, in := schedule.WindowAt(at(25, 0, 30), holidays)
+	assert.Assert(t, in)
+	assert.DeepEqual(t, window, sablier.ScheduleWindow{Start: at(24, 23, 0), End: at(25, 1, 0)})
+
+	_, in = schedule.WindowAt(at(25, 1, 30), holidays)
+	assert.Assert(t, !in)
+}
+
+func TestScheduleNext_SkipsHolidays(t *testing.T) {
+	holidays, err := sablier.ParseHolidays(strings.NewReader(holidaysICS))
+	assert.NilError(t, err)
+	schedule, err := sablier.ParseSchedule("0 8 * * * 1h")
+	assert.NilError(t, err)
+
+	window, ok := schedule.Next(time.Date(2026, time.December, 24, 12, 0, 0, 0, time.UTC), holidays)
+	assert.Assert(t, ok)
+	assert.Equal(t, window.Start, time.Date(2026, time.December, 26, 8, 0, 0, 0, time.UTC))
+}
+
+func TestScheduleNext(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	schedule, err := sablier.ParseSchedule("30 8 * * mon-fri 30m; 0 2 * * sun 2h")
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want sablier.ScheduleWindow
+	}{
+		{name: "open window", now: at(5, 8, 45), want: sablier.ScheduleWindow{Start: at(5, 8, 30), End: at(5, 9, 0)}},
+		{name: "later today", now: at(5, 7, 0), want: sablier.ScheduleWindow{Start: at(5, 8, 30), End: at(5, 9, 0)}},
+		{name: "tomorrow", now: at(5, 9, 0), want: sablier.ScheduleWindow{Start: at(6, 8, 30), End: at(6, 9, 0)}},
+		{name: "sunday before monday", now: at(9, 12, 0), want: sablier.ScheduleWindow{Start: at(11, 2, 0), End: at(11, 4, 0)}},
+		{name: "rounds up to the next minute", now: at(5, 8, 29).Add(30 * time.Second), want: sablier.ScheduleWindow{Start: at(5, 8, 30), End: at(5, 9, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, ok := schedule.Next(tt.now, nil)
+			assert.Assert(t, ok)
+			assert.DeepEqual(t, window, tt.want)
+		})
+	}
+}
+
+func TestScheduleNext_DayOfMonthOrDayOfWeek(t *testing.T) {
+	// As in cron, restricting both days matches either: the 1st of the month
+	// or any Friday.
+	schedule, err := sablier.ParseSchedule("0 0 1 * fri 1h")
+	assert.NilError(t, err)
+
+	window, ok := schedule.Next(time.Date(2026, time.January, 2, 12, 0, 0, 0, time.UTC), nil)
+	assert.Assert(t, ok)
+	assert.Equal(t, window.Start, time.Date(2026, time.January, 9, 0, 0, 0, 0, time.UTC))
+
+	window, ok = schedule.Next(time.Date(2026, time.January, 30, 12, 0, 0, 0, time.UTC), nil)
+	assert.Assert(t, ok)
+	assert.Equal(t, window.Start, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestScheduleNext_NeverMatches(t *testing.T) {
+	schedule, err := sablier.ParseSchedule("0 0 30 feb * 1h")
+	assert.NilError(t, err)
+
+	_, ok := schedule.Next(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), nil)
+	assert.Assert(t, !ok)
+}
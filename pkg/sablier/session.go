@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 )
 
 type SessionState struct {
@@ -67,6 +68,19 @@ func (s *SessionState) InstanceErrors() error {
 	return errors.Join(errs...)
 }
 
+// SessionDuration returns the shortest session duration among the instances,
+// the time after which the first of them stops unless the session is
+// requested again. Zero when no instance was given a session.
+func (s *SessionState) SessionDuration() time.Duration {
+	var shortest time.Duration
+	for _, v := range s.Instances {
+		if v.SessionDuration > 0 && (shortest == 0 || v.SessionDuration < shortest) {
+			shortest = v.SessionDuration
+		}
+	}
+	return shortest
+}
+
 func (s *SessionState) Status() string {
 	if s.IsReady() {
 		return "ready"
@@ -0,0 +1,31 @@
+package sablier
+
+import "time"
+
+// sessionDuration returns the duration of the session of instance for a
+// request asking for requested. A request without a duration (zero) gets the
+// sablier.session-duration of the instance, or DefaultSessionDuration. The
+// duration is then capped by the sablier.session-duration-max of the instance
+// and by MaxSessionDuration, so no client can keep an instance up longer than
+// its operator allows.
+func (s *Sablier) sessionDuration(instance InstanceInfo, requested time.Duration) time.Duration {
+	var cfg InstanceConfig
+	if instance.Config != nil {
+		cfg = *instance.Config
+	}
+
+	duration := requested
+	if duration <= 0 {
+		duration = cfg.SessionDuration
+	}
+	if duration <= 0 {
+		duration = s.DefaultSessionDuration
+	}
+	if cfg.SessionDurationMax > 0 && duration > cfg.SessionDurationMax {
+		duration = cfg.SessionDurationMax
+	}
+	if s.MaxSessionDuration > 0 && duration > s.MaxSessionDuration {
+		duration = s.MaxSessionDuration
+	}
+	return duration
+}
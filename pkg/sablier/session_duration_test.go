@@ -0,0 +1,37 @@
+package sablier
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSessionDuration(t *testing.T) {
+	labeled := func(duration, max time.Duration) InstanceInfo {
+		return InstanceInfo{Name: "whoami", Config: &InstanceConfig{SessionDuration: duration, SessionDurationMax: max}}
+	}
+	tests := []struct {
+		name      string
+		instance  InstanceInfo
+		requested time.Duration
+		max       time.Duration
+		want      time.Duration
+	}{
+		{name: "requested", instance: InstanceInfo{Name: "whoami"}, requested: time.Hour, want: time.Hour},
+		{name: "server default", instance: InstanceInfo{Name: "whoami"}, want: 5 * time.Minute},
+		{name: "instance default", instance: labeled(30*time.Minute, 0), want: 30 * time.Minute},
+		{name: "requested wins over the instance default", instance: labeled(30*time.Minute, 0), requested: time.Hour, want: time.Hour},
+		{name: "instance max", instance: labeled(0, time.Hour), requested: 1000 * time.Hour, want: time.Hour},
+		{name: "instance max caps the instance default", instance: labeled(2*time.Hour, time.Hour), want: time.Hour},
+		{name: "server max", instance: labeled(0, 0), requested: 1000 * time.Hour, max: 4 * time.Hour, want: 4 * time.Hour},
+		{name: "server max caps the instance max", instance: labeled(0, 8*time.Hour), requested: 1000 * time.Hour, max: 4 * time.Hour, want: 4 * time.Hour},
+		{name: "below every max", instance: labeled(0, 8*time.Hour), requested: time.Hour, max: 4 * time.Hour, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sablier{DefaultSessionDuration: 5 * time.Minute, MaxSessionDuration: tt.max}
+			assert.Equal(t, s.sessionDuration(tt.instance, tt.requested), tt.want)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package sablier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/store"
+)
+
+// ExpireSession ends the session of name right away: the session is removed
+// from the store and the instance is stopped. A pin on the instance and the
+// traffic it is kept alive by are dropped, otherwise the expiration would be
+// undone. The replica serving the
+// call stops the instance itself, leader or not: deleting a session emits no
+// expiration the leader would act on.
+func (s *Sablier) ExpireSession(ctx context.Context, name string) error {
+	_, err := s.sessions.Get(ctx, name)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return ErrSessionNotFound{Name: name}
+	}
+	if err != nil {
+		return fmt.Errorf("cannot retrieve instance from store: %w", err)
+	}
+
+	s.unpin(name)
+	s.forgetActivity(name)
+	if err := s.sessions.Delete(ctx, name); err != nil {
+		return fmt.Errorf("cannot remove instance from store: %w", err)
+	}
+
+	s.l.InfoContext(ctx, "session expired on demand", slog.String("instance", name))
+	// The queued instances may now fit the capacity.
+	s.capacityFreed(name)
+	// The stop runs asynchronously, so detach it from the caller's
+	// cancellation (e.g. an HTTP request that completes right away).
+	stopCtx := context.WithoutCancel(ctx)
+	go func() {
+		stopInstance(stopCtx, s.provider, s.metrics, s.l, s.verifyEnabledOnExpiration, name, StopReasonManual)
+		s.recordLifecycle(stopCtx, LifecycleEvent{Instance: name, Kind: LifecycleStopped, Reason: StopReasonManual})
+		s.triggerAntiAffinityReconcile(stopCtx)
+	}()
+	return nil
+}
+
+// ExtendSession pushes the expiration of the active session of name back by
+// duration, without inspecting or starting the instance.
+func (s *Sablier) ExtendSession(ctx context.Context, name string, duration time.Duration) (InstanceSession, error) {
+	if duration <= 0 {
+		return InstanceSession{}, fmt.Errorf("duration must be positive, got %s", duration)
+	}
+
+	session, err := s.lookupSession(ctx, name)
+	if err != nil {
+		return InstanceSession{}, err
+	}
+
+	session.ExpiresAt = session.ExpiresAt.Add(duration)
+	if session.Instance.SessionDeadline != nil {
+		// Move the deadline of a fixed session too, or the next request would
+		// bring the expiration back to it.
+		deadline := session.Instance.SessionDeadline.Add(duration)
+		session.Instance.SessionDeadline = &deadline
+	}
+	if err := s.sessions.Put(ctx, session.Instance, time.Until(session.ExpiresAt)); err != nil {
+		return InstanceSession{}, fmt.Errorf("could not put instance to store: %w", err)
+	}
+
+	s.l.InfoContext(ctx, "session extended on demand", slog.String("instance", name),
+		slog.Duration("extension", duration), slog.Time("expires_at", session.ExpiresAt))
+	return session, nil
+}
+
+// PinInstance keeps the managed instance name running until ttl has elapsed,
+// whatever shorter sessions are requested meanwhile. The instance is started
+// through a regular session request lasting ttl.
+func (s *Sablier) PinInstance(ctx context.Context, name string, ttl time.Duration) (*SessionState, time.Time, error) {
+	if ttl <= 0 {
+		return nil, time.Time{}, fmt.Errorf("ttl must be positive, got %s", ttl)
+	}
+
+	until := s.pin([]string{name}, ttl)
+	info, effective, err := s.instanceRequest(ctx, name, ttl, true, nil)
+	if err != nil {
+		s.unpin(name)
+		return nil, time.Time{}, err
+	}
+
+	s.l.InfoContext(ctx, "instance pinned", slog.String("instance", name), slog.Time("until", until))
+	return &SessionState{Instances: map[string]InstanceInfoWithError{
+		name: {Instance: info, SessionDuration: effective},
+	}}, until, nil
+}
+
+// PinGroup pins every member of group, like PinInstance.
+func (s *Sablier) PinGroup(ctx context.Context, group string, ttl time.Duration) (*SessionState, time.Time, error) {
+	if ttl <= 0 {
+		return nil, time.Time{}, fmt.Errorf("ttl must be positive, got %s", ttl)
+	}
+
+	names, ok := s.groups.Get(group)
+	if !ok {
+		return nil, time.Time{}, ErrGroupNotFound{
+			Group:           group,
+			AvailableGroups: s.groups.Keys(),
+		}
+	}
+	if len(names) == 0 {
+		return nil, time.Time{}, fmt.Errorf("group has no member")
+	}
+
+	until := s.pin(names, ttl)
+	session, err := s.requestSession(ctx, names, ttl, false)
+	if err != nil {
+		for _, name := range names {
+			s.unpin(name)
+		}
+		return nil, time.Time{}, err
+	}
+
+	s.l.InfoContext(ctx, "group pinned", slog.String("group", group), slog.Any("instances", names), slog.Time("until", until))
+	return session, until, nil
+}
+
+// lookupSession returns the active session of name with its expiration time.
+func (s *Sablier) lookupSession(ctx context.Context, name string) (InstanceSession, error) {
+	var (
+		session InstanceSession
+		found   bool
+	)
+	err := s.sessions.Range(ctx, func(info InstanceInfo, expiresAt time.Time) {
+		if info.Name == name {
+			session = InstanceSession{Instance: info, ExpiresAt: expiresAt, PinnedUntil: s.pinnedUntil(name)}
+			found = true
+		}
+	})
+	if err != nil {
+		return InstanceSession{}, err
+	}
+	if !found {
+		return InstanceSession{}, ErrSessionNotFound{Name: name}
+	}
+	return session, nil
+}
+
+// pin records that names must stay up for ttl and returns the pin deadline.
+// An existing pin is only ever lengthened.
+func (s *Sablier) pin(names []string, ttl time.Duration) time.Time {
+	until := time.Now().Add(ttl)
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	for _, name := range names {
+		if current, ok := s.pins[name]; !ok || current.Before(until) {
+			s.pins[name] = until
+		}
+	}
+	return until
+}
+
+func (s *Sablier) unpin(name string) {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	delete(s.pins, name)
+}
+
+// pinnedUntil returns the pin deadline of name, or nil when it is not pinned.
+func (s *Sablier) pinnedUntil(name string) *time.Time {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	until, ok := s.pins[name]
+	if !ok || !time.Now().Before(until) {
+		return nil
+	}
+	return &until
+}
+
+// pinRemaining returns how long name stays pinned, or 0 when it is not pinned.
+// Lapsed pins are dropped.
+func (s *Sablier) pinRemaining(name string) time.Duration {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	until, ok := s.pins[name]
+	if !ok {
+		return 0
+	}
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		delete(s.pins, name)
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,233 @@
+package sablier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestExpireSession_StopsInstance(t *testing.T) {
+	s, st, p, rec := setupSablierWithMetrics(t)
+	ctx := t.Context()
+
+	st.EXPECT().Get(ctx, "whoami").Return(sablier.InstanceInfo{Name: "whoami"}, nil)
+	st.EXPECT().Delete(ctx, "whoami").Return(nil)
+	stopped := make(chan struct{}, 1)
+	p.EXPECT().InstanceStop(gomock.Any(), "whoami").DoAndReturn(func(_ any, _ string) error {
+		stopped <- struct{}{}
+		return nil
+	})
+
+	assert.NilError(t, s.ExpireSession(ctx, "whoami"))
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("instance stop was not invoked")
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Assert(t, containsCall(rec.snapshot(), "stop:whoami/manual"))
+}
+
+func TestExpireSession_StopsInstanceOnFollower(t *testing.T) {
+	s, st, p, rec := setupSablierWithMetrics(t)
+	s.WithLeader(func() bool { return false })
+	ctx := t.Context()
+
+	st.EXPECT().Get(ctx, "whoami").Return(sablier.InstanceInfo{Name: "whoami"}, nil)
+	st.EXPECT().Delete(ctx, "whoami").Return(nil)
+	stopped := make(chan struct{}, 1)
+	p.EXPECT().InstanceStop(gomock.Any(), "whoami").DoAndReturn(func(_ any, _ string) error {
+		stopped <- struct{}{}
+		return nil
+	})
+
+	assert.NilError(t, s.ExpireSession(ctx, "whoami"))
+
+	// Deleting the session emits no expiration the leader would act on.
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("instance stop was not invoked")
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Assert(t, containsCall(rec.snapshot(), "stop:whoami/manual"))
+}
+
+func TestExpireSession_NotFound(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	st.EXPECT().Get(ctx, "ghost").Return(sablier.InstanceInfo{}, store.ErrKeyNotFound)
+
+	err := s.ExpireSession(ctx, "ghost")
+	_, ok := errors.AsType[sablier.ErrSessionNotFound](err)
+	assert.Assert(t, ok, "expected ErrSessionNotFound, got %v", err)
+}
+
+func TestExtendSession(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	info := sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady}
+	expiresAt := time.Now().Add(time.Minute)
+	st.EXPECT().Range(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+		f(sablier.InstanceInfo{Name: "nginx"}, time.Now().Add(time.Hour))
+		f(info, expiresAt)
+		return nil
+	})
+	st.EXPECT().Put(ctx, info, gomock.Any()).DoAndReturn(func(_ context.Context, _ sablier.InstanceInfo, d time.Duration) error {
+		// One minute left plus the ten minute extension.
+		assert.Assert(t, d > 10*time.Minute && d <= 11*time.Minute, "unexpected duration %s", d)
+		return nil
+	})
+
+	session, err := s.ExtendSession(ctx, "whoami", 10*time.Minute)
+	assert.NilError(t, err)
+	assert.Equal(t, session.ExpiresAt, expiresAt.Add(10*time.Minute))
+	assert.DeepEqual(t, session.Instance, info)
+}
+
+func TestExtendSession_MovesTheFixedDeadline(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	deadline := time.Now().Add(time.Minute)
+	info := sablier.InstanceInfo{Name: "preview", Status: sablier.InstanceStatusReady, SessionDeadline: &deadline}
+	st.EXPECT().Range(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+		f(info, deadline)
+		return nil
+	})
+	st.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+	session, err := s.ExtendSession(ctx, "preview", 10*time.Minute)
+	assert.NilError(t, err)
+	assert.Equal(t, *session.Instance.SessionDeadline, deadline.Add(10*time.Minute))
+	assert.Equal(t, *info.SessionDeadline, deadline, "the deadline of the stored instance must not be shared")
+}
+
+func TestExtendSession_NotFound(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	st.EXPECT().Range(ctx, gomock.Any()).Return(nil)
+
+	_, err := s.ExtendSession(ctx, "ghost", time.Minute)
+	_, ok := errors.AsType[sablier.ErrSessionNotFound](err)
+	assert.Assert(t, ok, "expected ErrSessionNotFound, got %v", err)
+}
+
+func TestExtendSession_InvalidDuration(t *testing.T) {
+	s, _, _ := setupSablier(t)
+
+	_, err := s.ExtendSession(t.Context(), "whoami", 0)
+	assert.ErrorContains(t, err, "duration must be positive")
+}
+
+func TestPinInstance_OutlivesShorterRequests(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	ready := sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady, Enabled: "true"}
+	st.EXPECT().Get(gomock.Any(), "whoami").Return(ready, nil).Times(2)
+
+	var durations []time.Duration
+	st.EXPECT().Put(gomock.Any(), ready, gomock.Any()).DoAndReturn(func(_ context.Context, _ sablier.InstanceInfo, d time.Duration) error {
+		durations = append(durations, d)
+		return nil
+	}).Times(2)
+
+	session, until, err := s.PinInstance(ctx, "whoami", time.Hour)
+	assert.NilError(t, err)
+	assert.Assert(t, session.IsReady())
+	assert.Assert(t, time.Until(until) > 59*time.Minute)
+
+	// An organic request for a shorter session keeps the pin.
+	_, err = s.InstanceRequest(ctx, "whoami", time.Minute)
+	assert.NilError(t, err)
+
+	assert.Equal(t, durations[0], time.Hour)
+	assert.Assert(t, durations[1] > 59*time.Minute, "pinned session shortened to %s", durations[1])
+}
+
+func TestPinInstance_ExpirationRenewsSession(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+
+	ready := sablier.InstanceInfo{Name: "whoami", Status: sablier.InstanceStatusReady, Enabled: "true"}
+	st.EXPECT().Get(gomock.Any(), "whoami").Return(ready, nil).Times(2)
+	renewed := make(chan time.Duration, 2)
+	st.EXPECT().Put(gomock.Any(), ready, gomock.Any()).DoAndReturn(func(_ context.Context, _ sablier.InstanceInfo, d time.Duration) error {
+		renewed <- d
+		return nil
+	}).Times(2)
+
+	_, _, err := s.PinInstance(ctx, "whoami", time.Hour)
+	assert.NilError(t, err)
+	<-renewed
+
+	// The store expiring the session early must not stop a pinned instance:
+	// no InstanceStop expectation is registered on the provider.
+	s.OnInstanceExpired(ctx)("whoami")
+
+	select {
+	case d := <-renewed:
+		assert.Assert(t, d > 59*time.Minute, "session renewed for %s only", d)
+	case <-time.After(2 * time.Second):
+		t.Fatal("pinned session was not renewed")
+	}
+}
+
+func TestPinInstance_NotManaged(t *testing.T) {
+	s, st, p := setupSablier(t)
+	ctx := t.Context()
+
+	st.EXPECT().Get(gomock.Any(), "db").Return(sablier.InstanceInfo{}, store.ErrKeyNotFound)
+	p.EXPECT().InstanceInspect(gomock.Any(), "db").Return(sablier.InstanceInfo{Name: "db"}, nil)
+
+	_, _, err := s.PinInstance(ctx, "db", time.Hour)
+	_, ok := errors.AsType[sablier.ErrInstanceNotManaged](err)
+	assert.Assert(t, ok, "expected ErrInstanceNotManaged, got %v", err)
+}
+
+func TestPinGroup_UnknownGroup(t *testing.T) {
+	s, _, _ := setupSablier(t)
+
+	_, _, err := s.PinGroup(t.Context(), "ghost", time.Hour)
+	_, ok := errors.AsType[sablier.ErrGroupNotFound](err)
+	assert.Assert(t, ok, "expected ErrGroupNotFound, got %v", err)
+}
+
+func TestPinGroup_PinsEveryMember(t *testing.T) {
+	s, st, _ := setupSablier(t)
+	ctx := t.Context()
+	s.SetGroups(map[string][]string{"demo": {"api", "web"}})
+
+	for _, name := range []string{"api", "web"} {
+		info := sablier.InstanceInfo{Name: name, Status: sablier.InstanceStatusReady}
+		st.EXPECT().Get(gomock.Any(), name).Return(info, nil)
+		st.EXPECT().Put(gomock.Any(), info, time.Hour).Return(nil)
+	}
+	st.EXPECT().Range(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+		f(sablier.InstanceInfo{Name: "api"}, time.Now().Add(time.Hour))
+		f(sablier.InstanceInfo{Name: "web"}, time.Now().Add(time.Hour))
+		return nil
+	})
+
+	session, until, err := s.PinGroup(ctx, "demo", time.Hour)
+	assert.NilError(t, err)
+	assert.Assert(t, session.IsReady())
+
+	sessions, err := s.ListSessions(ctx)
+	assert.NilError(t, err)
+	for _, session := range sessions {
+		assert.Assert(t, session.PinnedUntil != nil, "%s is not pinned", session.Instance.Name)
+		assert.Equal(t, *session.PinnedUntil, until)
+	}
+}
@@ -0,0 +1,49 @@
+package sablier
+
+import (
+	"context"
+	"fmt"
+)
+
+// SessionMode decides whether the requests of a session renew it.
+type SessionMode string
+
+const (
+	// SessionModeSliding renews the session on every request: the instance
+	// stops once no request came for the session duration. The default.
+	SessionModeSliding SessionMode = "sliding"
+	// SessionModeFixed sets an absolute deadline on the first request of the
+	// session. Later requests report the status of the instance without
+	// extending the session.
+	SessionModeFixed SessionMode = "fixed"
+)
+
+// ParseSessionMode parses a session mode. The empty string is the default,
+// sliding mode.
+func ParseSessionMode(v string) (SessionMode, error) {
+	switch SessionMode(v) {
+	case "", SessionModeSliding:
+		return SessionModeSliding, nil
+	case SessionModeFixed:
+		return SessionModeFixed, nil
+	default:
+		return "", fmt.Errorf("unknown session mode %q, must be %q or %q", v, SessionModeSliding, SessionModeFixed)
+	}
+}
+
+type sessionModeKey struct{}
+
+// WithSessionMode returns a context requesting sessions in the given mode.
+func WithSessionMode(ctx context.Context, mode SessionMode) context.Context {
+	return context.WithValue(ctx, sessionModeKey{}, mode)
+}
+
+// fixedSession reports whether the session of instance has a fixed deadline,
+// because either the request or the sablier.session-mode label of the
+// instance asks for it. A request cannot relax the fixed mode of an instance.
+func fixedSession(ctx context.Context, instance InstanceInfo) bool {
+	if mode, _ := ctx.Value(sessionModeKey{}).(SessionMode); mode == SessionModeFixed {
+		return true
+	}
+	return instance.Config != nil && instance.Config.SessionMode == SessionModeFixed
+}
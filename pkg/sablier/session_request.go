@@ -6,7 +6,6 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -25,6 +24,10 @@ type InstanceInfoWithError struct {
 	// Optional marks a best-effort instance, requested with the "optional:"
 	// name prefix. Its readiness and errors are ignored by SessionState.
 	Optional bool `json:"optional,omitempty"`
+	// SessionDuration is the duration the session of the instance was given,
+	// after the instance default and the caps were applied. Zero when no
+	// session was opened.
+	SessionDuration time.Duration `json:"sessionDuration,omitempty"`
 }
 
 func (s *Sablier) RequestSession(ctx context.Context, names []string, duration time.Duration) (sessionState *SessionState, err error) {
@@ -44,19 +47,21 @@ func (s *Sablier) requestSession(ctx context.Context, names []string, duration t
 		Instances: map[string]InstanceInfoWithError{},
 	}
 
+	batch := s.newInspectBatch(names)
 	wg.Add(len(names))
 
 	for i := range names {
 		name, optional := strings.CutPrefix(names[i], OptionalPrefix)
 		go func(name string, optional bool) {
 			defer wg.Done()
-			state, err := s.instanceRequest(ctx, name, duration, rejectUnlabeled)
+			state, effective, err := s.instanceRequest(ctx, name, duration, rejectUnlabeled, batch)
 			mx.Lock()
 			defer mx.Unlock()
 			sessionState.Instances[name] = InstanceInfoWithError{
-				Instance: state,
-				Error:    err,
-				Optional: optional,
+				Instance:        state,
+				Error:           err,
+				Optional:        optional,
+				SessionDuration: effective,
 			}
 		}(name, optional)
 	}
@@ -106,66 +111,50 @@ func (s *Sablier) requestReadySession(ctx context.Context, names []string, durat
 		return nil, err
 	}
 
-	ticker := time.NewTicker(s.BlockingRefreshFrequency)
-	// Buffered capacity 1: the goroutine can complete its send even when the
-	// outer select has already chosen a different case (timeout, cancellation).
-	// Without the buffer the goroutine would block forever on the channel send.
-	readiness := make(chan *SessionState, 1)
-	errch := make(chan error, 1)
-	quit := make(chan struct{})
-
-	// last publishes the most recent not-ready session so the timeout branch can
-	// report which instances were still pending, and why. Each poll produces a
-	// fresh SessionState, so the loaded value is never mutated concurrently.
-	var last atomic.Pointer[SessionState]
-	last.Store(session)
-
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				session, err := s.requestSession(ctx, names, duration, rejectUnlabeled)
-				if err != nil {
-					errch <- err
-					return
-				}
-				if session.IsReady() {
-					readiness <- session
-					return
-				}
-				if err := session.InstanceErrors(); err != nil {
-					errch <- err
-					return
-				}
-				last.Store(session)
-			case <-quit:
-				ticker.Stop()
-				return
+	// The session is re-evaluated only when one of its instances changed, as
+	// seen by the readiness watcher shared with every other waiting request.
+	waiter, done := s.readiness.wait(session)
+	defer done()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		var grace <-chan time.Time
+		if deadline, ok := readyAfterDeadline(session); ok {
+			grace = time.After(time.Until(deadline))
+		}
+		// A queued instance is requested again periodically to keep its place
+		// in the queue.
+		var queued <-chan time.Time
+		if hasQueuedInstances(session) {
+			queued = time.After(s.BlockingRefreshFrequency)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.l.DebugContext(ctx, "request cancelled", slog.Any("reason", ctx.Err()))
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("request cancelled by user: %w", ctx.Err())
 			}
+			return nil, fmt.Errorf("request cancelled by user")
+		case <-timer.C:
+			return nil, ErrTimeout{Duration: timeout, Instances: session.NotReadyInstances()}
+		case <-waiter.C:
+		case <-grace:
+		case <-queued:
 		}
-	}()
-
-	select {
-	case <-ctx.Done():
-		s.l.DebugContext(ctx, "request cancelled", slog.Any("reason", ctx.Err()))
-		close(quit)
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request cancelled by user: %w", ctx.Err())
+
+		next, err := s.requestSession(ctx, names, duration, rejectUnlabeled)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("request cancelled by user")
-	case status := <-readiness:
-		close(quit)
-		return status, nil
-	case err := <-errch:
-		close(quit)
-		return nil, err
-	case <-time.After(timeout):
-		close(quit)
-		timeoutErr := ErrTimeout{Duration: timeout}
-		if ls := last.Load(); ls != nil {
-			timeoutErr.Instances = ls.NotReadyInstances()
+		if next.IsReady() {
+			return next, nil
+		}
+		if err := next.InstanceErrors(); err != nil {
+			return nil, err
 		}
-		return nil, timeoutErr
+		session = next
 	}
 }
 
@@ -195,6 +195,49 @@ func TestRequestSession_OptionalPrefix(t *testing.T) {
 	}
 }
 
+// The session duration defaults to and is capped by the instance labels,
+// and the duration each instance was given is reported in the session.
+func TestRequestSession_SessionDurationLabels(t *testing.T) {
+	manager, sessions, _ := setupSablier(t)
+	manager.MaxSessionDuration = 4 * time.Hour
+	ctx := t.Context()
+
+	instance := func(name string, labels map[string]string) sablier.InstanceInfo {
+		info := sablier.InstanceInfo{Name: name, CurrentReplicas: 1, DesiredReplicas: 1, Status: sablier.InstanceStatusReady}
+		sablier.PopulateEnabledAndGroup(&info, labels)
+		return info
+	}
+	defaulted := instance("defaulted", map[string]string{sablier.LabelEnable: "true", sablier.LabelSessionDuration: "30m"})
+	capped := instance("capped", map[string]string{sablier.LabelEnable: "true", sablier.LabelSessionDurationMax: "1h"})
+	unlabeled := instance("unlabeled", map[string]string{sablier.LabelEnable: "true"})
+
+	sessions.EXPECT().Get(ctx, "defaulted").Return(defaulted, nil).Times(2)
+	sessions.EXPECT().Get(ctx, "capped").Return(capped, nil).Times(2)
+	sessions.EXPECT().Get(ctx, "unlabeled").Return(unlabeled, nil).Times(2)
+
+	// Without a duration, the labels and then the server default apply.
+	sessions.EXPECT().Put(ctx, defaulted, 30*time.Minute).Return(nil)
+	sessions.EXPECT().Put(ctx, capped, 5*time.Minute).Return(nil)
+	sessions.EXPECT().Put(ctx, unlabeled, 5*time.Minute).Return(nil)
+	session, err := manager.RequestSession(ctx, []string{"defaulted", "capped", "unlabeled"}, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, session.Instances["defaulted"].SessionDuration, 30*time.Minute)
+	assert.Equal(t, session.Instances["capped"].SessionDuration, 5*time.Minute)
+	assert.Equal(t, session.Instances["unlabeled"].SessionDuration, 5*time.Minute)
+	assert.Equal(t, session.SessionDuration(), 5*time.Minute)
+
+	// A long request is capped by the instance max, then the server max.
+	sessions.EXPECT().Put(ctx, defaulted, 4*time.Hour).Return(nil)
+	sessions.EXPECT().Put(ctx, capped, time.Hour).Return(nil)
+	sessions.EXPECT().Put(ctx, unlabeled, 4*time.Hour).Return(nil)
+	session, err = manager.RequestSession(ctx, []string{"defaulted", "capped", "unlabeled"}, 1000*time.Hour)
+	assert.NilError(t, err)
+	assert.Equal(t, session.Instances["defaulted"].SessionDuration, 4*time.Hour)
+	assert.Equal(t, session.Instances["capped"].SessionDuration, time.Hour)
+	assert.Equal(t, session.Instances["unlabeled"].SessionDuration, 4*time.Hour)
+	assert.Equal(t, session.SessionDuration(), time.Hour)
+}
+
 func TestRequestSessionGroup_DoesNotRejectUnlabeledInstances(t *testing.T) {
 	manager, sessions, provider := setupSablier(t)
 	manager.WithRejectUnlabeledRequests(true)
@@ -363,3 +406,91 @@ func TestSessionsManager_RequestReadySession(t *testing.T) {
 		assert.NilError(t, <-errchan)
 	})
 }
+
+// A fixed session gets a deadline on its first request; later requests only
+// refresh the state and report the remaining time, until the deadline passes.
+func TestRequestSession_FixedMode(t *testing.T) {
+	ready := func(labels map[string]string) sablier.InstanceInfo {
+		info := sablier.InstanceInfo{Name: "preview", CurrentReplicas: 1, DesiredReplicas: 1, Status: sablier.InstanceStatusReady}
+		sablier.PopulateEnabledAndGroup(&info, labels)
+		return info
+	}
+	withDeadline := func(info sablier.InstanceInfo, in time.Duration) sablier.InstanceInfo {
+		deadline := time.Now().Add(in)
+		info.SessionDeadline = &deadline
+		return info
+	}
+
+	t.Run("the first request sets the deadline", func(t *testing.T) {
+		manager, sessions, _ := setupSablier(t)
+		ctx := sablier.WithSessionMode(t.Context(), sablier.SessionModeFixed)
+
+		sessions.EXPECT().Get(ctx, "preview").Return(ready(nil), nil)
+		sessions.EXPECT().Put(ctx, gomock.Any(), time.Hour).DoAndReturn(func(_ context.Context, info sablier.InstanceInfo, _ time.Duration) error {
+			assert.Assert(t, info.SessionDeadline != nil)
+			assert.Assert(t, time.Until(*info.SessionDeadline) > 59*time.Minute)
+			return nil
+		})
+
+		session, err := manager.RequestSession(ctx, []string{"preview"}, time.Hour)
+		assert.NilError(t, err)
+		assert.Equal(t, session.Instances["preview"].SessionDuration, time.Hour)
+	})
+	t.Run("the label sets the deadline whatever the request asks for", func(t *testing.T) {
+		manager, sessions, _ := setupSablier(t)
+		ctx := sablier.WithSessionMode(t.Context(), sablier.SessionModeSliding)
+
+		sessions.EXPECT().Get(ctx, "preview").Return(ready(map[string]string{sablier.LabelSessionMode: "fixed"}), nil)
+		sessions.EXPECT().Put(ctx, gomock.Any(), time.Hour).DoAndReturn(func(_ context.Context, info sablier.InstanceInfo, _ time.Duration) error {
+			assert.Assert(t, info.SessionDeadline != nil)
+			return nil
+		})
+
+		_, err := manager.RequestSession(ctx, []string{"preview"}, time.Hour)
+		assert.NilError(t, err)
+	})
+	t.Run("later requests do not extend the session", func(t *testing.T) {
+		manager, sessions, _ := setupSablier(t)
+		ctx := t.Context()
+		info := withDeadline(ready(nil), 10*time.Minute)
+
+		sessions.EXPECT().Get(ctx, "preview").Return(info, nil)
+		sessions.EXPECT().Put(ctx, info, gomock.Any()).DoAndReturn(func(_ context.Context, _ sablier.InstanceInfo, ttl time.Duration) error {
+			assert.Assert(t, ttl <= 10*time.Minute && ttl > 9*time.Minute, "got %s", ttl)
+			return nil
+		})
+
+		session, err := manager.RequestSession(ctx, []string{"preview"}, time.Hour)
+		assert.NilError(t, err)
+		remaining := session.Instances["preview"].SessionDuration
+		assert.Assert(t, remaining <= 10*time.Minute && remaining > 9*time.Minute, "got %s", remaining)
+	})
+	t.Run("requests during startup do not extend the session", func(t *testing.T) {
+		manager, sessions, provider := setupSablier(t)
+		ctx := t.Context()
+		starting := withDeadline(sablier.InstanceInfo{Name: "preview", DesiredReplicas: 1, Status: sablier.InstanceStatusStarting}, 10*time.Minute)
+
+		sessions.EXPECT().Get(ctx, "preview").Return(starting, nil)
+		provider.EXPECT().InstanceInspect(gomock.Any(), "preview").Return(sablier.InstanceInfo{Name: "preview", DesiredReplicas: 1, Status: sablier.InstanceStatusStarting}, nil)
+		sessions.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, info sablier.InstanceInfo, ttl time.Duration) error {
+			assert.DeepEqual(t, info.SessionDeadline, starting.SessionDeadline)
+			assert.Assert(t, ttl <= 10*time.Minute && ttl > 9*time.Minute, "got %s", ttl)
+			return nil
+		})
+
+		session, err := manager.RequestSession(ctx, []string{"preview"}, time.Hour)
+		assert.NilError(t, err)
+		remaining := session.Instances["preview"].SessionDuration
+		assert.Assert(t, remaining <= 10*time.Minute && remaining > 9*time.Minute, "got %s", remaining)
+	})
+	t.Run("requests past the deadline leave the session to expire", func(t *testing.T) {
+		manager, sessions, _ := setupSablier(t)
+		ctx := t.Context()
+
+		sessions.EXPECT().Get(ctx, "preview").Return(withDeadline(ready(nil), -time.Second), nil)
+
+		session, err := manager.RequestSession(ctx, []string{"preview"}, time.Hour)
+		assert.NilError(t, err)
+		assert.Equal(t, session.Instances["preview"].SessionDuration, time.Duration(0))
+	})
+}
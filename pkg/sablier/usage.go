@@ -0,0 +1,126 @@
+package sablier
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// InstanceUsage sums up the recorded lifecycle of an instance over a period.
+type InstanceUsage struct {
+	Name string
+	// Sessions is the number of sessions requested over the period, by
+	// strategy. Sessions not opened by a strategy request are counted under "".
+	Sessions map[string]int
+	// ColdStarts is the number of sessions requested while the instance was
+	// not ready.
+	ColdStarts int
+	// ColdStartTime is the total time the cold starts took to be ready.
+	ColdStartTime time.Duration
+	// Stops is the number of times Sablier stopped the instance, by reason.
+	Stops map[string]int
+	// Uptime is the time the instance spent between a session request and a
+	// stop.
+	Uptime time.Duration
+	// IdleSavings is the rest of the period, which the instance spent stopped.
+	IdleSavings time.Duration
+}
+
+// instanceUsage follows the lifecycle of one instance through the history.
+type instanceUsage struct {
+	InstanceUsage
+	// upSince is when the running session was requested.
+	upSince *time.Time
+	// coldSince is when the pending cold start was requested.
+	coldSince *time.Time
+}
+
+// Usage replays the recorded lifecycle of the sessions to report the usage of
+// every instance with an event before to, sorted by name. Sessions running
+// across the bounds of the period only count for the part within it, and the
+// ones still running at to count up to to.
+func (s *Sablier) Usage(ctx context.Context, from, to time.Time) ([]InstanceUsage, error) {
+	if s.history == nil {
+		return nil, ErrHistoryDisabled{}
+	}
+
+	instances := map[string]*instanceUsage{}
+	err := s.history.LifecycleEvents(ctx, to, func(e LifecycleEvent) {
+		u, ok := instances[e.Instance]
+		if !ok {
+			u = &instanceUsage{InstanceUsage: InstanceUsage{
+				Name:     e.Instance,
+				Sessions: map[string]int{},
+				Stops:    map[string]int{},
+			}}
+			instances[e.Instance] = u
+		}
+		u.replay(e, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]InstanceUsage, 0, len(instances))
+	for _, u := range instances {
+		if u.upSince != nil {
+			u.Uptime += overlap(*u.upSince, to, from, to)
+		}
+		u.IdleSavings = to.Sub(from) - u.Uptime
+		usages = append(usages, u.InstanceUsage)
+	}
+	slices.SortFunc(usages, func(a, b InstanceUsage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return usages, nil
+}
+
+func (u *instanceUsage) replay(e LifecycleEvent, from, to time.Time) {
+	inPeriod := !e.At.Before(from) && e.At.Before(to)
+	switch e.Kind {
+	case LifecycleRequested:
+		if inPeriod {
+			u.Sessions[e.Strategy]++
+		}
+		if e.ColdStart {
+			if inPeriod {
+				u.ColdStarts++
+			}
+			u.coldSince = &e.At
+		}
+		// A session requested while the instance runs, after a stop that was
+		// not recorded, does not restart the uptime.
+		if u.upSince == nil {
+			u.upSince = &e.At
+		}
+	case LifecycleReady:
+		if u.coldSince != nil && !u.coldSince.Before(from) {
+			u.ColdStartTime += e.At.Sub(*u.coldSince)
+		}
+		u.coldSince = nil
+	case LifecycleStopped:
+		if inPeriod {
+			u.Stops[e.Reason]++
+		}
+		if u.upSince != nil {
+			u.Uptime += overlap(*u.upSince, e.At, from, to)
+		}
+		u.upSince = nil
+		u.coldSince = nil
+	}
+}
+
+// overlap returns how long [start, end) and [from, to) overlap.
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
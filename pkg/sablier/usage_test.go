@@ -0,0 +1,139 @@
+package sablier_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// memoryHistory keeps the lifecycle events in recording order.
+type memoryHistory struct {
+	mu     sync.Mutex
+	events []sablier.LifecycleEvent
+}
+
+func (h *memoryHistory) RecordLifecycle(_ context.Context, e sablier.LifecycleEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, e)
+	return nil
+}
+
+func (h *memoryHistory) LifecycleEvents(_ context.Context, until time.Time, f func(sablier.LifecycleEvent)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.events {
+		if e.At.Before(until) {
+			f(e)
+		}
+	}
+	return nil
+}
+
+func (h *memoryHistory) snapshot() []sablier.LifecycleEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]sablier.LifecycleEvent(nil), h.events...)
+}
+
+func TestUsage_HistoryDisabled(t *testing.T) {
+	manager, _, _ := setupSablier(t)
+
+	_, err := manager.Usage(t.Context(), time.Now().Add(-time.Hour), time.Now())
+	assert.ErrorType(t, err, sablier.ErrHistoryDisabled{})
+}
+
+func TestUsage(t *testing.T) {
+	manager, _, _ := setupSablier(t)
+	h := &memoryHistory{}
+	manager.WithHistory(h)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	at := func(d time.Duration) time.Time { return from.Add(d) }
+	for _, e := range []sablier.LifecycleEvent{
+		// Started before the period: only the part within it counts.
+		{Instance: "nginx", Kind: sablier.LifecycleRequested, At: at(-time.Hour), Strategy: "dynamic", ColdStart: true},
+		{Instance: "nginx", Kind: sablier.LifecycleReady, At: at(-59 * time.Minute)},
+		{Instance: "nginx", Kind: sablier.LifecycleStopped, At: at(time.Hour), Reason: sablier.StopReasonExpired},
+		{Instance: "nginx", Kind: sablier.LifecycleRequested, At: at(2 * time.Hour), Strategy: "blocking", ColdStart: true},
+		{Instance: "nginx", Kind: sablier.LifecycleReady, At: at(2*time.Hour + 30*time.Second)},
+		{Instance: "nginx", Kind: sablier.LifecycleStopped, At: at(5 * time.Hour), Reason: sablier.StopReasonAntiAffinity},
+		// Still running at the end of the period.
+		{Instance: "whoami", Kind: sablier.LifecycleRequested, At: at(20 * time.Hour), Strategy: "dynamic"},
+		// Never started by Sablier.
+		{Instance: "redis", Kind: sablier.LifecycleStopped, At: at(3 * time.Hour), Reason: sablier.StopReasonExternallyStarted},
+	} {
+		assert.NilError(t, h.RecordLifecycle(t.Context(), e))
+	}
+
+	usages, err := manager.Usage(t.Context(), from, to)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(usages, 3))
+
+	nginx := usages[0]
+	assert.Equal(t, nginx.Name, "nginx")
+	assert.DeepEqual(t, nginx.Sessions, map[string]int{"blocking": 1})
+	assert.Equal(t, nginx.ColdStarts, 1)
+	assert.Equal(t, nginx.ColdStartTime, 30*time.Second)
+	assert.DeepEqual(t, nginx.Stops, map[string]int{sablier.StopReasonExpired: 1, sablier.StopReasonAntiAffinity: 1})
+	assert.Equal(t, nginx.Uptime, 4*time.Hour)
+	assert.Equal(t, nginx.IdleSavings, 20*time.Hour)
+
+	redis := usages[1]
+	assert.Equal(t, redis.Name, "redis")
+	assert.Equal(t, redis.Uptime, time.Duration(0))
+	assert.DeepEqual(t, redis.Stops, map[string]int{sablier.StopReasonExternallyStarted: 1})
+
+	whoami := usages[2]
+	assert.Equal(t, whoami.Name, "whoami")
+	assert.DeepEqual(t, whoami.Sessions, map[string]int{"dynamic": 1})
+	assert.Equal(t, whoami.ColdStarts, 0)
+	assert.Equal(t, whoami.Uptime, 4*time.Hour)
+	assert.Equal(t, whoami.IdleSavings, 20*time.Hour)
+}
+
+func TestInstanceRequest_RecordsLifecycle(t *testing.T) {
+	manager, sessions, provider := setupSablier(t)
+	h := &memoryHistory{}
+	manager.WithHistory(h)
+	ctx := sablier.WithStrategy(t.Context(), "dynamic")
+
+	stopped := sablier.InstanceInfo{Name: "nginx", CurrentReplicas: 0, DesiredReplicas: 1, Status: sablier.InstanceStatusStopped}
+	ready := sablier.InstanceInfo{Name: "nginx", CurrentReplicas: 1, DesiredReplicas: 1, Status: sablier.InstanceStatusReady}
+	starting := stopped
+	starting.Status = sablier.InstanceStatusStarting
+
+	gomock.InOrder(
+		sessions.EXPECT().Get(ctx, "nginx").Return(sablier.InstanceInfo{}, store.ErrKeyNotFound),
+		sessions.EXPECT().Get(ctx, "nginx").Return(starting, nil).AnyTimes(),
+	)
+	gomock.InOrder(
+		provider.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(stopped, nil),
+		provider.EXPECT().InstanceInspect(gomock.Any(), "nginx").Return(ready, nil).AnyTimes(),
+	)
+	provider.EXPECT().InstanceStart(gomock.Any(), "nginx").Return(nil)
+	sessions.EXPECT().Put(ctx, gomock.Any(), time.Minute).Return(nil).AnyTimes()
+
+	_, err := manager.InstanceRequest(ctx, "nginx", time.Minute)
+	assert.NilError(t, err)
+	// The next requests see the instance ready once the start completed.
+	assert.Assert(t, checkWithTimeout(10*time.Millisecond, 2*time.Second, func() bool {
+		_, err := manager.InstanceRequest(ctx, "nginx", time.Minute)
+		return err == nil && len(h.snapshot()) == 2
+	}))
+
+	events := h.snapshot()
+	assert.Assert(t, is.Len(events, 2))
+	assert.Equal(t, events[0].Kind, sablier.LifecycleRequested)
+	assert.Equal(t, events[0].Strategy, "dynamic")
+	assert.Assert(t, events[0].ColdStart)
+	assert.Equal(t, events[1].Kind, sablier.LifecycleReady)
+}
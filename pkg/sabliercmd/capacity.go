@@ -0,0 +1,21 @@
+package sabliercmd
+
+import (
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// newCapacity builds the capacity of the capacity configuration section.
+func newCapacity(conf config.Capacity) sablier.Capacity {
+	c := sablier.Capacity{
+		MaxActive: conf.MaxActive,
+		Queue:     conf.Policy == config.CapacityPolicyQueue,
+	}
+	if len(conf.Groups) > 0 {
+		c.Groups = make(map[string]int, len(conf.Groups))
+		for _, g := range conf.Groups {
+			c.Groups[g.Group] = g.MaxActive
+		}
+	}
+	return c
+}
@@ -0,0 +1,18 @@
+package sabliercmd
+
+import (
+	"testing"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewCapacity(t *testing.T) {
+	assert.DeepEqual(t, sablier.Capacity{}, newCapacity(config.NewCapacityConfig()))
+	assert.DeepEqual(t, sablier.Capacity{MaxActive: 3, Groups: map[string]int{"gpu": 1}, Queue: true}, newCapacity(config.Capacity{
+		MaxActive: 3,
+		Policy:    config.CapacityPolicyQueue,
+		Groups:    []config.GroupCapacity{{Group: "gpu", MaxActive: 1}},
+	}))
+}
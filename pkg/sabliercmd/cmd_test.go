@@ -137,9 +137,31 @@ func TestPrecedence(t *testing.T) {
 			"--provider.docker.strategy", "pause",
 			"--server.port", "3333",
 			"--server.base-path", "/cli/",
+			"--server.socket", "/run/sablier/cli.sock",
+			"--server.tls.client-ca-file", "/etc/sablier/cli-ca.crt",
 			"--storage.file", "/tmp/cli.json",
+			"--storage.flush-interval", "3h",
+			"--storage.flush-delay", "3h",
+			"--storage.valkey.key-prefix", "cli:",
+			"--storage.kubernetes.namespace", "cli",
+			"--storage.kubernetes.name-prefix", "cli-",
+			"--storage.database.path", "/tmp/cli.db",
+			"--storage.database.history-retention", "3h",
+			"--leader-election.backend", "kubernetes",
+			"--leader-election.identity", "cli",
+			"--leader-election.lease-duration", "3m",
+			"--leader-election.renew-deadline", "90s",
+			"--leader-election.retry-period", "30s",
+			"--leader-election.kubernetes.namespace", "cli",
+			"--leader-election.kubernetes.name", "cli",
+			"--leader-election.valkey.key", "cli:leader",
 			"--sessions.default-duration", "3h",
 			"--sessions.expiration-interval", "3h",
+			"--sessions.max-duration", "4h",
+			"--running-hours.holidays-file", "/tmp/cli.ics",
+			"--capacity.max-active", "8",
+			"--capacity.policy", "evict",
+			"--reload.interval", "3m",
 			"--logging.level", "info",
 			"--strategy.dynamic.custom-themes-path", "/tmp/cli/themes",
 			// Must use `=` see https://github.com/spf13/cobra/issues/613
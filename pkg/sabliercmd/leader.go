@@ -0,0 +1,50 @@
+package sabliercmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/leader"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// setupLeaderElection creates the elector competing for the configured lock.
+// It does not start competing: see leader.Election.Run.
+func setupLeaderElection(ctx context.Context, logger *slog.Logger, conf config.LeaderElection, storage config.Storage) (leader.Election, error) {
+	identity := conf.ReplicaIdentity()
+	logger.InfoContext(ctx, "leader election enabled",
+		slog.String("backend", conf.Backend),
+		slog.String("identity", identity),
+		slog.Duration("lease_duration", conf.LeaseDuration),
+	)
+
+	switch conf.Backend {
+	case config.LeaderElectionBackendKubernetes:
+		cli, err := newInClusterClient()
+		if err != nil {
+			return nil, err
+		}
+		return leader.NewLeaseElector(logger, cli, conf.Kubernetes.LeaseNamespace(), conf.Kubernetes.Name, identity, conf)
+	case config.LeaderElectionBackendValkey:
+		client, err := newValkeyClient(storage.Valkey)
+		if err != nil {
+			return nil, err
+		}
+		lock := &leader.ValkeyLock{Client: client, Key: conf.Valkey.Key}
+		return leader.New(logger, lock, identity, conf), nil
+	default:
+		return nil, fmt.Errorf("unrecognized leader election backend %s", conf.Backend)
+	}
+}
+
+// newInClusterClient connects to the API server of the cluster Sablier runs in.
+func newInClusterClient() (k8s.Interface, error) {
+	kubeclientConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return k8s.NewForConfig(kubeclientConfig)
+}
@@ -0,0 +1,178 @@
+package sabliercmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/spf13/viper"
+)
+
+// newPolicies builds the policies of the groups and policies configuration
+// sections. It returns nil when both are empty.
+func newPolicies(groups []config.Group, policies []config.Policy) (*sablier.Policies, error) {
+	if err := config.ValidateGroups(groups); err != nil {
+		return nil, err
+	}
+	if err := config.ValidatePolicies(policies); err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 && len(policies) == 0 {
+		return nil, nil
+	}
+
+	p := &sablier.Policies{}
+	for i, g := range groups {
+		sel, err := newInstanceSelector(g.Match)
+		if err != nil {
+			return nil, fmt.Errorf("groups[%d]: %w", i, err)
+		}
+		p.Groups = append(p.Groups, sablier.GroupPolicy{Group: g.Name, Selector: sel})
+	}
+	for i, policy := range policies {
+		built, err := newPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("policies[%d]: %w", i, err)
+		}
+		if built.Name == "" {
+			built.Name = fmt.Sprintf("policies[%d]", i)
+		}
+		p.Policies = append(p.Policies, built)
+	}
+	return p, nil
+}
+
+func newInstanceSelector(m config.InstanceMatch) (sablier.InstanceSelector, error) {
+	return sablier.ParseInstanceSelector(m.Names, m.Regex, m.Labels)
+}
+
+// newPolicy translates the settings of a policy into the sablier.* labels it
+// stands for, validating them the way the labels are.
+func newPolicy(policy config.Policy) (sablier.Policy, error) {
+	sel, err := newInstanceSelector(policy.Match)
+	if err != nil {
+		return sablier.Policy{}, err
+	}
+
+	labels := map[string]string{}
+	if policy.SessionDuration > 0 {
+		labels[sablier.LabelSessionDuration] = policy.SessionDuration.String()
+	}
+	if policy.RunningHours != "" {
+		if _, err := sablier.ParseRunningHours(policy.RunningHours); err != nil {
+			return sablier.Policy{}, fmt.Errorf("invalid running-hours: %w", err)
+		}
+		labels[sablier.LabelRunningHours] = policy.RunningHours
+	}
+	if policy.RunningDays != "" {
+		if _, err := sablier.ParseRunningDays(policy.RunningDays); err != nil {
+			return sablier.Policy{}, fmt.Errorf("invalid running-days: %w", err)
+		}
+		labels[sablier.LabelRunningDays] = policy.RunningDays
+	}
+	if policy.Schedule != "" {
+		if _, err := sablier.ParseSchedule(policy.Schedule); err != nil {
+			return sablier.Policy{}, fmt.Errorf("invalid schedule: %w", err)
+		}
+		labels[sablier.LabelSchedule] = policy.Schedule
+	}
+	if policy.Timezone != "" {
+		if _, err := time.LoadLocation(policy.Timezone); err != nil {
+			return sablier.Policy{}, fmt.Errorf("invalid timezone: %w", err)
+		}
+		labels[sablier.LabelTimezone] = policy.Timezone
+	}
+	setResourceLabels(labels, policy.Scale.Idle, sablier.LabelIdleReplicas, sablier.LabelIdleCPU, sablier.LabelIdleMemory)
+	setResourceLabels(labels, policy.Scale.Active, sablier.LabelActiveReplicas, sablier.LabelActiveCPU, sablier.LabelActiveMemory)
+
+	var deps []sablier.InstanceDependency
+	for _, dep := range policy.DependsOn {
+		name, condition, _ := strings.Cut(dep, ":")
+		if condition == "" {
+			condition = "service_started"
+		}
+		deps = append(deps, sablier.InstanceDependency{Name: strings.TrimSpace(name), Condition: strings.TrimSpace(condition)})
+	}
+
+	return sablier.Policy{
+		Name:      policy.Name,
+		Selector:  sel,
+		Groups:    policy.Groups,
+		Labels:    labels,
+		DependsOn: deps,
+	}, nil
+}
+
+func setResourceLabels(labels map[string]string, r config.PolicyResources, replicas, cpu, memory string) {
+	if r.Replicas > 0 {
+		labels[replicas] = strconv.Itoa(r.Replicas)
+	}
+	if r.CPU != "" {
+		labels[cpu] = r.CPU
+	}
+	if r.Memory != "" {
+		labels[memory] = r.Memory
+	}
+}
+
+// readPolicies reads the groups and policies sections of the configuration
+// file at path.
+func readPolicies(path string) (*sablier.Policies, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	var groups []config.Group
+	if err := v.UnmarshalKey("groups", &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse groups configuration: %w", err)
+	}
+	var policies []config.Policy
+	if err := v.UnmarshalKey("policies", &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse policies configuration: %w", err)
+	}
+	return newPolicies(groups, policies)
+}
+
+// watchPolicies checks the configuration file at path for changes every
+// interval and reloads its groups and policies, until ctx is done. A file
+// that cannot be read or holds invalid groups or policies keeps the previous
+// ones in effect.
+func watchPolicies(ctx context.Context, logger *slog.Logger, s *sablier.Sablier, path string, interval time.Duration) {
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// os.Stat follows symlinks, so ConfigMap volumes, which swap a
+		// symlinked directory on update, are seen as changed too.
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue
+		}
+		modTime = info.ModTime()
+
+		policies, err := readPolicies(path)
+		if err != nil {
+			logger.WarnContext(ctx, "cannot reload the groups and policies, keeping the previous ones", slog.String("path", path), slog.Any("error", err))
+			continue
+		}
+		s.ReloadPolicies(ctx, policies)
+		logger.InfoContext(ctx, "groups and policies reloaded", slog.String("path", path))
+	}
+}
@@ -0,0 +1,109 @@
+package sabliercmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewPolicies(t *testing.T) {
+	p, err := newPolicies(nil, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, p == nil)
+
+	p, err = newPolicies(
+		[]config.Group{{Name: "previews", Match: config.InstanceMatch{Names: []string{"preview-*"}}}},
+		[]config.Policy{
+			{
+				Name:            "ci",
+				Match:           config.InstanceMatch{Labels: "team=ci"},
+				Groups:          []string{"ci"},
+				SessionDuration: 30 * time.Minute,
+				DependsOn:       []string{"db:service_healthy", "cache"},
+				RunningHours:    "08:00-18:00",
+				RunningDays:     "mon,fri",
+				Schedule:        "0 2 * * sun 2h",
+				Timezone:        "Europe/Paris",
+				Scale: config.PolicyScale{
+					Idle:   config.PolicyResources{CPU: "0.1", Memory: "64m"},
+					Active: config.PolicyResources{Replicas: 2},
+				},
+			},
+			{Match: config.InstanceMatch{Regex: "^web$"}},
+		},
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, len(p.Groups), 1)
+	assert.Equal(t, p.Groups[0].Group, "previews")
+	assert.Equal(t, len(p.Policies), 2)
+
+	ci := p.Policies[0]
+	assert.DeepEqual(t, ci.Labels, map[string]string{
+		sablier.LabelSessionDuration: "30m0s",
+		sablier.LabelRunningHours:    "08:00-18:00",
+		sablier.LabelRunningDays:     "mon,fri",
+		sablier.LabelSchedule:        "0 2 * * sun 2h",
+		sablier.LabelTimezone:        "Europe/Paris",
+		sablier.LabelIdleCPU:         "0.1",
+		sablier.LabelIdleMemory:      "64m",
+		sablier.LabelActiveReplicas:  "2",
+	})
+	assert.DeepEqual(t, ci.DependsOn, []sablier.InstanceDependency{
+		{Name: "db", Condition: "service_healthy"},
+		{Name: "cache", Condition: "service_started"},
+	})
+	assert.Equal(t, p.Policies[1].Name, "policies[1]")
+}
+
+func TestNewPolicies_Invalid(t *testing.T) {
+	match := config.InstanceMatch{Names: []string{"web"}}
+	tests := []struct {
+		name    string
+		policy  config.Policy
+		wantErr string
+	}{
+		{name: "running hours", policy: config.Policy{Match: match, RunningHours: "9am-6pm"}, wantErr: "policies[0]: invalid running-hours"},
+		{name: "running days", policy: config.Policy{Match: match, RunningDays: "someday"}, wantErr: "policies[0]: invalid running-days"},
+		{name: "schedule", policy: config.Policy{Match: match, Schedule: "every day"}, wantErr: "policies[0]: invalid schedule"},
+		{name: "timezone", policy: config.Policy{Match: match, Timezone: "Mars/Olympus_Mons"}, wantErr: "policies[0]: invalid timezone"},
+		{name: "match", policy: config.Policy{}, wantErr: "policies[0]: match must set names, regex or labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPolicies(nil, []config.Policy{tt.policy})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReadPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sablier.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte(`
+groups:
+  - name: previews
+    match:
+      names: [preview-*]
+policies:
+  - name: short sessions
+    match:
+      labels: env=preview
+    session-duration: 5m
+    depends-on: [db]
+`), 0o600))
+
+	p, err := readPolicies(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(p.Groups), 1)
+	assert.Equal(t, len(p.Policies), 1)
+	assert.Equal(t, p.Policies[0].Labels[sablier.LabelSessionDuration], "5m0s")
+	assert.DeepEqual(t, p.Policies[0].DependsOn, []sablier.InstanceDependency{{Name: "db", Condition: "service_started"}})
+
+	assert.NilError(t, os.WriteFile(path, []byte("policies:\n  - name: broken\n"), 0o600))
+	_, err = readPolicies(path)
+	assert.ErrorContains(t, err, "policies[0]: match must set names, regex or labels")
+}
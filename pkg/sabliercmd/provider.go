@@ -24,7 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provider) (sablier.Provider, error) {
+func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provider, policies *sablier.PolicySet) (sablier.Provider, error) {
 	if err := config.IsValid(); err != nil {
 		return nil, err
 	}
@@ -37,7 +37,12 @@ func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provi
 		if err != nil {
 			return nil, fmt.Errorf("cannot create docker swarm client: %v", err)
 		}
-		return dockerswarm.New(ctx, cli, logger)
+		p, err := dockerswarm.New(ctx, cli, logger)
+		if err != nil {
+			return nil, err
+		}
+		p.Policies = policies
+		return p, nil
 	case "docker":
 		// The Docker client is configured from the standard Docker environment
 		// variables (DOCKER_HOST, DOCKER_API_VERSION, DOCKER_CERT_PATH,
@@ -53,6 +58,7 @@ func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provi
 		}
 		//nolint:staticcheck // Intentionally wiring the deprecated transitional flag until it becomes the default in v2.
 		p.HonorRestartPolicy = config.Docker.HonorRestartPolicy
+		p.Policies = policies
 		return p, nil
 	case "kubernetes":
 		kubeclientConfig, err := rest.InClusterConfig()
@@ -77,7 +83,12 @@ func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provi
 		if err != nil {
 			return nil, err
 		}
-		return kubernetes.New(ctx, cli, dynamicCli, logger, config.Kubernetes)
+		p, err := kubernetes.New(ctx, cli, dynamicCli, logger, config.Kubernetes)
+		if err != nil {
+			return nil, err
+		}
+		p.Policies = policies
+		return p, nil
 	case "podman":
 		opts := []client.Opt{client.FromEnv}
 		if config.Podman.Uri != "" {
@@ -88,7 +99,12 @@ func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provi
 		if err != nil {
 			return nil, fmt.Errorf("cannot create podman client: %w", err)
 		}
-		return podman.New(ctx, cli, logger)
+		p, err := podman.New(ctx, cli, logger)
+		if err != nil {
+			return nil, err
+		}
+		p.Policies = policies
+		return p, nil
 	case "proxmox_lxc":
 		opts := []proxmox.Option{
 			proxmox.WithAPIToken(config.ProxmoxLXC.TokenID, config.ProxmoxLXC.TokenSecret),
@@ -105,7 +121,12 @@ func setupProvider(ctx context.Context, logger *slog.Logger, config config.Provi
 			Transport: otelhttp.NewTransport(baseTransport),
 		}))
 		cli := proxmox.NewClient(config.ProxmoxLXC.URL, opts...)
-		return proxmoxlxc.New(ctx, cli, logger)
+		p, err := proxmoxlxc.New(ctx, cli, logger)
+		if err != nil {
+			return nil, err
+		}
+		p.Policies = policies
+		return p, nil
 	}
 	return nil, fmt.Errorf("unimplemented provider %s", config.Name)
 }
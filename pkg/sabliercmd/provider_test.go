@@ -11,7 +11,7 @@ import (
 
 func TestSetupProviderInvalidConfig(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	if _, err := setupProvider(context.Background(), logger, config.Provider{Name: ""}); err == nil {
+	if _, err := setupProvider(context.Background(), logger, config.Provider{Name: ""}, nil); err == nil {
 		t.Fatal("expected an error for an invalid provider configuration")
 	}
 }
@@ -21,6 +21,10 @@ const (
 var conf = config.NewConfig()
 var cfgFile string
 
+// configFileUsed is the configuration file that was read, if any. Its groups
+// and policies are reloaded when it changes.
+var configFileUsed string
+
 // NewRootCommand creates the root cobra command
 func NewRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -73,12 +77,40 @@ It provides integrations with multiple reverse proxies and different loading str
 	_ = viper.BindPFlag("provider.proxmox-lxc.token-secret", startCmd.Flags().Lookup("provider.proxmox-lxc.token-secret"))
 	startCmd.Flags().BoolVar(&conf.Provider.ProxmoxLXC.TLSInsecure, "provider.proxmox-lxc.tls-insecure", false, "Skip TLS certificate verification for Proxmox VE API")
 	_ = viper.BindPFlag("provider.proxmox-lxc.tls-insecure", startCmd.Flags().Lookup("provider.proxmox-lxc.tls-insecure"))
+	startCmd.Flags().DurationVar(&conf.Provider.InspectCache.TTL, "provider.inspect-cache.ttl", 0, "How long instance inspections are served from memory, invalidated by the provider events (0 to disable)")
+	_ = viper.BindPFlag("provider.inspect-cache.ttl", startCmd.Flags().Lookup("provider.inspect-cache.ttl"))
+	startCmd.Flags().DurationVar(&conf.Provider.InspectCache.UnreliableEventsTTL, "provider.inspect-cache.unreliable-events-ttl", 2*time.Second, "Cap on the inspect cache TTL when the provider events can miss changes")
+	_ = viper.BindPFlag("provider.inspect-cache.unreliable-events-ttl", startCmd.Flags().Lookup("provider.inspect-cache.unreliable-events-ttl"))
 
 	// Server flags
 	startCmd.Flags().IntVar(&conf.Server.Port, "server.port", 10000, "The server port to use")
 	_ = viper.BindPFlag("server.port", startCmd.Flags().Lookup("server.port"))
 	startCmd.Flags().StringVar(&conf.Server.BasePath, "server.base-path", "/", "The base path for the API")
 	_ = viper.BindPFlag("server.base-path", startCmd.Flags().Lookup("server.base-path"))
+	startCmd.Flags().StringVar(&conf.Server.Socket, "server.socket", "", "Listen on this Unix domain socket instead of the TCP port")
+	_ = viper.BindPFlag("server.socket", startCmd.Flags().Lookup("server.socket"))
+	startCmd.Flags().StringVar(&conf.Server.SocketMode, "server.socket-mode", "0660", "Octal file mode of the Unix domain socket")
+	_ = viper.BindPFlag("server.socket-mode", startCmd.Flags().Lookup("server.socket-mode"))
+	startCmd.Flags().StringVar(&conf.Server.TLS.CertFile, "server.tls.cert-file", "", "PEM certificate file; serves HTTPS when set (reloaded on change)")
+	_ = viper.BindPFlag("server.tls.cert-file", startCmd.Flags().Lookup("server.tls.cert-file"))
+	startCmd.Flags().StringVar(&conf.Server.TLS.KeyFile, "server.tls.key-file", "", "PEM private key file of the certificate (reloaded on change)")
+	_ = viper.BindPFlag("server.tls.key-file", startCmd.Flags().Lookup("server.tls.key-file"))
+	startCmd.Flags().StringVar(&conf.Server.TLS.MinVersion, "server.tls.min-version", "1.2", "Minimum TLS version accepted: 1.2 or 1.3")
+	_ = viper.BindPFlag("server.tls.min-version", startCmd.Flags().Lookup("server.tls.min-version"))
+	startCmd.Flags().StringVar(&conf.Server.TLS.ClientCAFile, "server.tls.client-ca-file", "", "PEM CA bundle used to verify client certificates")
+	_ = viper.BindPFlag("server.tls.client-ca-file", startCmd.Flags().Lookup("server.tls.client-ca-file"))
+	startCmd.Flags().StringVar(&conf.Server.TLS.ClientAuth, "server.tls.client-auth", "verify-if-given", "Client certificate verification when a client CA is set: verify-if-given or require")
+	_ = viper.BindPFlag("server.tls.client-auth", startCmd.Flags().Lookup("server.tls.client-auth"))
+	startCmd.Flags().IntVar(&conf.Server.Proxy.Port, "server.proxy.port", 0, "Serve the routing.routes upstreams through the built-in reverse proxy on this port (0 disables it)")
+	_ = viper.BindPFlag("server.proxy.port", startCmd.Flags().Lookup("server.proxy.port"))
+	startCmd.Flags().IntVar(&conf.Server.ExtAuthz.Port, "server.ext-authz.port", 0, "Serve the Envoy ext_authz gRPC API on this port (0 disables it)")
+	_ = viper.BindPFlag("server.ext-authz.port", startCmd.Flags().Lookup("server.ext-authz.port"))
+	startCmd.Flags().StringVar(&conf.Server.ExtAuthz.TLS.CertFile, "server.ext-authz.tls.cert-file", "", "PEM certificate file; serves the ext_authz gRPC API over TLS when set (reloaded on change)")
+	_ = viper.BindPFlag("server.ext-authz.tls.cert-file", startCmd.Flags().Lookup("server.ext-authz.tls.cert-file"))
+	startCmd.Flags().StringVar(&conf.Server.ExtAuthz.TLS.KeyFile, "server.ext-authz.tls.key-file", "", "PEM private key file of the ext_authz certificate (reloaded on change)")
+	_ = viper.BindPFlag("server.ext-authz.tls.key-file", startCmd.Flags().Lookup("server.ext-authz.tls.key-file"))
+	startCmd.Flags().StringVar(&conf.Server.ExtAuthz.TLS.ClientCAFile, "server.ext-authz.tls.client-ca-file", "", "PEM CA bundle the ext_authz clients must present a certificate signed by")
+	_ = viper.BindPFlag("server.ext-authz.tls.client-ca-file", startCmd.Flags().Lookup("server.ext-authz.tls.client-ca-file"))
 	startCmd.Flags().BoolVar(&conf.Server.Metrics.Enabled, "server.metrics.enabled", false, "Enable the Prometheus /metrics endpoint")
 	_ = viper.BindPFlag("server.metrics.enabled", startCmd.Flags().Lookup("server.metrics.enabled"))
 	// Tracing flags
@@ -95,11 +127,69 @@ It provides integrations with multiple reverse proxies and different loading str
 	// Storage flags
 	startCmd.Flags().StringVar(&conf.Storage.File, "storage.file", "", "File path to save the state")
 	_ = viper.BindPFlag("storage.file", startCmd.Flags().Lookup("storage.file"))
+	startCmd.Flags().DurationVar(&conf.Storage.FlushInterval, "storage.flush-interval", time.Minute, "How often the state file is rewritten regardless of changes (0 disables the periodic flush)")
+	_ = viper.BindPFlag("storage.flush-interval", startCmd.Flags().Lookup("storage.flush-interval"))
+	startCmd.Flags().DurationVar(&conf.Storage.FlushDelay, "storage.flush-delay", time.Second, "How long to wait after a session change before writing the state file, batching bursts of changes into one write")
+	_ = viper.BindPFlag("storage.flush-delay", startCmd.Flags().Lookup("storage.flush-delay"))
+	startCmd.Flags().StringSliceVar(&conf.Storage.Valkey.Addresses, "storage.valkey.addresses", []string{}, "Valkey node addresses (host:port). Setting any address stores sessions in Valkey instead of in memory")
+	_ = viper.BindPFlag("storage.valkey.addresses", startCmd.Flags().Lookup("storage.valkey.addresses"))
+	startCmd.Flags().StringVar(&conf.Storage.Valkey.Username, "storage.valkey.username", "", "Valkey ACL username")
+	_ = viper.BindPFlag("storage.valkey.username", startCmd.Flags().Lookup("storage.valkey.username"))
+	startCmd.Flags().StringVar(&conf.Storage.Valkey.Password, "storage.valkey.password", "", "Valkey password")
+	_ = viper.BindPFlag("storage.valkey.password", startCmd.Flags().Lookup("storage.valkey.password"))
+	startCmd.Flags().IntVar(&conf.Storage.Valkey.DB, "storage.valkey.db", 0, "Valkey logical database index")
+	_ = viper.BindPFlag("storage.valkey.db", startCmd.Flags().Lookup("storage.valkey.db"))
+	startCmd.Flags().BoolVar(&conf.Storage.Valkey.TLS, "storage.valkey.tls", false, "Connect to Valkey over TLS")
+	_ = viper.BindPFlag("storage.valkey.tls", startCmd.Flags().Lookup("storage.valkey.tls"))
+	startCmd.Flags().BoolVar(&conf.Storage.Valkey.TLSInsecure, "storage.valkey.tls-insecure", false, "Skip TLS certificate verification for Valkey")
+	_ = viper.BindPFlag("storage.valkey.tls-insecure", startCmd.Flags().Lookup("storage.valkey.tls-insecure"))
+	startCmd.Flags().StringVar(&conf.Storage.Valkey.KeyPrefix, "storage.valkey.key-prefix", "", "Prefix prepended to every session key stored in Valkey")
+	_ = viper.BindPFlag("storage.valkey.key-prefix", startCmd.Flags().Lookup("storage.valkey.key-prefix"))
+	startCmd.Flags().BoolVar(&conf.Storage.Kubernetes.Enabled, "storage.kubernetes.enabled", false, "Keep sessions in ConfigMaps of the Kubernetes cluster Sablier runs in")
+	_ = viper.BindPFlag("storage.kubernetes.enabled", startCmd.Flags().Lookup("storage.kubernetes.enabled"))
+	startCmd.Flags().StringVar(&conf.Storage.Kubernetes.Namespace, "storage.kubernetes.namespace", "", "Namespace of the session ConfigMaps, defaults to the namespace of the Sablier pod")
+	_ = viper.BindPFlag("storage.kubernetes.namespace", startCmd.Flags().Lookup("storage.kubernetes.namespace"))
+	startCmd.Flags().StringVar(&conf.Storage.Kubernetes.NamePrefix, "storage.kubernetes.name-prefix", "sablier-session-", "Prefix of the session ConfigMap names")
+	_ = viper.BindPFlag("storage.kubernetes.name-prefix", startCmd.Flags().Lookup("storage.kubernetes.name-prefix"))
+	startCmd.Flags().StringVar(&conf.Storage.Database.Path, "storage.database.path", "", "Database file keeping the sessions and the history of their lifecycle")
+	_ = viper.BindPFlag("storage.database.path", startCmd.Flags().Lookup("storage.database.path"))
+	startCmd.Flags().DurationVar(&conf.Storage.Database.HistoryRetention, "storage.database.history-retention", 30*24*time.Hour, "How long the session history is kept, 0 keeps it forever")
+	_ = viper.BindPFlag("storage.database.history-retention", startCmd.Flags().Lookup("storage.database.history-retention"))
 	// Sessions flags
 	startCmd.Flags().DurationVar(&conf.Sessions.DefaultDuration, "sessions.default-duration", time.Duration(5)*time.Minute, "The default session duration")
 	_ = viper.BindPFlag("sessions.default-duration", startCmd.Flags().Lookup("sessions.default-duration"))
 	startCmd.Flags().DurationVar(&conf.Sessions.ExpirationInterval, "sessions.expiration-interval", time.Duration(20)*time.Second, "The expiration checking interval. Higher duration gives less stress on CPU. If you only use sessions of 1h, setting this to 5m is a good trade-off.")
 	_ = viper.BindPFlag("sessions.expiration-interval", startCmd.Flags().Lookup("sessions.expiration-interval"))
+	startCmd.Flags().DurationVar(&conf.Sessions.MaxDuration, "sessions.max-duration", 0, "The maximum session duration, whatever the request or the sablier.session-duration label asks for. 0 means no maximum.")
+	_ = viper.BindPFlag("sessions.max-duration", startCmd.Flags().Lookup("sessions.max-duration"))
+	startCmd.Flags().StringVar(&conf.RunningHours.HolidaysFile, "running-hours.holidays-file", "", "iCalendar (.ics) file of dates on which no running-hours or schedule window opens (reloaded on change)")
+	_ = viper.BindPFlag("running-hours.holidays-file", startCmd.Flags().Lookup("running-hours.holidays-file"))
+	startCmd.Flags().IntVar(&conf.Capacity.MaxActive, "capacity.max-active", 0, "The maximum number of instances active at once. 0 means no limit.")
+	_ = viper.BindPFlag("capacity.max-active", startCmd.Flags().Lookup("capacity.max-active"))
+	startCmd.Flags().StringVar(&conf.Capacity.Policy, "capacity.policy", config.CapacityPolicyEvict, "What a request over capacity does. Can be one of [evict, queue]")
+	_ = viper.BindPFlag("capacity.policy", startCmd.Flags().Lookup("capacity.policy"))
+	startCmd.Flags().DurationVar(&conf.Reload.Interval, "reload.interval", 10*time.Second, "How often the configuration file is checked for changes to its groups and policies. 0 disables the reloading.")
+	_ = viper.BindPFlag("reload.interval", startCmd.Flags().Lookup("reload.interval"))
+
+	// leader election
+	startCmd.Flags().BoolVar(&conf.LeaderElection.Enabled, "leader-election.enabled", false, "Elect a leader among the Sablier replicas sharing the storage to run the background loops")
+	_ = viper.BindPFlag("leader-election.enabled", startCmd.Flags().Lookup("leader-election.enabled"))
+	startCmd.Flags().StringVar(&conf.LeaderElection.Backend, "leader-election.backend", config.LeaderElectionBackendKubernetes, "Where the leader lock is kept. Can be one of [kubernetes, valkey]")
+	_ = viper.BindPFlag("leader-election.backend", startCmd.Flags().Lookup("leader-election.backend"))
+	startCmd.Flags().StringVar(&conf.LeaderElection.Identity, "leader-election.identity", "", "Identity of this replica in the leader lock, defaults to the hostname")
+	_ = viper.BindPFlag("leader-election.identity", startCmd.Flags().Lookup("leader-election.identity"))
+	startCmd.Flags().DurationVar(&conf.LeaderElection.LeaseDuration, "leader-election.lease-duration", 15*time.Second, "How long the leader lock stays valid without being renewed")
+	_ = viper.BindPFlag("leader-election.lease-duration", startCmd.Flags().Lookup("leader-election.lease-duration"))
+	startCmd.Flags().DurationVar(&conf.LeaderElection.RenewDeadline, "leader-election.renew-deadline", 10*time.Second, "How long the leader keeps leading without renewing the lock")
+	_ = viper.BindPFlag("leader-election.renew-deadline", startCmd.Flags().Lookup("leader-election.renew-deadline"))
+	startCmd.Flags().DurationVar(&conf.LeaderElection.RetryPeriod, "leader-election.retry-period", 2*time.Second, "How often the leader lock is renewed or tried")
+	_ = viper.BindPFlag("leader-election.retry-period", startCmd.Flags().Lookup("leader-election.retry-period"))
+	startCmd.Flags().StringVar(&conf.LeaderElection.Kubernetes.Namespace, "leader-election.kubernetes.namespace", "", "Namespace of the leader Lease, defaults to the namespace of the Sablier pod")
+	_ = viper.BindPFlag("leader-election.kubernetes.namespace", startCmd.Flags().Lookup("leader-election.kubernetes.namespace"))
+	startCmd.Flags().StringVar(&conf.LeaderElection.Kubernetes.Name, "leader-election.kubernetes.name", "sablier", "Name of the leader Lease")
+	_ = viper.BindPFlag("leader-election.kubernetes.name", startCmd.Flags().Lookup("leader-election.kubernetes.name"))
+	startCmd.Flags().StringVar(&conf.LeaderElection.Valkey.Key, "leader-election.valkey.key", "sablier:leader", "Valkey key holding the leader lock, not prefixed with storage.valkey.key-prefix")
+	_ = viper.BindPFlag("leader-election.valkey.key", startCmd.Flags().Lookup("leader-election.valkey.key"))
 
 	// logging level
 	rootCmd.PersistentFlags().StringVar(&conf.Logging.Level, "logging.level", strings.ToLower(slog.LevelInfo.String()), "The logging level. Can be one of [error, warn, info, debug]")
@@ -114,6 +204,8 @@ It provides integrations with multiple reverse proxies and different loading str
 	_ = viper.BindPFlag("strategy.dynamic.show-details-by-default", startCmd.Flags().Lookup("strategy.dynamic.show-details-by-default"))
 	startCmd.Flags().DurationVar(&conf.Strategy.Dynamic.DefaultRefreshFrequency, "strategy.dynamic.default-refresh-frequency", 5*time.Second, "Default refresh frequency in the HTML page for dynamic strategy")
 	_ = viper.BindPFlag("strategy.dynamic.default-refresh-frequency", startCmd.Flags().Lookup("strategy.dynamic.default-refresh-frequency"))
+	startCmd.Flags().StringVar(&conf.Strategy.Dynamic.PublicURL, "strategy.dynamic.public-url", "", "URL at which browsers reach Sablier, enables live updates on the waiting pages")
+	_ = viper.BindPFlag("strategy.dynamic.public-url", startCmd.Flags().Lookup("strategy.dynamic.public-url"))
 	startCmd.Flags().DurationVar(&conf.Strategy.Blocking.DefaultTimeout, "strategy.blocking.default-timeout", 1*time.Minute, "Default timeout used for blocking strategy")
 	_ = viper.BindPFlag("strategy.blocking.default-timeout", startCmd.Flags().Lookup("strategy.blocking.default-timeout"))
 	startCmd.Flags().DurationVar(&conf.Strategy.Blocking.DefaultRefreshFrequency, "strategy.blocking.default-refresh-frequency", 5*time.Second, "Default refresh frequency at which the instances status are checked for blocking strategy")
@@ -126,6 +218,12 @@ It provides integrations with multiple reverse proxies and different loading str
 	healthCmd.Flags().String("url", "http://localhost:10000/health", "Sablier health endpoint")
 	rootCmd.AddCommand(healthCmd)
 
+	usageCmd := NewUsageCmd()
+	usageCmd.Flags().String("url", "http://localhost:10000/api/usage", "Sablier usage endpoint")
+	usageCmd.Flags().String("token", "", "API token, when the API requires authentication")
+	usageCmd.Flags().Duration("since", 24*time.Hour, "Length of the period to report, ending now")
+	rootCmd.AddCommand(usageCmd)
+
 	return rootCmd
 }
 
@@ -170,6 +268,25 @@ func initializeConfig(cmd *cobra.Command) error {
 	if err := v.UnmarshalKey("webhooks", &conf.Webhooks); err != nil {
 		return fmt.Errorf("failed to parse webhooks configuration: %w", err)
 	}
+	if err := v.UnmarshalKey("server.auth", &conf.Server.Auth); err != nil {
+		return fmt.Errorf("failed to parse server.auth configuration: %w", err)
+	}
+	if err := v.UnmarshalKey("routing", &conf.Routing); err != nil {
+		return fmt.Errorf("failed to parse routing configuration: %w", err)
+	}
+	if err := v.UnmarshalKey("l4", &conf.L4); err != nil {
+		return fmt.Errorf("failed to parse l4 configuration: %w", err)
+	}
+	if err := v.UnmarshalKey("capacity.groups", &conf.Capacity.Groups); err != nil {
+		return fmt.Errorf("failed to parse capacity.groups configuration: %w", err)
+	}
+	if err := v.UnmarshalKey("groups", &conf.Groups); err != nil {
+		return fmt.Errorf("failed to parse groups configuration: %w", err)
+	}
+	if err := v.UnmarshalKey("policies", &conf.Policies); err != nil {
+		return fmt.Errorf("failed to parse policies configuration: %w", err)
+	}
+	configFileUsed = v.ConfigFileUsed()
 
 	return nil
 }
@@ -184,6 +301,18 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 		// Apply the viper config value to the flag when the flag is not set and viper has a value
 		if !f.Changed && v.IsSet(f.Name) {
 			val := v.Get(f.Name)
+			// A YAML list would be formatted as "[a b]" by %v; hand the items
+			// to slice flags one by one instead.
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				if items, ok := val.([]any); ok {
+					list := make([]string, 0, len(items))
+					for _, item := range items {
+						list = append(list, fmt.Sprintf("%v", item))
+					}
+					_ = sv.Replace(list)
+					return
+				}
+			}
 			_ = cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
 		}
 	})
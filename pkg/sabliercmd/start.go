@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"log/slog"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/sablierapp/sablier/internal/api"
+	"github.com/sablierapp/sablier/internal/l4"
 	"github.com/sablierapp/sablier/internal/server"
 	"github.com/sablierapp/sablier/pkg/config"
+	"github.com/sablierapp/sablier/pkg/leader"
 	"github.com/sablierapp/sablier/pkg/metrics"
 	provpkg "github.com/sablierapp/sablier/pkg/provider"
+	"github.com/sablierapp/sablier/pkg/provider/inspectcache"
 	"github.com/sablierapp/sablier/pkg/sablier"
 	"github.com/sablierapp/sablier/pkg/tracing"
 	"github.com/sablierapp/sablier/pkg/version"
@@ -44,6 +48,29 @@ func Start(ctx context.Context, conf config.Config) error {
 
 	logger.Info("running Sablier version " + version.Info())
 
+	if err := conf.Sessions.IsValid(); err != nil {
+		return fmt.Errorf("invalid sessions configuration: %w", err)
+	}
+	if err := conf.Routing.IsValid(); err != nil {
+		return fmt.Errorf("invalid routing configuration: %w", err)
+	}
+	if err := conf.L4.IsValid(); err != nil {
+		return fmt.Errorf("invalid l4 configuration: %w", err)
+	}
+	if err := conf.LeaderElection.IsValid(conf.Storage); err != nil {
+		return fmt.Errorf("invalid leader election configuration: %w", err)
+	}
+	if err := conf.Capacity.IsValid(); err != nil {
+		return fmt.Errorf("invalid capacity configuration: %w", err)
+	}
+	if err := conf.Reload.IsValid(); err != nil {
+		return fmt.Errorf("invalid reload configuration: %w", err)
+	}
+	policies, err := newPolicies(conf.Groups, conf.Policies)
+	if err != nil {
+		return fmt.Errorf("invalid groups or policies configuration: %w", err)
+	}
+
 	// Initialise OpenTelemetry tracing. The returned shutdown function flushes
 	// all in-flight spans; it must be called before the process exits.
 	tracingShutdown, err := tracing.Setup(ctx, conf.Tracing, logger)
@@ -66,18 +93,39 @@ func Start(ctx context.Context, conf config.Config) error {
 		)
 	}
 
-	provider, err := setupProvider(ctx, logger, conf.Provider)
+	// Policies apply to the instances inspected from now on: the provider
+	// gets them before the first group scan.
+	policySet := sablier.NewPolicySet(policies)
+	provider, err := setupProvider(ctx, logger, conf.Provider, policySet)
 	if err != nil {
 		return fmt.Errorf("cannot setup provider: %w", err)
 	}
 
 	rec := buildRecorder(conf.Server.Metrics.Enabled)
-	store, save := setupStorage(ctx, logger, conf)
+	if conf.Provider.InspectCache.TTL > 0 {
+		provider = inspectcache.New(ctx, logger, provider, conf.Provider.InspectCache, rec)
+	}
+	store, save, err := setupStorage(ctx, logger, conf)
+	if err != nil {
+		return fmt.Errorf("cannot setup storage: %w", err)
+	}
 
 	s := sablier.New(logger, store, provider)
+	s.WithPolicies(policySet)
 	s.WithMetrics(rec)
 	s.WithRejectUnlabeledRequests(conf.Provider.RejectUnlabeledRequests)
 	s.WithVerifyEnabledOnExpiration(conf.Provider.VerifyEnabledOnExpiration)
+	if history, ok := store.(sablier.History); ok {
+		s.WithHistory(history)
+	}
+	var elector leader.Election
+	if conf.LeaderElection.Enabled {
+		elector, err = setupLeaderElection(ctx, logger, conf.LeaderElection, conf.Storage)
+		if err != nil {
+			return fmt.Errorf("cannot setup leader election: %w", err)
+		}
+		s.WithLeader(elector.IsLeader)
+	}
 	err = store.OnExpire(ctx, s.OnInstanceExpired(ctx))
 	if err != nil {
 		return err
@@ -90,6 +138,21 @@ func Start(ctx context.Context, conf config.Config) error {
 	}
 	s.BlockingRefreshFrequency = conf.Strategy.Blocking.DefaultRefreshFrequency
 	s.DefaultSessionDuration = conf.Sessions.DefaultDuration
+	s.MaxSessionDuration = conf.Sessions.MaxDuration
+	if conf.RunningHours.HolidaysFile != "" {
+		if err := s.WithHolidays(conf.RunningHours.HolidaysFile); err != nil {
+			return fmt.Errorf("cannot load the holidays file: %w", err)
+		}
+	}
+	s.WithCapacity(newCapacity(conf.Capacity))
+
+	if configFileUsed != "" && conf.Reload.Interval > 0 {
+		go watchPolicies(ctx, logger, s, configFileUsed, conf.Reload.Interval)
+	}
+
+	if err := s.CheckActivitySupport(ctx); err != nil {
+		return fmt.Errorf("cannot keep sessions alive with instance traffic: %w", err)
+	}
 
 	groups, err := provider.InstanceGroups(ctx)
 	if err != nil {
@@ -125,30 +188,53 @@ func Start(ctx context.Context, conf config.Config) error {
 		}
 	}()
 
-	if conf.Provider.AutoStopOnStartup {
+	// stopUnregistered and watch act on instances on their own: with leader
+	// election, only the leader runs them.
+	stopUnregistered := func(ctx context.Context) {
+		if !conf.Provider.AutoStopOnStartup {
+			return
+		}
 		err := s.StopAllUnregisteredInstances(ctx)
 		if err != nil {
 			logger.ErrorContext(ctx, "unable to stop unregistered instances", slog.Any("reason", err))
 		}
 	}
-
-	if conf.Provider.AutoStopExternallyStarted {
-		go s.WatchAndStopExternallyStarted(ctx)
+	watch := func(ctx context.Context) {
+		var wg sync.WaitGroup
+		if conf.Provider.AutoStopExternallyStarted {
+			wg.Go(func() { s.WatchAndStopExternallyStarted(ctx) })
+		}
+		if conf.Provider.AutoWarmExternallyStarted {
+			wg.Go(func() { s.WatchAndWarmExternallyStarted(ctx) })
+		}
+		wg.Go(func() { s.WatchRunningHours(ctx) })
+		wg.Go(func() { s.WatchActivity(ctx) })
+		if len(conf.Webhooks.Endpoints) > 0 {
+			d := webhook.NewDispatcher(conf.Webhooks.Endpoints, logger)
+			stream := s.InstanceEvents(ctx, provpkg.InstanceEventsOptions{
+				Types: []provpkg.InstanceEventType{
+					provpkg.InstanceEventStarted,
+					provpkg.InstanceEventStopped,
+				},
+			})
+			wg.Go(func() { d.Watch(ctx, stream) })
+		}
+		wg.Wait()
 	}
-	if conf.Provider.AutoWarmExternallyStarted {
-		go s.WatchAndWarmExternallyStarted(ctx)
+
+	if elector != nil {
+		// A new leader also stops the instances whose session expired while
+		// no replica was leading.
+		go elector.Run(ctx, func(ctx context.Context) {
+			stopUnregistered(ctx)
+			watch(ctx)
+		})
+	} else {
+		stopUnregistered(ctx)
+		go watch(ctx)
 	}
-	go s.WatchRunningHours(ctx)
 
 	if len(conf.Webhooks.Endpoints) > 0 {
-		d := webhook.NewDispatcher(conf.Webhooks.Endpoints, logger)
-		stream := s.InstanceEvents(ctx, provpkg.InstanceEventsOptions{
-			Types: []provpkg.InstanceEventType{
-				provpkg.InstanceEventStarted,
-				provpkg.InstanceEventStopped,
-			},
-		})
-		go d.Watch(ctx, stream)
 		for i, ep := range conf.Webhooks.Endpoints {
 			events := ep.Events
 			if len(events) == 0 {
@@ -175,12 +261,32 @@ func Start(ctx context.Context, conf config.Config) error {
 		return fmt.Errorf("cannot setup theme: %w", err)
 	}
 
+	statusTokens, err := api.NewStatusTokens(conf.Server.Auth)
+	if err != nil {
+		return fmt.Errorf("cannot setup status tokens: %w", err)
+	}
 	strategy := &api.ServeStrategy{
 		Theme:          t,
 		Sablier:        s,
 		Metrics:        rec,
 		StrategyConfig: conf.Strategy,
 		SessionsConfig: conf.Sessions,
+		Routing:        conf.Routing,
+		StatusTokens:   statusTokens,
+	}
+
+	// Without a session duration, Sablier resolves it for each instance from
+	// its labels, falling back to sessions.default-duration.
+	listeners, err := l4.Listen(logger, conf.L4, s, rec, 0, conf.Strategy.Blocking.DefaultTimeout)
+	if err != nil {
+		return fmt.Errorf("cannot setup l4 listeners: %w", err)
+	}
+	for _, l := range listeners {
+		go func() {
+			if err := l.Serve(ctx); err != nil {
+				logger.ErrorContext(ctx, "l4 listener stopped", slog.String("listener", l.Name()), slog.Any("error", err))
+			}
+		}()
 	}
 
 	serverErr := make(chan error, 1)
@@ -2,93 +2,291 @@ package sabliercmd
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"sync"
+	"time"
+
+	vk "github.com/valkey-io/valkey-go"
 
 	"github.com/sablierapp/sablier/pkg/config"
 	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store/configmap"
 	"github.com/sablierapp/sablier/pkg/store/inmemory"
+	"github.com/sablierapp/sablier/pkg/store/sqlite"
+	"github.com/sablierapp/sablier/pkg/store/statefile"
+	"github.com/sablierapp/sablier/pkg/store/valkey"
 )
 
-// setupStorage creates the session store, restores any persisted state from disk,
-// and starts a periodic flush goroutine when a storage file is configured.
+// setupStorage creates the session store. With Valkey addresses configured it
+// connects to Valkey, which persists sessions on its own, with the Kubernetes
+// storage enabled it keeps them in ConfigMaps, and with a database path it
+// keeps them, along with their history, in a database file. Otherwise it builds
+// the in-memory store and, when a storage file is configured, restores the
+// persisted state from disk and keeps the file in sync from then on.
 // The returned function must be called on shutdown to perform a final flush.
-func setupStorage(ctx context.Context, logger *slog.Logger, conf config.Config) (sablier.Store, func()) {
+func setupStorage(ctx context.Context, logger *slog.Logger, conf config.Config) (sablier.Store, func(), error) {
+	if err := conf.Storage.IsValid(); err != nil {
+		return nil, nil, err
+	}
+
+	if conf.Storage.Valkey.Enabled() {
+		var ignore []string
+		if conf.LeaderElection.Enabled && conf.LeaderElection.Backend == config.LeaderElectionBackendValkey {
+			// The leader lock expires in the same database when a leader dies.
+			ignore = append(ignore, conf.LeaderElection.Valkey.Key)
+		}
+		store, err := setupValkey(ctx, conf.Storage.Valkey, ignore)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot setup valkey storage: %w", err)
+		}
+		logger.InfoContext(ctx, "using valkey session storage",
+			slog.Any("addresses", conf.Storage.Valkey.Addresses),
+			slog.Int("db", conf.Storage.Valkey.DB),
+			slog.String("key_prefix", conf.Storage.Valkey.KeyPrefix),
+		)
+		return store, func() {}, nil
+	}
+
+	if conf.Storage.Kubernetes.Enabled {
+		client, err := newInClusterClient()
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot setup kubernetes storage: %w", err)
+		}
+		namespace := conf.Storage.Kubernetes.SessionNamespace()
+		logger.InfoContext(ctx, "using kubernetes session storage",
+			slog.String("namespace", namespace),
+			slog.String("name_prefix", conf.Storage.Kubernetes.NamePrefix),
+		)
+		return configmap.New(client, configmap.Options{
+			Namespace:  namespace,
+			NamePrefix: conf.Storage.Kubernetes.NamePrefix,
+		}), func() {}, nil
+	}
+
+	if conf.Storage.Database.Enabled() {
+		db, err := sqlite.Open(conf.Storage.Database.Path, sqlite.Options{
+			HistoryRetention: conf.Storage.Database.HistoryRetention,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot setup database storage: %w", err)
+		}
+		logger.InfoContext(ctx, "using database session storage",
+			slog.String("path", conf.Storage.Database.Path),
+			slog.Duration("history_retention", conf.Storage.Database.HistoryRetention),
+		)
+		return db, func() {
+			if err := db.Close(); err != nil {
+				logger.ErrorContext(ctx, "failed to close database", slog.Any("error", err))
+			}
+		}, nil
+	}
+
 	store := inmemory.NewInMemory()
+	if conf.Storage.File == "" {
+		return store, func() {}, nil
+	}
+
+	loadFromFile(ctx, logger, conf.Storage.File, store)
+
+	persisted := newFileStore(logger, conf.Storage.File, store)
+	go persisted.flushLoop(ctx, conf.Storage.FlushInterval, conf.Storage.FlushDelay)
 
-	if conf.Storage.File != "" {
-		loadFromFile(ctx, logger, conf.Storage.File, store)
+	return persisted, func() { persisted.save(ctx) }, nil
+}
+
+// setupValkey connects to the configured Valkey nodes and returns a store that
+// has verified keyspace notifications for expired keys are published. The
+// expiration of the ignored keys is not reported as a session expiration.
+func setupValkey(ctx context.Context, conf config.Valkey, ignore []string) (sablier.Store, error) {
+	client, err := newValkeyClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	store, err := valkey.NewWithOptions(ctx, client, valkey.Options{
+		KeyPrefix:  conf.KeyPrefix,
+		DB:         conf.DB,
+		IgnoreKeys: ignore,
+	})
+	if err != nil {
+		client.Close()
+		return nil, err
 	}
+	return store, nil
+}
 
-	save := func() {
-		if conf.Storage.File != "" {
-			saveToFile(ctx, logger, conf.Storage.File, store)
+// newValkeyClient connects to the configured Valkey nodes.
+func newValkeyClient(conf config.Valkey) (vk.Client, error) {
+	opts := vk.ClientOption{
+		InitAddress: conf.Addresses,
+		Username:    conf.Username,
+		Password:    conf.Password,
+		SelectDB:    conf.DB,
+	}
+	if conf.TLS {
+		opts.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: conf.TLSInsecure, //nolint:gosec // explicitly opted in via storage.valkey.tls-insecure
 		}
 	}
 
-	return store, save
+	return vk.NewClient(opts)
+}
+
+// fileStore decorates the in-memory store to keep the state file in sync with
+// it: every change (put, delete, expiration) marks the state dirty, and
+// flushLoop writes it out shortly after, on top of a periodic flush. A crash
+// therefore loses at most the changes of the last flush delay instead of every
+// session since boot.
+type fileStore struct {
+	sablier.Store
+
+	file   string
+	logger *slog.Logger
+
+	// dirty has capacity 1 so bursts of changes coalesce into one pending flush.
+	dirty chan struct{}
+	// saveMu serialises snapshot-and-write so an older snapshot can never
+	// replace a newer one on disk.
+	saveMu sync.Mutex
+}
+
+func newFileStore(logger *slog.Logger, file string, store sablier.Store) *fileStore {
+	return &fileStore{
+		Store:  store,
+		file:   file,
+		logger: logger,
+		dirty:  make(chan struct{}, 1),
+	}
+}
+
+func (f *fileStore) markDirty() {
+	select {
+	case f.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (f *fileStore) Put(ctx context.Context, info sablier.InstanceInfo, duration time.Duration) error {
+	if err := f.Store.Put(ctx, info, duration); err != nil {
+		return err
+	}
+	f.markDirty()
+	return nil
+}
+
+func (f *fileStore) Delete(ctx context.Context, name string) error {
+	if err := f.Store.Delete(ctx, name); err != nil {
+		return err
+	}
+	f.markDirty()
+	return nil
+}
+
+func (f *fileStore) OnExpire(ctx context.Context, fn func(string)) error {
+	return f.Store.OnExpire(ctx, func(name string) {
+		fn(name)
+		f.markDirty()
+	})
+}
+
+// flushLoop writes the state file delay after a change, and every interval
+// regardless of changes (0 disables the periodic flush), until ctx is done.
+func (f *fileStore) flushLoop(ctx context.Context, interval, delay time.Duration) {
+	var periodic <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		periodic = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-periodic:
+			f.save(ctx)
+		case <-f.dirty:
+			// Wait out the burst: changes made meanwhile are captured by the
+			// snapshot, and later ones mark the state dirty again.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			f.save(ctx)
+		}
+	}
+}
+
+func (f *fileStore) save(ctx context.Context) {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+	saveToFile(ctx, f.logger, f.file, f.Store)
 }
 
 // loadFromFile restores persisted state into store from the given file path.
-// A missing file is silently ignored. Any other error logs a warning and leaves
-// the store empty so the server starts fresh.
+// A missing file is created empty. Files written by older versions are
+// upgraded on read. A file that cannot be decoded (corrupt, or written by a
+// newer version) is moved aside rather than overwritten by the next save, and
+// the server starts fresh.
 func loadFromFile(ctx context.Context, logger *slog.Logger, file string, store sablier.Store) {
-	data, err := os.ReadFile(file)
+	f, err := statefile.Read(file)
 	if errors.Is(err, os.ErrNotExist) {
 		initializeFile(ctx, logger, file, store)
 		return
 	}
-	if err != nil {
+	if _, ok := errors.AsType[*fs.PathError](err); ok {
 		logger.WarnContext(ctx, "failed to read state file, starting fresh",
 			slog.String("file", file), slog.Any("error", err))
 		return
 	}
-
-	imStore, ok := store.(*inmemory.InMemory)
-	if !ok {
+	if err != nil {
+		backup := fmt.Sprintf("%s.%s.bak", file, time.Now().UTC().Format("20060102T150405Z"))
+		if renameErr := os.Rename(file, backup); renameErr != nil {
+			logger.ErrorContext(ctx, "failed to restore state from file and to move it aside, starting fresh",
+				slog.String("file", file), slog.Any("error", err), slog.Any("rename_error", renameErr))
+			return
+		}
+		logger.ErrorContext(ctx, "failed to restore state from file, moved it aside and starting fresh",
+			slog.String("file", file), slog.String("backup", backup), slog.Any("error", err))
 		return
 	}
-	if err := json.Unmarshal(data, imStore); err != nil {
-		logger.WarnContext(ctx, "failed to restore state from file, starting fresh",
-			slog.String("file", file), slog.Any("error", err))
+
+	restored, err := statefile.Restore(ctx, f, store)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to restore every session from file",
+			slog.String("file", file), slog.Int("restored", restored), slog.Any("error", err))
 		return
 	}
-	logger.InfoContext(ctx, "state restored from file", slog.String("file", file))
+	logger.InfoContext(ctx, "state restored from file", slog.String("file", file), slog.Int("sessions", restored))
 }
 
 // initializeFile writes an empty store snapshot to file so the path exists for
 // subsequent saves. A failure is non-fatal and is logged as a warning.
 func initializeFile(ctx context.Context, logger *slog.Logger, file string, store sablier.Store) {
-	imStore, ok := store.(*inmemory.InMemory)
-	if !ok {
-		return
+	f, err := statefile.Snapshot(ctx, store)
+	if err == nil {
+		err = statefile.Write(file, f)
 	}
-	data, err := json.Marshal(imStore)
 	if err != nil {
 		logger.WarnContext(ctx, "failed to initialize state file",
 			slog.String("file", file), slog.Any("error", err))
-		return
-	}
-	if err := os.WriteFile(file, data, 0o600); err != nil {
-		logger.WarnContext(ctx, "failed to initialize state file",
-			slog.String("file", file), slog.Any("error", err))
 	}
 }
 
-// saveToFile serializes the store and writes it to file with restricted permissions.
+// saveToFile snapshots the store and atomically replaces file with it.
 func saveToFile(ctx context.Context, logger *slog.Logger, file string, store sablier.Store) {
-	imStore, ok := store.(*inmemory.InMemory)
-	if !ok {
-		return
-	}
-	data, err := json.Marshal(imStore)
+	f, err := statefile.Snapshot(ctx, store)
 	if err != nil {
-		logger.ErrorContext(ctx, "failed to marshal state for persistence", slog.Any("error", err))
+		logger.ErrorContext(ctx, "failed to snapshot state for persistence", slog.Any("error", err))
 		return
 	}
-	if err := os.WriteFile(file, data, 0o600); err != nil {
+	if err := statefile.Write(file, f); err != nil {
 		logger.ErrorContext(ctx, "failed to save state to file",
 			slog.String("file", file), slog.Any("error", err))
 	}
@@ -136,7 +136,8 @@ func TestSetupStorage_LoadOnStartup(t *testing.T) {
 	conf.Storage.File = path
 	conf.Sessions.ExpirationInterval = time.Hour // slow periodic save, irrelevant here
 
-	store, _ := setupStorage(ctx, slog.Default(), conf)
+	store, _, err := setupStorage(ctx, slog.Default(), conf)
+	assert.NilError(t, err)
 
 	info, err := store.Get(ctx, "startup-service")
 	assert.NilError(t, err)
@@ -153,7 +154,8 @@ func TestSetupStorage_SaveOnShutdown(t *testing.T) {
 	conf.Storage.File = path
 	conf.Sessions.ExpirationInterval = time.Hour // slow periodic save, irrelevant here
 
-	store, save := setupStorage(ctx, slog.Default(), conf)
+	store, save, err := setupStorage(ctx, slog.Default(), conf)
+	assert.NilError(t, err)
 
 	require.NoError(t, store.Put(ctx, sablier.InstanceInfo{
 		Name: "shutdown-service", Status: sablier.InstanceStatusReady,
@@ -170,3 +172,82 @@ func TestSetupStorage_SaveOnShutdown(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, info.Name, "shutdown-service")
 }
+
+func TestSetupStorage_Database(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.NewConfig()
+	conf.Storage.Database.Path = filepath.Join(t.TempDir(), "sablier.db")
+
+	store, closeDB, err := setupStorage(ctx, slog.Default(), conf)
+	assert.NilError(t, err)
+	_, ok := store.(sablier.History)
+	assert.Assert(t, ok, "the database storage records the session history")
+
+	require.NoError(t, store.Put(ctx, sablier.InstanceInfo{Name: "db-service"}, time.Hour))
+	closeDB()
+
+	// The file is released on shutdown and keeps the sessions.
+	store, closeDB, err = setupStorage(ctx, slog.Default(), conf)
+	assert.NilError(t, err)
+	defer closeDB()
+	info, err := store.Get(ctx, "db-service")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Name, "db-service")
+}
+
+func TestSetupStorage_InvalidConfig(t *testing.T) {
+	conf := config.NewConfig()
+	conf.Storage.File = filepath.Join(t.TempDir(), "state.json")
+	conf.Storage.Valkey.Addresses = []string{"valkey:6379"}
+
+	_, _, err := setupStorage(context.Background(), slog.Default(), conf)
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestLoadFromFile_Undecodable_MovedAside(t *testing.T) {
+	ctx := context.Background()
+	store := inmemory.NewInMemory()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "sessions": []}`), 0o600))
+
+	loadFromFile(ctx, slog.Default(), path, store)
+
+	// The unreadable file must survive under a backup name so the next save
+	// does not silently throw its sessions away.
+	backups, err := filepath.Glob(path + ".*.bak")
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 1)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSetupStorage_FlushOnChange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	conf := config.NewConfig()
+	conf.Storage.File = path
+	conf.Storage.FlushInterval = 0
+	conf.Storage.FlushDelay = 10 * time.Millisecond
+
+	store, _, err := setupStorage(ctx, slog.Default(), conf)
+	assert.NilError(t, err)
+
+	require.NoError(t, store.Put(ctx, sablier.InstanceInfo{
+		Name: "changed-service", Status: sablier.InstanceStatusReady,
+	}, time.Hour))
+
+	// No explicit save: the change alone must reach the file.
+	require.Eventually(t, func() bool {
+		fresh := inmemory.NewInMemory()
+		loadFromFile(ctx, slog.Default(), path, fresh)
+		_, err := fresh.Get(ctx, "changed-service")
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,129 @@
+package sabliercmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// Usage fetches the usage report of the period from the usage endpoint at
+// endpoint, authenticating with token when it is not empty.
+func Usage(endpoint, token string, from, to time.Time) (api.UsageResponse, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return api.UsageResponse{}, err
+	}
+	query := u.Query()
+	query.Set("from", from.Format(time.RFC3339))
+	query.Set("to", to.Format(time.RFC3339))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return api.UsageResponse{}, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return api.UsageResponse{}, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return api.UsageResponse{}, err
+	}
+	if resp.StatusCode >= 400 {
+		return api.UsageResponse{}, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	var report api.UsageResponse
+	if err := json.Unmarshal(body, &report); err != nil {
+		return api.UsageResponse{}, err
+	}
+	return report, nil
+}
+
+// printUsage writes the report as a table, one instance per line.
+func printUsage(w io.Writer, report api.UsageResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Usage from %s to %s\n\n", report.From.Format(time.RFC3339), report.To.Format(time.RFC3339))
+	_, _ = fmt.Fprintln(tw, "INSTANCE\tSESSIONS\tCOLD STARTS\tAVG COLD START\tUPTIME\tIDLE SAVINGS\tSTOPS")
+	for _, u := range report.Instances {
+		sessions := 0
+		for _, n := range u.Sessions {
+			sessions += n
+		}
+		avgColdStart := "-"
+		if u.ColdStarts > 0 {
+			avgColdStart = seconds(u.ColdStartTimeSeconds / float64(u.ColdStarts)).String()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			u.Name,
+			sessions,
+			u.ColdStarts,
+			avgColdStart,
+			seconds(u.UptimeSeconds),
+			seconds(u.IdleSavingsSeconds),
+			formatCounts(u.Stops),
+		)
+	}
+	return tw.Flush()
+}
+
+// seconds rounds a number of seconds to a readable duration.
+func seconds(s float64) time.Duration {
+	d := time.Duration(s * float64(time.Second))
+	if d >= time.Minute {
+		return d.Round(time.Minute)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+// formatCounts formats counts by key as "key=n", sorted by key.
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(counts))
+	for _, k := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func NewUsageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage",
+		Short: "Reports the usage of the instances from the session history of a Sablier instance",
+		Long: "Reports, for every instance, the sessions, cold starts, uptime and idle savings over the last period.\n" +
+			"The report is fetched over HTTP from the usage endpoint of a running Sablier (--url), which must use the\n" +
+			"database storage (--storage.database.path). The database file cannot be read directly: the running\n" +
+			"Sablier holds it under an exclusive lock.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, err := cmd.Flags().GetDuration("since")
+			if err != nil {
+				return err
+			}
+			to := time.Now()
+			report, err := Usage(cmd.Flag("url").Value.String(), cmd.Flag("token").Value.String(), to.Add(-since), to)
+			if err != nil {
+				return err
+			}
+			return printUsage(os.Stdout, report)
+		},
+	}
+}
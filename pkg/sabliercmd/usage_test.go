@@ -0,0 +1,49 @@
+package sabliercmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/internal/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestUsage(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, r.URL.Query().Get("from"), "2026-01-01T00:00:00Z")
+		assert.Equal(t, r.URL.Query().Get("to"), "2026-01-02T00:00:00Z")
+		_ = json.NewEncoder(w).Encode(api.UsageResponse{From: from, To: to, Instances: []api.InstanceUsageResponse{{
+			Name:                 "whoami",
+			Sessions:             map[string]int{"dynamic": 2, "blocking": 1},
+			ColdStarts:           2,
+			ColdStartTimeSeconds: 3,
+			Stops:                map[string]int{"expired": 2, "anti-affinity": 1},
+			UptimeSeconds:        3600,
+			IdleSavingsSeconds:   82800,
+		}}})
+	}))
+	defer srv.Close()
+
+	_, err := Usage(srv.URL+"/api/usage", "", from, to)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	report, err := Usage(srv.URL+"/api/usage", "secret", from, to)
+	assert.NilError(t, err)
+
+	var out strings.Builder
+	assert.NilError(t, printUsage(&out, report))
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, lines[0], "Usage from 2026-01-01T00:00:00Z to 2026-01-02T00:00:00Z")
+	assert.Equal(t, strings.Join(strings.Fields(lines[3]), " "), "whoami 3 2 1.5s 1h0m0s 23h0m0s anti-affinity=1,expired=2")
+}
@@ -0,0 +1,334 @@
+// Package configmap keeps Sablier sessions in Kubernetes ConfigMaps, one per
+// session, so they survive a Sablier restart without Valkey or a persistent
+// volume.
+package configmap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ sablier.Store = (*ConfigMap)(nil)
+
+const (
+	labelManagedBy     = "app.kubernetes.io/managed-by"
+	labelComponent     = "app.kubernetes.io/component"
+	annotationInstance = "sablier/instance"
+	annotationExpires  = "sablier/expires-at"
+	dataSession        = "session"
+
+	// maxNameLength leaves room under the 253 characters of a ConfigMap name
+	// for the hash suffix.
+	maxNameLength = 253 - 1 - hashLength
+	hashLength    = 10
+)
+
+// selector matches the session ConfigMaps of every Sablier deployment; the
+// name prefix tells them apart.
+var selector = metav1.FormatLabelSelector(&metav1.LabelSelector{
+	MatchLabels: map[string]string{labelManagedBy: "sablier", labelComponent: "session"},
+})
+
+// Options tunes where the store keeps its ConfigMaps.
+type Options struct {
+	// Namespace the session ConfigMaps are kept in.
+	Namespace string
+	// NamePrefix starts the name of every session ConfigMap. Only ConfigMaps
+	// carrying the prefix are enumerated by Range and reported by OnExpire.
+	NamePrefix string
+}
+
+// ConfigMap keeps each session in a ConfigMap holding the session record and
+// its expiration time. Kubernetes does not expire objects: a session past its
+// expiration is not returned anymore, and OnExpire deletes it.
+type ConfigMap struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Prefix    string
+
+	// reapInterval is how often OnExpire looks for expired sessions.
+	reapInterval time.Duration
+	now          func() time.Time
+}
+
+func New(client kubernetes.Interface, opts Options) sablier.Store {
+	return &ConfigMap{
+		Client:       client,
+		Namespace:    opts.Namespace,
+		Prefix:       opts.NamePrefix,
+		reapInterval: time.Second,
+		now:          time.Now,
+	}
+}
+
+// name derives a valid ConfigMap name from an instance name, which may hold
+// characters Kubernetes does not accept. The hash keeps names that sanitize to
+// the same string apart.
+func (c *ConfigMap) name(instance string) string {
+	sum := sha256.Sum256([]byte(instance))
+	var b strings.Builder
+	for _, r := range strings.ToLower(instance) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	name := c.Prefix + b.String()
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name + "-" + hex.EncodeToString(sum[:])[:hashLength]
+}
+
+func (c *ConfigMap) Get(ctx context.Context, s string) (sablier.InstanceInfo, error) {
+	cm, err := c.Client.CoreV1().ConfigMaps(c.Namespace).Get(ctx, c.name(s), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return sablier.InstanceInfo{}, store.ErrKeyNotFound
+	}
+	if err != nil {
+		return sablier.InstanceInfo{}, err
+	}
+	r, expiresAt, err := decode(cm)
+	if err != nil {
+		return sablier.InstanceInfo{}, err
+	}
+	if !expiresAt.After(c.now()) {
+		return sablier.InstanceInfo{}, store.ErrKeyNotFound
+	}
+	return r.Instance, nil
+}
+
+func (c *ConfigMap) Put(ctx context.Context, state sablier.InstanceInfo, duration time.Duration) error {
+	value, err := json.Marshal(sablier.NewSessionRecord(state))
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.name(state.Name),
+			Namespace: c.Namespace,
+			Labels:    map[string]string{labelManagedBy: "sablier", labelComponent: "session"},
+			Annotations: map[string]string{
+				annotationInstance: state.Name,
+				annotationExpires:  c.now().Add(duration).UTC().Format(time.RFC3339Nano),
+			},
+		},
+		Data: map[string]string{dataSession: string(value)},
+	}
+
+	configMaps := c.Client.CoreV1().ConfigMaps(c.Namespace)
+	// An update without resource version replaces the session whatever its
+	// current version, as a SET would.
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func (c *ConfigMap) Delete(ctx context.Context, s string) error {
+	err := c.Client.CoreV1().ConfigMaps(c.Namespace).Delete(ctx, c.name(s), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *ConfigMap) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+	list, err := c.Client.CoreV1().ConfigMaps(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	now := c.now()
+	for i := range list.Items {
+		cm := &list.Items[i]
+		if !c.owns(cm) {
+			continue
+		}
+		// Skip anything that is not a valid session record instead of aborting
+		// the whole enumeration on a single corrupt ConfigMap.
+		r, expiresAt, err := decode(cm)
+		if err != nil || !expiresAt.After(now) {
+			continue
+		}
+		f(r.Instance, expiresAt)
+	}
+	return nil
+}
+
+// OnExpire watches the session ConfigMaps and calls f when one of them
+// expires. Every Sablier sharing the namespace is told about an expiration,
+// whichever of them deletes the ConfigMap.
+func (c *ConfigMap) OnExpire(ctx context.Context, f func(string)) error {
+	w := &expiryWatcher{c: c, f: f, sessions: map[string]session{}}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(c.Client, 0,
+		informers.WithNamespace(c.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.track,
+		UpdateFunc: func(_, obj any) { w.track(obj) },
+		DeleteFunc: w.deleted,
+	})
+	if err != nil {
+		return err
+	}
+	go informer.Run(ctx.Done())
+	go w.reap(ctx)
+	return nil
+}
+
+// session is what the expiry watcher knows about a session ConfigMap.
+type session struct {
+	instance        string
+	expiresAt       time.Time
+	resourceVersion string
+}
+
+type expiryWatcher struct {
+	c *ConfigMap
+	f func(string)
+
+	mu sync.Mutex
+	// sessions maps the ConfigMap names to the sessions they hold.
+	sessions map[string]session
+}
+
+func (w *expiryWatcher) track(obj any) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok || !w.c.owns(cm) {
+		return
+	}
+	_, expiresAt, err := decode(cm)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sessions[cm.Name] = session{
+		instance:        cm.Annotations[annotationInstance],
+		expiresAt:       expiresAt,
+		resourceVersion: cm.ResourceVersion,
+	}
+}
+
+// deleted reports the sessions another Sablier deleted because they expired.
+// A session deleted before its expiration was ended on purpose.
+func (w *expiryWatcher) deleted(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	s, tracked := w.sessions[cm.Name]
+	delete(w.sessions, cm.Name)
+	w.mu.Unlock()
+	if tracked && !s.expiresAt.After(w.c.now()) {
+		w.f(s.instance)
+	}
+}
+
+func (w *expiryWatcher) reap(ctx context.Context) {
+	ticker := time.NewTicker(w.c.reapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, s := range w.expired() {
+				w.expire(ctx, s)
+			}
+		}
+	}
+}
+
+// expired stops tracking the expired sessions and returns them.
+func (w *expiryWatcher) expired() []session {
+	now := w.c.now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var expired []session
+	for name, s := range w.sessions {
+		if !s.expiresAt.After(now) {
+			expired = append(expired, s)
+			delete(w.sessions, name)
+		}
+	}
+	return expired
+}
+
+// expire deletes the ConfigMap of an expired session and calls f. The
+// deletion only applies to the version that expired: a session renewed
+// meanwhile is kept, and tracked again from its update event.
+func (w *expiryWatcher) expire(ctx context.Context, s session) {
+	err := w.c.Client.CoreV1().ConfigMaps(w.c.Namespace).Delete(ctx, w.c.name(s.instance), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &s.resourceVersion},
+	})
+	switch {
+	case err == nil, apierrors.IsNotFound(err):
+		w.f(s.instance)
+	case apierrors.IsConflict(err):
+	default:
+		slog.ErrorContext(ctx, "cannot delete expired session", slog.String("instance", s.instance), slog.Any("error", err))
+		// Try again at the next tick.
+		w.mu.Lock()
+		if _, tracked := w.sessions[w.c.name(s.instance)]; !tracked {
+			w.sessions[w.c.name(s.instance)] = s
+		}
+		w.mu.Unlock()
+	}
+}
+
+// owns reports whether cm is a session of this store.
+func (c *ConfigMap) owns(cm *corev1.ConfigMap) bool {
+	instance, ok := cm.Annotations[annotationInstance]
+	return ok && strings.HasPrefix(cm.Name, c.Prefix) && cm.Name == c.name(instance)
+}
+
+func decode(cm *corev1.ConfigMap) (sablier.SessionRecord, time.Time, error) {
+	expiresAt, err := time.Parse(time.RFC3339Nano, cm.Annotations[annotationExpires])
+	if err != nil {
+		return sablier.SessionRecord{}, time.Time{}, fmt.Errorf("invalid expiration of session %s: %w", cm.Name, err)
+	}
+	value, ok := cm.Data[dataSession]
+	if !ok {
+		return sablier.SessionRecord{}, time.Time{}, fmt.Errorf("no session in %s", cm.Name)
+	}
+	// Session entries are stored as versioned records; SessionRecord's
+	// unmarshaler transparently upgrades pre-versioning payloads.
+	var r sablier.SessionRecord
+	if err := json.Unmarshal([]byte(value), &r); err != nil {
+		return sablier.SessionRecord{}, time.Time{}, err
+	}
+	return r, expiresAt, nil
+}
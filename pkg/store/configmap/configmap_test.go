@@ -0,0 +1,165 @@
+package configmap
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func setupConfigMap(t *testing.T, objects ...*corev1.ConfigMap) (*ConfigMap, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewSimpleClientset()
+	for _, o := range objects {
+		_, err := client.CoreV1().ConfigMaps(o.Namespace).Create(t.Context(), o, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	s := New(client, Options{Namespace: "sablier", NamePrefix: "sablier-session-"}).(*ConfigMap)
+	s.reapInterval = 50 * time.Millisecond
+	return s, client
+}
+
+func TestConfigMap(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("ConfigMapErrNotFound", func(t *testing.T) {
+		s, _ := setupConfigMap(t)
+		_, err := s.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+	t.Run("ConfigMapPut", func(t *testing.T) {
+		s, client := setupConfigMap(t)
+		err := s.Put(ctx, sablier.InstanceInfo{Name: "deployment_default_nginx_1"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		i, err := s.Get(ctx, "deployment_default_nginx_1")
+		assert.NilError(t, err)
+		assert.Equal(t, i.Name, "deployment_default_nginx_1")
+
+		list, err := client.CoreV1().ConfigMaps("sablier").List(ctx, metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, len(list.Items), 1)
+		assert.Assert(t, strings.HasPrefix(list.Items[0].Name, "sablier-session-deployment-default-nginx-1-"), list.Items[0].Name)
+
+		// A second put replaces the session.
+		err = s.Put(ctx, sablier.InstanceInfo{Name: "deployment_default_nginx_1", Status: sablier.InstanceStatusReady}, 30*time.Second)
+		assert.NilError(t, err)
+		i, err = s.Get(ctx, "deployment_default_nginx_1")
+		assert.NilError(t, err)
+		assert.Equal(t, i.Status, sablier.InstanceStatusReady)
+	})
+	t.Run("ConfigMapExpiredSessionIsNotFound", func(t *testing.T) {
+		s, _ := setupConfigMap(t)
+		err := s.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, time.Minute)
+		assert.NilError(t, err)
+
+		s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		_, err = s.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+	t.Run("ConfigMapDelete", func(t *testing.T) {
+		s, _ := setupConfigMap(t)
+		err := s.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		assert.NilError(t, s.Delete(ctx, "nginx"))
+		_, err = s.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+
+		// Deleting a missing session is not an error.
+		assert.NilError(t, s.Delete(ctx, "nginx"))
+	})
+	t.Run("ConfigMapRange", func(t *testing.T) {
+		s, _ := setupConfigMap(t)
+		before := time.Now()
+		assert.NilError(t, s.Put(ctx, sablier.InstanceInfo{Name: "a"}, 30*time.Second))
+		assert.NilError(t, s.Put(ctx, sablier.InstanceInfo{Name: "b"}, time.Minute))
+
+		other := New(s.Client, Options{Namespace: "sablier", NamePrefix: "other-"})
+		assert.NilError(t, other.Put(ctx, sablier.InstanceInfo{Name: "c"}, time.Minute))
+
+		got := map[string]time.Time{}
+		err := s.Range(ctx, func(info sablier.InstanceInfo, expiresAt time.Time) {
+			got[info.Name] = expiresAt
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(got), 2)
+		assert.Assert(t, got["a"].After(before.Add(29*time.Second)))
+		assert.Assert(t, got["b"].After(before.Add(59*time.Second)))
+	})
+}
+
+func TestConfigMap_OnExpire(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	s, client := setupConfigMap(t)
+
+	expirations := make(chan string, 2)
+	err := s.OnExpire(ctx, func(key string) {
+		expirations <- key
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, s.Put(ctx, sablier.InstanceInfo{Name: "deleted"}, time.Minute))
+	assert.NilError(t, s.Put(ctx, sablier.InstanceInfo{Name: "expired"}, 200*time.Millisecond))
+	// Deleting a live session is not an expiration.
+	assert.NilError(t, s.Delete(ctx, "deleted"))
+
+	select {
+	case key := <-expirations:
+		assert.Equal(t, key, "expired")
+	case <-ctx.Done():
+		t.Fatal("expiration was not reported")
+	}
+
+	// The expired session is deleted.
+	list, err := client.CoreV1().ConfigMaps("sablier").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Items), 0)
+	select {
+	case key := <-expirations:
+		t.Fatalf("unexpected expiration of %s", key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestConfigMap_OnExpireReportsExpirationsReapedElsewhere(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	s, client := setupConfigMap(t)
+	// This replica never reaps: another one deletes the expired session.
+	s.reapInterval = time.Hour
+
+	expirations := make(chan string, 1)
+	err := s.OnExpire(ctx, func(key string) {
+		expirations <- key
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, s.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, 100*time.Millisecond))
+	time.Sleep(300 * time.Millisecond)
+	err = client.CoreV1().ConfigMaps("sablier").Delete(ctx, s.name("nginx"), metav1.DeleteOptions{})
+	assert.NilError(t, err)
+
+	select {
+	case key := <-expirations:
+		assert.Equal(t, key, "nginx")
+	case <-ctx.Done():
+		t.Fatal("expiration was not reported")
+	}
+}
+
+func TestConfigMap_Name(t *testing.T) {
+	s := New(nil, Options{NamePrefix: "sablier-session-"}).(*ConfigMap)
+
+	assert.Assert(t, s.name("Nginx") != s.name("nginx"), "names sanitizing alike must not collide")
+	long := s.name(strings.Repeat("a", 300))
+	assert.Equal(t, len(long), 253)
+}
@@ -0,0 +1,262 @@
+// Package sqlite keeps Sablier sessions in an embedded SQLite database file,
+// along with the history of their lifecycle, so the usage of the instances can
+// be reported after the sessions ended.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	_ "modernc.org/sqlite" // registers the pure Go "sqlite" driver
+)
+
+var _ sablier.Store = (*DB)(nil)
+var _ sablier.History = (*DB)(nil)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	name       TEXT PRIMARY KEY,
+	expires_at INTEGER NOT NULL,
+	session    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sessions_expires_at ON sessions (expires_at);
+CREATE TABLE IF NOT EXISTS history (
+	id    INTEGER PRIMARY KEY AUTOINCREMENT,
+	at    INTEGER NOT NULL,
+	event TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_at ON history (at, id);
+`
+
+// Options tunes the database.
+type Options struct {
+	// HistoryRetention is how long lifecycle events are kept. Zero keeps them
+	// forever.
+	HistoryRetention time.Duration
+}
+
+// DB keeps each session in a row named after its instance, with its
+// expiration time in Unix nanoseconds. SQLite does not expire rows: a session
+// past its expiration is not returned anymore, and OnExpire deletes it.
+type DB struct {
+	db        *sql.DB
+	retention time.Duration
+
+	// reapInterval is how often OnExpire looks for expired sessions.
+	reapInterval time.Duration
+	// pruneInterval is how often OnExpire drops the events past the retention.
+	pruneInterval time.Duration
+	now           func() time.Time
+}
+
+// Open opens the database file at path, creating it if needed. A database is
+// locked by the process that opened it: Open fails after a second when another
+// Sablier uses the file.
+func Open(path string, opts Options) (*DB, error) {
+	params := url.Values{}
+	// Pragmas run in order on the connection: wait for the lock before
+	// keeping it for the life of the connection.
+	params.Add("_pragma", "busy_timeout(1000)")
+	params.Add("_pragma", "locking_mode(EXCLUSIVE)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	db, err := sql.Open("sqlite", path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database %s: %w", path, err)
+	}
+	// The exclusive lock belongs to a connection: a second connection would
+	// wait for the first one to release it.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot initialize database %s: %w", path, err)
+	}
+	return &DB{
+		db:            db,
+		retention:     opts.HistoryRetention,
+		reapInterval:  time.Second,
+		pruneInterval: time.Hour,
+		now:           time.Now,
+	}, nil
+}
+
+// Close releases the database file.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+func (d *DB) Get(ctx context.Context, s string) (sablier.InstanceInfo, error) {
+	var value string
+	err := d.db.QueryRowContext(ctx,
+		`SELECT session FROM sessions WHERE name = ? AND expires_at > ?`,
+		s, d.now().UnixNano(),
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sablier.InstanceInfo{}, store.ErrKeyNotFound
+	}
+	if err != nil {
+		return sablier.InstanceInfo{}, err
+	}
+	var record sablier.SessionRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return sablier.InstanceInfo{}, err
+	}
+	return record.Instance, nil
+}
+
+func (d *DB) Put(ctx context.Context, state sablier.InstanceInfo, duration time.Duration) error {
+	value, err := json.Marshal(sablier.NewSessionRecord(state))
+	if err != nil {
+		return err
+	}
+	_, err = d.db.ExecContext(ctx,
+		`INSERT INTO sessions (name, expires_at, session) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET expires_at = excluded.expires_at, session = excluded.session`,
+		state.Name, d.now().Add(duration).UnixNano(), string(value),
+	)
+	return err
+}
+
+func (d *DB) Delete(ctx context.Context, s string) error {
+	_, err := d.db.ExecContext(ctx, `DELETE FROM sessions WHERE name = ?`, s)
+	return err
+}
+
+func (d *DB) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Time)) error {
+	type row struct {
+		info      sablier.InstanceInfo
+		expiresAt time.Time
+	}
+	var sessions []row
+	err := d.query(ctx, func(rows *sql.Rows) error {
+		var (
+			value     string
+			expiresAt int64
+		)
+		if err := rows.Scan(&value, &expiresAt); err != nil {
+			return err
+		}
+		// Skip anything that is not a valid session instead of aborting the
+		// whole enumeration on a single corrupt row.
+		var record sablier.SessionRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil
+		}
+		sessions = append(sessions, row{info: record.Instance, expiresAt: time.Unix(0, expiresAt)})
+		return nil
+	}, `SELECT session, expires_at FROM sessions WHERE expires_at > ? ORDER BY name`, d.now().UnixNano())
+	if err != nil {
+		return err
+	}
+	// f is called once the rows are closed, so it can use the database.
+	for _, s := range sessions {
+		f(s.info, s.expiresAt)
+	}
+	return nil
+}
+
+// OnExpire deletes the expired sessions every second and calls f for each of
+// them. It also drops the lifecycle events past the retention.
+func (d *DB) OnExpire(ctx context.Context, f func(string)) error {
+	go func() {
+		ticker := time.NewTicker(d.reapInterval)
+		defer ticker.Stop()
+		var lastPrune time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				expired, err := d.reap(ctx)
+				if err != nil {
+					slog.ErrorContext(ctx, "cannot delete expired sessions", slog.Any("error", err))
+				}
+				for _, name := range expired {
+					f(name)
+				}
+				if d.retention > 0 && d.now().Sub(lastPrune) >= d.pruneInterval {
+					if err := d.prune(ctx); err != nil {
+						slog.ErrorContext(ctx, "cannot prune session history", slog.Any("error", err))
+					}
+					lastPrune = d.now()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// reap deletes the expired sessions and returns their names.
+func (d *DB) reap(ctx context.Context) ([]string, error) {
+	var expired []string
+	err := d.query(ctx, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		expired = append(expired, name)
+		return nil
+	}, `DELETE FROM sessions WHERE expires_at <= ? RETURNING name`, d.now().UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// prune drops the lifecycle events older than the retention.
+func (d *DB) prune(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `DELETE FROM history WHERE at < ?`, d.now().Add(-d.retention).UnixNano())
+	return err
+}
+
+// RecordLifecycle appends e to the history.
+func (d *DB) RecordLifecycle(ctx context.Context, e sablier.LifecycleEvent) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.ExecContext(ctx, `INSERT INTO history (at, event) VALUES (?, ?)`, e.At.UnixNano(), string(value))
+	return err
+}
+
+func (d *DB) LifecycleEvents(ctx context.Context, until time.Time, f func(sablier.LifecycleEvent)) error {
+	// Events are ordered by time, then by the order they were recorded in.
+	return d.query(ctx, func(rows *sql.Rows) error {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return err
+		}
+		var e sablier.LifecycleEvent
+		if err := json.Unmarshal([]byte(value), &e); err != nil {
+			return nil
+		}
+		f(e)
+		return nil
+	}, `SELECT event FROM history WHERE at < ? ORDER BY at, id`, until.UnixNano())
+}
+
+// query runs a statement returning rows and calls scan for each of them.
+func (d *DB) query(ctx context.Context, scan func(*sql.Rows) error, query string, args ...any) error {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
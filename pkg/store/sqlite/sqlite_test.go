@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func setupDB(t *testing.T, opts Options) *DB {
+	t.Helper()
+	d, err := Open(filepath.Join(t.TempDir(), "sablier.db"), opts)
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = d.Close() })
+	d.reapInterval = 50 * time.Millisecond
+	return d
+}
+
+func TestDB(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("DBErrNotFound", func(t *testing.T) {
+		d := setupDB(t, Options{})
+		_, err := d.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+	t.Run("DBPut", func(t *testing.T) {
+		d := setupDB(t, Options{})
+		err := d.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		i, err := d.Get(ctx, "nginx")
+		assert.NilError(t, err)
+		assert.Equal(t, i.Name, "nginx")
+
+		// A second put replaces the session.
+		err = d.Put(ctx, sablier.InstanceInfo{Name: "nginx", Status: sablier.InstanceStatusReady}, 30*time.Second)
+		assert.NilError(t, err)
+		i, err = d.Get(ctx, "nginx")
+		assert.NilError(t, err)
+		assert.Equal(t, i.Status, sablier.InstanceStatusReady)
+	})
+	t.Run("DBExpiredSessionIsNotFound", func(t *testing.T) {
+		d := setupDB(t, Options{})
+		err := d.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, time.Minute)
+		assert.NilError(t, err)
+
+		d.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		_, err = d.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+	t.Run("DBDelete", func(t *testing.T) {
+		d := setupDB(t, Options{})
+		err := d.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		assert.NilError(t, d.Delete(ctx, "nginx"))
+		_, err = d.Get(ctx, "nginx")
+		assert.ErrorIs(t, err, store.ErrKeyNotFound)
+
+		// Deleting a missing session is not an error.
+		assert.NilError(t, d.Delete(ctx, "nginx"))
+	})
+	t.Run("DBRange", func(t *testing.T) {
+		d := setupDB(t, Options{})
+		before := time.Now()
+		assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "a"}, 30*time.Second))
+		assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "b"}, time.Minute))
+		assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "c"}, -time.Second))
+
+		got := map[string]time.Time{}
+		err := d.Range(ctx, func(info sablier.InstanceInfo, expiresAt time.Time) {
+			got[info.Name] = expiresAt
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(got), 2)
+		assert.Assert(t, got["a"].After(before.Add(29*time.Second)))
+		assert.Assert(t, got["b"].After(before.Add(59*time.Second)))
+	})
+}
+
+func TestDB_SessionsSurviveReopening(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "sablier.db")
+
+	d, err := Open(path, Options{})
+	assert.NilError(t, err)
+	assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "nginx"}, time.Minute))
+	assert.NilError(t, d.Close())
+
+	d, err = Open(path, Options{})
+	assert.NilError(t, err)
+	defer d.Close() //nolint:errcheck
+	i, err := d.Get(ctx, "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, i.Name, "nginx")
+}
+
+func TestDB_FileIsLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sablier.db")
+	d, err := Open(path, Options{})
+	assert.NilError(t, err)
+	defer d.Close() //nolint:errcheck
+
+	_, err = Open(path, Options{})
+	assert.ErrorContains(t, err, "cannot initialize database")
+}
+
+func TestDB_OnExpire(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	d := setupDB(t, Options{})
+
+	expirations := make(chan string, 2)
+	err := d.OnExpire(ctx, func(key string) {
+		expirations <- key
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "deleted"}, time.Minute))
+	assert.NilError(t, d.Put(ctx, sablier.InstanceInfo{Name: "expired"}, 200*time.Millisecond))
+	// Deleting a live session is not an expiration.
+	assert.NilError(t, d.Delete(ctx, "deleted"))
+
+	select {
+	case key := <-expirations:
+		assert.Equal(t, key, "expired")
+	case <-ctx.Done():
+		t.Fatal("expiration was not reported")
+	}
+	select {
+	case key := <-expirations:
+		t.Fatalf("unexpected expiration of %s", key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestDB_LifecycleEvents(t *testing.T) {
+	ctx := t.Context()
+	d := setupDB(t, Options{})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := []sablier.LifecycleEvent{
+		{Instance: "nginx", Kind: sablier.LifecycleReady, At: start.Add(time.Minute)},
+		{Instance: "nginx", Kind: sablier.LifecycleRequested, At: start, Strategy: "dynamic", ColdStart: true},
+		{Instance: "whoami", Kind: sablier.LifecycleRequested, At: start, Strategy: "blocking"},
+		{Instance: "nginx", Kind: sablier.LifecycleStopped, At: start.Add(time.Hour), Reason: sablier.StopReasonExpired},
+	}
+	for _, e := range events {
+		assert.NilError(t, d.RecordLifecycle(ctx, e))
+	}
+
+	var got []sablier.LifecycleEvent
+	err := d.LifecycleEvents(ctx, start.Add(time.Hour), func(e sablier.LifecycleEvent) {
+		got = append(got, e)
+	})
+	assert.NilError(t, err)
+	// Chronological order, then recording order; the stop is not before until.
+	assert.Assert(t, is.Len(got, 3))
+	assert.Equal(t, got[0].Instance, "nginx")
+	assert.Equal(t, got[0].Kind, sablier.LifecycleRequested)
+	assert.Equal(t, got[0].Strategy, "dynamic")
+	assert.Assert(t, got[0].ColdStart)
+	assert.Equal(t, got[1].Instance, "whoami")
+	assert.Equal(t, got[2].Kind, sablier.LifecycleReady)
+}
+
+func TestDB_PruneHistory(t *testing.T) {
+	ctx := t.Context()
+	d := setupDB(t, Options{HistoryRetention: 24 * time.Hour})
+	now := time.Now()
+
+	assert.NilError(t, d.RecordLifecycle(ctx, sablier.LifecycleEvent{Instance: "old", Kind: sablier.LifecycleRequested, At: now.Add(-48 * time.Hour)}))
+	assert.NilError(t, d.RecordLifecycle(ctx, sablier.LifecycleEvent{Instance: "recent", Kind: sablier.LifecycleRequested, At: now.Add(-time.Hour)}))
+	assert.NilError(t, d.prune(ctx))
+
+	var got []string
+	err := d.LifecycleEvents(ctx, now, func(e sablier.LifecycleEvent) {
+		got = append(got, e.Instance)
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, []string{"recent"})
+}
@@ -0,0 +1,184 @@
+// Package statefile persists session stores to a single JSON file.
+//
+// The file is a versioned snapshot built from sablier.Store.Range, so its shape
+// is owned here rather than by whatever a particular store implementation
+// happens to marshal as. Writes are atomic (temp file, fsync, rename): a crash
+// mid-write leaves the previous snapshot intact instead of a truncated file.
+package statefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+)
+
+// Version is the current on-disk format version. Bump it when the shape of
+// File changes, and teach Decode to upgrade the previous versions.
+//
+// Version 0 is the unversioned format written before this package existed:
+// the in-memory store's internal map of name to {"value", "expiresAt"}.
+const Version = 1
+
+// ErrUnsupportedVersion is returned when the file was written by a newer
+// Sablier whose format this version cannot read.
+var ErrUnsupportedVersion = errors.New("unsupported state file version")
+
+// File is the on-disk snapshot of every live session.
+type File struct {
+	Version  int       `json:"version"`
+	SavedAt  time.Time `json:"savedAt"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Session is one persisted session with its absolute expiration time.
+type Session struct {
+	Record    sablier.SessionRecord `json:"record"`
+	ExpiresAt time.Time             `json:"expiresAt"`
+}
+
+// Snapshot captures every live session of store. It only reads the store and
+// never renews a session.
+func Snapshot(ctx context.Context, store sablier.Store) (File, error) {
+	f := File{Version: Version, SavedAt: time.Now().UTC(), Sessions: []Session{}}
+	err := store.Range(ctx, func(info sablier.InstanceInfo, expiresAt time.Time) {
+		f.Sessions = append(f.Sessions, Session{
+			Record:    sablier.NewSessionRecord(info),
+			ExpiresAt: expiresAt.UTC(),
+		})
+	})
+	if err != nil {
+		return File{}, err
+	}
+	return f, nil
+}
+
+// Decode parses a state file of any supported version into the current
+// format, upgrading older versions on the fly.
+//
+// A versioned file is told apart from a version 0 file by its whole envelope,
+// a numeric version next to the sessions, since version 0 files are keyed by
+// instance name and an instance may be named "version".
+func Decode(b []byte) (File, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return File{}, err
+	}
+	var version int
+	if _, ok := probe["sessions"]; !ok || json.Unmarshal(probe["version"], &version) != nil {
+		return decodeV0(b)
+	}
+
+	switch version {
+	case 0:
+		return decodeV0(b)
+	case Version:
+		var f File
+		if err := json.Unmarshal(b, &f); err != nil {
+			return File{}, err
+		}
+		return f, nil
+	default:
+		return File{}, fmt.Errorf("%w: %d (this Sablier reads up to %d)", ErrUnsupportedVersion, version, Version)
+	}
+}
+
+// decodeV0 upgrades the unversioned format, a map of instance name to the
+// in-memory store's entry. Values are session records or, in files written
+// before records were versioned, bare instances; SessionRecord's unmarshaler
+// accepts both.
+func decodeV0(b []byte) (File, error) {
+	var entries map[string]struct {
+		Value     sablier.SessionRecord `json:"value"`
+		ExpiresAt time.Time             `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return File{}, err
+	}
+
+	f := File{Version: Version, Sessions: make([]Session, 0, len(entries))}
+	for name, e := range entries {
+		record := e.Value
+		if record.Instance.Name == "" {
+			record.Instance.Name = name
+		}
+		f.Sessions = append(f.Sessions, Session{Record: record, ExpiresAt: e.ExpiresAt})
+	}
+	return f, nil
+}
+
+// Restore puts every session of f that has not expired yet into store, with
+// its remaining duration. It returns the number of sessions restored.
+func Restore(ctx context.Context, f File, store sablier.Store) (int, error) {
+	restored := 0
+	for _, s := range f.Sessions {
+		remaining := time.Until(s.ExpiresAt)
+		if remaining <= 0 || s.Record.Instance.Name == "" {
+			continue
+		}
+		if err := store.Put(ctx, s.Record.Instance, remaining); err != nil {
+			return restored, fmt.Errorf("cannot restore session %q: %w", s.Record.Instance.Name, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// Read loads and decodes the state file at path.
+func Read(path string) (File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, err
+	}
+	return Decode(b)
+}
+
+// Write atomically replaces the state file at path with f. The snapshot is
+// written to a temporary file in the same directory, synced, and renamed over
+// path, so readers only ever see the previous or the new snapshot.
+func Write(path string, f File) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Best-effort cleanup; a no-op once the rename succeeded.
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself. Not every platform supports syncing a
+	// directory, and the snapshot is already in place, so this is best-effort.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
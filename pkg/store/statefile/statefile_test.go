@@ -0,0 +1,136 @@
+package statefile_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sablierapp/sablier/pkg/sablier"
+	"github.com/sablierapp/sablier/pkg/store/inmemory"
+	"github.com/sablierapp/sablier/pkg/store/statefile"
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := inmemory.NewInMemory()
+	assert.NilError(t, store.Put(ctx, sablier.InstanceInfo{
+		Name: "whoami", Status: sablier.InstanceStatusReady, Groups: []string{"demo"},
+	}, time.Hour))
+
+	f, err := statefile.Snapshot(ctx, store)
+	assert.NilError(t, err)
+	assert.Equal(t, f.Version, statefile.Version)
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NilError(t, statefile.Write(path, f))
+
+	read, err := statefile.Read(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(read.Sessions), 1)
+	assert.Equal(t, read.Sessions[0].Record.Instance.Name, "whoami")
+	assert.DeepEqual(t, read.Sessions[0].Record.Instance.Groups, []string{"demo"})
+
+	fresh := inmemory.NewInMemory()
+	restored, err := statefile.Restore(ctx, read, fresh)
+	assert.NilError(t, err)
+	assert.Equal(t, restored, 1)
+	info, err := fresh.Get(ctx, "whoami")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Status, sablier.InstanceStatusReady)
+}
+
+func TestWrite_ReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	assert.NilError(t, os.WriteFile(path, []byte("previous"), 0o644))
+
+	assert.NilError(t, statefile.Write(path, statefile.File{Version: statefile.Version}))
+
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1, "temporary files must not be left behind")
+
+	stat, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Equal(t, stat.Mode().Perm(), os.FileMode(0o600))
+}
+
+func TestDecode_V0(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	legacy, err := json.Marshal(map[string]any{
+		// Written by the in-memory store before session records were versioned.
+		"bare": map[string]any{
+			"value":     map[string]any{"name": "bare", "status": "ready"},
+			"expiresAt": expiresAt,
+		},
+		// Written by the in-memory store with versioned session records.
+		"record": map[string]any{
+			"value":     map[string]any{"v": 1, "instance": map[string]any{"name": "record", "status": "starting"}},
+			"expiresAt": expiresAt,
+		},
+	})
+	assert.NilError(t, err)
+
+	f, err := statefile.Decode(legacy)
+	assert.NilError(t, err)
+	assert.Equal(t, f.Version, statefile.Version)
+
+	got := map[string]statefile.Session{}
+	for _, s := range f.Sessions {
+		got[s.Record.Instance.Name] = s
+	}
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got["bare"].Record.Instance.Status, sablier.InstanceStatusReady)
+	assert.Equal(t, got["record"].Record.Instance.Status, sablier.InstanceStatusStarting)
+	assert.Assert(t, got["bare"].ExpiresAt.Equal(expiresAt))
+}
+
+func TestDecode_V0InstanceNamedVersion(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	legacy, err := json.Marshal(map[string]any{
+		"version": map[string]any{
+			"value":     map[string]any{"name": "version", "status": "ready"},
+			"expiresAt": expiresAt,
+		},
+		"sessions": map[string]any{
+			"value":     map[string]any{"name": "sessions", "status": "ready"},
+			"expiresAt": expiresAt,
+		},
+	})
+	assert.NilError(t, err)
+
+	f, err := statefile.Decode(legacy)
+	assert.NilError(t, err)
+	assert.Equal(t, len(f.Sessions), 2)
+	for _, s := range f.Sessions {
+		assert.Equal(t, s.Record.Instance.Status, sablier.InstanceStatusReady)
+	}
+}
+
+func TestDecode_UnsupportedVersion(t *testing.T) {
+	_, err := statefile.Decode([]byte(`{"version": 99, "sessions": []}`))
+	assert.ErrorIs(t, err, statefile.ErrUnsupportedVersion)
+}
+
+func TestRestore_SkipsExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	f := statefile.File{
+		Version: statefile.Version,
+		Sessions: []statefile.Session{
+			{Record: sablier.NewSessionRecord(sablier.InstanceInfo{Name: "live"}), ExpiresAt: time.Now().Add(time.Hour)},
+			{Record: sablier.NewSessionRecord(sablier.InstanceInfo{Name: "gone"}), ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+
+	store := inmemory.NewInMemory()
+	restored, err := statefile.Restore(ctx, f, store)
+	assert.NilError(t, err)
+	assert.Equal(t, restored, 1)
+
+	_, err = store.Get(ctx, "gone")
+	assert.ErrorContains(t, err, "not found")
+}
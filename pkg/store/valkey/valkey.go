@@ -3,38 +3,101 @@ package valkey
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/sablierapp/sablier/pkg/sablier"
 	"github.com/sablierapp/sablier/pkg/store"
 	"github.com/valkey-io/valkey-go"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 )
 
 var _ sablier.Store = (*ValKey)(nil)
 
+// notifyKeyspaceEvents is the server setting that controls which keyspace
+// notifications are published. OnExpire relies on keyspace events ("K") for
+// expired keys ("x", also covered by the "A" alias).
+const notifyKeyspaceEvents = "notify-keyspace-events"
+
+// Options tunes how the store lays out its keys.
+type Options struct {
+	// KeyPrefix is prepended to every session key. Only keys carrying the
+	// prefix are enumerated by Range and reported by OnExpire.
+	KeyPrefix string
+	// DB is the logical database the client selected. It scopes the keyspace
+	// notification subscription used by OnExpire.
+	DB int
+	// IgnoreKeys are full keys other Sablier components keep in the same
+	// database, such as the leader election lock. Their expiration is not
+	// reported by OnExpire.
+	IgnoreKeys []string
+}
+
 type ValKey struct {
 	Client valkey.Client
+	Prefix string
+	DB     int
+	Ignore []string
 }
 
+// New creates a store on the default database without a key prefix.
 func New(ctx context.Context, client valkey.Client) (sablier.Store, error) {
+	return NewWithOptions(ctx, client, Options{})
+}
+
+// NewWithOptions creates a store and verifies that the server publishes
+// keyspace notifications for expired keys, enabling them when they are off.
+// It fails when they are off and cannot be enabled (e.g. CONFIG is disabled on
+// a managed service), because OnExpire would otherwise never fire and no
+// session would ever be stopped.
+func NewWithOptions(ctx context.Context, client valkey.Client, opts Options) (sablier.Store, error) {
 	err := client.Do(ctx, client.B().Ping().Build()).Error()
 	if err != nil {
 		return nil, err
 	}
 
+	if err := ensureExpiredNotifications(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return &ValKey{Client: client, Prefix: opts.KeyPrefix, DB: opts.DB, Ignore: opts.IgnoreKeys}, nil
+}
+
+// ensureExpiredNotifications enables keyspace notifications for expired keys,
+// preserving any other notification classes already configured on the server.
+func ensureExpiredNotifications(ctx context.Context, client valkey.Client) error {
+	cfg, err := client.Do(ctx, client.B().ConfigGet().Parameter(notifyKeyspaceEvents).Build()).AsStrMap()
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", notifyKeyspaceEvents, err)
+	}
+	flags := cfg[notifyKeyspaceEvents]
+	if expiredNotificationsEnabled(flags) {
+		return nil
+	}
+
 	err = client.Do(ctx, client.B().ConfigSet().ParameterValue().
-		ParameterValue("notify-keyspace-events", "KEx").
+		ParameterValue(notifyKeyspaceEvents, flags+"Kx").
 		Build()).Error()
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("keyspace notifications for expired keys are disabled (%s=%q) and could not be enabled: %w; "+
+			"set %s to include \"Kx\" on the server", notifyKeyspaceEvents, flags, err, notifyKeyspaceEvents)
 	}
+	return nil
+}
+
+// expiredNotificationsEnabled reports whether the notify-keyspace-events flags
+// publish keyspace events for expired keys.
+func expiredNotificationsEnabled(flags string) bool {
+	return strings.ContainsRune(flags, 'K') && strings.ContainsAny(flags, "xA")
+}
 
-	return &ValKey{Client: client}, nil
+func (v *ValKey) key(name string) string {
+	return v.Prefix + name
 }
 
 func (v *ValKey) Get(ctx context.Context, s string) (sablier.InstanceInfo, error) {
-	b, err := v.Client.Do(ctx, v.Client.B().Get().Key(s).Build()).AsBytes()
+	b, err := v.Client.Do(ctx, v.Client.B().Get().Key(v.key(s)).Build()).AsBytes()
 	if valkey.IsValkeyNil(err) {
 		return sablier.InstanceInfo{}, store.ErrKeyNotFound
 	}
@@ -59,11 +122,11 @@ func (v *ValKey) Put(ctx context.Context, state sablier.InstanceInfo, duration t
 		return err
 	}
 
-	return v.Client.Do(ctx, v.Client.B().Set().Key(state.Name).Value(string(value)).Ex(duration).Build()).Error()
+	return v.Client.Do(ctx, v.Client.B().Set().Key(v.key(state.Name)).Value(string(value)).Ex(duration).Build()).Error()
 }
 
 func (v *ValKey) Delete(ctx context.Context, s string) error {
-	return v.Client.Do(ctx, v.Client.B().Del().Key(s).Build()).Error()
+	return v.Client.Do(ctx, v.Client.B().Del().Key(v.key(s)).Build()).Error()
 }
 
 func (v *ValKey) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Time)) error {
@@ -73,7 +136,7 @@ func (v *ValKey) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Ti
 	seen := make(map[string]struct{})
 	var cursor uint64
 	for {
-		entry, err := v.Client.Do(ctx, v.Client.B().Scan().Cursor(cursor).Count(100).Build()).AsScanEntry()
+		entry, err := v.Client.Do(ctx, v.Client.B().Scan().Cursor(cursor).Match(globEscape(v.Prefix)+"*").Count(100).Build()).AsScanEntry()
 		if err != nil {
 			return err
 		}
@@ -117,7 +180,7 @@ func (v *ValKey) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Ti
 			if err = json.Unmarshal(b, &r); err != nil {
 				continue
 			}
-			if r.Instance.Name != key {
+			if v.key(r.Instance.Name) != key {
 				continue
 			}
 
@@ -133,13 +196,25 @@ func (v *ValKey) Range(ctx context.Context, f func(sablier.InstanceInfo, time.Ti
 }
 
 func (v *ValKey) OnExpire(ctx context.Context, f func(string)) error {
+	// Keyspace channels are named __keyspace@<db>__:<key> and carry the event
+	// name as the message. Subscribing to this database and prefix only keeps
+	// expirations of foreign keys (or other Sablier deployments) out of f.
+	channelPrefix := fmt.Sprintf("__keyspace@%d__:", v.DB)
+	pattern := globEscape(channelPrefix+v.Prefix) + "*"
 	go func() {
-		err := v.Client.Receive(ctx, v.Client.B().Psubscribe().Pattern("__key*__:*").Build(), func(msg valkey.PubSubMessage) {
-			if msg.Message == "expired" {
-				split := strings.Split(msg.Channel, ":")
-				key := split[len(split)-1]
-				f(key)
+		err := v.Client.Receive(ctx, v.Client.B().Psubscribe().Pattern(pattern).Build(), func(msg valkey.PubSubMessage) {
+			if msg.Message != "expired" {
+				return
 			}
+			key := strings.TrimPrefix(msg.Channel, channelPrefix)
+			if slices.Contains(v.Ignore, key) {
+				return
+			}
+			name, ok := strings.CutPrefix(key, v.Prefix)
+			if !ok {
+				return
+			}
+			f(name)
 		})
 		if err != nil {
 			slog.Error("error subscribing", slog.Any("error", err))
@@ -147,3 +222,17 @@ func (v *ValKey) OnExpire(ctx context.Context, f func(string)) error {
 	}()
 	return nil
 }
+
+// globEscape escapes the glob metacharacters understood by SCAN MATCH and
+// PSUBSCRIBE so a literal prefix never widens the pattern.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package valkey
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExpiredNotificationsEnabled(t *testing.T) {
+	tests := []struct {
+		flags string
+		want  bool
+	}{
+		{flags: "", want: false},
+		{flags: "KEx", want: true},
+		{flags: "Kx", want: true},
+		{flags: "AK", want: true},
+		{flags: "Ex", want: false},
+		{flags: "K$", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flags, func(t *testing.T) {
+			assert.Equal(t, expiredNotificationsEnabled(tt.flags), tt.want)
+		})
+	}
+}
+
+func TestGlobEscape(t *testing.T) {
+	assert.Equal(t, globEscape("sablier:"), "sablier:")
+	assert.Equal(t, globEscape("a*b?c[d]e\\"), `a\*b\?c\[d\]e\\`)
+}
@@ -136,3 +136,83 @@ func TestValKey(t *testing.T) {
 		assert.Equal(t, expired, "ValKeyOnExpire")
 	})
 }
+
+func TestValKeyKeyPrefix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	ctx := t.Context()
+	client := setupValKeyContainer(t)
+	s, err := NewWithOptions(ctx, client, Options{KeyPrefix: "sablier:"})
+	assert.NilError(t, err)
+	vk := s.(*ValKey)
+
+	t.Run("keys are written under the prefix", func(t *testing.T) {
+		err := vk.Put(ctx, sablier.InstanceInfo{Name: "prefixed"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		exists, err := client.Do(ctx, client.B().Exists().Key("sablier:prefixed").Build()).AsInt64()
+		assert.NilError(t, err)
+		assert.Equal(t, exists, int64(1))
+
+		i, err := vk.Get(ctx, "prefixed")
+		assert.NilError(t, err)
+		assert.Equal(t, i.Name, "prefixed")
+	})
+	t.Run("range ignores keys outside the prefix", func(t *testing.T) {
+		other, err := New(ctx, client)
+		assert.NilError(t, err)
+		err = other.Put(ctx, sablier.InstanceInfo{Name: "unprefixed"}, 30*time.Second)
+		assert.NilError(t, err)
+
+		got := map[string]struct{}{}
+		err = vk.Range(ctx, func(info sablier.InstanceInfo, _ time.Time) {
+			got[info.Name] = struct{}{}
+		})
+		assert.NilError(t, err)
+		_, ok := got["prefixed"]
+		assert.Assert(t, ok)
+		_, ok = got["unprefixed"]
+		assert.Assert(t, !ok)
+	})
+	t.Run("expirations are reported without the prefix", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		expirations := make(chan string, 1)
+		err := vk.OnExpire(ctx, func(key string) {
+			expirations <- key
+		})
+		assert.NilError(t, err)
+
+		err = vk.Put(ctx, sablier.InstanceInfo{Name: "prefixed-expire"}, 1*time.Second)
+		assert.NilError(t, err)
+		assert.Equal(t, <-expirations, "prefixed-expire")
+	})
+}
+
+func TestValKeyIgnoreKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	client := setupValKeyContainer(t)
+	s, err := NewWithOptions(ctx, client, Options{IgnoreKeys: []string{"sablier:leader"}})
+	assert.NilError(t, err)
+
+	expirations := make(chan string, 2)
+	err = s.OnExpire(ctx, func(key string) {
+		expirations <- key
+	})
+	assert.NilError(t, err)
+
+	err = client.Do(ctx, client.B().Set().Key("sablier:leader").Value("sablier-0").Px(500*time.Millisecond).Build()).Error()
+	assert.NilError(t, err)
+	err = s.Put(ctx, sablier.InstanceInfo{Name: "session"}, 1*time.Second)
+	assert.NilError(t, err)
+
+	assert.Equal(t, <-expirations, "session")
+}
@@ -24,6 +24,7 @@ func (t *Themes) Render(name string, opts Options, writer io.Writer) error {
 		SessionDuration:  durations.Humanize(opts.SessionDuration),
 		RefreshFrequency: fmt.Sprintf("%d", int64(opts.RefreshFrequency.Seconds())),
 		Version:          version.Version,
+		StatusURL:        opts.StatusURL,
 	}
 
 	tpl := t.themes.Lookup(fmt.Sprintf("%s.html", name))
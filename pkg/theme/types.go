@@ -15,7 +15,7 @@ type Theme struct {
 // Instance holds the current state about an instance
 type Instance struct {
 	Name            string                          `jsonschema:"description=Name of the instance as registered with the provider,example=nginx"`
-	Status          string                          `jsonschema:"description=Current status of the instance (starting|ready|not-ready|error|not-found),example=starting"`
+	Status          string                          `jsonschema:"description=Current status of the instance (starting|ready|not-ready|queued|error|not-found),example=starting"`
 	Error           error                           `json:",omitempty" jsonschema:"description=Error encountered while resolving the instance state if any"`
 	CurrentReplicas int32                           `jsonschema:"description=Current number of running replicas,example=0"`
 	DesiredReplicas int32                           `jsonschema:"description=Target number of replicas to reach before the session is considered ready,example=1"`
@@ -33,6 +33,7 @@ type Options struct {
 	InstanceStates   []Instance
 	SessionDuration  time.Duration
 	RefreshFrequency time.Duration
+	StatusURL        string
 }
 
 // TemplateData is the data passed to a theme template when rendering a loading page.
@@ -43,4 +44,5 @@ type TemplateData struct {
 	SessionDuration  string     `jsonschema:"description=Human-readable remaining session duration (e.g. '1 hour 30 minutes').,example=1 hour 30 minutes"`
 	RefreshFrequency string     `jsonschema:"description=Page auto-refresh interval in whole seconds as a string (e.g. '30').,example=5"`
 	Version          string     `jsonschema:"description=Current Sablier server version string (e.g. '1.8.0').,example=1.8.0"`
+	StatusURL        string     `jsonschema:"description=URL of the session status stream (Server-Sent Events). Subscribe to it and reload the page on the ready event. Empty when live updates are not configured.,example=https://sablier.example.com/api/strategies/dynamic/status?names=nginx"`
 }
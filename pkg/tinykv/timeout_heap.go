@@ -49,6 +49,30 @@ func timeheapPop(h timeheapInterface) tohVal {
 	return h.Pop()
 }
 
+// Remove removes and returns the element at index i from the heap.
+// The complexity is O(log(n)) where n = h.Len().
+func timeheapRemove(h timeheapInterface, i int) tohVal {
+	n := h.Len() - 1
+	if n != i {
+		h.Swap(i, n)
+		if !timeheapdown(h, i, n) {
+			timeheapup(h, i)
+		}
+	}
+	return h.Pop()
+}
+
+// Fix re-establishes the heap ordering after the element at index i has
+// changed its value. Changing the value of the element at index i and then
+// calling Fix is equivalent to, but less expensive than, calling Remove(h, i)
+// followed by a Push of the new value.
+// The complexity is O(log(n)) where n = h.Len().
+func timeheapFix(h timeheapInterface, i int) {
+	if !timeheapdown(h, i, h.Len()) {
+		timeheapup(h, i)
+	}
+}
+
 func timeheapup(h timeheapInterface, j int) {
 	for {
 		i := (j - 1) / 2 // parent
@@ -10,6 +10,9 @@ type timeout struct {
 	expiresAt    time.Time
 	expiresAfter time.Duration
 	key          string
+	// index is the position of the timeout in the heap of its shard, -1 once
+	// it left the heap.
+	index int
 }
 
 func newTimeout(
@@ -31,17 +34,27 @@ func (to *timeout) expired() bool {
 
 //-----------------------------------------------------------------------------
 
-// timeout heap
+// timeout heap, which keeps the index of every timeout up to date so a
+// timeout can be moved or removed in place
 type th []*timeout
 
 func (h th) Len() int           { return len(h) }
 func (h th) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
-func (h th) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
-func (h *th) Push(x tohVal)     { *h = append(*h, x) }
+func (h th) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *th) Push(x tohVal) {
+	x.index = len(*h)
+	*h = append(*h, x)
+}
 func (h *th) Pop() tohVal {
 	old := *h
 	n := len(old)
 	x := old[n-1]
+	old[n-1] = nil
+	x.index = -1
 	*h = old[0 : n-1]
 	return x
 }
@@ -72,35 +85,63 @@ type KV[T any] interface {
 
 //-----------------------------------------------------------------------------
 
-// store is a registry for values (like/is a concurrent map) with timeout and sliding timeout
+// defaultShards is the number of shards of a store. It must be a power of two.
+const defaultShards = 32
+
+// shard holds the entries whose key hashes to it, and their timeouts: every
+// entry has exactly one timeout in the heap, moved in place when the entry is
+// put again and removed with it.
+type shard[T any] struct {
+	mx   sync.Mutex
+	kv   map[string]*entry[T]
+	heap th
+}
+
+// store is a registry for values (like/is a concurrent map) with timeout and sliding timeout.
+// Entries are spread over shards by key, each behind its own lock, so
+// concurrent operations on different keys rarely wait on each other.
 type store[T any] struct {
 	onExpire func(k string, v T)
 
 	stop               chan struct{}
 	stopOnce           sync.Once
 	expirationInterval time.Duration
-	mx                 sync.Mutex
-	kv                 map[string]*entry[T]
-	heap               th
+	shards             []*shard[T]
 }
 
 // New creates a new *store, onExpire is for notification (must be fast).
 func New[T any](expirationInterval time.Duration, onExpire func(k string, v T)) KV[T] {
+	return newStore(expirationInterval, onExpire, defaultShards)
+}
+
+func newStore[T any](expirationInterval time.Duration, onExpire func(k string, v T), shards int) *store[T] {
 	if expirationInterval <= 0 {
 		expirationInterval = time.Second * 20
 	}
 	res := &store[T]{
 		onExpire:           onExpire,
 		stop:               make(chan struct{}),
-		kv:                 make(map[string]*entry[T]),
 		expirationInterval: expirationInterval,
-		heap:               th{},
+		shards:             make([]*shard[T], shards),
+	}
+	for i := range res.shards {
+		res.shards[i] = &shard[T]{kv: make(map[string]*entry[T])}
 	}
 
 	go res.expireLoop()
 	return res
 }
 
+// shard returns the shard of key k, hashed with FNV-1a.
+func (kv *store[T]) shard(k string) *shard[T] {
+	h := uint32(2166136261)
+	for i := 0; i < len(k); i++ {
+		h ^= uint32(k[i])
+		h *= 16777619
+	}
+	return kv.shards[h&uint32(len(kv.shards)-1)]
+}
+
 func (kv *store[T]) SetOnExpire(onExpire func(k string, v T)) {
 	kv.onExpire = onExpire
 }
@@ -112,78 +153,100 @@ func (kv *store[T]) Stop() {
 
 // Delete deletes an entry
 func (kv *store[T]) Delete(k string) {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-	delete(kv.kv, k)
+	s := kv.shard(k)
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.remove(k)
+}
+
+// remove deletes the entry of k and its timeout. Must be called with mx held.
+func (s *shard[T]) remove(k string) {
+	e, ok := s.kv[k]
+	if !ok {
+		return
+	}
+	delete(s.kv, k)
+	if e.timeout != nil && e.index >= 0 {
+		timeheapRemove(&s.heap, e.index)
+	}
 }
 
 func (kv *store[T]) Get(k string) (T, bool) {
 	var zero T
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
+	s := kv.shard(k)
+	s.mx.Lock()
+	defer s.mx.Unlock()
 
-	e, ok := kv.kv[k]
+	e, ok := s.kv[k]
 	if !ok {
 		return zero, ok
 	}
 	if e.expired() {
 		go notifyExpirations(map[string]T{k: e.value}, kv.onExpire)
-		delete(kv.kv, k)
+		s.remove(k)
 		return zero, false
 	}
 	return e.value, ok
 }
 
 func (kv *store[T]) Keys() (keys []string) {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-
-	for k := range kv.kv {
-		keys = append(keys, k)
+	for _, s := range kv.shards {
+		s.mx.Lock()
+		for k := range s.kv {
+			keys = append(keys, k)
+		}
+		s.mx.Unlock()
 	}
 	return keys
 }
 
 func (kv *store[T]) Values() (values []T) {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-
-	for _, v := range kv.kv {
-		values = append(values, v.value)
+	for _, s := range kv.shards {
+		s.mx.Lock()
+		for _, v := range s.kv {
+			values = append(values, v.value)
+		}
+		s.mx.Unlock()
 	}
 	return values
 }
 
 func (kv *store[T]) Entries() (entries map[string]entry[T]) {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-
 	entries = make(map[string]entry[T])
-	for k, v := range kv.kv {
-
-		e := entry[T]{
-			value: v.value,
+	for _, s := range kv.shards {
+		s.mx.Lock()
+		for k, v := range s.kv {
+			entries[k] = *v.copy(k)
 		}
-		if v.timeout != nil {
+		s.mx.Unlock()
+	}
+	return entries
+}
 
-			t := &timeout{
-				expiresAt:    v.expiresAt,
-				expiresAfter: v.expiresAfter,
-				key:          k,
-			}
-			e.timeout = t
+// copy returns a copy of e, which the store does not update anymore. Must be
+// called with the lock of the shard of e held.
+func (e *entry[T]) copy(k string) *entry[T] {
+	c := &entry[T]{
+		value: e.value,
+	}
+	if e.timeout != nil {
+		c.timeout = &timeout{
+			expiresAt:    e.expiresAt,
+			expiresAfter: e.expiresAfter,
+			key:          k,
+			index:        -1,
 		}
-		entries[k] = e
 	}
-	return entries
+	return c
 }
 
 // Range calls f for every non-expired entry, passing the key, its value and its
-// absolute expiration time. It takes an instant snapshot under the store lock
-// and then invokes f for each item with the lock released, so f may be slow and
-// may safely call back into the store. Entries without a timeout or already past
-// their expiration are skipped. Range only reads: it never renews an entry's
-// timeout, so it is safe to use for observing sessions without extending them.
+// absolute expiration time. It takes an instant snapshot of each shard under
+// its lock and then invokes f for each item with the locks released, so f may
+// be slow and may safely call back into the store. Entries without a timeout or
+// already past their expiration are skipped. Range only reads: it never renews
+// an entry's timeout, so it is safe to use for observing sessions without
+// extending them.
 func (kv *store[T]) Range(f func(key string, value T, expiresAt time.Time)) {
 	type snapshotItem struct {
 		key       string
@@ -191,40 +254,57 @@ func (kv *store[T]) Range(f func(key string, value T, expiresAt time.Time)) {
 		expiresAt time.Time
 	}
 
-	kv.mx.Lock()
-	snapshot := make([]snapshotItem, 0, len(kv.kv))
-	for k, e := range kv.kv {
-		if e.timeout == nil || e.expired() {
-			continue
+	var snapshot []snapshotItem
+	for _, s := range kv.shards {
+		s.mx.Lock()
+		for k, e := range s.kv {
+			if e.timeout == nil || e.expired() {
+				continue
+			}
+			snapshot = append(snapshot, snapshotItem{key: k, value: e.value, expiresAt: e.expiresAt})
 		}
-		snapshot = append(snapshot, snapshotItem{key: k, value: e.value, expiresAt: e.expiresAt})
+		s.mx.Unlock()
 	}
-	kv.mx.Unlock()
 
 	for _, it := range snapshot {
 		f(it.key, it.value, it.expiresAt)
 	}
 }
 
-// Put puts an entry inside kv store with provided options
+// Put puts an entry inside kv store with provided options. Putting an
+// existing key replaces its value and moves its timeout in place.
 func (kv *store[T]) Put(k string, v T, expiresAfter time.Duration) error {
-	e := &entry[T]{
-		value: v,
+	s := kv.shard(k)
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if e, ok := s.kv[k]; ok && e.timeout != nil && e.index >= 0 {
+		e.value = v
+		e.expiresAt = time.Now().Add(expiresAfter)
+		e.expiresAfter = expiresAfter
+		timeheapFix(&s.heap, e.index)
+		return nil
 	}
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-
-	e.timeout = newTimeout(k, expiresAfter)
-	timeheapPush(&kv.heap, e.timeout)
 
-	kv.kv[k] = e
+	e := &entry[T]{
+		value:   v,
+		timeout: newTimeout(k, expiresAfter),
+	}
+	timeheapPush(&s.heap, e.timeout)
+	s.kv[k] = e
 	return nil
 }
 
 func (kv *store[T]) MarshalJSON() ([]byte, error) {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-	return json.Marshal(kv.kv)
+	entries := make(map[string]*entry[T])
+	for _, s := range kv.shards {
+		s.mx.Lock()
+		for k, e := range s.kv {
+			entries[k] = e.copy(k)
+		}
+		s.mx.Unlock()
+	}
+	return json.Marshal(entries)
 }
 
 func (e *entry[T]) MarshalJSON() ([]byte, error) {
@@ -311,61 +391,45 @@ func (kv *store[T]) expireLoop() {
 	}
 }
 
+// expireFunc removes the expired entries of every shard and notifies them. It
+// returns the time until the next expiration, or 0 when no entry is left.
 func (kv *store[T]) expireFunc() time.Duration {
-	kv.mx.Lock()
-	defer kv.mx.Unlock()
-
 	var interval time.Duration
-	if len(kv.heap) == 0 {
-		return interval
-	}
 	expired := make(map[string]T)
-	c := -1
-	for len(kv.heap) > 0 {
-		c++
-		if c >= len(kv.heap) {
-			break
-		}
-		last := kv.heap[0]
-		entry, ok := kv.kv[last.key]
-		if !ok {
-			timeheapPop(&kv.heap)
-			continue
-		}
-		if !last.expired() {
-			interval = time.Until(last.expiresAt)
-			if interval < 0 {
-				interval = last.expiresAfter
-			}
-			break
-		}
-		last = timeheapPop(&kv.heap)
-		if ok {
-			expired[last.key] = entry.value
+	for _, s := range kv.shards {
+		next := s.expire(expired)
+		if next > 0 && (interval == 0 || next < interval) {
+			interval = next
 		}
 	}
-REVAL:
-	for k := range expired {
-		newVal, ok := kv.kv[k]
-		if !ok ||
-			newVal.timeout == nil ||
-			!newVal.expired() {
-			delete(expired, k)
-			goto REVAL
-		}
-		delete(kv.kv, k)
-	}
-	go notifyExpirations(expired, kv.onExpire)
-	if interval == 0 && len(kv.heap) > 0 {
-		last := kv.heap[0]
-		interval = time.Until(last.expiresAt)
-		if interval < 0 {
-			interval = last.expiresAfter
-		}
+	if len(expired) > 0 {
+		go notifyExpirations(expired, kv.onExpire)
 	}
 	return interval
 }
 
+// expire moves the expired entries of the shard to expired and returns the
+// time until its next expiration, or 0 when it is empty.
+func (s *shard[T]) expire(expired map[string]T) time.Duration {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for len(s.heap) > 0 {
+		next := s.heap[0]
+		if !next.expired() {
+			interval := time.Until(next.expiresAt)
+			if interval <= 0 {
+				interval = next.expiresAfter
+			}
+			return interval
+		}
+		timeheapPop(&s.heap)
+		expired[next.key] = s.kv[next.key].value
+		delete(s.kv, next.key)
+	}
+	return 0
+}
+
 func notifyExpirations[T any](
 	expired map[string]T,
 	onExpire func(k string, v T)) {
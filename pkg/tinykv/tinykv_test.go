@@ -411,6 +411,92 @@ func TestOrdering(t *testing.T) {
 	assert.Equal(1, 1)
 }
 
+func TestPutAgainMovesTheTimeout(t *testing.T) {
+	assert := assert.New(t)
+	rg := newStore[int](time.Hour, nil, defaultShards)
+	defer rg.Stop()
+
+	for i := range 100 {
+		require.NoError(t, rg.Put("1", i, time.Duration(i+1)*time.Minute))
+	}
+	s := rg.shard("1")
+	assert.Len(s.heap, 1, "an entry put again must not leave its previous timeout in the heap")
+	v, ok := rg.Get("1")
+	assert.True(ok)
+	assert.Equal(99, v)
+	assert.WithinDuration(time.Now().Add(100*time.Minute), s.heap[0].expiresAt, time.Second)
+
+	// A shorter timeout moves the entry ahead of the others.
+	require.NoError(t, rg.Put("2", 2, time.Hour))
+	require.NoError(t, rg.Put("1", 1, time.Millisecond))
+	<-time.After(time.Millisecond * 5)
+	rg.expireFunc()
+	_, ok = rg.Get("1")
+	assert.False(ok)
+	_, ok = rg.Get("2")
+	assert.True(ok)
+}
+
+func TestDeleteRemovesTheTimeout(t *testing.T) {
+	assert := assert.New(t)
+	rg := newStore[int](time.Hour, nil, 1)
+	defer rg.Stop()
+
+	for i := range 10 {
+		require.NoError(t, rg.Put(strconv.Itoa(i), i, time.Duration(i+1)*time.Minute))
+	}
+	for i := 0; i < 10; i += 2 {
+		rg.Delete(strconv.Itoa(i))
+	}
+	s := rg.shards[0]
+	assert.Len(s.heap, 5)
+	for i, to := range s.heap {
+		assert.Equal(i, to.index)
+		_, ok := s.kv[to.key]
+		assert.True(ok, "the heap must only hold timeouts of live entries")
+	}
+
+	var prev time.Time
+	for len(s.heap) > 0 {
+		to := timeheapPop(&s.heap)
+		assert.False(to.expiresAt.Before(prev))
+		prev = to.expiresAt
+	}
+}
+
+func TestBulkExpirationAcrossShards(t *testing.T) {
+	assert := assert.New(t)
+	var cnt int64
+	rg := newStore(time.Hour, func(k string, v int) {
+		atomic.AddInt64(&cnt, 1)
+	}, defaultShards)
+	defer rg.Stop()
+
+	n := 10000
+	for i := range n {
+		require.NoError(t, rg.Put(strconv.Itoa(i), i, time.Millisecond))
+	}
+	require.NoError(t, rg.Put("live", 0, time.Hour))
+	<-time.After(time.Millisecond * 5)
+
+	next := rg.expireFunc()
+	assert.Greater(next, 59*time.Minute, "the next expiration is the live entry")
+	assert.Eventually(func() bool { return atomic.LoadInt64(&cnt) == int64(n) }, time.Second, time.Millisecond)
+	assert.Equal([]string{"live"}, rg.Keys())
+}
+
+func TestShardsSpreadKeys(t *testing.T) {
+	rg := newStore[int](time.Hour, nil, defaultShards)
+	defer rg.Stop()
+
+	for i := range 10000 {
+		require.NoError(t, rg.Put(fmt.Sprintf("docker_container_%d", i), i, time.Hour))
+	}
+	for i, s := range rg.shards {
+		assert.Greater(t, len(s.kv), 200, "shard %d holds too few keys", i)
+	}
+}
+
 func BenchmarkGetNoValue(b *testing.B) {
 	rg := New[any](-1, nil)
 	for n := 0; n < b.N; n++ {
@@ -440,3 +526,104 @@ func BenchmarkPutExpire(b *testing.B) {
 		assert.NoError(b, rg.Put("1", 1, time.Second*10))
 	}
 }
+
+// benchmarkShards runs the benchmark with a single shard, which behaves like a
+// store behind one global lock, and with the default shards.
+func benchmarkShards(b *testing.B, f func(b *testing.B, rg *store[int])) {
+	for _, shards := range []int{1, defaultShards} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			rg := newStore[int](time.Hour, nil, shards)
+			defer rg.Stop()
+			f(b, rg)
+		})
+	}
+}
+
+const benchmarkKeys = 10000
+
+func benchmarkKey(i int) string {
+	return "docker_container_" + strconv.Itoa(i%benchmarkKeys)
+}
+
+func BenchmarkPutParallel(b *testing.B) {
+	benchmarkShards(b, func(b *testing.B, rg *store[int]) {
+		var next atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := int(next.Add(1))
+				_ = rg.Put(benchmarkKey(i), i, time.Minute+time.Duration(i%1000)*time.Millisecond)
+			}
+		})
+	})
+}
+
+func BenchmarkGetPutParallel(b *testing.B) {
+	benchmarkShards(b, func(b *testing.B, rg *store[int]) {
+		for i := range benchmarkKeys {
+			_ = rg.Put(benchmarkKey(i), i, time.Minute)
+		}
+		var next atomic.Int64
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := int(next.Add(1))
+				// Strategy requests read the session far more often than they
+				// create or renew it.
+				if i%4 == 0 {
+					_ = rg.Put(benchmarkKey(i), i, time.Minute)
+				} else {
+					rg.Get(benchmarkKey(i))
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkBulkExpiration(b *testing.B) {
+	benchmarkShards(b, func(b *testing.B, rg *store[int]) {
+		for b.Loop() {
+			b.StopTimer()
+			for i := range benchmarkKeys {
+				_ = rg.Put(benchmarkKey(i), i, -time.Millisecond)
+			}
+			b.StartTimer()
+			rg.expireFunc()
+		}
+	})
+}
+
+// BenchmarkGetDuringBulkExpiration measures reads while another goroutine
+// expires batches of entries.
+func BenchmarkGetDuringBulkExpiration(b *testing.B) {
+	benchmarkShards(b, func(b *testing.B, rg *store[int]) {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = rg.Put("expiring_"+benchmarkKey(i), i, -time.Millisecond)
+				if i%1000 == 0 {
+					rg.expireFunc()
+				}
+			}
+		}()
+		for i := range benchmarkKeys {
+			_ = rg.Put(benchmarkKey(i), i, time.Hour)
+		}
+		var next atomic.Int64
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				rg.Get(benchmarkKey(int(next.Add(1))))
+			}
+		})
+		b.StopTimer()
+		close(stop)
+		<-done
+	})
+}